@@ -2,28 +2,48 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	// Project imports
+	userv1 "arritech-user-management/api/user/v1"
 	_ "arritech-user-management/docs" // Swagger docs
+	graphqlHandler "arritech-user-management/internal/handler/graphql"
+	grpcHandler "arritech-user-management/internal/handler/grpc"
 	httpHandler "arritech-user-management/internal/handler/http"
 	"arritech-user-management/internal/repository/mysql"
 	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/blob"
 	"arritech-user-management/pkg/database"
+	"arritech-user-management/pkg/health"
 	"arritech-user-management/pkg/logger"
+	"arritech-user-management/pkg/mailer"
+	"arritech-user-management/pkg/metrics"
 	"arritech-user-management/pkg/middleware"
+	"arritech-user-management/pkg/problem"
+	"arritech-user-management/pkg/token"
+	"arritech-user-management/pkg/tracing"
 
 	// Third party imports
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/joho/godotenv"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
 	swaggerFiles "github.com/swaggo/files"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 // @title Arritech User Management API
@@ -34,6 +54,14 @@ import (
 // @host localhost:8080
 // @BasePath /api/v1
 // @schemes http https
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT bearer token, sent as "Bearer <token>"
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key for machine clients, limited to the routes its scopes allow
 func main() {
 	// Load environment variables
 	if err := godotenv.Load(); err != nil {
@@ -45,6 +73,12 @@ func main() {
 	log := logger.NewLogger()
 	log.Info("Starting Arritech User Management API")
 
+	// Initialize tracing
+	tracerProvider, err := tracing.Setup(context.Background(), tracing.GetConfigFromEnv())
+	if err != nil {
+		log.WithError(err).Fatal("Failed to initialize tracing")
+	}
+
 	// Initialize database
 	dbConfig := database.GetConfigFromEnv()
 	db, err := database.NewMySQLConnection(dbConfig)
@@ -52,6 +86,22 @@ func main() {
 		log.WithError(err).Fatal("Failed to connect to database")
 	}
 
+	// Trace and time every query and export the connection pool stats
+	if err := db.Use(tracing.GORMPlugin(tracerProvider)); err != nil {
+		log.WithError(err).Fatal("Failed to register database tracing")
+	}
+	appMetrics := metrics.New()
+	if err := db.Use(appMetrics.GORMPlugin()); err != nil {
+		log.WithError(err).Fatal("Failed to register database metrics")
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.WithError(err).Fatal("Failed to get database connection pool")
+	}
+	if err := appMetrics.RegisterDB(sqlDB, dbConfig.DBName); err != nil {
+		log.WithError(err).Fatal("Failed to register connection pool metrics")
+	}
+
 	// Run migrations
 	if err := database.RunMigrations(db); err != nil {
 		log.WithError(err).Fatal("Failed to run database migrations")
@@ -61,43 +111,348 @@ func main() {
 	// Initialize validator
 	validator := validator.New()
 
-	// Initialize repository
-	userRepo := mysql.NewUserRepository(db)
+	// Initialize repositories
+	userRepo := mysql.NewUserRepository(db, log)
+	attrRepo := mysql.NewAttributeDefinitionRepository(db)
+	orgRepo := mysql.NewOrganizationRepository(db)
+	tagRepo := mysql.NewTagRepository(db)
+	groupRepo := mysql.NewGroupRepository(db)
+	noteRepo := mysql.NewUserNoteRepository(db)
+	eventRepo := mysql.NewUserEventRepository(db)
+	statsRepo := mysql.NewUserStatsRepository(db)
+	apiKeyRepo := mysql.NewAPIKeyRepository(db)
+	idempotencyRepo := mysql.NewIdempotencyKeyRepository(db)
 
-	// Initialize service
-	userService := service.NewUserService(userRepo, log)
+	// Initialize mailer
+	mailConfig := mailer.GetConfigFromEnv()
+	mail, err := mailer.New(mailConfig)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to initialize mailer")
+	}
+	// Emails are delivered in the background once the request that sends them has saved its
+	// changes, so a slow mail server doesn't hold requests up
+	outbox := mailer.NewAsyncMailer(mail, mailer.AsyncConfig{
+		QueueSize: int(getInt64Env("MAILER_QUEUE_SIZE", 100)),
+		Timeout:   mailConfig.Timeout,
+	}, log)
 
-	// Initialize handler
+	// Readiness depends on the database being reachable and migrated; the mailer only degrades
+	// email verification, so it is reported without taking the service out of rotation
+	checks := health.New(getDurationEnv("HEALTH_CHECK_TIMEOUT", 2*time.Second))
+	checks.Register("database", health.CheckerFunc(sqlDB.PingContext))
+	checks.Register("migrations", health.CheckerFunc(func(ctx context.Context) error {
+		return database.CheckMigrations(ctx, db)
+	}))
+	if pinger, ok := mail.(interface{ Ping(context.Context) error }); ok {
+		checks.RegisterOptional("mailer", health.CheckerFunc(pinger.Ping))
+	}
+
+	// Initialize blob storage
+	store, err := blob.New(blob.GetConfigFromEnv())
+	if err != nil {
+		log.WithError(err).Fatal("Failed to initialize blob storage")
+	}
+
+	// Roles and their permissions; RBAC_POLICY_FILE assigns roles to token subjects
+	policy, err := auth.LoadPolicy(os.Getenv("RBAC_POLICY_FILE"))
+	if err != nil {
+		log.WithError(err).Fatal("Failed to load RBAC policy")
+	}
+
+	authEnabled := getBoolEnv("AUTH_ENABLED", true)
+
+	// Callers without the users:pii permission see masked personal data
+	var redactor *service.PIIRedactor
+	if authEnabled {
+		redactor = service.NewPIIRedactor(policy)
+	}
+
+	verificationSecret, err := getSecretEnv("EMAIL_VERIFICATION_SECRET")
+	if err != nil {
+		log.WithError(err).Fatal("Invalid email verification configuration")
+	}
+	calendarSecret, err := getSecretEnv("CALENDAR_FEED_SECRET")
+	if err != nil {
+		log.WithError(err).Fatal("Invalid calendar feed configuration")
+	}
+
+	// Changes made through either API are streamed to gRPC watchers
+	userChanges := service.NewUserChangeFeed(64)
+
+	// Initialize services
+	verificationService := service.NewEmailVerificationService(
+		userRepo,
+		outbox,
+		token.NewSigner([]byte(verificationSecret)),
+		service.EmailVerificationConfig{
+			TokenTTL:       getDurationEnv("EMAIL_VERIFICATION_TTL", 24*time.Hour),
+			ResendCooldown: getDurationEnv("EMAIL_VERIFICATION_RESEND_COOLDOWN", 5*time.Minute),
+			VerifyURL:      getEnv("EMAIL_VERIFICATION_URL", "http://localhost:5173/verify-email"),
+		},
+		log,
+		service.WithVerificationChanges(userChanges),
+	)
+	avatarMaxBytes := getInt64Env("AVATAR_MAX_BYTES", 5<<20)
+	avatarService := service.NewAvatarService(userRepo, store, service.AvatarConfig{MaxBytes: avatarMaxBytes}, log, service.WithAvatarPIIRedaction(redactor), service.WithAvatarChanges(userChanges))
+	var attributeOptions []service.AttributeServiceOption
+	var organizationOptions []service.OrganizationServiceOption
+	if authEnabled {
+		attributeOptions = append(attributeOptions, service.WithAttributeAuthorization(policy))
+		organizationOptions = append(organizationOptions, service.WithOrganizationAuthorization(policy))
+	}
+	attributeService := service.NewAttributeService(attrRepo, log, attributeOptions...)
+	organizationService := service.NewOrganizationService(orgRepo, log, organizationOptions...)
+	tagService := service.NewTagService(tagRepo, log)
+	groupService := service.NewGroupService(groupRepo, log)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, log)
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo, service.IdempotencyConfig{
+		TTL:         getDurationEnv("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		LockTimeout: time.Minute,
+	}, log)
+	noteService := service.NewUserNoteService(noteRepo, eventRepo, userRepo, log)
+	statsService := service.NewUserStatsService(statsRepo, service.UserStatsConfig{
+		CacheTTL:        getDurationEnv("USER_STATS_CACHE_TTL", time.Minute),
+		CacheMaxEntries: int(getInt64Env("USER_STATS_CACHE_MAX_ENTRIES", 1000)),
+	}, log)
+	birthdayService := service.NewBirthdayService(
+		userRepo,
+		token.NewSigner([]byte(calendarSecret)),
+		service.BirthdayConfig{
+			FeedTTL: getDurationEnv("CALENDAR_FEED_TTL", 365*24*time.Hour),
+			FeedURL: getEnv("CALENDAR_FEED_URL", "http://localhost:8080/api/v1/users/birthdays.ics"),
+		},
+		log,
+	)
+	userOptions := []service.UserServiceOption{
+		service.WithEmailVerification(verificationService),
+		service.WithAttributeValidation(attributeService),
+		service.WithOrganizationPolicy(organizationService),
+		service.WithAvatarCleanup(avatarService),
+		service.WithActivityLog(eventRepo),
+		service.WithPIIRedaction(redactor),
+	}
+	if authEnabled {
+		// Enforce roles again in the service so callers other than the HTTP routes are covered
+		userOptions = append(userOptions, service.WithAuthorization(policy))
+	}
+	userService := service.NewTracedUserService(
+		service.NewMeteredUserService(
+			service.NewPublishingUserService(service.NewUserService(userRepo, log, userOptions...), userChanges),
+			appMetrics,
+		),
+		tracerProvider,
+	)
+
+	// Initialize handlers
 	userHandler := httpHandler.NewUserHandler(userService, validator, log)
+	verificationHandler := httpHandler.NewEmailVerificationHandler(verificationService, validator, log)
+	attributeHandler := httpHandler.NewAttributeHandler(attributeService, validator, log)
+	organizationHandler := httpHandler.NewOrganizationHandler(organizationService, validator, log)
+	tagHandler := httpHandler.NewTagHandler(tagService, validator, log)
+	groupHandler := httpHandler.NewGroupHandler(groupService, validator, log)
+	avatarHandler := httpHandler.NewAvatarHandler(avatarService, avatarMaxBytes, log)
+	noteHandler := httpHandler.NewUserNoteHandler(noteService, validator, log)
+	birthdayHandler := httpHandler.NewBirthdayHandler(birthdayService, log)
+	statsHandler := httpHandler.NewUserStatsHandler(statsService, validator, log)
+	apiKeyHandler := httpHandler.NewAPIKeyHandler(apiKeyService, validator, log)
+	graphqlLimits := graphqlHandler.DefaultLimits()
+	graphqlLimits.MaxDepth = int(getInt64Env("GRAPHQL_MAX_DEPTH", int64(graphqlLimits.MaxDepth)))
+	graphqlLimits.MaxComplexity = int(getInt64Env("GRAPHQL_MAX_COMPLEXITY", int64(graphqlLimits.MaxComplexity)))
+	graphQLHandler, err := graphqlHandler.NewGraphQLHandler(userService, validator, graphqlLimits, log)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to build GraphQL schema")
+	}
+
+	// Requests without an X-Organization header use this organization; an empty value makes the header mandatory
+	defaultOrganization, ok := os.LookupEnv("DEFAULT_ORGANIZATION")
+	if !ok {
+		defaultOrganization = "default"
+	}
 
 	// Initialize Gin router
 	router := gin.New()
-	router.Use(gin.Recovery())
+	// X-Forwarded-For is only believed from TRUSTED_PROXIES, so clients can't choose their own IP
+	if err := router.SetTrustedProxies(getListEnv("TRUSTED_PROXIES", nil)); err != nil {
+		log.WithError(err).Fatal("Invalid TRUSTED_PROXIES")
+	}
+	// Every request joins the caller's trace or starts one, then gets an ID and a request-scoped
+	// log entry, so error responses, logs and spans can be tied together
+	router.Use(middleware.TracingMiddleware(tracerProvider, otel.GetTextMapPropagator()))
+	router.Use(middleware.RequestIDMiddleware(log))
+	// Panics become a problem+json 500 like any other server error
+	router.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		problem.Abort(c, problem.CodeInternalError, "")
+	}))
 	router.Use(middleware.RequestLoggingMiddleware(log))
-	router.Use(middleware.CORSMiddleware())
+	router.Use(middleware.MetricsMiddleware(appMetrics))
+	corsConfig, err := loadCORSConfig()
+	if err != nil {
+		log.WithError(err).Fatal("Invalid CORS configuration")
+	}
+	router.Use(middleware.CORSMiddleware(corsConfig))
+
+	// Every client IP gets a bucket shared by all its requests, checked before any credentials so
+	// that guessing tokens and API keys is limited too
+	rateLimitStore := middleware.NewMemoryRateLimitStore()
+	rateLimitsEnabled := getBoolEnv("RATE_LIMIT_ENABLED", true)
+	if rateLimitsEnabled {
+		clientLimit, err := middleware.ParseLimit(getEnv("RATE_LIMIT_CLIENT_IP", "600/1m"))
+		if err != nil {
+			log.WithError(err).Fatal("Invalid RATE_LIMIT_CLIENT_IP")
+		}
+		router.Use(middleware.ClientIPRateLimitMiddleware(rateLimitStore, clientLimit, log))
+	}
+
+	// Machine clients authenticate with an X-API-Key header instead of a bearer token
+	router.Use(middleware.APIKeyMiddleware(authenticateAPIKey(apiKeyService)))
+
+	// Every route outside AUTH_PUBLIC_PATHS requires a bearer token unless AUTH_ENABLED=false
+	var verifier *auth.Verifier
+	if authEnabled {
+		verifier, err = auth.NewVerifier(auth.GetConfigFromEnv())
+		if err != nil {
+			log.WithError(err).Fatal("Failed to initialize authentication")
+		}
+		router.Use(middleware.AuthMiddleware(verifier, getListEnv("AUTH_PUBLIC_PATHS", middleware.DefaultPublicPaths)))
+	} else {
+		log.Warn("Authentication is disabled; the API is open to anyone who can reach it")
+	}
 
-	// Health check endpoint
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status":    "healthy",
-			"timestamp": time.Now().Unix(),
-		})
-	})
+	// Probes: liveness only needs the process, readiness checks its dependencies.
+	// /health is kept for existing monitors and reports readiness.
+	healthHandler := httpHandler.NewHealthHandler(checks, log)
+	router.GET("/livez", healthHandler.Livez)
+	router.GET("/readyz", healthHandler.Readyz)
+	router.GET("/health", healthHandler.Readyz)
 
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Rate limits per route group, with a bucket per API key, token subject or client IP
+	limit := func(group string) gin.HandlerFunc {
+		if !rateLimitsEnabled {
+			return func(c *gin.Context) { c.Next() }
+		}
+		config, err := rateLimitConfig(group)
+		if err != nil {
+			log.WithError(err).WithField("group", group).Fatal("Invalid rate limit")
+		}
+		return middleware.RateLimitMiddleware(rateLimitStore, config, log)
+	}
+
+	// Retries of mutations with an Idempotency-Key get the original response. It runs after the
+	// rate limit of each group, so replays and rejected retries are counted too, and the bodies it
+	// buffers are bounded; the default leaves room for an avatar upload.
+	idempotent := middleware.IdempotencyMiddleware(idempotencyService, getInt64Env("IDEMPOTENCY_MAX_BODY_BYTES", avatarMaxBytes+1<<20), log)
+
 	// API routes
 	v1 := router.Group("/api/v1")
 	{
+		// Calendar clients can't send headers; the feed token selects the organization
+		v1.GET("/users/birthdays.ics", limit("calendar"), birthdayHandler.Calendar)
+
+		// Callers need a role (or, for API keys, a scope) that grants each route's permission
+		read := middleware.RequirePermission(policy, auth.PermissionUsersRead)
+		write := middleware.RequirePermission(policy, auth.PermissionUsersWrite)
+		remove := middleware.RequirePermission(policy, auth.PermissionUsersDelete)
+		export := middleware.RequirePermission(policy, auth.PermissionUsersExport)
+		pii := middleware.RequirePermission(policy, auth.PermissionUsersPII)
+		manageKeys := middleware.RequirePermission(policy, auth.PermissionAPIKeysManage)
+		manageOrganizations := middleware.RequirePermission(policy, auth.PermissionOrganizationsManage)
+		manageAttributes := middleware.RequirePermission(policy, auth.PermissionAttributesManage)
+		humansOnly := middleware.DenyAPIKeys()
+
+		// Every resource below is scoped to the organization selected by the X-Organization header
+		tenancy := middleware.TenantMiddleware(resolveOrganization(organizationService), policy, defaultOrganization)
+
 		users := v1.Group("/users")
+		users.Use(limit("users"), idempotent, tenancy)
 		{
-			users.POST("", userHandler.CreateUser)
-			users.GET("", userHandler.ListUsers)
-			users.GET("/:id", userHandler.GetUser)
-			users.PUT("/:id", userHandler.UpdateUser)
-			users.DELETE("/:id", userHandler.DeleteUser)
+			users.POST("", write, userHandler.CreateUser)
+			users.POST("/verify-email", verificationHandler.VerifyEmail)
+			users.GET("", read, userHandler.ListUsers)
+			users.GET("/stats", read, statsHandler.GetStats)
+			// Birthdays reveal exact dates of birth
+			users.GET("/birthdays", read, pii, birthdayHandler.UpcomingBirthdays)
+			users.POST("/birthdays/feed", export, pii, birthdayHandler.IssueFeed)
+			users.GET("/:id", read, userHandler.GetUser)
+			users.PUT("/:id", write, userHandler.UpdateUser)
+			users.PATCH("/:id", write, userHandler.PatchUser)
+			users.DELETE("/:id", remove, userHandler.DeleteUser)
+			users.POST("/:id/activate", write, userHandler.ActivateUser)
+			users.POST("/:id/suspend", write, userHandler.SuspendUser)
+			users.POST("/:id/archive", write, userHandler.ArchiveUser)
+			users.POST("/:id/resend-verification", write, verificationHandler.ResendVerification)
+			users.PUT("/:id/avatar", write, avatarHandler.UploadAvatar)
+			users.GET("/:id/avatar", read, avatarHandler.GetAvatar)
+			users.POST("/:id/notes", write, noteHandler.CreateNote)
+			users.GET("/:id/notes", read, noteHandler.ListNotes)
+			users.GET("/:id/notes/:noteId", read, noteHandler.GetNote)
+			users.PUT("/:id/notes/:noteId", write, noteHandler.UpdateNote)
+			users.DELETE("/:id/notes/:noteId", remove, noteHandler.DeleteNote)
+			users.GET("/:id/timeline", read, noteHandler.Timeline)
 		}
+
+		// API keys are managed by admins
+		apiKeys := v1.Group("/api-keys")
+		apiKeys.Use(limit("api-keys"), idempotent, manageKeys, tenancy)
+		{
+			apiKeys.POST("", apiKeyHandler.CreateKey)
+			apiKeys.GET("", apiKeyHandler.ListKeys)
+			apiKeys.DELETE("/:id", apiKeyHandler.RevokeKey)
+		}
+
+		organizations := v1.Group("/organizations")
+		// Callers see and manage their own organization; platform admins see every one
+		organizations.Use(limit("organizations"), idempotent, humansOnly, tenancy)
+		{
+			organizations.POST("", manageOrganizations, organizationHandler.CreateOrganization)
+			organizations.GET("", organizationHandler.ListOrganizations)
+			organizations.GET("/:id", organizationHandler.GetOrganization)
+			organizations.PUT("/:id", manageOrganizations, organizationHandler.UpdateOrganization)
+		}
+
+		attributes := v1.Group("/attributes")
+		attributes.Use(limit("attributes"), idempotent, tenancy)
+		{
+			attributes.POST("", humansOnly, manageAttributes, attributeHandler.CreateDefinition)
+			attributes.GET("", read, attributeHandler.ListDefinitions)
+			attributes.GET("/schema", read, attributeHandler.Schema)
+			attributes.PUT("/:key", humansOnly, manageAttributes, attributeHandler.UpdateDefinition)
+			attributes.DELETE("/:key", humansOnly, manageAttributes, attributeHandler.DeleteDefinition)
+		}
+
+		tags := v1.Group("/tags")
+		tags.Use(limit("tags"), idempotent, tenancy)
+		{
+			tags.POST("", write, tagHandler.CreateTag)
+			tags.GET("", read, tagHandler.ListTags)
+			tags.GET("/:id", read, tagHandler.GetTag)
+			tags.PUT("/:id", write, tagHandler.UpdateTag)
+			tags.DELETE("/:id", remove, tagHandler.DeleteTag)
+			tags.POST("/:id/users", write, tagHandler.AddUsers)
+			tags.DELETE("/:id/users", remove, tagHandler.RemoveUsers)
+		}
+
+		groups := v1.Group("/groups")
+		groups.Use(limit("groups"), idempotent, tenancy)
+		{
+			groups.POST("", write, groupHandler.CreateGroup)
+			groups.GET("", read, groupHandler.ListGroups)
+			groups.GET("/:id", read, groupHandler.GetGroup)
+			groups.PUT("/:id", write, groupHandler.UpdateGroup)
+			groups.DELETE("/:id", remove, groupHandler.DeleteGroup)
+			groups.POST("/:id/users", write, groupHandler.AddUsers)
+			groups.DELETE("/:id/users", remove, groupHandler.RemoveUsers)
+		}
+	}
+
+	// GraphQL serves the same users as /api/v1/users; the user service checks each operation's
+	// permission, since a single query can read and write
+	graphqlBodyLimit := middleware.BodyLimitMiddleware(getInt64Env("GRAPHQL_MAX_BODY_BYTES", 1<<20))
+	router.POST("/graphql", limit("graphql"), graphqlBodyLimit, idempotent, middleware.TenantMiddleware(resolveOrganization(organizationService), policy, defaultOrganization), graphQLHandler.Query)
+	if getBoolEnv("GRAPHIQL_ENABLED", gin.Mode() == gin.DebugMode) {
+		router.GET("/graphiql", graphQLHandler.GraphiQL)
 	}
 
 	// Start server
@@ -115,11 +470,76 @@ func main() {
 		}
 	}()
 
+	// The gRPC API serves the same users with the same credentials, organizations and roles
+	grpcSteps := []grpcHandler.Step{grpcHandler.APIKeyStep(authenticateAPIKey(apiKeyService))}
+	var watchAuthorizer service.Authorizer
+	if authEnabled {
+		grpcSteps = append(grpcSteps, grpcHandler.AuthStep(verifier, grpcHandler.DefaultPublicMethods))
+		watchAuthorizer = policy
+	}
+	grpcSteps = append(grpcSteps, grpcHandler.TenantStep(resolveOrganization(organizationService), policy, defaultOrganization, grpcHandler.DefaultPublicMethods))
+	grpcSrv := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler(otelgrpc.WithTracerProvider(tracerProvider))),
+		grpc.ChainUnaryInterceptor(grpcHandler.UnaryLoggingInterceptor(log), grpcHandler.UnaryInterceptor(grpcSteps...)),
+		grpc.ChainStreamInterceptor(grpcHandler.StreamLoggingInterceptor(log), grpcHandler.StreamInterceptor(grpcSteps...)),
+	)
+	userv1.RegisterUserServiceServer(grpcSrv, grpcHandler.NewUserServer(userService, userChanges, watchAuthorizer, validator, log))
+	healthpb.RegisterHealthServer(grpcSrv, grpcHandler.NewHealthServer(checks, getDurationEnv("GRPC_HEALTH_POLL_INTERVAL", 5*time.Second), log))
+	reflection.Register(grpcSrv)
+
+	grpcPort := getEnv("GRPC_PORT", "9000")
+	grpcListener, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to listen for gRPC")
+	}
+	go func() {
+		log.WithField("port", grpcPort).Info("Starting gRPC server")
+		if err := grpcSrv.Serve(grpcListener); err != nil {
+			log.WithError(err).Fatal("Failed to start gRPC server")
+		}
+	}()
+
+	// Metrics are served on a separate admin port that isn't exposed publicly
+	adminPort := getEnv("ADMIN_PORT", "9090")
+	adminMux := http.NewServeMux()
+	adminMux.Handle("/metrics", appMetrics.Handler())
+	adminSrv := &http.Server{
+		Addr:    ":" + adminPort,
+		Handler: adminMux,
+	}
+	go func() {
+		log.WithField("port", adminPort).Info("Starting admin server")
+		if err := adminSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.WithError(err).Fatal("Failed to start admin server")
+		}
+	}()
+
+	// Purge expired idempotency keys in the background
+	jobs, stopJobs := context.WithCancel(context.Background())
+	go func() {
+		ticker := time.NewTicker(getDurationEnv("IDEMPOTENCY_CLEANUP_INTERVAL", time.Hour))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-jobs.Done():
+				return
+			case <-ticker.C:
+				_, _ = idempotencyService.PurgeExpired(jobs)
+			}
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Info("Shutting down server...")
+	stopJobs()
+
+	// Fail readiness first and keep serving until load balancers have noticed, so that no new
+	// requests are sent to a server that is about to stop accepting them
+	checks.Drain()
+	time.Sleep(getDurationEnv("SHUTDOWN_DRAIN_DELAY", 5*time.Second))
 
 	// Give outstanding requests 30 seconds to complete
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -128,6 +548,27 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.WithError(err).Fatal("Server forced to shutdown")
 	}
+	// Watch streams only end with their clients, so they are cut off once the deadline passes
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcSrv.GracefulStop()
+		close(grpcStopped)
+	}()
+	select {
+	case <-grpcStopped:
+	case <-ctx.Done():
+		grpcSrv.Stop()
+	}
+	if err := adminSrv.Shutdown(ctx); err != nil {
+		log.WithError(err).Warn("Admin server forced to shutdown")
+	}
+	if err := outbox.Close(ctx); err != nil {
+		log.WithError(err).Warn("Queued emails were not delivered before shutdown")
+	}
+	// Flush the spans of the last requests
+	if err := tracerProvider.Shutdown(ctx); err != nil {
+		log.WithError(err).Warn("Failed to flush traces")
+	}
 
 	log.Info("Server exited")
 }
@@ -138,3 +579,125 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+func getInt64Env(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+// getSecretEnv reads a signing secret. Debug mode falls back to a well-known placeholder;
+// anywhere else an unset secret is an error, as tokens signed with it could be forged.
+func getSecretEnv(key string) (string, error) {
+	if value := os.Getenv(key); value != "" {
+		return value, nil
+	}
+	if gin.Mode() != gin.DebugMode {
+		return "", fmt.Errorf("%s must be set outside debug mode", key)
+	}
+	return "change-me", nil
+}
+
+// rateLimitConfig reads the limits of a route group from RATE_LIMIT_<GROUP>_READ and
+// RATE_LIMIT_<GROUP>_WRITE, falling back to RATE_LIMIT_READ and RATE_LIMIT_WRITE
+func rateLimitConfig(group string) (middleware.RateLimitConfig, error) {
+	config := middleware.RateLimitConfig{Group: group}
+	prefix := "RATE_LIMIT_" + strings.ToUpper(strings.ReplaceAll(group, "-", "_"))
+
+	var err error
+	if config.Read, err = middleware.ParseLimit(getEnv(prefix+"_READ", getEnv("RATE_LIMIT_READ", "120/1m"))); err != nil {
+		return config, err
+	}
+	if config.Write, err = middleware.ParseLimit(getEnv(prefix+"_WRITE", getEnv("RATE_LIMIT_WRITE", "60/1m"))); err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
+// loadCORSConfig reads the CORS policy from CORS_CONFIG_FILE, if set, and applies the CORS_*
+// environment variables on top of it
+func loadCORSConfig() (middleware.CORSConfig, error) {
+	config, err := middleware.LoadCORSConfig(os.Getenv("CORS_CONFIG_FILE"))
+	if err != nil {
+		return config, err
+	}
+	config.AllowedOrigins = getListEnv("CORS_ALLOWED_ORIGINS", config.AllowedOrigins)
+	config.AllowedMethods = getListEnv("CORS_ALLOWED_METHODS", config.AllowedMethods)
+	config.AllowedHeaders = getListEnv("CORS_ALLOWED_HEADERS", config.AllowedHeaders)
+	config.ExposedHeaders = getListEnv("CORS_EXPOSED_HEADERS", config.ExposedHeaders)
+	config.AllowCredentials = getBoolEnv("CORS_ALLOW_CREDENTIALS", config.AllowCredentials)
+	config.MaxAge = int(getDurationEnv("CORS_MAX_AGE", time.Duration(config.MaxAge)*time.Second) / time.Second)
+	return config, config.Validate()
+}
+
+// getListEnv reads a comma-separated list; an unset variable yields defaultValue
+func getListEnv(key string, defaultValue []string) []string {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// authenticateAPIKey adapts the API key service to the API key middleware
+func authenticateAPIKey(apiKeys service.APIKeyService) middleware.APIKeyAuthenticator {
+	return func(ctx context.Context, raw string) (*auth.Claims, error) {
+		key, err := apiKeys.Authenticate(ctx, raw)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrAPIKeyExpired):
+				return nil, auth.ErrExpiredToken
+			case errors.Is(err, service.ErrInvalidAPIKey), errors.Is(err, service.ErrAPIKeyRevoked):
+				return nil, auth.ErrInvalidToken
+			}
+			return nil, err
+		}
+		return &auth.Claims{
+			Subject:        "api-key:" + key.Prefix,
+			OrganizationID: key.OrganizationID,
+			APIKeyID:       key.ID,
+			Scopes:         key.Scopes,
+		}, nil
+	}
+}
+
+// resolveOrganization adapts the organization service to the tenant middleware
+func resolveOrganization(organizations service.OrganizationService) middleware.OrganizationResolver {
+	return func(ctx context.Context, ref string) (uint, error) {
+		org, err := organizations.ResolveOrganization(ctx, ref)
+		if err != nil {
+			if errors.Is(err, service.ErrOrganizationNotFound) {
+				return 0, middleware.ErrUnknownOrganization
+			}
+			return 0, err
+		}
+		return org.ID, nil
+	}
+}
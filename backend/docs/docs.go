@@ -18,53 +18,27 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/users": {
+        "/api-keys": {
             "get": {
-                "description": "Get list of users with pagination, search and sorting functionality",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Get the API keys of the organization, newest first. Secrets are never returned.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "api-keys"
                 ],
-                "summary": "List users",
+                "summary": "List API keys",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search term",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Items per page",
-                        "name": "per_page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "default": "created_at",
-                        "description": "Sort field (name, email, age, phone, created_at, updated_at)",
-                        "name": "sort_by",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "default": "desc",
-                        "description": "Sort direction (asc, desc)",
-                        "name": "sort_dir",
-                        "in": "query"
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -74,22 +48,21 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handler_http.SuccessResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler_http.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_http.ErrorResponse"
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Create a new user with the provided information",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create an API key for a machine client. The key is only returned in this response; store it securely.",
                 "consumes": [
                     "application/json"
                 ],
@@ -97,17 +70,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "api-keys"
                 ],
-                "summary": "Create a new user",
+                "summary": "Create API key",
                 "parameters": [
                     {
-                        "description": "User information",
-                        "name": "user",
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "API key name, scopes and optional expiry",
+                        "name": "key",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.CreateUserRequest"
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.CreateAPIKeyRequest"
                         }
                     }
                 ],
@@ -121,35 +100,43 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_http.ErrorResponse"
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_http.ErrorResponse"
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
                         }
                     }
                 }
             }
         },
-        "/users/{id}": {
-            "get": {
-                "description": "Get user information by user ID",
-                "consumes": [
-                    "application/json"
+        "/api-keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Revoke an API key; requests using it are rejected from now on. The key stays listed for auditing.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "api-keys"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Revoke API key",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "API key ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -165,25 +152,162 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_http.ErrorResponse"
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_http.ErrorResponse"
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/attributes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get all custom attribute definitions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attributes"
+                ],
+                "summary": "List attribute definitions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_http.ErrorResponse"
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
                         }
                     }
                 }
             },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Define a custom attribute that can be set on users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attributes"
+                ],
+                "summary": "Create attribute definition",
+                "parameters": [
+                    {
+                        "description": "Attribute definition",
+                        "name": "definition",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.CreateAttributeDefinitionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/attributes/schema": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get the custom attribute definitions rendered as a JSON Schema (draft 2020-12)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attributes"
+                ],
+                "summary": "Attribute JSON Schema",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/attributes/{key}": {
             "put": {
-                "description": "Update user information",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the constraints of a custom attribute. Key and type are immutable.",
                 "consumes": [
                     "application/json"
                 ],
@@ -191,24 +315,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "attributes"
                 ],
-                "summary": "Update user",
+                "summary": "Update attribute definition",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Attribute key",
+                        "name": "key",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "User information to update",
-                        "name": "user",
+                        "description": "Attribute constraints to update",
+                        "name": "definition",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.UpdateUserRequest"
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.UpdateAttributeDefinitionRequest"
                         }
                     }
                 ],
@@ -222,40 +346,48 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_http.ErrorResponse"
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_http.ErrorResponse"
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_http.ErrorResponse"
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Delete user by ID",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Delete a custom attribute and remove its values from all users",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "attributes"
                 ],
-                "summary": "Delete user",
+                "summary": "Delete attribute definition",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Attribute key",
+                        "name": "key",
                         "in": "path",
                         "required": true
                     }
@@ -267,93 +399,3751 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handler_http.SuccessResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_http.ErrorResponse"
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_http.ErrorResponse"
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_http.ErrorResponse"
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "arritech-user-management_internal_domain_entity.CreateUserRequest": {
-            "type": "object",
-            "required": [
-                "date_of_birth",
-                "email",
-                "name"
-            ],
-            "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "date_of_birth": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 2
-                },
-                "phone": {
-                    "type": "string",
-                    "maxLength": 20,
-                    "minLength": 10
-                }
-            }
         },
-        "arritech-user-management_internal_domain_entity.UpdateUserRequest": {
-            "type": "object",
-            "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "date_of_birth": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 2
-                },
-                "phone": {
-                    "type": "string",
-                    "maxLength": 20,
-                    "minLength": 10
-                }
-            }
-        },
-        "internal_handler_http.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "details": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
-                },
-                "error": {
-                    "type": "string"
+        "/groups": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get all groups with their member counts, ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "List groups",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Create a group to organise users into a cohort",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Create group",
+                "parameters": [
+                    {
+                        "description": "Group data",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.CreateGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get a single group and its member count by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get group by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Rename a group or change its description",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Update group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Group fields to update",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.UpdateGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Delete a group and remove all of its members",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Delete group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/users": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Add up to 1000 users to a group. Users that are already members or do not exist are skipped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Add group members",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User IDs",
+                        "name": "users",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.MembershipRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.MembershipResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Remove up to 1000 users from a group",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Remove group members",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User IDs",
+                        "name": "users",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.MembershipRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.MembershipResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the caller's organization, or every organization ordered by name for platform admins",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "List organizations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a tenant organization with its own users and settings. Needs the platform:admin permission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Create organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Organization data",
+                        "name": "organization",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.CreateOrganizationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a single organization and its settings. Callers other than platform admins can only get their own organization.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Get organization by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an organization's name, minimum user age or allowed email domains. Callers other than platform admins can only update their own organization.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Update organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Settings to update",
+                        "name": "organization",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.UpdateOrganizationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get all tags ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "List tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Create a tag that can be attached to users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Create tag",
+                "parameters": [
+                    {
+                        "description": "Tag data",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.CreateTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get a single tag by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Get tag by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Rename or recolor a tag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Update tag",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tag fields to update",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.UpdateTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Delete a tag and detach it from all users",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Delete tag",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}/users": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Attach a tag to up to 1000 users. Users that already have the tag or do not exist are skipped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Tag users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User IDs",
+                        "name": "users",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.MembershipRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.MembershipResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Detach a tag from up to 1000 users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Untag users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User IDs",
+                        "name": "users",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.MembershipRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.MembershipResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get list of users with pagination, search and sorting functionality. Callers without the users:pii permission get masked personal data, search names only and can't sort by email or phone.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search term for name, email or phone",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Sort field (name, email, age, phone, created_at, updated_at)",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort direction (asc, desc)",
+                        "name": "sort_dir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter on a custom attribute value, e.g. attr[department]=sales",
+                        "name": "attr[key]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status filter (pending, active, suspended, archived, all); archived users are hidden by default",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated tag names to filter on",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "any",
+                        "description": "Whether users need any or all of the tags (any, all)",
+                        "name": "tag_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated group names to filter on",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "any",
+                        "description": "Whether users need to be in any or all of the groups (any, all)",
+                        "name": "group_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return for each user, e.g. id,name,email,age; all by default",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated related resources to embed in each user (groups)",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Create a new user with the provided information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "User information",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/birthdays": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get users whose birthday falls between from and to (inclusive), ordered by date. The range may wrap into the next year but must be shorter than a year. People born on Feb 29 are listed on Feb 28 in common years.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "birthdays"
+                ],
+                "summary": "List upcoming birthdays",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day, YYYY-MM-DD (default: today)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, YYYY-MM-DD (default: 30 days after from)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/birthdays.ics": {
+            "get": {
+                "description": "Get the organization's birthdays as an iCalendar (RFC 5545) file with yearly recurring events. Authenticate with the token of a calendar link, as a query parameter or bearer token.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "birthdays"
+                ],
+                "summary": "Birthday calendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Calendar feed token",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer calendar feed token",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/birthdays/feed": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Create a link to the organization's birthday calendar for mail and calendar clients. The link contains a secret token; anyone who has it can read the calendar until it expires.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "birthdays"
+                ],
+                "summary": "Create birthday calendar link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get user totals, counts by status, an age histogram, signups over time, the top email domains and the distribution by phone calling code",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "18,25,35,45,55,65",
+                        "description": "Comma-separated ages where histogram buckets start",
+                        "name": "age_buckets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "day",
+                        "description": "Signup interval: day, week or month",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day of the signup series, YYYY-MM-DD (default: 30 days before to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day of the signup series, YYYY-MM-DD (default: today)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of email domains to return (max 100)",
+                        "name": "top_domains",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.UserStats"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/verify-email": {
+            "post": {
+                "description": "Consume an email verification token and mark the user's email as verified",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Verify email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Verification token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.VerifyEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get user information by user ID. Phone, address, the day and month of birth and the local part of the email are masked for callers without the users:pii permission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, e.g. id,name,email,age; all by default",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated related resources to embed (groups)",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Update user information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User information to update",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Delete user by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Partially update a user with a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902).\nThe patch applies to name, email, date_of_birth, phone, address and attributes; JSON Patch\ntest operations can guard against concurrent changes. The result is validated like a PUT and\nonly saved if the user didn't change while the patch was applied.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Patch user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge patch document or array of JSON Patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/activate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Activate a pending or suspended user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Activate user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for the status change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.ChangeUserStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Archive a user, taking them out of circulation without deleting them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Archive user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for the status change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.ChangeUserStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/avatar": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get a user's avatar as JPEG in one of the generated sizes",
+                "produces": [
+                    "image/jpeg"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get avatar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 256,
+                        "description": "Size in pixels: 64, 128 or 256",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Upload a JPEG, PNG or GIF avatar. The image is cropped to a square, resized to 64, 128 and 256 pixels and re-encoded as JPEG without metadata.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Upload avatar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Avatar image",
+                        "name": "avatar",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/notes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get all notes of a user, pinned notes first and then newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "List notes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/arritech-user-management_internal_domain_entity.UserNote"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Add an internal markdown note to a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Create note",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note data",
+                        "name": "note",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.CreateUserNoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.UserNote"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/notes/{noteId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get a single note of a user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Get note",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Note ID",
+                        "name": "noteId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.UserNote"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Edit the body of a note or pin and unpin it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Update note",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Note ID",
+                        "name": "noteId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note fields to update",
+                        "name": "note",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.UpdateUserNoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.UserNote"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Delete a note of a user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Delete note",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Note ID",
+                        "name": "noteId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/resend-verification": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Send a new verification email. Limited to one email per cooldown window.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Resend verification email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/suspend": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Suspend a pending or active user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Suspend user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for the status change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.ChangeUserStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/timeline": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get a user's notes and lifecycle events (created, updated, status changes, deleted), newest first. Pass next_cursor of a page as cursor to get the next one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Get user timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)",
+                        "name": "X-Organization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries older than this RFC 3339 time",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries after this next_cursor of a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Entries per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/internal_handler_http.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/arritech-user-management_internal_domain_entity.TimelineResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "arritech-user-management_internal_domain_entity.AgeBucket": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "max": {
+                    "type": "integer"
+                },
+                "min": {
+                    "type": "integer"
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.ChangeUserStatusRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "minLength": 3
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 2
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.CreateAttributeDefinitionRequest": {
+            "type": "object",
+            "required": [
+                "key",
+                "type"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "enum": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "key": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "minLength": 1
+                },
+                "pattern": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "string",
+                        "number",
+                        "integer",
+                        "boolean"
+                    ]
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.CreateGroupRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.CreateOrganizationRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "slug"
+            ],
+            "properties": {
+                "allowed_email_domains": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "minimum_age": {
+                    "type": "integer",
+                    "maximum": 150,
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 2
+                },
+                "slug": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "minLength": 2
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.CreateTagRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 1
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.CreateUserNoteRequest": {
+            "type": "object",
+            "required": [
+                "author",
+                "body"
+            ],
+            "properties": {
+                "author": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "body": {
+                    "type": "string",
+                    "maxLength": 10000,
+                    "minLength": 1
+                },
+                "pinned": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.CreateUserRequest": {
+            "type": "object",
+            "required": [
+                "date_of_birth",
+                "email",
+                "name"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "date_of_birth": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 2
+                },
+                "phone": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 10
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.DomainCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "domain": {
+                    "type": "string"
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.MembershipRequest": {
+            "type": "object",
+            "required": [
+                "user_ids"
+            ],
+            "properties": {
+                "user_ids": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.MembershipResponse": {
+            "type": "object",
+            "properties": {
+                "affected": {
+                    "type": "integer"
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.PhoneCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "prefix": {
+                    "type": "string"
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.SignupBucket": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "period": {
+                    "type": "string"
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.TimelineEntry": {
+            "type": "object",
+            "properties": {
+                "event": {
+                    "$ref": "#/definitions/arritech-user-management_internal_domain_entity.UserEvent"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "note": {
+                    "$ref": "#/definitions/arritech-user-management_internal_domain_entity.UserNote"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.TimelineResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/arritech-user-management_internal_domain_entity.TimelineEntry"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor is the cursor for the next page, absent on the last page",
+                    "type": "string"
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.UpdateAttributeDefinitionRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "enum": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pattern": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "required": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.UpdateGroupRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.UpdateOrganizationRequest": {
+            "type": "object",
+            "properties": {
+                "allowed_email_domains": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "minimum_age": {
+                    "type": "integer",
+                    "maximum": 150,
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 2
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.UpdateTagRequest": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 1
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.UpdateUserNoteRequest": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 10000,
+                    "minLength": 1
+                },
+                "pinned": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.UpdateUserRequest": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "attributes": {
+                    "description": "Attributes replaces the full set of custom attributes when present",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "date_of_birth": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 2
+                },
+                "phone": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 10
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.UserEvent": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "Actor is the subject of the token that made the change, empty when unauthenticated",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/arritech-user-management_internal_domain_entity.UserEventType"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.UserEventType": {
+            "type": "string",
+            "enum": [
+                "created",
+                "updated",
+                "status_changed",
+                "deleted"
+            ],
+            "x-enum-varnames": [
+                "UserEventCreated",
+                "UserEventUpdated",
+                "UserEventStatusChanged",
+                "UserEventDeleted"
+            ]
+        },
+        "arritech-user-management_internal_domain_entity.UserNote": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "body": {
+                    "description": "Body is markdown and is returned as written; clients are responsible for rendering it safely",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "pinned": {
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.UserStats": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "Active counts users that are not soft-deleted, whatever their lifecycle status",
+                    "type": "integer"
+                },
+                "age_histogram": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/arritech-user-management_internal_domain_entity.AgeBucket"
+                    }
+                },
+                "by_status": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "deleted": {
+                    "type": "integer"
+                },
+                "phone_countries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/arritech-user-management_internal_domain_entity.PhoneCount"
+                    }
+                },
+                "signups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/arritech-user-management_internal_domain_entity.SignupBucket"
+                    }
+                },
+                "top_email_domains": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/arritech-user-management_internal_domain_entity.DomainCount"
+                    }
+                },
+                "total": {
+                    "description": "Total counts all users, including soft-deleted ones",
+                    "type": "integer"
+                }
+            }
+        },
+        "arritech-user-management_internal_domain_entity.VerifyEmailRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "arritech-user-management_pkg_problem.Code": {
+            "type": "string",
+            "enum": [
+                "MALFORMED_REQUEST",
+                "VALIDATION_FAILED",
+                "INVALID_PARAMETER",
+                "UNSUPPORTED_MEDIA_TYPE",
+                "REQUEST_TOO_LARGE",
+                "INTERNAL_ERROR",
+                "AUTHENTICATION_REQUIRED",
+                "TOKEN_INVALID",
+                "TOKEN_EXPIRED",
+                "API_KEY_INVALID",
+                "API_KEY_EXPIRED",
+                "API_KEY_NOT_ALLOWED",
+                "PERMISSION_DENIED",
+                "ORGANIZATION_REQUIRED",
+                "ORGANIZATION_UNKNOWN",
+                "ORGANIZATION_FORBIDDEN",
+                "RATE_LIMITED",
+                "IDEMPOTENCY_KEY_INVALID",
+                "IDEMPOTENCY_KEY_REUSED",
+                "IDEMPOTENCY_KEY_IN_PROGRESS",
+                "USER_NOT_FOUND",
+                "USER_EMAIL_TAKEN",
+                "USER_UNDERAGE",
+                "USER_EMAIL_DOMAIN_NOT_ALLOWED",
+                "USER_STATUS_TRANSITION_INVALID",
+                "PATCH_INVALID",
+                "PATCH_TEST_FAILED",
+                "PATCH_RESULT_INVALID",
+                "USER_MODIFIED",
+                "VERIFICATION_TOKEN_INVALID",
+                "EMAIL_ALREADY_VERIFIED",
+                "VERIFICATION_RATE_LIMITED",
+                "AVATAR_NOT_FOUND",
+                "AVATAR_INVALID",
+                "AVATAR_TOO_LARGE",
+                "AVATAR_REPLACED",
+                "BIRTHDAY_RANGE_INVALID",
+                "FEED_TOKEN_INVALID",
+                "STATS_PARAMS_INVALID",
+                "NOTE_NOT_FOUND",
+                "TAG_NOT_FOUND",
+                "TAG_NAME_TAKEN",
+                "GROUP_NOT_FOUND",
+                "GROUP_NAME_TAKEN",
+                "ORGANIZATION_NOT_FOUND",
+                "ORGANIZATION_SLUG_TAKEN",
+                "ORGANIZATION_INVALID",
+                "API_KEY_NOT_FOUND",
+                "ATTRIBUTE_NOT_FOUND",
+                "ATTRIBUTE_KEY_TAKEN",
+                "ATTRIBUTE_DEFINITION_INVALID"
+            ],
+            "x-enum-comments": {
+                "CodeAPIKeyExpired": "401: The API key has expired",
+                "CodeAPIKeyInvalid": "401: The API key is unknown or revoked",
+                "CodeAPIKeyNotAllowed": "403: The route is reserved for people and can't be called with an API key",
+                "CodeAPIKeyNotFound": "404: The API key doesn't exist in the organization",
+                "CodeAttributeDefinitionInvalid": "400: The attribute definition is inconsistent, e.g. a select without options",
+                "CodeAttributeKeyTaken": "409: Another attribute definition in the organization has the key",
+                "CodeAttributeNotFound": "404: The attribute definition doesn't exist in the organization",
+                "CodeAuthenticationRequired": "401: The request carries neither a bearer token nor an API key",
+                "CodeAvatarInvalid": "400: The avatar file is missing or isn't a supported image",
+                "CodeAvatarNotFound": "404: The user has no avatar",
+                "CodeAvatarReplaced": "409: Another upload replaced the avatar while this one was processed; upload it again",
+                "CodeAvatarTooLarge": "413: The avatar file exceeds the upload limit",
+                "CodeBirthdayRangeInvalid": "400: The birthday date range is empty or too long",
+                "CodeEmailAlreadyVerified": "409: The user's email address is already verified",
+                "CodeFeedTokenInvalid": "401: The calendar feed token is missing, invalid or expired",
+                "CodeGroupNameTaken": "409: Another group in the organization has the name",
+                "CodeGroupNotFound": "404: The group doesn't exist in the organization",
+                "CodeIdempotencyKeyInProgress": "409: A request with the same Idempotency-Key is still running; Retry-After says when to try again",
+                "CodeIdempotencyKeyInvalid": "400: The Idempotency-Key header is too long",
+                "CodeIdempotencyKeyReused": "422: The Idempotency-Key was already used for a different request",
+                "CodeInternalError": "500: The server failed to process the request",
+                "CodeInvalidParameter": "400: A path or query parameter is invalid; errors names it",
+                "CodeMalformedRequest": "400: The request body is not valid JSON or doesn't match the expected shape",
+                "CodeNoteNotFound": "404: The note doesn't exist on the user",
+                "CodeOrganizationForbidden": "403: The X-Organization header names an organization the caller can't act in",
+                "CodeOrganizationInvalid": "400: The organization settings are inconsistent",
+                "CodeOrganizationNotFound": "404: The organization doesn't exist",
+                "CodeOrganizationRequired": "400: No organization was selected and the server has no default",
+                "CodeOrganizationSlugTaken": "409: Another organization has the slug",
+                "CodeOrganizationUnknown": "400: The X-Organization header names an organization that doesn't exist",
+                "CodePatchInvalid": "400: The patch document is malformed or uses an unsupported operation",
+                "CodePatchResultInvalid": "422: The patch applied, but the resulting user is invalid",
+                "CodePatchTestFailed": "409: A test operation of the patch didn't match the current user",
+                "CodePermissionDenied": "403: The caller lacks the permission named in the detail",
+                "CodeRateLimited": "429: The caller sent too many requests; Retry-After says when to try again",
+                "CodeRequestTooLarge": "413: The request body exceeds the size limit",
+                "CodeStatsParamsInvalid": "400: The statistics parameters are inconsistent, e.g. from is after to",
+                "CodeTagNameTaken": "409: Another tag in the organization has the name",
+                "CodeTagNotFound": "404: The tag doesn't exist in the organization",
+                "CodeTokenExpired": "401: The bearer token has expired",
+                "CodeTokenInvalid": "401: The bearer token is malformed, has a bad signature or is not yet valid",
+                "CodeUnsupportedMediaType": "415: The request body has a content type the endpoint doesn't accept",
+                "CodeUserEmailDomainNotAllowed": "400: The organization doesn't accept email addresses from the domain",
+                "CodeUserEmailTaken": "400: Another user in the organization has the email address",
+                "CodeUserModified": "409: The user changed while the patch was applied; apply it again",
+                "CodeUserNotFound": "404: The user doesn't exist in the organization",
+                "CodeUserStatusTransitionInvalid": "409: The user can't move from its current status to the requested one",
+                "CodeUserUnderage": "400: The user is younger than the organization's minimum age",
+                "CodeValidationFailed": "400: One or more fields failed validation; errors lists them",
+                "CodeVerificationRateLimited": "429: A verification email was sent recently; Retry-After says when another can be sent",
+                "CodeVerificationTokenInvalid": "400: The email verification token is invalid or has expired"
+            },
+            "x-enum-descriptions": [
+                "400: The request body is not valid JSON or doesn't match the expected shape",
+                "400: One or more fields failed validation; errors lists them",
+                "400: A path or query parameter is invalid; errors names it",
+                "415: The request body has a content type the endpoint doesn't accept",
+                "413: The request body exceeds the size limit",
+                "500: The server failed to process the request",
+                "401: The request carries neither a bearer token nor an API key",
+                "401: The bearer token is malformed, has a bad signature or is not yet valid",
+                "401: The bearer token has expired",
+                "401: The API key is unknown or revoked",
+                "401: The API key has expired",
+                "403: The route is reserved for people and can't be called with an API key",
+                "403: The caller lacks the permission named in the detail",
+                "400: No organization was selected and the server has no default",
+                "400: The X-Organization header names an organization that doesn't exist",
+                "403: The X-Organization header names an organization the caller can't act in",
+                "429: The caller sent too many requests; Retry-After says when to try again",
+                "400: The Idempotency-Key header is too long",
+                "422: The Idempotency-Key was already used for a different request",
+                "409: A request with the same Idempotency-Key is still running; Retry-After says when to try again",
+                "404: The user doesn't exist in the organization",
+                "400: Another user in the organization has the email address",
+                "400: The user is younger than the organization's minimum age",
+                "400: The organization doesn't accept email addresses from the domain",
+                "409: The user can't move from its current status to the requested one",
+                "400: The patch document is malformed or uses an unsupported operation",
+                "409: A test operation of the patch didn't match the current user",
+                "422: The patch applied, but the resulting user is invalid",
+                "409: The user changed while the patch was applied; apply it again",
+                "400: The email verification token is invalid or has expired",
+                "409: The user's email address is already verified",
+                "429: A verification email was sent recently; Retry-After says when another can be sent",
+                "404: The user has no avatar",
+                "400: The avatar file is missing or isn't a supported image",
+                "413: The avatar file exceeds the upload limit",
+                "409: Another upload replaced the avatar while this one was processed; upload it again",
+                "400: The birthday date range is empty or too long",
+                "401: The calendar feed token is missing, invalid or expired",
+                "400: The statistics parameters are inconsistent, e.g. from is after to",
+                "404: The note doesn't exist on the user",
+                "404: The tag doesn't exist in the organization",
+                "409: Another tag in the organization has the name",
+                "404: The group doesn't exist in the organization",
+                "409: Another group in the organization has the name",
+                "404: The organization doesn't exist",
+                "409: Another organization has the slug",
+                "400: The organization settings are inconsistent",
+                "404: The API key doesn't exist in the organization",
+                "404: The attribute definition doesn't exist in the organization",
+                "409: Another attribute definition in the organization has the key",
+                "400: The attribute definition is inconsistent, e.g. a select without options"
+            ],
+            "x-enum-varnames": [
+                "CodeMalformedRequest",
+                "CodeValidationFailed",
+                "CodeInvalidParameter",
+                "CodeUnsupportedMediaType",
+                "CodeRequestTooLarge",
+                "CodeInternalError",
+                "CodeAuthenticationRequired",
+                "CodeTokenInvalid",
+                "CodeTokenExpired",
+                "CodeAPIKeyInvalid",
+                "CodeAPIKeyExpired",
+                "CodeAPIKeyNotAllowed",
+                "CodePermissionDenied",
+                "CodeOrganizationRequired",
+                "CodeOrganizationUnknown",
+                "CodeOrganizationForbidden",
+                "CodeRateLimited",
+                "CodeIdempotencyKeyInvalid",
+                "CodeIdempotencyKeyReused",
+                "CodeIdempotencyKeyInProgress",
+                "CodeUserNotFound",
+                "CodeUserEmailTaken",
+                "CodeUserUnderage",
+                "CodeUserEmailDomainNotAllowed",
+                "CodeUserStatusTransitionInvalid",
+                "CodePatchInvalid",
+                "CodePatchTestFailed",
+                "CodePatchResultInvalid",
+                "CodeUserModified",
+                "CodeVerificationTokenInvalid",
+                "CodeEmailAlreadyVerified",
+                "CodeVerificationRateLimited",
+                "CodeAvatarNotFound",
+                "CodeAvatarInvalid",
+                "CodeAvatarTooLarge",
+                "CodeAvatarReplaced",
+                "CodeBirthdayRangeInvalid",
+                "CodeFeedTokenInvalid",
+                "CodeStatsParamsInvalid",
+                "CodeNoteNotFound",
+                "CodeTagNotFound",
+                "CodeTagNameTaken",
+                "CodeGroupNotFound",
+                "CodeGroupNameTaken",
+                "CodeOrganizationNotFound",
+                "CodeOrganizationSlugTaken",
+                "CodeOrganizationInvalid",
+                "CodeAPIKeyNotFound",
+                "CodeAttributeNotFound",
+                "CodeAttributeKeyTaken",
+                "CodeAttributeDefinitionInvalid"
+            ]
+        },
+        "arritech-user-management_pkg_problem.FieldError": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string",
+                    "example": "Must be a date in YYYY-MM-DD format"
+                },
+                "parameter": {
+                    "type": "string",
+                    "example": "sort_by"
+                },
+                "pointer": {
+                    "type": "string",
+                    "example": "/date_of_birth"
+                }
+            }
+        },
+        "arritech-user-management_pkg_problem.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is the stable, machine-readable error code",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/arritech-user-management_pkg_problem.Code"
+                        }
+                    ],
+                    "example": "VALIDATION_FAILED"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "The request has 1 invalid field"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/arritech-user-management_pkg_problem.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "3f2b8c1e-9a4d-4e57-b1a2-6c0d9e8f7a65"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Validation failed"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "https://arritech.com/problems/validation-failed"
                 }
             }
         },
@@ -366,6 +4156,20 @@ const docTemplate = `{
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "APIKeyAuth": {
+            "description": "API key for machine clients, limited to the routes its scopes allow",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "JWT bearer token, sent as \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
@@ -0,0 +1,58 @@
+package entity
+
+import "time"
+
+// API key scopes grant machine clients access to groups of user routes
+const (
+	ScopeUsersRead   = "users:read"
+	ScopeUsersWrite  = "users:write"
+	ScopeUsersDelete = "users:delete"
+	ScopeUsersExport = "users:export"
+	ScopeUsersPII    = "users:pii"
+)
+
+// APIKey is a credential for machine clients. Only a hash of the secret is stored;
+// the full key is shown once when it is created.
+type APIKey struct {
+	ID             uint   `json:"id" gorm:"primarykey"`
+	OrganizationID uint   `json:"-" gorm:"not null;index"`
+	Name           string `json:"name" gorm:"not null;size:100"`
+	// Prefix identifies the key in listings and logs and is used to look it up
+	Prefix     string     `json:"prefix" gorm:"not null;size:16;uniqueIndex"`
+	SecretHash string     `json:"-" gorm:"not null;size:64"`
+	Scopes     StringList `json:"scopes" gorm:"type:json"`
+	CreatedBy  string     `json:"created_by,omitempty" gorm:"size:255"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName returns the table name for the APIKey entity
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// HasScope reports whether the key grants scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAPIKeyRequest represents the request payload for creating an API key
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,min=2,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,oneof=users:read users:write users:delete users:export users:pii"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// CreatedAPIKey is the response for a new API key; Key is never returned again
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,123 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AttributeType represents the JSON type of a custom attribute value
+type AttributeType string
+
+const (
+	AttributeTypeString  AttributeType = "string"
+	AttributeTypeNumber  AttributeType = "number"
+	AttributeTypeInteger AttributeType = "integer"
+	AttributeTypeBoolean AttributeType = "boolean"
+)
+
+// Attributes holds the custom attribute values of a user, stored as a JSON column
+type Attributes map[string]interface{}
+
+// Value implements driver.Valuer
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner
+func (a *Attributes) Scan(value interface{}) error {
+	if value == nil {
+		*a = nil
+		return nil
+	}
+	b, err := bytesOf(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, a)
+}
+
+// StringList is a list of strings stored as a JSON column
+type StringList []string
+
+// Value implements driver.Valuer
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner
+func (l *StringList) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+	b, err := bytesOf(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, l)
+}
+
+func bytesOf(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		return nil, fmt.Errorf("unsupported JSON column type %T", value)
+	}
+}
+
+// AttributeDefinition describes a custom attribute that can be set on users
+type AttributeDefinition struct {
+	ID             uint          `json:"id" gorm:"primarykey"`
+	OrganizationID uint          `json:"organization_id" gorm:"not null;default:1;uniqueIndex:idx_attribute_definitions_organization_key,priority:1"`
+	Key            string        `json:"key" gorm:"uniqueIndex:idx_attribute_definitions_organization_key,priority:2;not null;size:64"`
+	Type           AttributeType `json:"type" gorm:"size:20;not null"`
+	Required       bool          `json:"required" gorm:"not null;default:false"`
+	Enum           StringList    `json:"enum,omitempty" gorm:"type:json"`
+	Pattern        string        `json:"pattern,omitempty" gorm:"size:255"`
+	Description    string        `json:"description,omitempty" gorm:"size:255"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+// TableName returns the table name for the AttributeDefinition entity
+func (AttributeDefinition) TableName() string {
+	return "attribute_definitions"
+}
+
+// CreateAttributeDefinitionRequest represents the request payload for creating an attribute definition
+type CreateAttributeDefinitionRequest struct {
+	Key         string   `json:"key" validate:"required,min=1,max=64"`
+	Type        string   `json:"type" validate:"required,oneof=string number integer boolean"`
+	Required    bool     `json:"required"`
+	Enum        []string `json:"enum,omitempty"`
+	Pattern     string   `json:"pattern,omitempty" validate:"max=255"`
+	Description string   `json:"description,omitempty" validate:"max=255"`
+}
+
+// UpdateAttributeDefinitionRequest represents the request payload for updating an attribute definition.
+// The key and type are immutable because existing user data depends on them.
+type UpdateAttributeDefinitionRequest struct {
+	Required    *bool     `json:"required,omitempty"`
+	Enum        *[]string `json:"enum,omitempty"`
+	Pattern     *string   `json:"pattern,omitempty" validate:"omitempty,max=255"`
+	Description *string   `json:"description,omitempty" validate:"omitempty,max=255"`
+}
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttributes_ValueAndScan(t *testing.T) {
+	attrs := Attributes{"department": "sales", "remote": true, "desk_count": float64(2)}
+
+	value, err := attrs.Value()
+	require.NoError(t, err)
+
+	var scanned Attributes
+	require.NoError(t, scanned.Scan([]byte(value.(string))))
+	assert.Equal(t, attrs, scanned)
+
+	var fromString Attributes
+	require.NoError(t, fromString.Scan(`{"department":"sales"}`))
+	assert.Equal(t, "sales", fromString["department"])
+}
+
+func TestAttributes_Nil(t *testing.T) {
+	var attrs Attributes
+
+	value, err := attrs.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	attrs = Attributes{"stale": "value"}
+	assert.NoError(t, attrs.Scan(nil))
+	assert.Nil(t, attrs)
+}
+
+func TestAttributes_ScanUnsupportedType(t *testing.T) {
+	var attrs Attributes
+	assert.Error(t, attrs.Scan(42))
+}
+
+func TestStringList_ValueAndScan(t *testing.T) {
+	list := StringList{"engineering", "sales"}
+
+	value, err := list.Value()
+	require.NoError(t, err)
+	assert.Equal(t, `["engineering","sales"]`, value)
+
+	var scanned StringList
+	require.NoError(t, scanned.Scan([]byte(`["engineering","sales"]`)))
+	assert.Equal(t, list, scanned)
+}
+
+func TestAttributeDefinition_TableName(t *testing.T) {
+	assert.Equal(t, "attribute_definitions", AttributeDefinition{}.TableName())
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// Birthday is an upcoming birthday of a user
+type Birthday struct {
+	UserID      uint      `json:"user_id"`
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	DateOfBirth time.Time `json:"date_of_birth"`
+	// Date is the day the birthday is celebrated in the requested range (YYYY-MM-DD)
+	Date       string `json:"date"`
+	TurningAge int    `json:"turning_age"`
+}
+
+// BirthdayFeed is a subscription link to an organization's birthday calendar
+type BirthdayFeed struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+// Group represents a cohort of users
+type Group struct {
+	ID             uint      `json:"id" gorm:"primarykey"`
+	OrganizationID uint      `json:"organization_id" gorm:"not null;default:1;uniqueIndex:idx_groups_organization_name,priority:1"`
+	Name           string    `json:"name" gorm:"uniqueIndex:idx_groups_organization_name,priority:2;not null;size:100"`
+	Description    string    `json:"description,omitempty" gorm:"type:text"`
+	MemberCount    int64     `json:"member_count" gorm:"->;-:migration"` // Computed by queries, not stored
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for the Group entity
+func (Group) TableName() string {
+	return "groups"
+}
+
+// CreateGroupRequest represents the request payload for creating a group
+type CreateGroupRequest struct {
+	Name        string `json:"name" validate:"required,min=1,max=100"`
+	Description string `json:"description,omitempty" validate:"max=1000"`
+}
+
+// UpdateGroupRequest represents the request payload for updating a group
+type UpdateGroupRequest struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
+}
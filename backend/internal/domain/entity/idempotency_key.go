@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// IdempotencyKey holds the response to a mutating request made with an Idempotency-Key header
+type IdempotencyKey struct {
+	ID uint `gorm:"primarykey"`
+	// Key is a hash of the caller and the key they sent
+	Key string `gorm:"not null;size:64;uniqueIndex"`
+	// Fingerprint is a hash of the request the key was first used for
+	Fingerprint string `gorm:"not null;size:64"`
+	// StatusCode is zero while the request is in progress
+	StatusCode  int    `gorm:"not null;default:0"`
+	ContentType string `gorm:"size:255"`
+	Body        []byte `gorm:"type:mediumblob"`
+	CreatedAt   time.Time
+	ExpiresAt   time.Time `gorm:"not null;index"`
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// DefaultOrganizationID is the organization that existing single-tenant data belongs to
+const DefaultOrganizationID uint = 1
+
+// Organization represents a tenant that owns a separate set of users
+type Organization struct {
+	ID   uint   `json:"id" gorm:"primarykey"`
+	Slug string `json:"slug" gorm:"uniqueIndex;not null;size:64"`
+	Name string `json:"name" gorm:"not null;size:255"`
+	// MinimumAge overrides the default age requirement for users when greater than zero
+	MinimumAge int `json:"minimum_age" gorm:"not null;default:0"`
+	// AllowedEmailDomains restricts user emails to these domains when not empty
+	AllowedEmailDomains StringList `json:"allowed_email_domains,omitempty" gorm:"type:json"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// TableName returns the table name for the Organization entity
+func (Organization) TableName() string {
+	return "organizations"
+}
+
+// AllowsEmail reports whether the domain of email is allowed in the organization
+func (o *Organization) AllowsEmail(email string) bool {
+	if len(o.AllowedEmailDomains) == 0 {
+		return true
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, allowed := range o.AllowedEmailDomains {
+		if domain == strings.ToLower(allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateOrganizationRequest represents the request payload for creating an organization
+type CreateOrganizationRequest struct {
+	Slug                string   `json:"slug" validate:"required,min=2,max=64"`
+	Name                string   `json:"name" validate:"required,min=2,max=255"`
+	MinimumAge          int      `json:"minimum_age,omitempty" validate:"min=0,max=150"`
+	AllowedEmailDomains []string `json:"allowed_email_domains,omitempty" validate:"omitempty,dive,fqdn"`
+}
+
+// UpdateOrganizationRequest represents the request payload for updating an organization.
+// The slug is immutable because clients use it to select the organization.
+type UpdateOrganizationRequest struct {
+	Name                *string   `json:"name,omitempty" validate:"omitempty,min=2,max=255"`
+	MinimumAge          *int      `json:"minimum_age,omitempty" validate:"omitempty,min=0,max=150"`
+	AllowedEmailDomains *[]string `json:"allowed_email_domains,omitempty" validate:"omitempty,dive,fqdn"`
+}
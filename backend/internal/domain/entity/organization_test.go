@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganization_AllowsEmail(t *testing.T) {
+	tests := []struct {
+		name     string
+		domains  StringList
+		email    string
+		expected bool
+	}{
+		{name: "No restriction", domains: nil, email: "john@anything.com", expected: true},
+		{name: "Allowed domain", domains: StringList{"acme.com"}, email: "john@acme.com", expected: true},
+		{name: "Domain is case insensitive", domains: StringList{"Acme.com"}, email: "john@ACME.COM", expected: true},
+		{name: "Other domain", domains: StringList{"acme.com"}, email: "john@example.com", expected: false},
+		{name: "Subdomain is not the domain", domains: StringList{"acme.com"}, email: "john@mail.acme.com", expected: false},
+		{name: "Missing domain", domains: StringList{"acme.com"}, email: "john", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			org := &Organization{AllowedEmailDomains: tt.domains}
+			assert.Equal(t, tt.expected, org.AllowsEmail(tt.email))
+		})
+	}
+}
+
+func TestOrganization_TableName(t *testing.T) {
+	assert.Equal(t, "organizations", Organization{}.TableName())
+}
@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+// Tag represents a label that can be attached to users
+type Tag struct {
+	ID             uint      `json:"id" gorm:"primarykey"`
+	OrganizationID uint      `json:"organization_id" gorm:"not null;default:1;uniqueIndex:idx_tags_organization_name,priority:1"`
+	Name           string    `json:"name" gorm:"uniqueIndex:idx_tags_organization_name,priority:2;not null;size:50"`
+	Color          string    `json:"color,omitempty" gorm:"size:7"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for the Tag entity
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// CreateTagRequest represents the request payload for creating a tag
+type CreateTagRequest struct {
+	Name  string `json:"name" validate:"required,min=1,max=50"`
+	Color string `json:"color,omitempty" validate:"omitempty,hexcolor"`
+}
+
+// UpdateTagRequest represents the request payload for updating a tag
+type UpdateTagRequest struct {
+	Name  *string `json:"name,omitempty" validate:"omitempty,min=1,max=50"`
+	Color *string `json:"color,omitempty" validate:"omitempty,hexcolor"`
+}
+
+// MembershipRequest represents the request payload for adding or removing users in bulk
+type MembershipRequest struct {
+	UserIDs []uint `json:"user_ids" validate:"required,min=1,max=1000,dive,min=1"`
+}
+
+// MembershipResponse reports how many memberships a bulk operation changed
+type MembershipResponse struct {
+	Affected int64 `json:"affected"`
+}
@@ -1,22 +1,46 @@
 package entity
 
 import (
+	"encoding/json"
 	"gorm.io/gorm"
+	"strings"
 	"time"
 )
 
+// UserStatus represents the lifecycle status of a user
+type UserStatus string
+
+const (
+	UserStatusPending   UserStatus = "pending"
+	UserStatusActive    UserStatus = "active"
+	UserStatusSuspended UserStatus = "suspended"
+	UserStatusArchived  UserStatus = "archived"
+)
+
 // User represents a user in the system
 type User struct {
-	ID          uint           `json:"id" gorm:"primarykey"`
-	Name        string         `json:"name" gorm:"not null" validate:"required,min=2,max=100"`
-	Email       string         `json:"email" gorm:"uniqueIndex;not null;size:255" validate:"required,email"`
-	DateOfBirth time.Time      `json:"date_of_birth" gorm:"not null" validate:"required"`
-	Age         int            `json:"age" gorm:"-"` // Computed field, not stored
-	Phone       string         `json:"phone,omitempty" gorm:"size:20" validate:"omitempty,min=10,max=20"`
-	Address     string         `json:"address,omitempty" gorm:"type:text"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                 uint           `json:"id" gorm:"primarykey"`
+	OrganizationID     uint           `json:"organization_id" gorm:"not null;default:1;uniqueIndex:idx_users_organization_email,priority:1"`
+	Name               string         `json:"name" gorm:"not null" validate:"required,min=2,max=100"`
+	Email              string         `json:"email" gorm:"uniqueIndex:idx_users_organization_email,priority:2;not null;size:255" validate:"required,email"`
+	EmailVerifiedAt    *time.Time     `json:"email_verified_at,omitempty"`
+	VerificationSentAt *time.Time     `json:"-"`
+	DateOfBirth        time.Time      `json:"date_of_birth" gorm:"not null" validate:"required"`
+	Age                int            `json:"age" gorm:"-"` // Computed field, not stored
+	Phone              string         `json:"phone,omitempty" gorm:"size:20" validate:"omitempty,min=10,max=20"`
+	Address            string         `json:"address,omitempty" gorm:"type:text"`
+	AvatarKey          string         `json:"-" gorm:"size:255"`
+	AvatarURL          string         `json:"avatar_url,omitempty" gorm:"-"` // Computed field, not stored
+	Attributes         Attributes     `json:"attributes,omitempty" gorm:"type:json"`
+	Tags               []Tag          `json:"tags,omitempty" gorm:"many2many:user_tags;constraint:OnDelete:CASCADE"`
+	Groups             []Group        `json:"-" gorm:"many2many:user_groups;constraint:OnDelete:CASCADE"`
+	Status             UserStatus     `json:"status" gorm:"size:20;not null;default:active;index"`
+	StatusReason       string         `json:"status_reason,omitempty" gorm:"size:500"`
+	StatusChangedAt    *time.Time     `json:"status_changed_at,omitempty"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `json:"-" gorm:"index"`
+	PIIRedacted        bool           `json:"pii_redacted,omitempty" gorm:"-"` // Set when personal data is masked for the caller
 }
 
 // TableName returns the table name for the User entity
@@ -26,11 +50,12 @@ func (User) TableName() string {
 
 // CreateUserRequest represents the request payload for creating a user
 type CreateUserRequest struct {
-	Name        string `json:"name" validate:"required,min=2,max=100"`
-	Email       string `json:"email" validate:"required,email"`
-	DateOfBirth string `json:"date_of_birth" validate:"required"`
-	Phone       string `json:"phone,omitempty" validate:"omitempty,min=10,max=20"`
-	Address     string `json:"address,omitempty"`
+	Name        string                 `json:"name" validate:"required,min=2,max=100"`
+	Email       string                 `json:"email" validate:"required,email"`
+	DateOfBirth string                 `json:"date_of_birth" validate:"required"`
+	Phone       string                 `json:"phone,omitempty" validate:"omitempty,min=10,max=20"`
+	Address     string                 `json:"address,omitempty"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
 }
 
 // UpdateUserRequest represents the request payload for updating a user
@@ -40,6 +65,18 @@ type UpdateUserRequest struct {
 	DateOfBirth *string `json:"date_of_birth,omitempty"`
 	Phone       *string `json:"phone,omitempty" validate:"omitempty,min=10,max=20"`
 	Address     *string `json:"address,omitempty"`
+	// Attributes replaces the full set of custom attributes when present
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// ChangeUserStatusRequest represents the request payload for a status transition
+type ChangeUserStatusRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=500"`
+}
+
+// VerifyEmailRequest represents the request payload for confirming an email address
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
 }
 
 // UserListResponse represents the response for listing users with pagination
@@ -58,4 +95,116 @@ type UserSearchParams struct {
 	PerPage int    `json:"per_page" form:"per_page" query:"per_page" validate:"min=1,max=100"`
 	SortBy  string `json:"sortBy" form:"sortBy" query:"sortBy"`
 	SortDir string `json:"sortDir" form:"sortDir" query:"sortDir" validate:"oneof=asc desc"`
+	Status  string `json:"status" form:"status" query:"status" validate:"omitempty,oneof=pending active suspended archived all"`
+	// Tags filters on comma-separated tag names; TagMatch selects any (OR) or all (AND)
+	Tags     string `json:"tags" form:"tags" query:"tags"`
+	TagMatch string `json:"tag_match" form:"tag_match" query:"tag_match" validate:"omitempty,oneof=any all"`
+	// Group filters on comma-separated group names; GroupMatch selects any (OR) or all (AND)
+	Group      string `json:"group" form:"group" query:"group"`
+	GroupMatch string `json:"group_match" form:"group_match" query:"group_match" validate:"omitempty,oneof=any all"`
+	// Attributes filters on custom attribute values, bound from attr[key]=value
+	Attributes map[string]string `json:"attributes" form:"-"`
+	// SearchNameOnly restricts Search to names, for callers who may not search personal data
+	SearchNameOnly bool `json:"-" form:"-"`
+	// Projection limits the fields loaded for each user, bound from ?fields= and ?expand=
+	Projection UserProjection `json:"-" form:"-"`
+}
+
+// CalculateAge returns the user's age in full years based on DateOfBirth
+func (u *User) CalculateAge() int {
+	return u.AgeAt(time.Now())
+}
+
+// AgeAt returns the user's age in full years on the day of now. Someone born on
+// February 29 turns a year older on February 28 in common years, like BirthdayIn.
+func (u *User) AgeAt(now time.Time) int {
+	if u.DateOfBirth.IsZero() {
+		return 0
+	}
+
+	age := now.Year() - u.DateOfBirth.Year()
+
+	// Adjust if birthday hasn't occurred this year
+	birthday := BirthdayIn(u.DateOfBirth, now.Year())
+	if now.Month() < birthday.Month() ||
+		(now.Month() == birthday.Month() && now.Day() < birthday.Day()) {
+		age--
+	}
+
+	return age
+}
+
+// BirthdayIn returns the date, at midnight UTC, of the birthday in year of someone born on dob.
+// February 29 birthdays fall on February 28 in common years.
+func BirthdayIn(dob time.Time, year int) time.Time {
+	day := dob.Day()
+	if dob.Month() == time.February && day == 29 && !isLeapYear(year) {
+		day = 28
+	}
+	return time.Date(year, dob.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// RedactedDateLayout formats a masked date of birth, keeping only the year
+const RedactedDateLayout = "2006-**-**"
+
+// redactedMask replaces values that are hidden entirely
+const redactedMask = "***"
+
+// RedactPII masks personal data: the local part of the email, all but the last two digits of the
+// phone number, the address and the day and month of birth. Age must be computed before, as the
+// date of birth keeps only its year.
+func (u *User) RedactPII() {
+	if u.PIIRedacted {
+		return
+	}
+	u.Email = maskEmail(u.Email)
+	u.Phone = maskPhone(u.Phone)
+	if u.Address != "" {
+		u.Address = redactedMask
+	}
+	if !u.DateOfBirth.IsZero() {
+		u.DateOfBirth = time.Date(u.DateOfBirth.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	u.PIIRedacted = true
+}
+
+// MarshalJSON renders a redacted date of birth as its year only, e.g. "1990-**-**"
+func (u User) MarshalJSON() ([]byte, error) {
+	type plain User
+	if !u.PIIRedacted {
+		return json.Marshal(plain(u))
+	}
+	return json.Marshal(struct {
+		plain
+		DateOfBirth string `json:"date_of_birth"`
+	}{plain(u), u.DateOfBirth.Format(RedactedDateLayout)})
+}
+
+func maskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return redactedMask
+	}
+	return redactedMask + email[at:]
+}
+
+// maskPhone hides every digit but the last two, keeping the number's formatting
+func maskPhone(phone string) string {
+	masked := []byte(phone)
+	keep := 2
+	for i := len(masked) - 1; i >= 0; i-- {
+		if masked[i] < '0' || masked[i] > '9' {
+			continue
+		}
+		if keep > 0 {
+			keep--
+			continue
+		}
+		masked[i] = '*'
+	}
+	return string(masked)
 }
@@ -0,0 +1,115 @@
+package entity
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UserEventType identifies a lifecycle event in a user's history
+type UserEventType string
+
+const (
+	UserEventCreated       UserEventType = "created"
+	UserEventUpdated       UserEventType = "updated"
+	UserEventStatusChanged UserEventType = "status_changed"
+	UserEventDeleted       UserEventType = "deleted"
+)
+
+// UserEvent records a lifecycle event of a user for the activity timeline
+type UserEvent struct {
+	ID             uint          `json:"id" gorm:"primarykey"`
+	OrganizationID uint          `json:"-" gorm:"not null;index"`
+	UserID         uint          `json:"user_id" gorm:"not null;index:idx_user_events_user_created,priority:1"`
+	Type           UserEventType `json:"type" gorm:"size:30;not null"`
+	Description    string        `json:"description" gorm:"type:text"`
+	// Actor is the subject of the token that made the change, empty when unauthenticated
+	Actor     string    `json:"actor,omitempty" gorm:"size:255"`
+	CreatedAt time.Time `json:"created_at" gorm:"index:idx_user_events_user_created,priority:2"`
+}
+
+// TableName returns the table name for the UserEvent entity
+func (UserEvent) TableName() string {
+	return "user_events"
+}
+
+// Timeline entry kinds
+const (
+	TimelineEntryNote  = "note"
+	TimelineEntryEvent = "event"
+)
+
+// TimelineEntry is a note or lifecycle event in a user's timeline
+type TimelineEntry struct {
+	Kind  string     `json:"kind"`
+	Time  time.Time  `json:"time"`
+	Note  *UserNote  `json:"note,omitempty"`
+	Event *UserEvent `json:"event,omitempty"`
+}
+
+// Cursor returns the position of the entry in the timeline
+func (e TimelineEntry) Cursor() TimelineCursor {
+	cursor := TimelineCursor{Time: e.Time, Kind: e.Kind}
+	if e.Note != nil {
+		cursor.ID = e.Note.ID
+	}
+	if e.Event != nil {
+		cursor.ID = e.Event.ID
+	}
+	return cursor
+}
+
+// TimelineCursor is the position of an entry in a user's timeline. Entries are ordered by time,
+// events before notes of the same time, and then by ID, all newest first, so entries sharing a
+// timestamp are neither skipped nor repeated across pages.
+type TimelineCursor struct {
+	Time time.Time
+	Kind string
+	ID   uint
+}
+
+// String encodes the cursor as an opaque token
+func (c TimelineCursor) String() string {
+	raw := fmt.Sprintf("%d:%s:%d", c.Time.UnixNano(), c.Kind, c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseTimelineCursor decodes a token made by TimelineCursor.String
+func ParseTimelineCursor(token string) (TimelineCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return TimelineCursor{}, fmt.Errorf("invalid cursor")
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 || (parts[1] != TimelineEntryNote && parts[1] != TimelineEntryEvent) {
+		return TimelineCursor{}, fmt.Errorf("invalid cursor")
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return TimelineCursor{}, fmt.Errorf("invalid cursor")
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return TimelineCursor{}, fmt.Errorf("invalid cursor")
+	}
+	return TimelineCursor{Time: time.Unix(0, nanos).UTC(), Kind: parts[1], ID: uint(id)}, nil
+}
+
+// TimelineParams selects a page of a user's timeline, newest first
+type TimelineParams struct {
+	// Before returns only entries older than this time; zero starts at the newest entry
+	Before time.Time
+	// After returns only entries after this cursor, the NextCursor of the previous page. It takes
+	// precedence over Before.
+	After *TimelineCursor
+	Limit int
+}
+
+// TimelineResponse is a page of a user's timeline
+type TimelineResponse struct {
+	Entries []TimelineEntry `json:"entries"`
+	// NextCursor is the cursor for the next page, absent on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+// UserNote is an internal note kept by support agents about a user
+type UserNote struct {
+	ID             uint   `json:"id" gorm:"primarykey"`
+	OrganizationID uint   `json:"-" gorm:"not null;index"`
+	UserID         uint   `json:"user_id" gorm:"not null;index:idx_user_notes_user_created,priority:1"`
+	Author         string `json:"author" gorm:"not null;size:100"`
+	// Body is markdown and is returned as written; clients are responsible for rendering it safely
+	Body      string    `json:"body" gorm:"type:text;not null"`
+	Pinned    bool      `json:"pinned" gorm:"not null;default:false"`
+	CreatedAt time.Time `json:"created_at" gorm:"index:idx_user_notes_user_created,priority:2"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for the UserNote entity
+func (UserNote) TableName() string {
+	return "user_notes"
+}
+
+// CreateUserNoteRequest represents the request payload for creating a note
+type CreateUserNoteRequest struct {
+	Author string `json:"author" validate:"required,min=1,max=100"`
+	Body   string `json:"body" validate:"required,min=1,max=10000"`
+	Pinned bool   `json:"pinned,omitempty"`
+}
+
+// UpdateUserNoteRequest represents the request payload for updating a note
+type UpdateUserNoteRequest struct {
+	Body   *string `json:"body,omitempty" validate:"omitempty,min=1,max=10000"`
+	Pinned *bool   `json:"pinned,omitempty"`
+}
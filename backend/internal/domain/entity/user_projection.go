@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// UserFields are the fields of a user that ?fields= can select, in the order of the JSON response
+var UserFields = []string{
+	"id", "organization_id", "name", "email", "email_verified_at", "date_of_birth", "age", "phone",
+	"address", "avatar_url", "attributes", "tags", "status", "status_reason", "status_changed_at",
+	"created_at", "updated_at",
+}
+
+// UserExpansions are the related resources that ?expand= can embed in a user
+var UserExpansions = []string{"groups"}
+
+// UserProjection selects the fields of users to load and return, and the related resources to
+// embed in them. No fields selects all of them; the ID is always included.
+type UserProjection struct {
+	Fields []string
+	Expand []string
+}
+
+// UnknownFieldError reports a name in ?fields= or ?expand= that isn't one of the valid ones
+type UnknownFieldError struct {
+	// Parameter is fields or expand
+	Parameter string
+	Name      string
+	Valid     []string
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("%q is not a valid value of %s; use any of %s", e.Name, e.Parameter, strings.Join(e.Valid, ", "))
+}
+
+// ParseUserProjection parses the comma-separated values of ?fields= and ?expand=, dropping
+// blanks and duplicates
+func ParseUserProjection(fields, expand string) (UserProjection, error) {
+	var projection UserProjection
+	var err error
+	if projection.Fields, err = parseNames("fields", fields, UserFields); err != nil {
+		return UserProjection{}, err
+	}
+	if projection.Expand, err = parseNames("expand", expand, UserExpansions); err != nil {
+		return UserProjection{}, err
+	}
+	return projection, nil
+}
+
+func parseNames(parameter, value string, valid []string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || slices.Contains(names, name) {
+			continue
+		}
+		if !slices.Contains(valid, name) {
+			return nil, &UnknownFieldError{Parameter: parameter, Name: name, Valid: valid}
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// IsZero reports whether the projection loads every field and embeds nothing
+func (p UserProjection) IsZero() bool {
+	return len(p.Fields) == 0 && len(p.Expand) == 0
+}
+
+// Selects reports whether field is returned
+func (p UserProjection) Selects(field string) bool {
+	return len(p.Fields) == 0 || field == "id" || slices.Contains(p.Fields, field)
+}
+
+// Expands reports whether the related resource is embedded
+func (p UserProjection) Expands(resource string) bool {
+	return slices.Contains(p.Expand, resource)
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUserProjection(t *testing.T) {
+	t.Run("Parses fields and expansions", func(t *testing.T) {
+		projection, err := ParseUserProjection(" id,name, email,,age,name ", "groups")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "name", "email", "age"}, projection.Fields)
+		assert.Equal(t, []string{"groups"}, projection.Expand)
+	})
+
+	t.Run("Empty parameters select everything", func(t *testing.T) {
+		projection, err := ParseUserProjection("", "")
+
+		require.NoError(t, err)
+		assert.True(t, projection.IsZero())
+		assert.True(t, projection.Selects("address"))
+		assert.False(t, projection.Expands("groups"))
+	})
+
+	t.Run("Rejects unknown fields", func(t *testing.T) {
+		_, err := ParseUserProjection("id,password", "")
+
+		var fieldErr *UnknownFieldError
+		require.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "fields", fieldErr.Parameter)
+		assert.Equal(t, "password", fieldErr.Name)
+		assert.Contains(t, err.Error(), `"password" is not a valid value of fields; use any of id, organization_id, name`)
+	})
+
+	t.Run("Rejects unknown expansions", func(t *testing.T) {
+		_, err := ParseUserProjection("", "notes")
+
+		assert.EqualError(t, err, `"notes" is not a valid value of expand; use any of groups`)
+	})
+}
+
+func TestUserProjection_Selects(t *testing.T) {
+	projection := UserProjection{Fields: []string{"name", "age"}}
+
+	assert.True(t, projection.Selects("name"))
+	assert.True(t, projection.Selects("age"))
+	assert.True(t, projection.Selects("id"), "the ID is always selected")
+	assert.False(t, projection.Selects("address"))
+	assert.False(t, projection.IsZero())
+}
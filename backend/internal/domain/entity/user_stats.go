@@ -0,0 +1,70 @@
+package entity
+
+import "time"
+
+// Signup intervals for UserStatsParams
+const (
+	StatsIntervalDay   = "day"
+	StatsIntervalWeek  = "week"
+	StatsIntervalMonth = "month"
+)
+
+// UserStatsParams represents the query parameters of the statistics endpoint
+type UserStatsParams struct {
+	// AgeBuckets is a comma-separated list of increasing ages where histogram buckets start, e.g. 18,25,35
+	AgeBuckets string `json:"age_buckets" form:"age_buckets"`
+	Interval   string `json:"interval" form:"interval" validate:"omitempty,oneof=day week month"`
+	From       string `json:"from" form:"from" validate:"omitempty,datetime=2006-01-02"`
+	To         string `json:"to" form:"to" validate:"omitempty,datetime=2006-01-02"`
+	TopDomains int    `json:"top_domains" form:"top_domains" validate:"omitempty,min=1,max=100"`
+}
+
+// UserStatsQuery is the normalized form of UserStatsParams used to compute statistics
+type UserStatsQuery struct {
+	AgeBuckets []int
+	Interval   string
+	// From and To bound the signup series, both inclusive
+	From       time.Time
+	To         time.Time
+	TopDomains int
+}
+
+// UserStats summarizes the users of an organization
+type UserStats struct {
+	// Total counts all users, including soft-deleted ones
+	Total int64 `json:"total"`
+	// Active counts users that are not soft-deleted, whatever their lifecycle status
+	Active          int64            `json:"active"`
+	Deleted         int64            `json:"deleted"`
+	ByStatus        map[string]int64 `json:"by_status"`
+	AgeHistogram    []AgeBucket      `json:"age_histogram"`
+	Signups         []SignupBucket   `json:"signups"`
+	TopEmailDomains []DomainCount    `json:"top_email_domains"`
+	PhoneCountries  []PhoneCount     `json:"phone_countries"`
+}
+
+// AgeBucket counts users whose age is in [Min, Max]; a nil bound is open
+type AgeBucket struct {
+	Label string `json:"label"`
+	Min   *int   `json:"min,omitempty"`
+	Max   *int   `json:"max,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// SignupBucket counts users created in a period: 2024-05-01 (day), 2024-W18 (ISO week) or 2024-05 (month)
+type SignupBucket struct {
+	Period string `json:"period"`
+	Count  int64  `json:"count"`
+}
+
+// DomainCount counts users with an email address at Domain
+type DomainCount struct {
+	Domain string `json:"domain"`
+	Count  int64  `json:"count"`
+}
+
+// PhoneCount counts users whose phone number has the calling code Prefix, or "unknown"
+type PhoneCount struct {
+	Prefix string `json:"prefix"`
+	Count  int64  `json:"count"`
+}
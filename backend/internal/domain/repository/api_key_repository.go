@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"arritech-user-management/internal/domain/entity"
+	"context"
+	"time"
+)
+
+// APIKeyRepository defines the interface for API key data operations
+type APIKeyRepository interface {
+	// Create creates a new API key
+	Create(ctx context.Context, key *entity.APIKey) error
+
+	// GetByID retrieves an API key of the organization by ID
+	GetByID(ctx context.Context, id uint) (*entity.APIKey, error)
+
+	// GetByPrefix retrieves an API key by prefix across all organizations, for authentication
+	GetByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error)
+
+	// List retrieves all API keys of the organization, newest first
+	List(ctx context.Context) ([]entity.APIKey, error)
+
+	// Revoke marks an API key of the organization as revoked
+	Revoke(ctx context.Context, id uint, at time.Time) error
+
+	// TouchLastUsed records when an API key was last used
+	TouchLastUsed(ctx context.Context, id uint, at time.Time) error
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"arritech-user-management/internal/domain/entity"
+	"context"
+)
+
+// AttributeDefinitionRepository defines the interface for custom attribute definition operations
+type AttributeDefinitionRepository interface {
+	// Create creates a new attribute definition
+	Create(ctx context.Context, def *entity.AttributeDefinition) error
+
+	// GetByKey retrieves an attribute definition by key
+	GetByKey(ctx context.Context, key string) (*entity.AttributeDefinition, error)
+
+	// List retrieves all attribute definitions ordered by key
+	List(ctx context.Context) ([]entity.AttributeDefinition, error)
+
+	// Update updates an attribute definition
+	Update(ctx context.Context, def *entity.AttributeDefinition) error
+
+	// Delete deletes an attribute definition and removes its values from all users
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"arritech-user-management/internal/domain/entity"
+	"context"
+	"time"
+)
+
+// IdempotencyKeyRepository defines the interface for idempotency key data operations.
+// Keys already identify the caller, so they are not scoped to an organization.
+type IdempotencyKeyRepository interface {
+	// Create stores a new key; it fails with "idempotency key already exists" if the key is taken
+	Create(ctx context.Context, key *entity.IdempotencyKey) error
+
+	// GetByKey retrieves a key
+	GetByKey(ctx context.Context, key string) (*entity.IdempotencyKey, error)
+
+	// Complete stores the response to the request holding a key
+	Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+
+	// Delete removes a key
+	Delete(ctx context.Context, key string) error
+
+	// DeleteExpired removes keys that expired before the given time and returns how many were removed
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
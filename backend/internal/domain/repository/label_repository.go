@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"arritech-user-management/internal/domain/entity"
+	"context"
+)
+
+// LabelRepository defines the data operations shared by labels that collect users, such as
+// tags and groups
+type LabelRepository[T any] interface {
+	// Create creates a new label
+	Create(ctx context.Context, label *T) error
+
+	// GetByID retrieves a label by ID
+	GetByID(ctx context.Context, id uint) (*T, error)
+
+	// List retrieves all labels ordered by name
+	List(ctx context.Context) ([]T, error)
+
+	// Update updates a label
+	Update(ctx context.Context, label *T) error
+
+	// Delete deletes a label and detaches it from all users
+	Delete(ctx context.Context, id uint) error
+
+	// AddUsers attaches the label to the given users, ignoring existing and unknown users
+	AddUsers(ctx context.Context, id uint, userIDs []uint) (int64, error)
+
+	// RemoveUsers detaches the label from the given users
+	RemoveUsers(ctx context.Context, id uint, userIDs []uint) (int64, error)
+}
+
+// TagRepository defines the interface for tag data operations
+type TagRepository = LabelRepository[entity.Tag]
+
+// GroupRepository defines the interface for group data operations; groups are loaded with
+// their member counts
+type GroupRepository = LabelRepository[entity.Group]
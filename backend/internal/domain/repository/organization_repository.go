@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"arritech-user-management/internal/domain/entity"
+	"context"
+	"errors"
+)
+
+// ErrOrganizationNotFound is returned when no organization has the requested ID or slug
+var ErrOrganizationNotFound = errors.New("organization not found")
+
+// OrganizationRepository defines the interface for organization data operations
+type OrganizationRepository interface {
+	// Create creates a new organization
+	Create(ctx context.Context, org *entity.Organization) error
+
+	// GetByID retrieves an organization by ID
+	GetByID(ctx context.Context, id uint) (*entity.Organization, error)
+
+	// GetBySlug retrieves an organization by its slug
+	GetBySlug(ctx context.Context, slug string) (*entity.Organization, error)
+
+	// List retrieves all organizations ordered by name
+	List(ctx context.Context) ([]entity.Organization, error)
+
+	// Update updates an organization
+	Update(ctx context.Context, org *entity.Organization) error
+}
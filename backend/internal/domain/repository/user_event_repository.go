@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"arritech-user-management/internal/domain/entity"
+	"context"
+	"time"
+)
+
+// UserEventRepository defines the interface for user lifecycle event operations
+type UserEventRepository interface {
+	// Create records a new event
+	Create(ctx context.Context, event *entity.UserEvent) error
+
+	// ListBefore retrieves up to limit events of a user that come before (before, beforeID) in
+	// (created_at, id) order, newest first. A beforeID of zero returns only events created before the given
+	// time, and a zero time returns the newest events.
+	ListBefore(ctx context.Context, userID uint, before time.Time, beforeID uint, limit int) ([]entity.UserEvent, error)
+}
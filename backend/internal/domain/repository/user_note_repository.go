@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"arritech-user-management/internal/domain/entity"
+	"context"
+	"time"
+)
+
+// UserNoteRepository defines the interface for user note data operations
+type UserNoteRepository interface {
+	// Create creates a new note
+	Create(ctx context.Context, note *entity.UserNote) error
+
+	// GetByID retrieves a note of a user by ID
+	GetByID(ctx context.Context, userID, id uint) (*entity.UserNote, error)
+
+	// ListByUser retrieves all notes of a user, pinned notes first and then newest first
+	ListByUser(ctx context.Context, userID uint) ([]entity.UserNote, error)
+
+	// ListBefore retrieves up to limit notes of a user that come before (before, beforeID) in
+	// (created_at, id) order, newest first. A beforeID of zero returns only notes created before the given
+	// time, and a zero time returns the newest notes.
+	ListBefore(ctx context.Context, userID uint, before time.Time, beforeID uint, limit int) ([]entity.UserNote, error)
+
+	// Update updates a note
+	Update(ctx context.Context, note *entity.UserNote) error
+
+	// Delete deletes a note of a user
+	Delete(ctx context.Context, userID, id uint) error
+}
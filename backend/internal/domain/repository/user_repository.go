@@ -3,9 +3,11 @@ package repository
 import (
 	"arritech-user-management/internal/domain/entity"
 	"context"
+	"time"
 )
 
-// UserRepository defines the interface for user data operations
+// UserRepository defines the interface for user data operations.
+// Every operation is scoped to the organization carried by ctx (see pkg/tenant).
 type UserRepository interface {
 	// Create creates a new user
 	Create(ctx context.Context, user *entity.User) error
@@ -13,18 +15,33 @@ type UserRepository interface {
 	// GetByID retrieves a user by ID
 	GetByID(ctx context.Context, id uint) (*entity.User, error)
 
+	// GetByIDProjected retrieves a user by ID with only the fields and related resources of projection
+	GetByIDProjected(ctx context.Context, id uint, projection entity.UserProjection) (*entity.User, error)
+
 	// GetByEmail retrieves a user by email
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 
 	// Update updates a user
 	Update(ctx context.Context, user *entity.User) error
 
-	// Delete soft deletes a user
+	// UpdateUnmodifiedSince updates a user only if it is stored with updatedAt, the UpdatedAt it was
+	// loaded with, and reports whether it did; false means it was changed in the meantime
+	UpdateUnmodifiedSince(ctx context.Context, user *entity.User, updatedAt time.Time) (bool, error)
+
+	// UpdateAvatarKey sets a user's avatar key, leaving its other columns alone, only if it is still
+	// oldKey, and reports whether it did; false means another upload replaced it in the meantime
+	UpdateAvatarKey(ctx context.Context, id uint, oldKey, key string) (bool, error)
+
+	// Delete soft deletes a user and clears its avatar key
 	Delete(ctx context.Context, id uint) error
 
 	// List retrieves users with pagination and search
 	List(ctx context.Context, params entity.UserSearchParams) (*entity.UserListResponse, error)
 
-	// EmailExists checks if an email already exists (for validation)
+	// ListBirthdays retrieves non-archived users whose birthday falls between from and to (inclusive),
+	// ordered by the next occurrence. The range may wrap into the next year but must be shorter than a year.
+	ListBirthdays(ctx context.Context, from, to time.Time) ([]entity.User, error)
+
+	// EmailExists checks if an email already exists in the organization (for validation)
 	EmailExists(ctx context.Context, email string, excludeID uint) (bool, error)
 }
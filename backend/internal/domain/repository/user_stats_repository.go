@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"arritech-user-management/internal/domain/entity"
+	"context"
+)
+
+// UserStatsRepository defines the interface for aggregate queries over users.
+// Every query is scoped to the organization carried by ctx (see pkg/tenant).
+type UserStatsRepository interface {
+	// Stats computes the statistics selected by query
+	Stats(ctx context.Context, query entity.UserStatsQuery) (*entity.UserStats, error)
+}
@@ -0,0 +1,109 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/logger"
+	"arritech-user-management/pkg/problem"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+// Codes of GraphQL errors that have no problem code, as they only occur in GraphQL
+const (
+	CodeQueryTooDeep    = "QUERY_TOO_DEEP"
+	CodeQueryTooComplex = "QUERY_TOO_COMPLEX"
+)
+
+// Error is a GraphQL error with the problem code the HTTP API would answer with in its
+// extensions, and the invalid fields keyed by their GraphQL argument path, if any
+type Error struct {
+	Code    problem.Code
+	Message string
+	Fields  map[string]string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Extensions implements gqlerrors.ExtendedError
+func (e *Error) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": string(e.Code)}
+	if len(e.Fields) > 0 {
+		extensions["fields"] = e.Fields
+	}
+	return extensions
+}
+
+func newError(code problem.Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// userError converts an error of the user service to the problem code of the HTTP API. Other
+// errors are logged and reported with the given message, so that their details don't reach
+// the client.
+func userError(ctx context.Context, log *logrus.Logger, err error, message string) error {
+	switch service.FailureOutcome(err) {
+	case service.OutcomeNotFound:
+		return newError(problem.CodeUserNotFound, err.Error())
+	case service.OutcomeDenied:
+		var permErr *auth.PermissionError
+		errors.As(err, &permErr)
+		return newError(problem.CodePermissionDenied, "missing permission "+permErr.Permission)
+	case service.OutcomeEmailTaken:
+		return &Error{Code: problem.CodeUserEmailTaken, Message: err.Error(), Fields: map[string]string{"input.email": "Another user has this email address"}}
+	case service.OutcomeRejectedUnderage:
+		return &Error{Code: problem.CodeUserUnderage, Message: err.Error(), Fields: map[string]string{"input.dateOfBirth": err.Error()}}
+	case service.OutcomeRejectedEmailDomain:
+		return &Error{Code: problem.CodeUserEmailDomainNotAllowed, Message: err.Error(), Fields: map[string]string{"input.email": err.Error()}}
+	case service.OutcomeInvalid:
+		var attrErr *service.AttributeValidationError
+		if !errors.As(err, &attrErr) {
+			return newError(problem.CodeValidationFailed, err.Error())
+		}
+		fields := make(map[string]string, len(attrErr.Fields))
+		for key, detail := range attrErr.Fields {
+			if key == "" {
+				fields["filter.attributes"] = detail
+				continue
+			}
+			fields["attributes."+key] = detail
+		}
+		return &Error{Code: problem.CodeValidationFailed, Message: "invalid attributes", Fields: fields}
+	case service.OutcomeInvalidTransition:
+		return newError(problem.CodeUserStatusTransitionInvalid, err.Error())
+	}
+
+	logger.FromContext(ctx, log).WithError(err).Error(message)
+	return newError(problem.CodeInternalError, message)
+}
+
+// validationError lists the input fields that failed validation under their GraphQL names,
+// prefixed with the input argument that carries them, if any
+func validationError(argument string, err error) error {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return newError(problem.CodeValidationFailed, err.Error())
+	}
+	fields := make(map[string]string, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		field := lowerFirst(fieldErr.Field())
+		if argument != "" {
+			field = argument + "." + field
+		}
+		fields[field] = fieldErr.Tag() + " validation failed"
+	}
+	return &Error{Code: problem.CodeValidationFailed, Message: "validation failed", Fields: fields}
+}
+
+// lowerFirst converts a Go field name such as DateOfBirth to its GraphQL name, dateOfBirth
+func lowerFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	return string(name[0]|0x20) + name[1:]
+}
@@ -0,0 +1,71 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/problem"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    problem.Code
+		message string
+		fields  map[string]string
+	}{
+		{name: "Not found", err: errors.New("user not found"), code: problem.CodeUserNotFound, message: "user not found"},
+		{name: "Permission", err: fmt.Errorf("wrapped: %w", &auth.PermissionError{Permission: auth.PermissionUsersWrite}), code: problem.CodePermissionDenied, message: "missing permission users:write"},
+		{name: "Email taken", err: errors.New("email already exists"), code: problem.CodeUserEmailTaken, message: "email already exists", fields: map[string]string{"input.email": "Another user has this email address"}},
+		{name: "Underage", err: &service.PolicyError{Rule: service.PolicyMinimumAge, Message: "users must be at least 18"}, code: problem.CodeUserUnderage, message: "users must be at least 18", fields: map[string]string{"input.dateOfBirth": "users must be at least 18"}},
+		{name: "Email domain", err: &service.PolicyError{Rule: service.PolicyEmailDomain, Message: "email domain not allowed"}, code: problem.CodeUserEmailDomainNotAllowed, message: "email domain not allowed", fields: map[string]string{"input.email": "email domain not allowed"}},
+		{name: "Attributes", err: &service.AttributeValidationError{Fields: map[string]string{"team": "unknown", "": "bad filter"}}, code: problem.CodeValidationFailed, message: "invalid attributes", fields: map[string]string{"attributes.team": "unknown", "filter.attributes": "bad filter"}},
+		{name: "Date format", err: errors.New("invalid date of birth format, expected YYYY-MM-DD"), code: problem.CodeValidationFailed, message: "invalid date of birth format, expected YYYY-MM-DD"},
+		{name: "Transition", err: service.ErrInvalidStatusTransition, code: problem.CodeUserStatusTransitionInvalid, message: service.ErrInvalidStatusTransition.Error()},
+		{name: "Internal", err: errors.New("connection refused"), code: problem.CodeInternalError, message: "failed to do it"},
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := userError(context.Background(), logger, tt.err, "failed to do it")
+
+			var gqlErr *Error
+			require.ErrorAs(t, err, &gqlErr)
+			assert.Equal(t, tt.code, gqlErr.Code)
+			assert.Equal(t, tt.message, gqlErr.Message)
+			assert.Equal(t, tt.fields, gqlErr.Fields)
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	err := validator.New().Struct(entity.CreateUserRequest{Name: "A", Email: "nope", DateOfBirth: "1990-01-01"})
+
+	var gqlErr *Error
+	require.ErrorAs(t, validationError("input", err), &gqlErr)
+	assert.Equal(t, problem.CodeValidationFailed, gqlErr.Code)
+	assert.Equal(t, map[string]string{"input.name": "min validation failed", "input.email": "email validation failed"}, gqlErr.Fields)
+
+	require.ErrorAs(t, validationError("", validator.New().Struct(entity.ChangeUserStatusRequest{})), &gqlErr)
+	assert.Equal(t, map[string]string{"reason": "required validation failed"}, gqlErr.Fields)
+}
+
+func TestErrorExtensions(t *testing.T) {
+	err := &Error{Code: problem.CodeValidationFailed, Message: "validation failed", Fields: map[string]string{"first": "too big"}}
+
+	assert.Equal(t, map[string]interface{}{"code": "VALIDATION_FAILED", "fields": map[string]string{"first": "too big"}}, err.Extensions())
+	assert.Equal(t, map[string]interface{}{"code": "USER_NOT_FOUND"}, newError(problem.CodeUserNotFound, "user not found").Extensions())
+}
@@ -0,0 +1,129 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/sirupsen/logrus"
+)
+
+// Request is the body of a POST /graphql request
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// GraphQLHandler serves the user API as GraphQL. The schema is self-describing through
+// introspection, so the endpoint is left out of the Swagger docs.
+type GraphQLHandler struct {
+	schema graphql.Schema
+	limits Limits
+	logger *logrus.Logger
+}
+
+func NewGraphQLHandler(userService service.UserService, validator *validator.Validate, limits Limits, logger *logrus.Logger) (*GraphQLHandler, error) {
+	schema, err := newSchema(&resolver{users: userService, validator: validator, logger: logger})
+	if err != nil {
+		return nil, err
+	}
+	return &GraphQLHandler{
+		schema: schema,
+		limits: limits,
+		logger: logger,
+	}, nil
+}
+
+// Query runs a GraphQL query or mutation. Errors of the query are reported in the errors of the
+// result with status 200, as GraphQL clients expect; only a body that isn't a GraphQL request
+// gets a problem response.
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req Request
+	if err := c.ShouldBindJSON(&req); err != nil || req.Query == "" {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			problem.Abort(c, problem.CodeRequestTooLarge, fmt.Sprintf("Request body may have at most %d bytes", tooLarge.Limit))
+			return
+		}
+		problem.Abort(c, problem.CodeMalformedRequest, "Expected a JSON body with a query")
+		return
+	}
+	c.JSON(http.StatusOK, h.execute(c.Request.Context(), req))
+}
+
+// execute parses the query and checks it against the limits before validating and running it,
+// so queries that are too costly to validate are turned away too
+func (h *GraphQLHandler) execute(ctx context.Context, req Request) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	if err := checkLimits(doc, req.OperationName, req.Variables, h.limits); err != nil {
+		var limitErr *limitError
+		if !errors.As(err, &limitErr) {
+			return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+		}
+		return &graphql.Result{Errors: []gqlerrors.FormattedError{{
+			Message:    limitErr.Error(),
+			Locations:  []location.SourceLocation{},
+			Extensions: limitErr.Extensions(),
+		}}}
+	}
+
+	validation := graphql.ValidateDocument(&h.schema, doc, nil)
+	if !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}
+	}
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+}
+
+// GraphiQL serves an in-browser IDE for the endpoint. It loads its scripts from a CDN and is
+// only routed in debug mode.
+func (h *GraphQLHandler) GraphiQL(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(graphiQLPage))
+}
+
+const graphiQLPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>GraphiQL - User Management</title>
+  <link rel="stylesheet" href="https://unpkg.com/graphiql@3/graphiql.min.css">
+  <style>body { margin: 0; height: 100vh; } #graphiql { height: 100vh; }</style>
+</head>
+<body>
+  <div id="graphiql">Loading...</div>
+  <script crossorigin src="https://unpkg.com/react@18/umd/react.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/react-dom@18/umd/react-dom.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/graphiql@3/graphiql.min.js"></script>
+  <script>
+    // Requests carry the headers set in the editor, e.g. Authorization and X-Organization
+    const fetcher = GraphiQL.createFetcher({ url: '/graphql' });
+    ReactDOM.createRoot(document.getElementById('graphiql')).render(
+      React.createElement(GraphiQL, { fetcher: fetcher, defaultEditorToolsVisibility: 'headers' })
+    );
+  </script>
+</body>
+</html>
+`
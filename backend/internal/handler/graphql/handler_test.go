@@ -0,0 +1,141 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"arritech-user-management/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type response struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func setupHandler(t *testing.T, limits Limits) (*gin.Engine, *MockUserService) {
+	gin.SetMode(gin.TestMode)
+	mockService := &MockUserService{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel) // Reduce noise in tests
+	handler, err := NewGraphQLHandler(mockService, validator.New(), limits, logger)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.POST("/graphql", handler.Query)
+	router.GET("/graphiql", handler.GraphiQL)
+	return router, mockService
+}
+
+func post(t *testing.T, router *gin.Engine, body string) (*httptest.ResponseRecorder, response) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp response
+	if w.Header().Get("Content-Type") == "application/json; charset=utf-8" {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	}
+	return w, resp
+}
+
+func TestGraphQLHandler_Query(t *testing.T) {
+	t.Run("Runs the operation with its variables", func(t *testing.T) {
+		router, mockService := setupHandler(t, DefaultLimits())
+		user := testUser(3)
+		mockService.On("GetUser", mock.Anything, uint(3)).Return(&user, nil)
+
+		w, resp := post(t, router, `{"query": "query Get($id: ID!) { user(id: $id) { name } } query Other { user(id: \"1\") { id } }", "operationName": "Get", "variables": {"id": "3"}}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, resp.Errors)
+		assert.Equal(t, map[string]interface{}{"user": map[string]interface{}{"name": "Test User"}}, resp.Data)
+	})
+
+	t.Run("Reports syntax and schema errors", func(t *testing.T) {
+		router, _ := setupHandler(t, DefaultLimits())
+
+		w, resp := post(t, router, `{"query": "{ user(id: "}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0].Message, "Syntax Error")
+
+		_, resp = post(t, router, `{"query": "{ user(id: \"1\") { password } }"}`)
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0].Message, `Cannot query field "password"`)
+	})
+
+	t.Run("Rejects queries over the limits before running them", func(t *testing.T) {
+		router, mockService := setupHandler(t, Limits{MaxDepth: 3})
+
+		w, resp := post(t, router, `{"query": "{ users { edges { node { tags { id } } } } }"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Nil(t, resp.Data)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "QUERY_TOO_DEEP", resp.Errors[0].Extensions["code"])
+		mockService.AssertNotCalled(t, "ListUsers", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Checks the limits before validating the query", func(t *testing.T) {
+		router, _ := setupHandler(t, Limits{MaxDepth: 3})
+
+		_, resp := post(t, router, `{"query": "{ users { edges { node { unknown { id } } } } }"}`)
+
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "QUERY_TOO_DEEP", resp.Errors[0].Extensions["code"])
+	})
+
+	t.Run("Rejects bodies over the size limit", func(t *testing.T) {
+		handler, err := NewGraphQLHandler(&MockUserService{}, validator.New(), DefaultLimits(), logrus.New())
+		require.NoError(t, err)
+		router := gin.New()
+		router.POST("/graphql", middleware.BodyLimitMiddleware(32), handler.Query)
+
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBufferString(`{"query": "{ users { edges { node { id name email } } } }"}`))
+		req.Header.Set("Content-Type", "application/json")
+		// A body of unknown length is only cut off while the handler reads it
+		req.ContentLength = -1
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), "REQUEST_TOO_LARGE")
+	})
+
+	t.Run("Rejects bodies that aren't GraphQL requests", func(t *testing.T) {
+		router, _ := setupHandler(t, DefaultLimits())
+
+		for _, body := range []string{`not json`, `{"variables": {}}`} {
+			w, _ := post(t, router, body)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Body.String(), "MALFORMED_REQUEST")
+		}
+	})
+}
+
+func TestGraphQLHandler_GraphiQL(t *testing.T) {
+	router, _ := setupHandler(t, DefaultLimits())
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphiql", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "url: '/graphql'")
+}
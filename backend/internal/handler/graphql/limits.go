@@ -0,0 +1,198 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Limits bound the cost of a query before it runs
+type Limits struct {
+	// MaxDepth is the deepest nesting of fields a query may select; zero disables the check
+	MaxDepth int
+	// MaxComplexity bounds the number of fields a query may resolve, counting the fields under a
+	// paginated field once per requested item; zero disables the check
+	MaxComplexity int
+}
+
+// DefaultLimits allow a page of 100 users with a dozen fields each
+func DefaultLimits() Limits {
+	return Limits{MaxDepth: 10, MaxComplexity: 2000}
+}
+
+// defaultPageSize is the number of items a paginated field returns without a first argument
+const defaultPageSize = 10
+
+// MaxIntrospectionDepth bounds the nesting under __schema and __type, whatever MaxDepth is. It
+// leaves room for the type references that GraphiQL's introspection query unwraps.
+const MaxIntrospectionDepth = 15
+
+// limitError reports a query that exceeds the limits
+type limitError struct {
+	code    string
+	message string
+}
+
+func (e *limitError) Error() string {
+	return e.message
+}
+
+func (e *limitError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+// checkLimits measures the operation of doc that will run and rejects it when it is too deep or
+// too complex. Introspection fields count toward the complexity like any other; their depth is
+// held to MaxIntrospectionDepth rather than MaxDepth.
+func checkLimits(doc *ast.Document, operationName string, variables map[string]interface{}, limits Limits) error {
+	a := &analysis{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		costs:     make(map[string]cost),
+		visiting:  make(map[string]bool),
+		variables: variables,
+	}
+	var operation *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.FragmentDefinition:
+			a.fragments[def.Name.Value] = def
+		case *ast.OperationDefinition:
+			if operationName == "" || (def.Name != nil && def.Name.Value == operationName) {
+				operation = def
+			}
+		}
+	}
+	if operation == nil {
+		// Validation reports the missing operation
+		return nil
+	}
+
+	measured := a.selectionSet(operation.SelectionSet, 1)
+	if limits.MaxDepth > 0 && measured.depth > limits.MaxDepth {
+		return &limitError{code: CodeQueryTooDeep, message: fmt.Sprintf("query depth %d exceeds the limit of %d", measured.depth, limits.MaxDepth)}
+	}
+	if measured.introspectionDepth > MaxIntrospectionDepth {
+		return &limitError{code: CodeQueryTooDeep, message: fmt.Sprintf("introspection depth %d exceeds the limit of %d", measured.introspectionDepth, MaxIntrospectionDepth)}
+	}
+	if limits.MaxComplexity > 0 && measured.complexity > limits.MaxComplexity {
+		return &limitError{code: CodeQueryTooComplex, message: fmt.Sprintf("query complexity %d exceeds the limit of %d", measured.complexity, limits.MaxComplexity)}
+	}
+	return nil
+}
+
+type analysis struct {
+	fragments map[string]*ast.FragmentDefinition
+	// costs memoizes fragments, so that spreading a fragment many times doesn't make the analysis
+	// itself expensive
+	costs map[string]cost
+	// visiting guards against fragment cycles, which validation rejects after the analysis
+	visiting  map[string]bool
+	variables map[string]interface{}
+}
+
+// cost is the depth of the deepest field of a selection set, the depth of the deepest field under
+// __schema or __type, zero without any, and the complexity of the set
+type cost struct {
+	depth              int
+	introspectionDepth int
+	complexity         int
+}
+
+// shifted returns the cost of a fragment, measured from depth one, spread at depth
+func (c cost) shifted(depth int) cost {
+	c.depth += depth - 1
+	if c.introspectionDepth > 0 {
+		c.introspectionDepth += depth - 1
+	}
+	return c
+}
+
+func (a *analysis) fragment(name string) (cost, bool) {
+	if measured, ok := a.costs[name]; ok {
+		return measured, true
+	}
+	definition, ok := a.fragments[name]
+	if !ok || a.visiting[name] {
+		return cost{}, false
+	}
+	a.visiting[name] = true
+	measured := a.selectionSet(definition.SelectionSet, 1)
+	a.visiting[name] = false
+	a.costs[name] = measured
+	return measured, true
+}
+
+// selectionSet measures set, whose fields are at depth
+func (a *analysis) selectionSet(set *ast.SelectionSet, depth int) cost {
+	total := cost{depth: depth - 1}
+	if set == nil {
+		return total
+	}
+	for _, selection := range set.Selections {
+		var c cost
+		switch selection := selection.(type) {
+		case *ast.Field:
+			c = a.selectionSet(selection.SelectionSet, depth+1)
+			c.complexity = 1 + c.complexity*a.pageSize(selection)
+			if isIntrospection(selection) {
+				// The whole subtree is held to the introspection depth instead
+				c.introspectionDepth = max(c.introspectionDepth, c.depth, depth)
+				c.depth = depth - 1
+			} else {
+				c.depth = max(c.depth, depth)
+			}
+		case *ast.InlineFragment:
+			c = a.selectionSet(selection.SelectionSet, depth)
+		case *ast.FragmentSpread:
+			measured, ok := a.fragment(selection.Name.Value)
+			if !ok {
+				continue
+			}
+			c = measured.shifted(depth)
+		}
+		total.depth = max(total.depth, c.depth)
+		total.introspectionDepth = max(total.introspectionDepth, c.introspectionDepth)
+		total.complexity += c.complexity
+	}
+	return total
+}
+
+// isIntrospection reports whether field is an entry point into the schema's introspection.
+// __typename is a plain leaf and counts like any other field.
+func isIntrospection(field *ast.Field) bool {
+	return field.Name.Value == "__schema" || field.Name.Value == "__type"
+}
+
+// pageSize is how many items a field returns: the first argument of paginated fields, one
+// otherwise. Fields without a limit, such as a user's tags, are counted once.
+func (a *analysis) pageSize(field *ast.Field) int {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "first" {
+			continue
+		}
+		switch value := arg.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(value.Value); err == nil && n > 0 {
+				return n
+			}
+		case *ast.Variable:
+			// Variables decoded from JSON are float64
+			switch n := a.variables[value.Name.Value].(type) {
+			case float64:
+				if n > 0 {
+					return int(n)
+				}
+			case int:
+				if n > 0 {
+					return n
+				}
+			}
+		}
+		return defaultPageSize
+	}
+	if field.Name.Value == "users" {
+		return defaultPageSize
+	}
+	return 1
+}
@@ -0,0 +1,113 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func checkQuery(t *testing.T, query string, variables map[string]interface{}, limits Limits) error {
+	t.Helper()
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	require.NoError(t, err)
+	return checkLimits(doc, "", variables, limits)
+}
+
+func TestCheckLimits(t *testing.T) {
+	t.Run("Accepts queries within the limits", func(t *testing.T) {
+		err := checkQuery(t, `{ users(first: 100) { nodes { id name email age } } }`, nil, DefaultLimits())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Rejects deep queries", func(t *testing.T) {
+		err := checkQuery(t, `{ users { edges { node { tags { id } } } } }`, nil, Limits{MaxDepth: 4})
+
+		var limitErr *limitError
+		require.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, CodeQueryTooDeep, limitErr.code)
+		assert.Equal(t, "query depth 5 exceeds the limit of 4", limitErr.Error())
+	})
+
+	t.Run("Counts fields under paginated fields once per item", func(t *testing.T) {
+		// 1 (users) + 100 * (1 (nodes) + 4 fields)
+		err := checkQuery(t, `{ users(first: 100) { nodes { id name email age } } }`, nil, Limits{MaxComplexity: 500})
+
+		var limitErr *limitError
+		require.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, CodeQueryTooComplex, limitErr.code)
+		assert.Equal(t, "query complexity 501 exceeds the limit of 500", limitErr.Error())
+	})
+
+	t.Run("Reads the page size from variables", func(t *testing.T) {
+		query := `query($n: Int) { users(first: $n) { nodes { id } } }`
+
+		assert.NoError(t, checkQuery(t, query, map[string]interface{}{"n": float64(5)}, Limits{MaxComplexity: 20}))
+		assert.Error(t, checkQuery(t, query, map[string]interface{}{"n": float64(50)}, Limits{MaxComplexity: 20}))
+		// Without the variable the default page size applies: 1 + 10 * 2
+		assert.Error(t, checkQuery(t, query, nil, Limits{MaxComplexity: 20}))
+	})
+
+	t.Run("Measures fragments where they are spread", func(t *testing.T) {
+		query := `
+			{ users { ...page } }
+			fragment page on UserConnection { edges { node { ...fields } } }
+			fragment fields on User { id tags { name } }`
+
+		assert.Error(t, checkQuery(t, query, nil, Limits{MaxDepth: 4}))
+		assert.NoError(t, checkQuery(t, query, nil, Limits{MaxDepth: 5}))
+	})
+
+	t.Run("Analyzes repeated fragments quickly", func(t *testing.T) {
+		// Each fragment spreads the previous one twice, which would take 2^30 steps unmemoized
+		var query strings.Builder
+		query.WriteString(`{ user(id: "1") { ...f30 } } fragment f0 on User { id }`)
+		for i := 1; i <= 30; i++ {
+			query.WriteString(fmt.Sprintf(" fragment f%d on User { ...f%d ...f%d }", i, i-1, i-1))
+		}
+
+		err := checkQuery(t, query.String(), nil, DefaultLimits())
+
+		var limitErr *limitError
+		require.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, CodeQueryTooComplex, limitErr.code)
+	})
+
+	t.Run("Counts introspection toward the complexity", func(t *testing.T) {
+		err := checkQuery(t, `{ __schema { types { name fields { name type { name } } } } }`, nil, Limits{MaxComplexity: 5})
+
+		var limitErr *limitError
+		require.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, CodeQueryTooComplex, limitErr.code)
+		assert.Equal(t, "query complexity 7 exceeds the limit of 5", limitErr.Error())
+	})
+
+	t.Run("Holds introspection to its own depth", func(t *testing.T) {
+		// GraphiQL's introspection query unwraps type references seven levels deep
+		typeRef := "name"
+		for i := 0; i < 7; i++ {
+			typeRef = fmt.Sprintf("name ofType { %s }", typeRef)
+		}
+		query := fmt.Sprintf(`fragment TypeRef on __Type { %s } { __schema { types { fields { args { type { ...TypeRef } } } } } }`, typeRef)
+
+		assert.NoError(t, checkQuery(t, query, nil, Limits{MaxDepth: 2}))
+
+		deeper := strings.Replace(query, "{ name }", "{ name ofType { name ofType { name ofType { name } } } }", 1)
+		err := checkQuery(t, deeper, nil, Limits{MaxDepth: 2})
+
+		var limitErr *limitError
+		require.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, CodeQueryTooDeep, limitErr.code)
+		assert.Equal(t, "introspection depth 16 exceeds the limit of 15", limitErr.Error())
+	})
+
+	t.Run("Zero limits disable the checks", func(t *testing.T) {
+		err := checkQuery(t, `{ users(first: 100) { edges { node { tags { id name } } } } }`, nil, Limits{})
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,594 @@
+package graphql
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/problem"
+	"github.com/go-playground/validator/v10"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/sirupsen/logrus"
+)
+
+// maxPageSize matches the largest per_page of GET /api/v1/users
+const maxPageSize = 100
+
+// resolver resolves the fields of the schema with the user service
+type resolver struct {
+	users     service.UserService
+	validator *validator.Validate
+	logger    *logrus.Logger
+}
+
+var jsonScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:         "JSON",
+	Description:  "Any JSON value; used for custom attributes",
+	Serialize:    func(value interface{}) interface{} { return value },
+	ParseValue:   func(value interface{}) interface{} { return value },
+	ParseLiteral: parseJSONLiteral,
+})
+
+// parseJSONLiteral converts an inline value to what decoding it from JSON would give
+func parseJSONLiteral(value ast.Value) interface{} {
+	switch value := value.(type) {
+	case *ast.StringValue:
+		return value.Value
+	case *ast.BooleanValue:
+		return value.Value
+	case *ast.IntValue:
+		n, _ := strconv.ParseFloat(value.Value, 64)
+		return n
+	case *ast.FloatValue:
+		n, _ := strconv.ParseFloat(value.Value, 64)
+		return n
+	case *ast.EnumValue:
+		return value.Value
+	case *ast.ListValue:
+		list := make([]interface{}, 0, len(value.Values))
+		for _, item := range value.Values {
+			list = append(list, parseJSONLiteral(item))
+		}
+		return list
+	case *ast.ObjectValue:
+		object := make(map[string]interface{}, len(value.Fields))
+		for _, field := range value.Fields {
+			object[field.Name.Value] = parseJSONLiteral(field.Value)
+		}
+		return object
+	}
+	return nil
+}
+
+var userStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "UserStatus",
+	Values: graphql.EnumValueConfigMap{
+		"PENDING":   {Value: string(entity.UserStatusPending)},
+		"ACTIVE":    {Value: string(entity.UserStatusActive)},
+		"SUSPENDED": {Value: string(entity.UserStatusSuspended)},
+		"ARCHIVED":  {Value: string(entity.UserStatusArchived)},
+	},
+})
+
+var userStatusFilterEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "UserStatusFilter",
+	Description: "Status to list; archived users are hidden unless ARCHIVED or ALL is asked for",
+	Values: graphql.EnumValueConfigMap{
+		"PENDING":   {Value: string(entity.UserStatusPending)},
+		"ACTIVE":    {Value: string(entity.UserStatusActive)},
+		"SUSPENDED": {Value: string(entity.UserStatusSuspended)},
+		"ARCHIVED":  {Value: string(entity.UserStatusArchived)},
+		"ALL":       {Value: "all"},
+	},
+})
+
+var matchModeEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "MatchMode",
+	Description: "Whether users need any or all of the given tags or groups",
+	Values: graphql.EnumValueConfigMap{
+		"ANY": {Value: "any"},
+		"ALL": {Value: "all"},
+	},
+})
+
+var userSortFieldEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "UserSortField",
+	Values: graphql.EnumValueConfigMap{
+		"NAME":       {Value: "name"},
+		"EMAIL":      {Value: "email"},
+		"AGE":        {Value: "age"},
+		"PHONE":      {Value: "phone"},
+		"CREATED_AT": {Value: "created_at"},
+		"UPDATED_AT": {Value: "updated_at"},
+	},
+})
+
+var sortDirectionEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "SortDirection",
+	Values: graphql.EnumValueConfigMap{
+		"ASC":  {Value: "asc"},
+		"DESC": {Value: "desc"},
+	},
+})
+
+var tagType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Tag",
+	Fields: graphql.Fields{
+		"id":    tagField(graphql.NewNonNull(graphql.ID), func(t entity.Tag) interface{} { return strconv.FormatUint(uint64(t.ID), 10) }),
+		"name":  tagField(graphql.NewNonNull(graphql.String), func(t entity.Tag) interface{} { return t.Name }),
+		"color": tagField(graphql.String, func(t entity.Tag) interface{} { return optionalString(t.Color) }),
+	},
+})
+
+var userType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "User",
+	Description: "Phone, address, the day and month of birth and the local part of the email are masked for callers without the users:pii permission",
+	Fields: graphql.Fields{
+		"id":              userField(graphql.NewNonNull(graphql.ID), func(u *entity.User) interface{} { return strconv.FormatUint(uint64(u.ID), 10) }),
+		"organizationId":  userField(graphql.NewNonNull(graphql.ID), func(u *entity.User) interface{} { return strconv.FormatUint(uint64(u.OrganizationID), 10) }),
+		"name":            userField(graphql.NewNonNull(graphql.String), func(u *entity.User) interface{} { return u.Name }),
+		"email":           userField(graphql.NewNonNull(graphql.String), func(u *entity.User) interface{} { return u.Email }),
+		"emailVerifiedAt": userField(graphql.String, func(u *entity.User) interface{} { return optionalTime(u.EmailVerifiedAt) }),
+		"dateOfBirth": {
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "YYYY-MM-DD, or YYYY-**-** when personal data is masked",
+			Resolve:     resolveUser(dateOfBirth),
+		},
+		"age": {
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "Age in full years, computed from the date of birth",
+			Resolve:     resolveUser(func(u *entity.User) interface{} { return u.Age }),
+		},
+		"phone":           userField(graphql.String, func(u *entity.User) interface{} { return optionalString(u.Phone) }),
+		"address":         userField(graphql.String, func(u *entity.User) interface{} { return optionalString(u.Address) }),
+		"avatarUrl":       userField(graphql.String, func(u *entity.User) interface{} { return optionalString(u.AvatarURL) }),
+		"attributes":      userField(jsonScalar, func(u *entity.User) interface{} { return optionalAttributes(u.Attributes) }),
+		"tags":            userField(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(tagType))), func(u *entity.User) interface{} { return u.Tags }),
+		"status":          userField(graphql.NewNonNull(userStatusEnum), func(u *entity.User) interface{} { return string(u.Status) }),
+		"statusReason":    userField(graphql.String, func(u *entity.User) interface{} { return optionalString(u.StatusReason) }),
+		"statusChangedAt": userField(graphql.String, func(u *entity.User) interface{} { return optionalTime(u.StatusChangedAt) }),
+		"createdAt":       userField(graphql.NewNonNull(graphql.String), func(u *entity.User) interface{} { return u.CreatedAt.Format(time.RFC3339) }),
+		"updatedAt":       userField(graphql.NewNonNull(graphql.String), func(u *entity.User) interface{} { return u.UpdatedAt.Format(time.RFC3339) }),
+		"piiRedacted":     userField(graphql.NewNonNull(graphql.Boolean), func(u *entity.User) interface{} { return u.PIIRedacted }),
+	},
+})
+
+var pageInfoType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "PageInfo",
+	Fields: graphql.Fields{
+		"hasNextPage":     {Type: graphql.NewNonNull(graphql.Boolean)},
+		"hasPreviousPage": {Type: graphql.NewNonNull(graphql.Boolean)},
+		"startCursor":     {Type: graphql.String},
+		"endCursor":       {Type: graphql.String},
+	},
+})
+
+var userEdgeType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "UserEdge",
+	Fields: graphql.Fields{
+		"cursor": {Type: graphql.NewNonNull(graphql.String)},
+		"node":   {Type: graphql.NewNonNull(userType)},
+	},
+})
+
+var userConnectionType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "UserConnection",
+	Fields: graphql.Fields{
+		"edges":      {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(userEdgeType)))},
+		"nodes":      {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(userType)))},
+		"pageInfo":   {Type: graphql.NewNonNull(pageInfoType)},
+		"totalCount": {Type: graphql.NewNonNull(graphql.Int)},
+	},
+})
+
+var attributeFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "AttributeFilter",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"key":   {Type: graphql.NewNonNull(graphql.String)},
+		"value": {Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+var userFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "UserFilter",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"search":     {Type: graphql.String, Description: "Matches name, email or phone; only the name without the users:pii permission"},
+		"status":     {Type: userStatusFilterEnum},
+		"tags":       {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"tagMatch":   {Type: matchModeEnum, DefaultValue: "any"},
+		"groups":     {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"groupMatch": {Type: matchModeEnum, DefaultValue: "any"},
+		"attributes": {Type: graphql.NewList(graphql.NewNonNull(attributeFilterInput))},
+	},
+})
+
+var userSortInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "UserSort",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"field":     {Type: userSortFieldEnum, DefaultValue: "created_at"},
+		"direction": {Type: sortDirectionEnum, DefaultValue: "desc"},
+	},
+})
+
+var createUserInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "CreateUserInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name":        {Type: graphql.NewNonNull(graphql.String)},
+		"email":       {Type: graphql.NewNonNull(graphql.String)},
+		"dateOfBirth": {Type: graphql.NewNonNull(graphql.String), Description: "YYYY-MM-DD"},
+		"phone":       {Type: graphql.String},
+		"address":     {Type: graphql.String},
+		"attributes":  {Type: jsonScalar},
+	},
+})
+
+var updateUserInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "UpdateUserInput",
+	Description: "Fields left out keep their value; attributes replaces all custom attributes",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name":        {Type: graphql.String},
+		"email":       {Type: graphql.String},
+		"dateOfBirth": {Type: graphql.String, Description: "YYYY-MM-DD"},
+		"phone":       {Type: graphql.String},
+		"address":     {Type: graphql.String},
+		"attributes":  {Type: jsonScalar},
+	},
+})
+
+// newSchema builds the GraphQL schema of the user API
+func newSchema(r *resolver) (graphql.Schema, error) {
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"user": {
+				Type: userType,
+				Args: graphql.FieldConfigArgument{
+					"id": {Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: r.user,
+			},
+			"users": {
+				Type:        graphql.NewNonNull(userConnectionType),
+				Description: "Users of the organization, paginated with first and the cursor of the last edge seen",
+				Args: graphql.FieldConfigArgument{
+					"first":  {Type: graphql.Int, DefaultValue: defaultPageSize, Description: "Page size, at most 100"},
+					"after":  {Type: graphql.String},
+					"filter": {Type: userFilterInput},
+					"sort":   {Type: userSortInput},
+				},
+				Resolve: r.listUsers,
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createUser": {
+				Type: graphql.NewNonNull(userType),
+				Args: graphql.FieldConfigArgument{
+					"input": {Type: graphql.NewNonNull(createUserInput)},
+				},
+				Resolve: r.createUser,
+			},
+			"updateUser": {
+				Type: graphql.NewNonNull(userType),
+				Args: graphql.FieldConfigArgument{
+					"id":    {Type: graphql.NewNonNull(graphql.ID)},
+					"input": {Type: graphql.NewNonNull(updateUserInput)},
+				},
+				Resolve: r.updateUser,
+			},
+			"deleteUser": {
+				Type:        graphql.NewNonNull(graphql.ID),
+				Description: "Deletes a user and returns its ID",
+				Args: graphql.FieldConfigArgument{
+					"id": {Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: r.deleteUser,
+			},
+			"changeUserStatus": {
+				Type: graphql.NewNonNull(userType),
+				Args: graphql.FieldConfigArgument{
+					"id":     {Type: graphql.NewNonNull(graphql.ID)},
+					"status": {Type: graphql.NewNonNull(userStatusEnum)},
+					"reason": {Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: r.changeUserStatus,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+func (r *resolver) user(p graphql.ResolveParams) (interface{}, error) {
+	id, err := userID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	user, err := r.users.GetUser(p.Context, id)
+	if err != nil {
+		return nil, userError(p.Context, r.logger, err, "failed to get user")
+	}
+	return user, nil
+}
+
+func (r *resolver) listUsers(p graphql.ResolveParams) (interface{}, error) {
+	first, _ := p.Args["first"].(int)
+	if first < 1 || first > maxPageSize {
+		return nil, &Error{Code: problem.CodeValidationFailed, Message: "invalid first", Fields: map[string]string{"first": fmt.Sprintf("Must be between 1 and %d", maxPageSize)}}
+	}
+	offset := 0
+	if after, ok := p.Args["after"].(string); ok {
+		n, err := decodeCursor(after)
+		if err != nil {
+			return nil, &Error{Code: problem.CodeInvalidParameter, Message: "invalid after", Fields: map[string]string{"after": "Invalid cursor"}}
+		}
+		offset = n + 1
+	}
+
+	params := entity.UserSearchParams{SortBy: "created_at", SortDir: "desc"}
+	if filter, ok := p.Args["filter"].(map[string]interface{}); ok {
+		params.Search, _ = filter["search"].(string)
+		params.Status, _ = filter["status"].(string)
+		params.Tags = strings.Join(stringList(filter["tags"]), ",")
+		params.TagMatch, _ = filter["tagMatch"].(string)
+		params.Group = strings.Join(stringList(filter["groups"]), ",")
+		params.GroupMatch, _ = filter["groupMatch"].(string)
+		if attributes, ok := filter["attributes"].([]interface{}); ok && len(attributes) > 0 {
+			params.Attributes = make(map[string]string, len(attributes))
+			for _, attribute := range attributes {
+				pair, _ := attribute.(map[string]interface{})
+				key, _ := pair["key"].(string)
+				value, _ := pair["value"].(string)
+				params.Attributes[key] = value
+			}
+		}
+	}
+	if sort, ok := p.Args["sort"].(map[string]interface{}); ok {
+		if field, ok := sort["field"].(string); ok {
+			params.SortBy = field
+		}
+		if direction, ok := sort["direction"].(string); ok {
+			params.SortDir = direction
+		}
+	}
+
+	// The service paginates by page; a cursor that doesn't start a page takes the rest of its
+	// page and the beginning of the next one
+	params.PerPage = first
+	params.Page = offset/first + 1
+	result, err := r.users.ListUsers(p.Context, params)
+	if err != nil {
+		return nil, userError(p.Context, r.logger, err, "failed to list users")
+	}
+	users := result.Users[min(offset%first, len(result.Users)):]
+	if offset%first != 0 && int64(offset+len(users)) < result.Total {
+		params.Page++
+		next, err := r.users.ListUsers(p.Context, params)
+		if err != nil {
+			return nil, userError(p.Context, r.logger, err, "failed to list users")
+		}
+		users = append(users, next.Users[:min(first-len(users), len(next.Users))]...)
+	}
+
+	edges := make([]interface{}, 0, len(users))
+	nodes := make([]interface{}, 0, len(users))
+	for i := range users {
+		user := &users[i]
+		edges = append(edges, map[string]interface{}{"cursor": encodeCursor(offset + i), "node": user})
+		nodes = append(nodes, user)
+	}
+	pageInfo := map[string]interface{}{
+		"hasNextPage":     int64(offset+len(users)) < result.Total,
+		"hasPreviousPage": offset > 0,
+	}
+	if len(users) > 0 {
+		pageInfo["startCursor"] = encodeCursor(offset)
+		pageInfo["endCursor"] = encodeCursor(offset + len(users) - 1)
+	}
+	return map[string]interface{}{
+		"edges":      edges,
+		"nodes":      nodes,
+		"pageInfo":   pageInfo,
+		"totalCount": result.Total,
+	}, nil
+}
+
+func (r *resolver) createUser(p graphql.ResolveParams) (interface{}, error) {
+	input, _ := p.Args["input"].(map[string]interface{})
+	req := entity.CreateUserRequest{}
+	req.Name, _ = input["name"].(string)
+	req.Email, _ = input["email"].(string)
+	req.DateOfBirth, _ = input["dateOfBirth"].(string)
+	req.Phone, _ = input["phone"].(string)
+	req.Address, _ = input["address"].(string)
+	if attributes, ok := input["attributes"]; ok {
+		var err error
+		if req.Attributes, err = attributeMap(attributes); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.validator.Struct(req); err != nil {
+		return nil, validationError("input", err)
+	}
+
+	user, err := r.users.CreateUser(p.Context, req)
+	if err != nil {
+		return nil, userError(p.Context, r.logger, err, "failed to create user")
+	}
+	return user, nil
+}
+
+func (r *resolver) updateUser(p graphql.ResolveParams) (interface{}, error) {
+	id, err := userID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	input, _ := p.Args["input"].(map[string]interface{})
+	req := entity.UpdateUserRequest{
+		Name:        optionalArg(input, "name"),
+		Email:       optionalArg(input, "email"),
+		DateOfBirth: optionalArg(input, "dateOfBirth"),
+		Phone:       optionalArg(input, "phone"),
+		Address:     optionalArg(input, "address"),
+	}
+	if attributes, ok := input["attributes"]; ok {
+		if req.Attributes, err = attributeMap(attributes); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.validator.Struct(req); err != nil {
+		return nil, validationError("input", err)
+	}
+
+	user, err := r.users.UpdateUser(p.Context, id, req)
+	if err != nil {
+		return nil, userError(p.Context, r.logger, err, "failed to update user")
+	}
+	return user, nil
+}
+
+func (r *resolver) deleteUser(p graphql.ResolveParams) (interface{}, error) {
+	id, err := userID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	if err := r.users.DeleteUser(p.Context, id); err != nil {
+		return nil, userError(p.Context, r.logger, err, "failed to delete user")
+	}
+	return p.Args["id"], nil
+}
+
+func (r *resolver) changeUserStatus(p graphql.ResolveParams) (interface{}, error) {
+	id, err := userID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	req := entity.ChangeUserStatusRequest{}
+	req.Reason, _ = p.Args["reason"].(string)
+	if err := r.validator.Struct(req); err != nil {
+		return nil, validationError("", err)
+	}
+	status, _ := p.Args["status"].(string)
+
+	user, err := r.users.ChangeUserStatus(p.Context, id, entity.UserStatus(status), req.Reason)
+	if err != nil {
+		return nil, userError(p.Context, r.logger, err, "failed to change user status")
+	}
+	return user, nil
+}
+
+// userID parses an ID argument, which must fit the ID column like the HTTP API's path parameter
+func userID(arg interface{}) (uint, error) {
+	raw, _ := arg.(string)
+	id, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil || id == 0 {
+		return 0, &Error{Code: problem.CodeInvalidParameter, Message: "invalid user ID", Fields: map[string]string{"id": "Invalid user ID"}}
+	}
+	return uint(id), nil
+}
+
+// cursorPrefix marks cursors, which are the base64 encoded offset of an edge
+const cursorPrefix = "offset:"
+
+func encodeCursor(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(string(raw), cursorPrefix))
+	if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) || n < 0 {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	return n, nil
+}
+
+func attributeMap(value interface{}) (map[string]interface{}, error) {
+	attributes, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, &Error{Code: problem.CodeValidationFailed, Message: "invalid input.attributes", Fields: map[string]string{"input.attributes": "Must be an object"}}
+	}
+	return attributes, nil
+}
+
+func optionalArg(input map[string]interface{}, name string) *string {
+	value, ok := input[name].(string)
+	if !ok {
+		return nil
+	}
+	return &value
+}
+
+func stringList(value interface{}) []string {
+	items, _ := value.([]interface{})
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+func resolveUser(get func(u *entity.User) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		user, ok := p.Source.(*entity.User)
+		if !ok {
+			return nil, nil
+		}
+		return get(user), nil
+	}
+}
+
+func userField(typ graphql.Output, get func(u *entity.User) interface{}) *graphql.Field {
+	return &graphql.Field{Type: typ, Resolve: resolveUser(get)}
+}
+
+func tagField(typ graphql.Output, get func(t entity.Tag) interface{}) *graphql.Field {
+	return &graphql.Field{Type: typ, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+		tag, ok := p.Source.(entity.Tag)
+		if !ok {
+			return nil, nil
+		}
+		return get(tag), nil
+	}}
+}
+
+func dateOfBirth(u *entity.User) interface{} {
+	if u.PIIRedacted {
+		return u.DateOfBirth.Format(entity.RedactedDateLayout)
+	}
+	return u.DateOfBirth.Format("2006-01-02")
+}
+
+func optionalString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func optionalTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Format(time.RFC3339)
+}
+
+func optionalAttributes(attributes entity.Attributes) interface{} {
+	if len(attributes) == 0 {
+		return nil
+	}
+	return map[string]interface{}(attributes)
+}
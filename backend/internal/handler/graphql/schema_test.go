@@ -0,0 +1,381 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/graphql-go/graphql"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockUserService is a mock implementation of the UserService interface
+type MockUserService struct {
+	mock.Mock
+}
+
+func (m *MockUserService) CreateUser(ctx context.Context, req entity.CreateUserRequest) (*entity.User, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserService) GetUser(ctx context.Context, id uint) (*entity.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserService) GetUserProjected(ctx context.Context, id uint, projection entity.UserProjection) (*entity.User, error) {
+	args := m.Called(ctx, id, projection)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserService) ListUsers(ctx context.Context, params entity.UserSearchParams) (*entity.UserListResponse, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.UserListResponse), args.Error(1)
+}
+
+func (m *MockUserService) UpdateUser(ctx context.Context, id uint, req entity.UpdateUserRequest) (*entity.User, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserService) PatchUser(ctx context.Context, id uint, patchType service.PatchType, patch []byte, validate func(entity.UpdateUserRequest) error) (*entity.User, error) {
+	args := m.Called(ctx, id, patchType, patch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserService) DeleteUser(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserService) ChangeUserStatus(ctx context.Context, id uint, status entity.UserStatus, reason string) (*entity.User, error) {
+	args := m.Called(ctx, id, status, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func setupSchema(t *testing.T) (graphql.Schema, *MockUserService) {
+	mockService := &MockUserService{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel) // Reduce noise in tests
+	schema, err := newSchema(&resolver{users: mockService, validator: validator.New(), logger: logger})
+	require.NoError(t, err)
+	return schema, mockService
+}
+
+// run executes query and returns its data as decoded JSON, along with its errors
+func run(t *testing.T, schema graphql.Schema, query string, variables map[string]interface{}) (map[string]interface{}, *graphql.Result) {
+	t.Helper()
+	result := graphql.Do(graphql.Params{Schema: schema, RequestString: query, VariableValues: variables, Context: context.Background()})
+	body, err := json.Marshal(result.Data)
+	require.NoError(t, err)
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &data))
+	return data, result
+}
+
+func testUser(id uint) entity.User {
+	return entity.User{
+		ID:          id,
+		Name:        "Test User",
+		Email:       "test@example.com",
+		DateOfBirth: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+		Age:         35,
+		Status:      entity.UserStatusActive,
+		Attributes:  entity.Attributes{"department": "sales"},
+		Tags:        []entity.Tag{{ID: 2, Name: "vip"}},
+		CreatedAt:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func pageOf(total int64, ids ...uint) *entity.UserListResponse {
+	users := make([]entity.User, 0, len(ids))
+	for _, id := range ids {
+		users = append(users, testUser(id))
+	}
+	return &entity.UserListResponse{Users: users, Total: total}
+}
+
+func TestUserQuery(t *testing.T) {
+	t.Run("Resolves the user with its computed age", func(t *testing.T) {
+		schema, mockService := setupSchema(t)
+		user := testUser(1)
+		mockService.On("GetUser", mock.Anything, uint(1)).Return(&user, nil)
+
+		data, result := run(t, schema, `{ user(id: "1") { id name age dateOfBirth phone status attributes tags { id name } createdAt } }`, nil)
+
+		require.Empty(t, result.Errors)
+		assert.Equal(t, map[string]interface{}{
+			"id":          "1",
+			"name":        "Test User",
+			"age":         float64(35),
+			"dateOfBirth": "1990-05-17",
+			"phone":       nil,
+			"status":      "ACTIVE",
+			"attributes":  map[string]interface{}{"department": "sales"},
+			"tags":        []interface{}{map[string]interface{}{"id": "2", "name": "vip"}},
+			"createdAt":   "2024-01-02T03:04:05Z",
+		}, data["user"])
+	})
+
+	t.Run("Masks the date of birth of redacted users", func(t *testing.T) {
+		schema, mockService := setupSchema(t)
+		user := testUser(1)
+		user.PIIRedacted = true
+		mockService.On("GetUser", mock.Anything, uint(1)).Return(&user, nil)
+
+		data, _ := run(t, schema, `{ user(id: "1") { dateOfBirth piiRedacted } }`, nil)
+
+		assert.Equal(t, map[string]interface{}{"dateOfBirth": "1990-**-**", "piiRedacted": true}, data["user"])
+	})
+
+	t.Run("Reports missing users with their problem code", func(t *testing.T) {
+		schema, mockService := setupSchema(t)
+		mockService.On("GetUser", mock.Anything, uint(9)).Return(nil, errors.New("user not found"))
+
+		data, result := run(t, schema, `{ user(id: "9") { id } }`, nil)
+
+		assert.Nil(t, data["user"])
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "user not found", result.Errors[0].Message)
+		assert.Equal(t, "USER_NOT_FOUND", result.Errors[0].Extensions["code"])
+	})
+
+	t.Run("Rejects IDs that aren't user IDs", func(t *testing.T) {
+		schema, _ := setupSchema(t)
+
+		_, result := run(t, schema, `{ user(id: "abc") { id } }`, nil)
+
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "INVALID_PARAMETER", result.Errors[0].Extensions["code"])
+	})
+}
+
+func TestUsersQuery(t *testing.T) {
+	t.Run("Maps filter and sort onto the search parameters", func(t *testing.T) {
+		schema, mockService := setupSchema(t)
+		mockService.On("ListUsers", mock.Anything, entity.UserSearchParams{
+			Search:     "ann",
+			Page:       1,
+			PerPage:    2,
+			SortBy:     "name",
+			SortDir:    "asc",
+			Status:     "all",
+			Tags:       "vip,beta",
+			TagMatch:   "all",
+			Group:      "ops",
+			GroupMatch: "any",
+			Attributes: map[string]string{"team": "red"},
+		}).Return(pageOf(3, 1, 2), nil)
+
+		data, result := run(t, schema, `{
+			users(first: 2, sort: {field: NAME, direction: ASC}, filter: {
+				search: "ann", status: ALL, tags: ["vip", "beta"], tagMatch: ALL, groups: ["ops"],
+				attributes: [{key: "team", value: "red"}]
+			}) {
+				totalCount
+				nodes { id }
+				edges { cursor }
+				pageInfo { hasNextPage hasPreviousPage startCursor endCursor }
+			}
+		}`, nil)
+
+		require.Empty(t, result.Errors)
+		assert.Equal(t, map[string]interface{}{
+			"totalCount": float64(3),
+			"nodes":      []interface{}{map[string]interface{}{"id": "1"}, map[string]interface{}{"id": "2"}},
+			"edges":      []interface{}{map[string]interface{}{"cursor": encodeCursor(0)}, map[string]interface{}{"cursor": encodeCursor(1)}},
+			"pageInfo": map[string]interface{}{
+				"hasNextPage":     true,
+				"hasPreviousPage": false,
+				"startCursor":     encodeCursor(0),
+				"endCursor":       encodeCursor(1),
+			},
+		}, data["users"])
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Uses the defaults of the REST API", func(t *testing.T) {
+		schema, mockService := setupSchema(t)
+		mockService.On("ListUsers", mock.Anything, entity.UserSearchParams{Page: 1, PerPage: 10, SortBy: "created_at", SortDir: "desc"}).Return(pageOf(0), nil)
+
+		data, result := run(t, schema, `{ users { totalCount pageInfo { hasNextPage startCursor } } }`, nil)
+
+		require.Empty(t, result.Errors)
+		assert.Equal(t, map[string]interface{}{
+			"totalCount": float64(0),
+			"pageInfo":   map[string]interface{}{"hasNextPage": false, "startCursor": nil},
+		}, data["users"])
+	})
+
+	t.Run("Continues after the cursor on the next page", func(t *testing.T) {
+		schema, mockService := setupSchema(t)
+		mockService.On("ListUsers", mock.Anything, mock.MatchedBy(func(p entity.UserSearchParams) bool {
+			return p.Page == 2 && p.PerPage == 2
+		})).Return(pageOf(4, 3, 4), nil)
+
+		data, result := run(t, schema, `query($after: String) { users(first: 2, after: $after) { nodes { id } pageInfo { hasNextPage hasPreviousPage } } }`,
+			map[string]interface{}{"after": encodeCursor(1)})
+
+		require.Empty(t, result.Errors)
+		assert.Equal(t, map[string]interface{}{
+			"nodes":    []interface{}{map[string]interface{}{"id": "3"}, map[string]interface{}{"id": "4"}},
+			"pageInfo": map[string]interface{}{"hasNextPage": false, "hasPreviousPage": true},
+		}, data["users"])
+	})
+
+	t.Run("Spans two pages for a cursor inside a page", func(t *testing.T) {
+		schema, mockService := setupSchema(t)
+		mockService.On("ListUsers", mock.Anything, mock.MatchedBy(func(p entity.UserSearchParams) bool { return p.Page == 1 })).Return(pageOf(5, 1, 2, 3), nil)
+		mockService.On("ListUsers", mock.Anything, mock.MatchedBy(func(p entity.UserSearchParams) bool { return p.Page == 2 })).Return(pageOf(5, 4, 5), nil)
+
+		data, result := run(t, schema, `query($after: String) { users(first: 3, after: $after) { edges { cursor node { id } } } }`,
+			map[string]interface{}{"after": encodeCursor(0)})
+
+		require.Empty(t, result.Errors)
+		assert.Equal(t, map[string]interface{}{
+			"edges": []interface{}{
+				map[string]interface{}{"cursor": encodeCursor(1), "node": map[string]interface{}{"id": "2"}},
+				map[string]interface{}{"cursor": encodeCursor(2), "node": map[string]interface{}{"id": "3"}},
+				map[string]interface{}{"cursor": encodeCursor(3), "node": map[string]interface{}{"id": "4"}},
+			},
+		}, data["users"])
+	})
+
+	t.Run("Rejects page sizes out of range and bad cursors", func(t *testing.T) {
+		schema, _ := setupSchema(t)
+
+		_, result := run(t, schema, `{ users(first: 101) { totalCount } }`, nil)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "VALIDATION_FAILED", result.Errors[0].Extensions["code"])
+
+		_, result = run(t, schema, `{ users(after: "bm90IGEgY3Vyc29y") { totalCount } }`, nil)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "INVALID_PARAMETER", result.Errors[0].Extensions["code"])
+	})
+}
+
+func TestUserMutations(t *testing.T) {
+	t.Run("Creates users", func(t *testing.T) {
+		schema, mockService := setupSchema(t)
+		user := testUser(7)
+		mockService.On("CreateUser", mock.Anything, entity.CreateUserRequest{
+			Name:        "Test User",
+			Email:       "test@example.com",
+			DateOfBirth: "1990-05-17",
+			Attributes:  map[string]interface{}{"department": "sales", "level": float64(3)},
+		}).Return(&user, nil)
+
+		data, result := run(t, schema, `mutation {
+			createUser(input: {name: "Test User", email: "test@example.com", dateOfBirth: "1990-05-17", attributes: {department: "sales", level: 3}}) { id age }
+		}`, nil)
+
+		require.Empty(t, result.Errors)
+		assert.Equal(t, map[string]interface{}{"id": "7", "age": float64(35)}, data["createUser"])
+	})
+
+	t.Run("Validates the input before creating", func(t *testing.T) {
+		schema, mockService := setupSchema(t)
+
+		_, result := run(t, schema, `mutation { createUser(input: {name: "Test User", email: "nope", dateOfBirth: "1990-05-17"}) { id } }`, nil)
+
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "VALIDATION_FAILED", result.Errors[0].Extensions["code"])
+		assert.Equal(t, map[string]string{"input.email": "email validation failed"}, result.Errors[0].Extensions["fields"])
+		mockService.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Reports taken email addresses on the field", func(t *testing.T) {
+		schema, mockService := setupSchema(t)
+		mockService.On("CreateUser", mock.Anything, mock.Anything).Return(nil, errors.New("email already exists"))
+
+		_, result := run(t, schema, `mutation { createUser(input: {name: "Test User", email: "test@example.com", dateOfBirth: "1990-05-17"}) { id } }`, nil)
+
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "USER_EMAIL_TAKEN", result.Errors[0].Extensions["code"])
+	})
+
+	t.Run("Updates only the given fields", func(t *testing.T) {
+		schema, mockService := setupSchema(t)
+		user := testUser(7)
+		name := "New Name"
+		mockService.On("UpdateUser", mock.Anything, uint(7), entity.UpdateUserRequest{Name: &name}).Return(&user, nil)
+
+		_, result := run(t, schema, `mutation { updateUser(id: "7", input: {name: "New Name"}) { id } }`, nil)
+
+		require.Empty(t, result.Errors)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Deletes users", func(t *testing.T) {
+		schema, mockService := setupSchema(t)
+		mockService.On("DeleteUser", mock.Anything, uint(7)).Return(nil)
+
+		data, result := run(t, schema, `mutation { deleteUser(id: "7") }`, nil)
+
+		require.Empty(t, result.Errors)
+		assert.Equal(t, "7", data["deleteUser"])
+	})
+
+	t.Run("Changes the status with a reason", func(t *testing.T) {
+		schema, mockService := setupSchema(t)
+		user := testUser(7)
+		user.Status = entity.UserStatusSuspended
+		mockService.On("ChangeUserStatus", mock.Anything, uint(7), entity.UserStatusSuspended, "abuse").Return(&user, nil)
+
+		data, result := run(t, schema, `mutation { changeUserStatus(id: "7", status: SUSPENDED, reason: "abuse") { status } }`, nil)
+
+		require.Empty(t, result.Errors)
+		assert.Equal(t, map[string]interface{}{"status": "SUSPENDED"}, data["changeUserStatus"])
+	})
+
+	t.Run("Requires a reason for status changes", func(t *testing.T) {
+		schema, _ := setupSchema(t)
+
+		_, result := run(t, schema, `mutation { changeUserStatus(id: "7", status: SUSPENDED, reason: "") { status } }`, nil)
+
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, map[string]string{"reason": "required validation failed"}, result.Errors[0].Extensions["fields"])
+	})
+}
+
+func TestDecodeCursor(t *testing.T) {
+	n, err := decodeCursor(encodeCursor(42))
+	require.NoError(t, err)
+	assert.Equal(t, 42, n)
+
+	for _, cursor := range []string{"", "!!", encodeCursor(-1), "b2Zmc2V0Og=="} {
+		_, err := decodeCursor(cursor)
+		assert.Error(t, err, cursor)
+	}
+}
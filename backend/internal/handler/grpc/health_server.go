@@ -0,0 +1,81 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	userv1 "arritech-user-management/api/user/v1"
+	"arritech-user-management/pkg/health"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// HealthServer implements the standard gRPC health service with the readiness checks of the
+// HTTP /readyz probe. The server as a whole ("") and the user service report the same status.
+type HealthServer struct {
+	healthpb.UnimplementedHealthServer
+	health       *health.Health
+	pollInterval time.Duration
+	logger       *logrus.Logger
+}
+
+// NewHealthServer creates the health service; Watch runs the checks every pollInterval
+func NewHealthServer(health *health.Health, pollInterval time.Duration, logger *logrus.Logger) *HealthServer {
+	return &HealthServer{
+		health:       health,
+		pollInterval: pollInterval,
+		logger:       logger,
+	}
+}
+
+func (s *HealthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if !knownService(req.GetService()) {
+		return nil, status.Error(codes.NotFound, "unknown service")
+	}
+	return &healthpb.HealthCheckResponse{Status: s.status(ctx)}, nil
+}
+
+// Watch sends the current status and then every change, as the health protocol asks; unknown
+// services are reported as SERVICE_UNKNOWN rather than failing the call
+func (s *HealthServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx := stream.Context()
+	if !knownService(req.GetService()) {
+		if err := stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVICE_UNKNOWN}); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		return status.FromContextError(ctx.Err()).Err()
+	}
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	last := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		if current := s.status(ctx); current != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: current}); err != nil {
+				return err
+			}
+			last = current
+		}
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *HealthServer) status(ctx context.Context) healthpb.HealthCheckResponse_ServingStatus {
+	report := s.health.Ready(ctx)
+	if !report.Ready() {
+		s.logger.WithField("report", report).Warn("Readiness check failed")
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	return healthpb.HealthCheckResponse_SERVING
+}
+
+func knownService(name string) bool {
+	return name == "" || name == userv1.UserService_ServiceDesc.ServiceName
+}
@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"arritech-user-management/pkg/health"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func setupHealthClient(t *testing.T, checks *health.Health) healthpb.HealthClient {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	server := NewHealthServer(checks, 10*time.Millisecond, logger)
+	conn := startServer(t, func(s *grpc.Server) { healthpb.RegisterHealthServer(s, server) })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestHealthServer_Check(t *testing.T) {
+	t.Run("Serving when the checks pass", func(t *testing.T) {
+		checks := health.New(time.Second)
+		checks.Register("database", health.CheckerFunc(func(context.Context) error { return nil }))
+		client := setupHealthClient(t, checks)
+
+		for _, service := range []string{"", "user.v1.UserService"} {
+			resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+
+			require.NoError(t, err)
+			assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+		}
+	})
+
+	t.Run("Not serving when a required check fails", func(t *testing.T) {
+		checks := health.New(time.Second)
+		checks.Register("database", health.CheckerFunc(func(context.Context) error { return errors.New("down") }))
+		client := setupHealthClient(t, checks)
+
+		resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+		require.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.GetStatus())
+	})
+
+	t.Run("Unknown services", func(t *testing.T) {
+		client := setupHealthClient(t, health.New(time.Second))
+
+		_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "other.Service"})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestHealthServer_Watch(t *testing.T) {
+	checks := health.New(time.Second)
+	client := setupHealthClient(t, checks)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+
+	checks.Drain()
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.GetStatus())
+}
@@ -0,0 +1,238 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/logger"
+	"arritech-user-management/pkg/middleware"
+	"arritech-user-management/pkg/tenant"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata keys matching the HTTP headers of the same name; gRPC metadata keys are lower case
+const (
+	authorizationKey = "authorization"
+	apiKeyKey        = "x-api-key"
+	organizationKey  = "x-organization"
+	requestIDKey     = "x-request-id"
+)
+
+// DefaultPublicMethods are callable without credentials: the health and reflection services.
+// A trailing "*" matches any suffix.
+var DefaultPublicMethods = []string{
+	"/grpc.health.v1.Health/*",
+	"/grpc.reflection.v1.ServerReflection/*",
+	"/grpc.reflection.v1alpha.ServerReflection/*",
+}
+
+// Step prepares the context of a call, e.g. by authenticating the caller, or rejects the call
+// with a status error. Steps are the gRPC counterparts of the HTTP middleware.
+type Step func(ctx context.Context, method string) (context.Context, error)
+
+// UnaryInterceptor runs steps in order before every unary call
+func UnaryInterceptor(steps ...Step) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := runSteps(ctx, info.FullMethod, steps)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor runs steps in order before every streaming call
+func StreamInterceptor(steps ...Step) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := runSteps(ss.Context(), info.FullMethod, steps)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func runSteps(ctx context.Context, method string, steps []Step) (context.Context, error) {
+	for _, step := range steps {
+		var err error
+		if ctx, err = step(ctx, method); err != nil {
+			return nil, err
+		}
+	}
+	return ctx, nil
+}
+
+// serverStream replaces the context of a stream with the one prepared by the steps
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// APIKeyStep authenticates calls that carry x-api-key metadata, like APIKeyMiddleware. Calls
+// without it pass through to the bearer token check.
+func APIKeyStep(authenticate middleware.APIKeyAuthenticator) Step {
+	return func(ctx context.Context, _ string) (context.Context, error) {
+		key := metadataValue(ctx, apiKeyKey)
+		if key == "" {
+			return ctx, nil
+		}
+
+		claims, err := authenticate(ctx, key)
+		if err != nil {
+			switch {
+			case errors.Is(err, auth.ErrExpiredToken):
+				return nil, status.Error(codes.Unauthenticated, "API key expired")
+			case errors.Is(err, auth.ErrInvalidToken):
+				return nil, status.Error(codes.Unauthenticated, "invalid API key")
+			}
+			logger.FromContext(ctx, logrus.StandardLogger()).WithError(err).Error("Failed to verify API key")
+			return nil, status.Error(codes.Internal, "failed to verify API key")
+		}
+		return withClaims(ctx, claims), nil
+	}
+}
+
+// AuthStep requires a valid bearer token in the authorization metadata on every method except
+// publicMethods, like AuthMiddleware. Calls already authenticated with an API key pass.
+func AuthStep(verifier middleware.TokenVerifier, publicMethods []string) Step {
+	return func(ctx context.Context, method string) (context.Context, error) {
+		if isPublicMethod(method, publicMethods) {
+			return ctx, nil
+		}
+		if _, ok := auth.FromContext(ctx); ok {
+			return ctx, nil
+		}
+
+		scheme, token, found := strings.Cut(metadataValue(ctx, authorizationKey), " ")
+		token = strings.TrimSpace(token)
+		if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			return nil, status.Error(codes.Unauthenticated, "authentication required")
+		}
+
+		claims, err := verifier.Verify(token)
+		if err != nil {
+			if errors.Is(err, auth.ErrExpiredToken) {
+				return nil, status.Error(codes.Unauthenticated, "token expired")
+			}
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		return withClaims(ctx, claims), nil
+	}
+}
+
+// TenantStep places the organization of the call in its context, like TenantMiddleware: the
+// organization bound to the credentials, the one named by the x-organization metadata when the
+// caller may choose, or defaultRef. Public methods don't act on an organization.
+func TenantStep(resolve middleware.OrganizationResolver, policy *auth.Policy, defaultRef string, publicMethods []string) Step {
+	return func(ctx context.Context, method string) (context.Context, error) {
+		if isPublicMethod(method, publicMethods) {
+			return ctx, nil
+		}
+
+		id, err := middleware.SelectOrganization(ctx, resolve, policy, metadataValue(ctx, organizationKey), defaultRef)
+		if err != nil {
+			switch {
+			case errors.Is(err, middleware.ErrOrganizationRequired):
+				return nil, status.Error(codes.InvalidArgument, "set the "+organizationKey+" metadata")
+			case errors.Is(err, middleware.ErrUnknownOrganization):
+				return nil, status.Error(codes.InvalidArgument, "unknown organization")
+			case errors.Is(err, middleware.ErrOrganizationForbidden):
+				return nil, status.Error(codes.PermissionDenied, "organization not allowed")
+			}
+			logger.FromContext(ctx, logrus.StandardLogger()).WithError(err).Error("Failed to resolve organization")
+			return nil, status.Error(codes.Internal, "failed to resolve organization")
+		}
+		return tenant.NewContext(ctx, id), nil
+	}
+}
+
+// UnaryLoggingInterceptor gives every unary call a request ID and request-scoped log entry, like
+// RequestIDMiddleware, logs the call and turns panics into INTERNAL errors
+func UnaryLoggingInterceptor(log *logrus.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		ctx = withRequestLog(ctx, log)
+		defer logCall(ctx, info.FullMethod, time.Now(), &err)
+		return handler(ctx, req)
+	}
+}
+
+// StreamLoggingInterceptor is the streaming counterpart of UnaryLoggingInterceptor
+func StreamLoggingInterceptor(log *logrus.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		ctx := withRequestLog(ss.Context(), log)
+		defer logCall(ctx, info.FullMethod, time.Now(), &err)
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// withRequestLog keeps a well-formed x-request-id or generates one, echoes it in the response
+// headers and returns a context with a log entry carrying it and the IDs of the call's span
+func withRequestLog(ctx context.Context, log *logrus.Logger) context.Context {
+	requestID := middleware.RequestID(metadataValue(ctx, requestIDKey))
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+
+	fields := logrus.Fields{"request_id": requestID}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields["trace_id"] = sc.TraceID().String()
+		fields["span_id"] = sc.SpanID().String()
+	}
+	return logger.NewContext(ctx, log.WithFields(fields))
+}
+
+// logCall logs a finished call. A panic is recovered and reported as INTERNAL, so that it
+// doesn't take down the server.
+func logCall(ctx context.Context, method string, start time.Time, err *error) {
+	entry := logger.FromContext(ctx, logrus.StandardLogger())
+	if r := recover(); r != nil {
+		entry.WithField("panic", r).Error("Recovered from panic in gRPC call")
+		*err = status.Error(codes.Internal, "internal error")
+	}
+	entry.WithFields(logrus.Fields{
+		"method":  method,
+		"code":    status.Code(*err).String(),
+		"latency": time.Since(start),
+	}).Info("gRPC Call")
+}
+
+func withClaims(ctx context.Context, claims *auth.Claims) context.Context {
+	ctx = auth.NewContext(ctx, claims)
+	if claims.OrganizationID != 0 {
+		ctx = tenant.NewContext(ctx, claims.OrganizationID)
+	}
+	return ctx
+}
+
+// metadataValue returns the first value of an incoming metadata key
+func metadataValue(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func isPublicMethod(method string, publicMethods []string) bool {
+	for _, public := range publicMethods {
+		if prefix, ok := strings.CutSuffix(public, "*"); ok {
+			if strings.HasPrefix(method, prefix) {
+				return true
+			}
+			continue
+		}
+		if method == public {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,245 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/logger"
+	"arritech-user-management/pkg/middleware"
+	"arritech-user-management/pkg/tenant"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const userMethod = "/user.v1.UserService/GetUser"
+
+type fakeVerifier struct{}
+
+func (fakeVerifier) Verify(token string) (*auth.Claims, error) {
+	switch token {
+	case "valid":
+		return &auth.Claims{Subject: "alice", OrganizationID: 4}, nil
+	case "expired":
+		return nil, auth.ErrExpiredToken
+	}
+	return nil, auth.ErrInvalidToken
+}
+
+func incoming(pairs ...string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+}
+
+func TestAuthStep(t *testing.T) {
+	step := AuthStep(fakeVerifier{}, DefaultPublicMethods)
+
+	t.Run("Accepts valid bearer tokens", func(t *testing.T) {
+		ctx, err := step(incoming("authorization", "Bearer valid"), userMethod)
+
+		require.NoError(t, err)
+		claims, ok := auth.FromContext(ctx)
+		require.True(t, ok)
+		assert.Equal(t, "alice", claims.Subject)
+		orgID, _ := tenant.FromContext(ctx)
+		assert.Equal(t, uint(4), orgID)
+	})
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		message string
+	}{
+		{name: "Missing token", ctx: incoming(), message: "authentication required"},
+		{name: "Other scheme", ctx: incoming("authorization", "Basic dXNlcg=="), message: "authentication required"},
+		{name: "Expired token", ctx: incoming("authorization", "Bearer expired"), message: "token expired"},
+		{name: "Invalid token", ctx: incoming("authorization", "Bearer forged"), message: "invalid token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := step(tt.ctx, userMethod)
+
+			assert.Equal(t, codes.Unauthenticated, status.Code(err))
+			assert.Equal(t, tt.message, status.Convert(err).Message())
+		})
+	}
+
+	t.Run("Lets public methods through", func(t *testing.T) {
+		_, err := step(incoming(), "/grpc.health.v1.Health/Check")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Lets callers authenticated with an API key through", func(t *testing.T) {
+		ctx := auth.NewContext(incoming(), &auth.Claims{Subject: "api-key:abc"})
+
+		_, err := step(ctx, userMethod)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestAPIKeyStep(t *testing.T) {
+	step := APIKeyStep(func(_ context.Context, key string) (*auth.Claims, error) {
+		switch key {
+		case "good":
+			return &auth.Claims{Subject: "api-key:good", OrganizationID: 2}, nil
+		case "old":
+			return nil, auth.ErrExpiredToken
+		case "broken":
+			return nil, errors.New("database down")
+		}
+		return nil, auth.ErrInvalidToken
+	})
+
+	t.Run("Authenticates API keys", func(t *testing.T) {
+		ctx, err := step(incoming("x-api-key", "good"), userMethod)
+
+		require.NoError(t, err)
+		claims, _ := auth.FromContext(ctx)
+		assert.Equal(t, "api-key:good", claims.Subject)
+	})
+
+	t.Run("Passes calls without a key", func(t *testing.T) {
+		ctx, err := step(incoming(), userMethod)
+
+		require.NoError(t, err)
+		_, ok := auth.FromContext(ctx)
+		assert.False(t, ok)
+	})
+
+	t.Run("Rejects bad keys", func(t *testing.T) {
+		_, err := step(incoming("x-api-key", "old"), userMethod)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		_, err = step(incoming("x-api-key", "unknown"), userMethod)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		_, err = step(incoming("x-api-key", "broken"), userMethod)
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestTenantStep(t *testing.T) {
+	resolve := func(_ context.Context, ref string) (uint, error) {
+		switch ref {
+		case "default":
+			return 1, nil
+		case "acme":
+			return 7, nil
+		}
+		return 0, middleware.ErrUnknownOrganization
+	}
+
+	t.Run("Resolves the organization metadata", func(t *testing.T) {
+		ctx, err := TenantStep(resolve, auth.DefaultPolicy(), "default", nil)(incoming("x-organization", "acme"), userMethod)
+
+		require.NoError(t, err)
+		orgID, _ := tenant.FromContext(ctx)
+		assert.Equal(t, uint(7), orgID)
+	})
+
+	t.Run("Falls back to the default organization", func(t *testing.T) {
+		ctx, err := TenantStep(resolve, auth.DefaultPolicy(), "default", nil)(incoming(), userMethod)
+
+		require.NoError(t, err)
+		orgID, _ := tenant.FromContext(ctx)
+		assert.Equal(t, uint(1), orgID)
+	})
+
+	t.Run("Keeps the organization of the credentials", func(t *testing.T) {
+		ctx, err := TenantStep(resolve, auth.DefaultPolicy(), "default", nil)(tenant.NewContext(incoming(), 3), userMethod)
+
+		require.NoError(t, err)
+		orgID, _ := tenant.FromContext(ctx)
+		assert.Equal(t, uint(3), orgID)
+	})
+
+	t.Run("Rejects other organizations than the credentials allow", func(t *testing.T) {
+		_, err := TenantStep(resolve, auth.DefaultPolicy(), "default", nil)(tenant.NewContext(incoming("x-organization", "acme"), 3), userMethod)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		unbound := auth.NewContext(incoming("x-organization", "acme"), &auth.Claims{Subject: "jane", Roles: []string{auth.RoleAdmin}})
+		_, err = TenantStep(resolve, auth.DefaultPolicy(), "default", nil)(unbound, userMethod)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("Rejects missing and unknown organizations", func(t *testing.T) {
+		_, err := TenantStep(resolve, auth.DefaultPolicy(), "", nil)(incoming(), userMethod)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = TenantStep(resolve, auth.DefaultPolicy(), "default", nil)(incoming("x-organization", "nope"), userMethod)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	reject := func(ctx context.Context, _ string) (context.Context, error) {
+		return nil, status.Error(codes.Unauthenticated, "no")
+	}
+	mark := func(ctx context.Context, _ string) (context.Context, error) {
+		return tenant.NewContext(ctx, 5), nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: userMethod}
+
+	t.Run("Runs the handler with the prepared context", func(t *testing.T) {
+		resp, err := UnaryInterceptor(mark)(context.Background(), nil, info, func(ctx context.Context, _ any) (any, error) {
+			orgID, _ := tenant.FromContext(ctx)
+			return orgID, nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, uint(5), resp)
+	})
+
+	t.Run("Stops at the first rejecting step", func(t *testing.T) {
+		called := false
+		_, err := UnaryInterceptor(reject, mark)(context.Background(), nil, info, func(context.Context, any) (any, error) {
+			called = true
+			return nil, nil
+		})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		assert.False(t, called)
+	})
+}
+
+func TestUnaryLoggingInterceptor(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	info := &grpc.UnaryServerInfo{FullMethod: userMethod}
+
+	t.Run("Logs calls with their request ID", func(t *testing.T) {
+		hook.Reset()
+		var entry *logrus.Entry
+		_, err := UnaryLoggingInterceptor(log)(incoming("x-request-id", "req-1"), nil, info, func(ctx context.Context, _ any) (any, error) {
+			entry = logger.FromContext(ctx, nil)
+			return nil, status.Error(codes.NotFound, "user not found")
+		})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		require.NotNil(t, entry)
+		assert.Equal(t, "req-1", entry.Data["request_id"])
+		last := hook.LastEntry()
+		require.NotNil(t, last)
+		assert.Equal(t, "req-1", last.Data["request_id"])
+		assert.Equal(t, "NotFound", last.Data["code"])
+		assert.Equal(t, userMethod, last.Data["method"])
+	})
+
+	t.Run("Recovers from panics", func(t *testing.T) {
+		hook.Reset()
+		_, err := UnaryLoggingInterceptor(log)(incoming(), nil, info, func(context.Context, any) (any, error) {
+			panic("boom")
+		})
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Equal(t, logrus.ErrorLevel, hook.AllEntries()[0].Level)
+	})
+}
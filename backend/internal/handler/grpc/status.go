@@ -0,0 +1,107 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/logger"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusError converts an error of the user service to a status with the code matching the HTTP
+// API's response: NOT_FOUND for 404, PERMISSION_DENIED for 403, INVALID_ARGUMENT for rejected
+// input, ALREADY_EXISTS for a taken email and FAILED_PRECONDITION for a disallowed status change.
+// Invalid fields are listed in a BadRequest detail. Other errors are logged and reported as
+// INTERNAL with the given message, so that their details don't reach the client.
+func statusError(ctx context.Context, log *logrus.Logger, err error, message string) error {
+	switch service.FailureOutcome(err) {
+	case service.OutcomeNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case service.OutcomeDenied:
+		var permErr *auth.PermissionError
+		errors.As(err, &permErr)
+		return status.Error(codes.PermissionDenied, "missing permission "+permErr.Permission)
+	case service.OutcomeEmailTaken:
+		return withViolations(codes.AlreadyExists, err.Error(), fieldViolation("email", "Another user has this email address"))
+	case service.OutcomeRejectedUnderage, service.OutcomeRejectedEmailDomain:
+		var policyErr *service.PolicyError
+		errors.As(err, &policyErr)
+		field := "email"
+		if policyErr.Rule == service.PolicyMinimumAge {
+			field = "date_of_birth"
+		}
+		return withViolations(codes.InvalidArgument, policyErr.Message, fieldViolation(field, policyErr.Message))
+	case service.OutcomeInvalid:
+		var attrErr *service.AttributeValidationError
+		if !errors.As(err, &attrErr) {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		keys := make([]string, 0, len(attrErr.Fields))
+		for key := range attrErr.Fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(keys))
+		for _, key := range keys {
+			violations = append(violations, fieldViolation("attributes."+key, attrErr.Fields[key]))
+		}
+		return withViolations(codes.InvalidArgument, "invalid attributes", violations...)
+	case service.OutcomeInvalidTransition:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case service.OutcomeInvalidPatch:
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	logger.FromContext(ctx, log).WithError(err).Error(message)
+	return status.Error(codes.Internal, message)
+}
+
+// validationStatus reports the request fields that failed validation under their proto names
+func validationStatus(err error) error {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		violations = append(violations, fieldViolation(protoFieldName(fieldErr.Field()), fieldErr.Tag()+" validation failed"))
+	}
+	return withViolations(codes.InvalidArgument, "request validation failed", violations...)
+}
+
+// protoFieldName converts the name of a request struct field, e.g. DateOfBirth, to the name of
+// the proto field it is read from, e.g. date_of_birth
+func protoFieldName(field string) string {
+	var b strings.Builder
+	for i, r := range field {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func fieldViolation(field, description string) *errdetails.BadRequest_FieldViolation {
+	return &errdetails.BadRequest_FieldViolation{Field: field, Description: description}
+}
+
+func withViolations(code codes.Code, message string, violations ...*errdetails.BadRequest_FieldViolation) error {
+	st := status.New(code, message)
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
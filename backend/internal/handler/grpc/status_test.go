@@ -0,0 +1,54 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/auth"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    codes.Code
+		message string
+		fields  []string
+	}{
+		{name: "Not found", err: errors.New("user not found"), code: codes.NotFound, message: "user not found"},
+		{name: "Permission", err: fmt.Errorf("wrapped: %w", &auth.PermissionError{Permission: auth.PermissionUsersWrite}), code: codes.PermissionDenied, message: "missing permission users:write"},
+		{name: "Email taken", err: errors.New("email already exists"), code: codes.AlreadyExists, message: "email already exists", fields: []string{"email"}},
+		{name: "Underage", err: &service.PolicyError{Rule: service.PolicyMinimumAge, Message: "users must be at least 18"}, code: codes.InvalidArgument, message: "users must be at least 18", fields: []string{"date_of_birth"}},
+		{name: "Email domain", err: &service.PolicyError{Rule: service.PolicyEmailDomain, Message: "email domain not allowed"}, code: codes.InvalidArgument, message: "email domain not allowed", fields: []string{"email"}},
+		{name: "Attributes", err: &service.AttributeValidationError{Fields: map[string]string{"team": "unknown", "age": "too low"}}, code: codes.InvalidArgument, message: "invalid attributes", fields: []string{"attributes.age", "attributes.team"}},
+		{name: "Date format", err: errors.New("invalid date of birth format, expected YYYY-MM-DD"), code: codes.InvalidArgument, message: "invalid date of birth format, expected YYYY-MM-DD"},
+		{name: "Transition", err: service.ErrInvalidStatusTransition, code: codes.FailedPrecondition, message: service.ErrInvalidStatusTransition.Error()},
+		{name: "Internal", err: errors.New("connection refused"), code: codes.Internal, message: "failed to do it"},
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := statusError(context.Background(), logger, tt.err, "failed to do it")
+
+			assert.Equal(t, tt.code, status.Code(err))
+			assert.Equal(t, tt.message, status.Convert(err).Message())
+			assert.Equal(t, tt.fields, badRequestFields(t, err))
+		})
+	}
+}
+
+func TestProtoFieldName(t *testing.T) {
+	assert.Equal(t, "name", protoFieldName("Name"))
+	assert.Equal(t, "date_of_birth", protoFieldName("DateOfBirth"))
+	assert.Equal(t, "per_page", protoFieldName("PerPage"))
+}
@@ -0,0 +1,293 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	userv1 "arritech-user-management/api/user/v1"
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/auth"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// UserWatcher streams the changes to the users of the organization carried by ctx
+type UserWatcher interface {
+	Watch(ctx context.Context) <-chan service.UserChange
+}
+
+// UserServer serves the gRPC user API on top of the same user service as the HTTP handlers
+type UserServer struct {
+	userv1.UnimplementedUserServiceServer
+	userService service.UserService
+	watcher     UserWatcher
+	authorizer  service.Authorizer
+	validator   *validator.Validate
+	logger      *logrus.Logger
+}
+
+// NewUserServer creates the gRPC user API. The authorizer checks that callers of WatchUsers may
+// read users before any change is streamed; a nil authorizer allows everyone, which matches
+// running without authentication.
+func NewUserServer(userService service.UserService, watcher UserWatcher, authorizer service.Authorizer, validator *validator.Validate, logger *logrus.Logger) *UserServer {
+	return &UserServer{
+		userService: userService,
+		watcher:     watcher,
+		authorizer:  authorizer,
+		validator:   validator,
+		logger:      logger,
+	}
+}
+
+func (s *UserServer) CreateUser(ctx context.Context, req *userv1.CreateUserRequest) (*userv1.CreateUserResponse, error) {
+	create := entity.CreateUserRequest{
+		Name:        req.GetName(),
+		Email:       req.GetEmail(),
+		DateOfBirth: req.GetDateOfBirth(),
+		Phone:       req.GetPhone(),
+		Address:     req.GetAddress(),
+	}
+	if req.Attributes != nil {
+		create.Attributes = req.Attributes.AsMap()
+	}
+	if err := s.validator.Struct(create); err != nil {
+		return nil, validationStatus(err)
+	}
+
+	user, err := s.userService.CreateUser(ctx, create)
+	if err != nil {
+		return nil, statusError(ctx, s.logger, err, "failed to create user")
+	}
+	pb, err := s.toProto(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.CreateUserResponse{User: pb}, nil
+}
+
+func (s *UserServer) GetUser(ctx context.Context, req *userv1.GetUserRequest) (*userv1.GetUserResponse, error) {
+	id, err := userID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userService.GetUser(ctx, id)
+	if err != nil {
+		return nil, statusError(ctx, s.logger, err, "failed to get user")
+	}
+	pb, err := s.toProto(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.GetUserResponse{User: pb}, nil
+}
+
+func (s *UserServer) UpdateUser(ctx context.Context, req *userv1.UpdateUserRequest) (*userv1.UpdateUserResponse, error) {
+	id, err := userID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	update := entity.UpdateUserRequest{
+		Name:        req.Name,
+		Email:       req.Email,
+		DateOfBirth: req.DateOfBirth,
+		Phone:       req.Phone,
+		Address:     req.Address,
+	}
+	if req.Attributes != nil {
+		update.Attributes = req.Attributes.AsMap()
+	}
+	if err := s.validator.Struct(update); err != nil {
+		return nil, validationStatus(err)
+	}
+
+	user, err := s.userService.UpdateUser(ctx, id, update)
+	if err != nil {
+		return nil, statusError(ctx, s.logger, err, "failed to update user")
+	}
+	pb, err := s.toProto(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.UpdateUserResponse{User: pb}, nil
+}
+
+func (s *UserServer) DeleteUser(ctx context.Context, req *userv1.DeleteUserRequest) (*userv1.DeleteUserResponse, error) {
+	id, err := userID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userService.DeleteUser(ctx, id); err != nil {
+		return nil, statusError(ctx, s.logger, err, "failed to delete user")
+	}
+	return &userv1.DeleteUserResponse{}, nil
+}
+
+// validSortFields are the fields users can be sorted on, as for GET /api/v1/users
+var validSortFields = map[string]bool{
+	"name": true, "email": true, "age": true, "phone": true,
+	"created_at": true, "updated_at": true,
+}
+
+func (s *UserServer) ListUsers(ctx context.Context, req *userv1.ListUsersRequest) (*userv1.ListUsersResponse, error) {
+	params := entity.UserSearchParams{
+		Search:     req.GetSearch(),
+		Page:       int(req.GetPage()),
+		PerPage:    int(req.GetPerPage()),
+		SortBy:     req.GetSortBy(),
+		SortDir:    req.GetSortDir(),
+		Status:     req.GetStatus(),
+		Tags:       strings.Join(req.GetTags(), ","),
+		TagMatch:   req.GetTagMatch(),
+		Group:      strings.Join(req.GetGroups(), ","),
+		GroupMatch: req.GetGroupMatch(),
+		Attributes: req.GetAttributes(),
+	}
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.PerPage == 0 {
+		params.PerPage = 10
+	}
+	if params.SortBy == "" {
+		params.SortBy = "created_at"
+	}
+	if params.SortDir == "" {
+		params.SortDir = "desc"
+	}
+	if !validSortFields[params.SortBy] {
+		return nil, withViolations(codes.InvalidArgument, "invalid sort field", fieldViolation("sort_by", "Invalid sort field"))
+	}
+	if err := s.validator.Struct(params); err != nil {
+		return nil, validationStatus(err)
+	}
+
+	result, err := s.userService.ListUsers(ctx, params)
+	if err != nil {
+		return nil, statusError(ctx, s.logger, err, "failed to list users")
+	}
+
+	resp := &userv1.ListUsersResponse{
+		Users:      make([]*userv1.User, 0, len(result.Users)),
+		Total:      result.Total,
+		Page:       int32(result.Page),
+		PerPage:    int32(result.PerPage),
+		TotalPages: int32(result.TotalPages),
+	}
+	for i := range result.Users {
+		pb, err := s.toProto(ctx, &result.Users[i])
+		if err != nil {
+			return nil, err
+		}
+		resp.Users = append(resp.Users, pb)
+	}
+	return resp, nil
+}
+
+// changeTypes maps the user lifecycle events to the change types of the API
+var changeTypes = map[entity.UserEventType]userv1.ChangeType{
+	entity.UserEventCreated:       userv1.ChangeType_CHANGE_TYPE_CREATED,
+	entity.UserEventUpdated:       userv1.ChangeType_CHANGE_TYPE_UPDATED,
+	entity.UserEventStatusChanged: userv1.ChangeType_CHANGE_TYPE_STATUS_CHANGED,
+	entity.UserEventDeleted:       userv1.ChangeType_CHANGE_TYPE_DELETED,
+}
+
+// WatchUsers streams the changes to the users of the caller's organization. Every changed user
+// is loaded again with the caller's context, so the caller's permissions and PII masking apply.
+func (s *UserServer) WatchUsers(_ *userv1.WatchUsersRequest, stream userv1.UserService_WatchUsersServer) error {
+	ctx := stream.Context()
+	if s.authorizer != nil {
+		if err := s.authorizer.Authorize(ctx, auth.PermissionUsersRead); err != nil {
+			return statusError(ctx, s.logger, err, "failed to watch users")
+		}
+	}
+
+	for change := range s.watcher.Watch(ctx) {
+		resp := &userv1.WatchUsersResponse{
+			Type:       changeTypes[change.Type],
+			UserId:     uint64(change.UserID),
+			OccurredAt: timestamppb.New(change.OccurredAt),
+		}
+		if change.Type != entity.UserEventDeleted {
+			user, err := s.userService.GetUser(ctx, change.UserID)
+			if err != nil {
+				if service.FailureOutcome(err) == service.OutcomeNotFound {
+					// Deleted since; the deletion follows on the stream
+					continue
+				}
+				return statusError(ctx, s.logger, err, "failed to watch users")
+			}
+			if resp.User, err = s.toProto(ctx, user); err != nil {
+				return err
+			}
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.ResourceExhausted, "watcher fell behind; reopen the stream")
+}
+
+// userID checks that a user ID fits the ID column, like the HTTP API's path parameter
+func userID(id uint64) (uint, error) {
+	if id == 0 || id > math.MaxUint32 {
+		return 0, withViolations(codes.InvalidArgument, "invalid user ID", fieldViolation("id", "Invalid user ID"))
+	}
+	return uint(id), nil
+}
+
+func (s *UserServer) toProto(ctx context.Context, user *entity.User) (*userv1.User, error) {
+	dateLayout := "2006-01-02"
+	if user.PIIRedacted {
+		dateLayout = entity.RedactedDateLayout
+	}
+	pb := &userv1.User{
+		Id:              uint64(user.ID),
+		OrganizationId:  uint64(user.OrganizationID),
+		Name:            user.Name,
+		Email:           user.Email,
+		EmailVerifiedAt: optionalTimestamp(user.EmailVerifiedAt),
+		DateOfBirth:     user.DateOfBirth.Format(dateLayout),
+		Age:             int32(user.Age),
+		Phone:           user.Phone,
+		Address:         user.Address,
+		AvatarUrl:       user.AvatarURL,
+		Status:          string(user.Status),
+		StatusReason:    user.StatusReason,
+		StatusChangedAt: optionalTimestamp(user.StatusChangedAt),
+		CreatedAt:       timestamppb.New(user.CreatedAt),
+		UpdatedAt:       timestamppb.New(user.UpdatedAt),
+		PiiRedacted:     user.PIIRedacted,
+	}
+	if len(user.Attributes) > 0 {
+		attributes, err := structpb.NewStruct(user.Attributes)
+		if err != nil {
+			return nil, statusError(ctx, s.logger, fmt.Errorf("failed to convert attributes of user %d: %w", user.ID, err), "failed to convert user")
+		}
+		pb.Attributes = attributes
+	}
+	for _, tag := range user.Tags {
+		pb.Tags = append(pb.Tags, &userv1.Tag{Id: uint64(tag.ID), Name: tag.Name, Color: tag.Color})
+	}
+	return pb, nil
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,363 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	userv1 "arritech-user-management/api/user/v1"
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/auth"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// MockUserService is a mock implementation of the UserService interface
+type MockUserService struct {
+	mock.Mock
+}
+
+func (m *MockUserService) CreateUser(ctx context.Context, req entity.CreateUserRequest) (*entity.User, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserService) GetUser(ctx context.Context, id uint) (*entity.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserService) GetUserProjected(ctx context.Context, id uint, projection entity.UserProjection) (*entity.User, error) {
+	args := m.Called(ctx, id, projection)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserService) ListUsers(ctx context.Context, params entity.UserSearchParams) (*entity.UserListResponse, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.UserListResponse), args.Error(1)
+}
+
+func (m *MockUserService) UpdateUser(ctx context.Context, id uint, req entity.UpdateUserRequest) (*entity.User, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserService) PatchUser(ctx context.Context, id uint, patchType service.PatchType, patch []byte, validate func(entity.UpdateUserRequest) error) (*entity.User, error) {
+	args := m.Called(ctx, id, patchType, patch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserService) DeleteUser(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserService) ChangeUserStatus(ctx context.Context, id uint, status entity.UserStatus, reason string) (*entity.User, error) {
+	args := m.Called(ctx, id, status, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+// fakeWatcher hands out a channel the test controls
+type fakeWatcher struct {
+	changes chan service.UserChange
+}
+
+func (w *fakeWatcher) Watch(context.Context) <-chan service.UserChange {
+	return w.changes
+}
+
+type denyAll struct{}
+
+func (denyAll) Authorize(_ context.Context, permission string) error {
+	return &auth.PermissionError{Permission: permission}
+}
+
+// startServer serves server over an in-memory connection and returns a client for it
+func startServer(t *testing.T, register func(*grpc.Server), opts ...grpc.ServerOption) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(opts...)
+	register(srv)
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func setupUserClient(t *testing.T, watcher UserWatcher, authorizer service.Authorizer) (userv1.UserServiceClient, *MockUserService) {
+	mockService := &MockUserService{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel) // Reduce noise in tests
+	server := NewUserServer(mockService, watcher, authorizer, validator.New(), logger)
+	conn := startServer(t, func(s *grpc.Server) { userv1.RegisterUserServiceServer(s, server) })
+	return userv1.NewUserServiceClient(conn), mockService
+}
+
+func testUser() *entity.User {
+	return &entity.User{
+		ID:          1,
+		Name:        "Test User",
+		Email:       "test@example.com",
+		DateOfBirth: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+		Age:         35,
+		Status:      entity.UserStatusActive,
+		Attributes:  entity.Attributes{"department": "sales"},
+		Tags:        []entity.Tag{{ID: 2, Name: "vip"}},
+	}
+}
+
+func badRequestFields(t *testing.T, err error) []string {
+	t.Helper()
+	var fields []string
+	for _, detail := range status.Convert(err).Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				fields = append(fields, violation.GetField())
+			}
+		}
+	}
+	return fields
+}
+
+func TestUserServer_CreateUser(t *testing.T) {
+	t.Run("Creates the user", func(t *testing.T) {
+		client, mockService := setupUserClient(t, nil, nil)
+		attributes, err := structpb.NewStruct(map[string]interface{}{"department": "sales"})
+		require.NoError(t, err)
+		mockService.On("CreateUser", mock.Anything, entity.CreateUserRequest{
+			Name:        "Test User",
+			Email:       "test@example.com",
+			DateOfBirth: "1990-05-17",
+			Attributes:  map[string]interface{}{"department": "sales"},
+		}).Return(testUser(), nil)
+
+		resp, err := client.CreateUser(context.Background(), &userv1.CreateUserRequest{
+			Name:        "Test User",
+			Email:       "test@example.com",
+			DateOfBirth: "1990-05-17",
+			Attributes:  attributes,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), resp.GetUser().GetId())
+		assert.Equal(t, "1990-05-17", resp.GetUser().GetDateOfBirth())
+		assert.Equal(t, "sales", resp.GetUser().GetAttributes().AsMap()["department"])
+		assert.Equal(t, "vip", resp.GetUser().GetTags()[0].GetName())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Rejects invalid fields", func(t *testing.T) {
+		client, mockService := setupUserClient(t, nil, nil)
+
+		_, err := client.CreateUser(context.Background(), &userv1.CreateUserRequest{Name: "T", Email: "invalid", DateOfBirth: "1990-05-17"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.ElementsMatch(t, []string{"name", "email"}, badRequestFields(t, err))
+		mockService.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Reports a taken email", func(t *testing.T) {
+		client, mockService := setupUserClient(t, nil, nil)
+		mockService.On("CreateUser", mock.Anything, mock.Anything).Return(nil, errors.New("email already exists"))
+
+		_, err := client.CreateUser(context.Background(), &userv1.CreateUserRequest{Name: "Test User", Email: "test@example.com", DateOfBirth: "1990-05-17"})
+
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+		assert.Equal(t, []string{"email"}, badRequestFields(t, err))
+	})
+}
+
+func TestUserServer_GetUser(t *testing.T) {
+	t.Run("Masks the date of birth of redacted users", func(t *testing.T) {
+		client, mockService := setupUserClient(t, nil, nil)
+		user := testUser()
+		user.RedactPII()
+		mockService.On("GetUser", mock.Anything, uint(1)).Return(user, nil)
+
+		resp, err := client.GetUser(context.Background(), &userv1.GetUserRequest{Id: 1})
+
+		require.NoError(t, err)
+		assert.Equal(t, "1990-**-**", resp.GetUser().GetDateOfBirth())
+		assert.True(t, resp.GetUser().GetPiiRedacted())
+	})
+
+	t.Run("Reports missing users", func(t *testing.T) {
+		client, mockService := setupUserClient(t, nil, nil)
+		mockService.On("GetUser", mock.Anything, uint(9)).Return(nil, errors.New("user not found"))
+
+		_, err := client.GetUser(context.Background(), &userv1.GetUserRequest{Id: 9})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("Rejects IDs out of range", func(t *testing.T) {
+		client, _ := setupUserClient(t, nil, nil)
+
+		_, err := client.GetUser(context.Background(), &userv1.GetUserRequest{Id: 1 << 40})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, []string{"id"}, badRequestFields(t, err))
+	})
+
+	t.Run("Hides internal errors", func(t *testing.T) {
+		client, mockService := setupUserClient(t, nil, nil)
+		mockService.On("GetUser", mock.Anything, uint(1)).Return(nil, errors.New("connection refused"))
+
+		_, err := client.GetUser(context.Background(), &userv1.GetUserRequest{Id: 1})
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Equal(t, "failed to get user", status.Convert(err).Message())
+	})
+}
+
+func TestUserServer_UpdateUser(t *testing.T) {
+	client, mockService := setupUserClient(t, nil, nil)
+	name := "New Name"
+	mockService.On("UpdateUser", mock.Anything, uint(1), entity.UpdateUserRequest{Name: &name}).Return(testUser(), nil)
+
+	_, err := client.UpdateUser(context.Background(), &userv1.UpdateUserRequest{Id: 1, Name: proto.String(name)})
+
+	require.NoError(t, err)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserServer_DeleteUser(t *testing.T) {
+	client, mockService := setupUserClient(t, nil, nil)
+	mockService.On("DeleteUser", mock.Anything, uint(1)).Return(&auth.PermissionError{Permission: auth.PermissionUsersDelete})
+
+	_, err := client.DeleteUser(context.Background(), &userv1.DeleteUserRequest{Id: 1})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, "missing permission "+auth.PermissionUsersDelete, status.Convert(err).Message())
+}
+
+func TestUserServer_ListUsers(t *testing.T) {
+	t.Run("Applies the defaults of the HTTP API", func(t *testing.T) {
+		client, mockService := setupUserClient(t, nil, nil)
+		mockService.On("ListUsers", mock.Anything, entity.UserSearchParams{
+			Page:       1,
+			PerPage:    10,
+			SortBy:     "created_at",
+			SortDir:    "desc",
+			Tags:       "vip,beta",
+			Attributes: map[string]string{"department": "sales"},
+		}).Return(&entity.UserListResponse{Users: []entity.User{*testUser()}, Total: 1, Page: 1, PerPage: 10, TotalPages: 1}, nil)
+
+		resp, err := client.ListUsers(context.Background(), &userv1.ListUsersRequest{
+			Tags:       []string{"vip", "beta"},
+			Attributes: map[string]string{"department": "sales"},
+		})
+
+		require.NoError(t, err)
+		assert.Len(t, resp.GetUsers(), 1)
+		assert.Equal(t, int64(1), resp.GetTotal())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Rejects unknown sort fields", func(t *testing.T) {
+		client, _ := setupUserClient(t, nil, nil)
+
+		_, err := client.ListUsers(context.Background(), &userv1.ListUsersRequest{SortBy: "password"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, []string{"sort_by"}, badRequestFields(t, err))
+	})
+
+	t.Run("Rejects invalid parameters", func(t *testing.T) {
+		client, _ := setupUserClient(t, nil, nil)
+
+		_, err := client.ListUsers(context.Background(), &userv1.ListUsersRequest{PerPage: 500, SortDir: "up"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.ElementsMatch(t, []string{"per_page", "sort_dir"}, badRequestFields(t, err))
+	})
+}
+
+func TestUserServer_WatchUsers(t *testing.T) {
+	t.Run("Streams changed users as the caller sees them", func(t *testing.T) {
+		watcher := &fakeWatcher{changes: make(chan service.UserChange, 2)}
+		client, mockService := setupUserClient(t, watcher, nil)
+		mockService.On("GetUser", mock.Anything, uint(1)).Return(testUser(), nil)
+		watcher.changes <- service.UserChange{Type: entity.UserEventUpdated, UserID: 1, OccurredAt: time.Now()}
+		watcher.changes <- service.UserChange{Type: entity.UserEventDeleted, UserID: 2, OccurredAt: time.Now()}
+
+		stream, err := client.WatchUsers(context.Background(), &userv1.WatchUsersRequest{})
+		require.NoError(t, err)
+
+		updated, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, userv1.ChangeType_CHANGE_TYPE_UPDATED, updated.GetType())
+		assert.Equal(t, "Test User", updated.GetUser().GetName())
+
+		deleted, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, userv1.ChangeType_CHANGE_TYPE_DELETED, deleted.GetType())
+		assert.Equal(t, uint64(2), deleted.GetUserId())
+		assert.Nil(t, deleted.GetUser())
+		mockService.AssertNumberOfCalls(t, "GetUser", 1)
+	})
+
+	t.Run("Ends streams that fell behind", func(t *testing.T) {
+		watcher := &fakeWatcher{changes: make(chan service.UserChange)}
+		close(watcher.changes)
+		client, _ := setupUserClient(t, watcher, nil)
+
+		stream, err := client.WatchUsers(context.Background(), &userv1.WatchUsersRequest{})
+		require.NoError(t, err)
+
+		_, err = stream.Recv()
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+
+	t.Run("Requires permission to read users", func(t *testing.T) {
+		client, _ := setupUserClient(t, &fakeWatcher{changes: make(chan service.UserChange)}, denyAll{})
+
+		stream, err := client.WatchUsers(context.Background(), &userv1.WatchUsersRequest{})
+		require.NoError(t, err)
+
+		_, err = stream.Recv()
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
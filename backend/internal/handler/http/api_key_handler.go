@@ -0,0 +1,133 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+	validator     *validator.Validate
+	logger        *logrus.Logger
+}
+
+func NewAPIKeyHandler(apiKeyService service.APIKeyService, validator *validator.Validate, logger *logrus.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+		validator:     validator,
+		logger:        logger,
+	}
+}
+
+// CreateKey creates a new API key
+// @Summary Create API key
+// @Description Create an API key for a machine client. The key is only returned in this response; store it securely.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param key body entity.CreateAPIKeyRequest true "API key name, scopes and optional expiry"
+// @Success 201 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Router /api-keys [post]
+func (h *APIKeyHandler) CreateKey(c *gin.Context) {
+	var req entity.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to bind JSON")
+		problem.Abort(c, problem.CodeMalformedRequest, "Invalid request format")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		problem.Respond(c, validationProblem(req, err))
+		return
+	}
+
+	key, err := h.apiKeyService.CreateKey(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAPIKeyExpiry) {
+			problem.Respond(c, problem.New(problem.CodeValidationFailed, invalidFieldsDetail(1)).
+				WithErrors(problem.FieldError{Pointer: "/expires_at", Detail: "Must be in the future"}))
+			return
+		}
+		h.logger.WithError(err).Error("Failed to create API key")
+		problem.Abort(c, problem.CodeInternalError, "Failed to create API key")
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "API key created successfully; copy the key now, it won't be shown again",
+		Data:    key,
+	})
+}
+
+// ListKeys lists API keys
+// @Summary List API keys
+// @Description Get the API keys of the organization, newest first. Secrets are never returned.
+// @Tags api-keys
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Success 200 {object} SuccessResponse
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Router /api-keys [get]
+func (h *APIKeyHandler) ListKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.ListKeys(c.Request.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list API keys")
+		problem.Abort(c, problem.CodeInternalError, "Failed to list API keys")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "API keys retrieved successfully",
+		Data:    keys,
+	})
+}
+
+// RevokeKey revokes an API key
+// @Summary Revoke API key
+// @Description Revoke an API key; requests using it are rejected from now on. The key stays listed for auditing.
+// @Tags api-keys
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param id path int true "API key ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Router /api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		invalidParameter(c, "id", "Invalid API key ID")
+		return
+	}
+
+	key, err := h.apiKeyService.RevokeKey(c.Request.Context(), uint(id))
+	if err != nil {
+		if err.Error() == "API key not found" {
+			problem.Abort(c, problem.CodeAPIKeyNotFound, "")
+			return
+		}
+		h.logger.WithError(err).Error("Failed to revoke API key")
+		problem.Abort(c, problem.CodeInternalError, "Failed to revoke API key")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "API key revoked successfully",
+		Data:    key,
+	})
+}
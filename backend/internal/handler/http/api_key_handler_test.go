@@ -0,0 +1,202 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAPIKeyService is a mock implementation of the APIKeyService interface
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) CreateKey(ctx context.Context, req entity.CreateAPIKeyRequest) (*entity.CreatedAPIKey, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.CreatedAPIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) ListKeys(ctx context.Context) ([]entity.APIKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) RevokeKey(ctx context.Context, id uint) (*entity.APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) Authenticate(ctx context.Context, key string) (*entity.APIKey, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.APIKey), args.Error(1)
+}
+
+func setupAPIKeyTestRouter() (*gin.Engine, *MockAPIKeyService) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &MockAPIKeyService{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	handler := NewAPIKeyHandler(mockService, validator.New(), logger)
+
+	router := gin.New()
+	keys := router.Group("/api/v1/api-keys")
+	{
+		keys.POST("", handler.CreateKey)
+		keys.GET("", handler.ListKeys)
+		keys.DELETE("/:id", handler.RevokeKey)
+	}
+
+	return router, mockService
+}
+
+func TestAPIKeyHandler_CreateKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		expectedStatus int
+		setupMock      func(*MockAPIKeyService)
+	}{
+		{
+			name:           "Successful creation",
+			requestBody:    entity.CreateAPIKeyRequest{Name: "HR sync", Scopes: []string{"users:read", "users:write"}},
+			expectedStatus: http.StatusCreated,
+			setupMock: func(mockService *MockAPIKeyService) {
+				mockService.On("CreateKey", mock.Anything, entity.CreateAPIKeyRequest{Name: "HR sync", Scopes: []string{"users:read", "users:write"}}).
+					Return(&entity.CreatedAPIKey{APIKey: entity.APIKey{ID: 1, Prefix: "uk_0a1b2c3d4e5f"}, Key: "uk_0a1b2c3d4e5f.secret"}, nil)
+			},
+		},
+		{
+			name:           "Unknown scope",
+			requestBody:    entity.CreateAPIKeyRequest{Name: "HR sync", Scopes: []string{"admin"}},
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockAPIKeyService) {},
+		},
+		{
+			name:           "Missing scopes",
+			requestBody:    entity.CreateAPIKeyRequest{Name: "HR sync"},
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockAPIKeyService) {},
+		},
+		{
+			name:           "Expiry in the past",
+			requestBody:    entity.CreateAPIKeyRequest{Name: "HR sync", Scopes: []string{"users:read"}},
+			expectedStatus: http.StatusBadRequest,
+			setupMock: func(mockService *MockAPIKeyService) {
+				mockService.On("CreateKey", mock.Anything, mock.Anything).Return(nil, service.ErrInvalidAPIKeyExpiry)
+			},
+		},
+		{
+			name:           "Service error",
+			requestBody:    entity.CreateAPIKeyRequest{Name: "HR sync", Scopes: []string{"users:read"}},
+			expectedStatus: http.StatusInternalServerError,
+			setupMock: func(mockService *MockAPIKeyService) {
+				mockService.On("CreateKey", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupAPIKeyTestRouter()
+			tt.setupMock(mockService)
+
+			requestBody, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest("POST", "/api/v1/api-keys", bytes.NewBuffer(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIKeyHandler_ListKeysHidesSecrets(t *testing.T) {
+	router, mockService := setupAPIKeyTestRouter()
+	mockService.On("ListKeys", mock.Anything).Return([]entity.APIKey{
+		{ID: 1, Name: "HR sync", Prefix: "uk_0a1b2c3d4e5f", SecretHash: "deadbeef", Scopes: entity.StringList{"users:read"}},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/api-keys", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "uk_0a1b2c3d4e5f")
+	assert.NotContains(t, w.Body.String(), "deadbeef")
+}
+
+func TestAPIKeyHandler_RevokeKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		expectedStatus int
+		setupMock      func(*MockAPIKeyService)
+	}{
+		{
+			name:           "Successful revocation",
+			id:             "3",
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockAPIKeyService) {
+				mockService.On("RevokeKey", mock.Anything, uint(3)).Return(&entity.APIKey{ID: 3}, nil)
+			},
+		},
+		{
+			name:           "Not found",
+			id:             "9",
+			expectedStatus: http.StatusNotFound,
+			setupMock: func(mockService *MockAPIKeyService) {
+				mockService.On("RevokeKey", mock.Anything, uint(9)).Return(nil, errors.New("API key not found"))
+			},
+		},
+		{
+			name:           "Invalid ID",
+			id:             "abc",
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockAPIKeyService) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupAPIKeyTestRouter()
+			tt.setupMock(mockService)
+
+			req, _ := http.NewRequest("DELETE", "/api/v1/api-keys/"+tt.id, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
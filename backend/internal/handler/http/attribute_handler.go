@@ -0,0 +1,208 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type AttributeHandler struct {
+	attributeService service.AttributeService
+	validator        *validator.Validate
+	logger           *logrus.Logger
+}
+
+func NewAttributeHandler(attributeService service.AttributeService, validator *validator.Validate, logger *logrus.Logger) *AttributeHandler {
+	return &AttributeHandler{
+		attributeService: attributeService,
+		validator:        validator,
+		logger:           logger,
+	}
+}
+
+// CreateDefinition creates a new custom attribute definition
+// @Summary Create attribute definition
+// @Description Define a custom attribute that can be set on users
+// @Tags attributes
+// @Accept json
+// @Produce json
+// @Param definition body entity.CreateAttributeDefinitionRequest true "Attribute definition"
+// @Success 201 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Router /attributes [post]
+func (h *AttributeHandler) CreateDefinition(c *gin.Context) {
+	var req entity.CreateAttributeDefinitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to bind JSON")
+		problem.Abort(c, problem.CodeMalformedRequest, "Invalid request format")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		problem.Respond(c, validationProblem(req, err))
+		return
+	}
+
+	def, err := h.attributeService.CreateDefinition(c.Request.Context(), req)
+	if err != nil {
+		if handlePermissionError(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrInvalidAttributeDefinition) {
+			problem.Abort(c, problem.CodeAttributeDefinitionInvalid, err.Error())
+			return
+		}
+		if err.Error() == "attribute key already exists" {
+			problem.Abort(c, problem.CodeAttributeKeyTaken, err.Error())
+			return
+		}
+		h.logger.WithError(err).Error("Failed to create attribute definition")
+		problem.Abort(c, problem.CodeInternalError, "Failed to create attribute definition")
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Attribute definition created successfully",
+		Data:    def,
+	})
+}
+
+// ListDefinitions lists all custom attribute definitions
+// @Summary List attribute definitions
+// @Description Get all custom attribute definitions
+// @Tags attributes
+// @Produce json
+// @Success 200 {object} SuccessResponse
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /attributes [get]
+func (h *AttributeHandler) ListDefinitions(c *gin.Context) {
+	defs, err := h.attributeService.ListDefinitions(c.Request.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list attribute definitions")
+		problem.Abort(c, problem.CodeInternalError, "Failed to list attribute definitions")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Attribute definitions retrieved successfully",
+		Data:    defs,
+	})
+}
+
+// Schema returns the attribute definitions as a JSON Schema document
+// @Summary Attribute JSON Schema
+// @Description Get the custom attribute definitions rendered as a JSON Schema (draft 2020-12)
+// @Tags attributes
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /attributes/schema [get]
+func (h *AttributeHandler) Schema(c *gin.Context) {
+	schema, err := h.attributeService.Schema(c.Request.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to build attribute schema")
+		problem.Abort(c, problem.CodeInternalError, "Failed to build attribute schema")
+		return
+	}
+
+	c.Header("Content-Type", "application/schema+json")
+	c.JSON(http.StatusOK, schema)
+}
+
+// UpdateDefinition updates a custom attribute definition
+// @Summary Update attribute definition
+// @Description Update the constraints of a custom attribute. Key and type are immutable.
+// @Tags attributes
+// @Accept json
+// @Produce json
+// @Param key path string true "Attribute key"
+// @Param definition body entity.UpdateAttributeDefinitionRequest true "Attribute constraints to update"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Router /attributes/{key} [put]
+func (h *AttributeHandler) UpdateDefinition(c *gin.Context) {
+	var req entity.UpdateAttributeDefinitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to bind JSON")
+		problem.Abort(c, problem.CodeMalformedRequest, "Invalid request format")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		problem.Respond(c, validationProblem(req, err))
+		return
+	}
+
+	def, err := h.attributeService.UpdateDefinition(c.Request.Context(), c.Param("key"), req)
+	if err != nil {
+		if handlePermissionError(c, err) {
+			return
+		}
+		if err.Error() == "attribute definition not found" {
+			problem.Abort(c, problem.CodeAttributeNotFound, "")
+			return
+		}
+		if errors.Is(err, service.ErrInvalidAttributeDefinition) {
+			problem.Abort(c, problem.CodeAttributeDefinitionInvalid, err.Error())
+			return
+		}
+		h.logger.WithError(err).Error("Failed to update attribute definition")
+		problem.Abort(c, problem.CodeInternalError, "Failed to update attribute definition")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Attribute definition updated successfully",
+		Data:    def,
+	})
+}
+
+// DeleteDefinition deletes a custom attribute definition
+// @Summary Delete attribute definition
+// @Description Delete a custom attribute and remove its values from all users
+// @Tags attributes
+// @Produce json
+// @Param key path string true "Attribute key"
+// @Success 200 {object} SuccessResponse
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Router /attributes/{key} [delete]
+func (h *AttributeHandler) DeleteDefinition(c *gin.Context) {
+	err := h.attributeService.DeleteDefinition(c.Request.Context(), c.Param("key"))
+	if err != nil {
+		if handlePermissionError(c, err) {
+			return
+		}
+		if err.Error() == "attribute definition not found" {
+			problem.Abort(c, problem.CodeAttributeNotFound, "")
+			return
+		}
+		h.logger.WithError(err).Error("Failed to delete attribute definition")
+		problem.Abort(c, problem.CodeInternalError, "Failed to delete attribute definition")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Attribute definition deleted successfully",
+	})
+}
@@ -0,0 +1,193 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAttributeService is a mock implementation of the AttributeService interface
+type MockAttributeService struct {
+	mock.Mock
+}
+
+func (m *MockAttributeService) CreateDefinition(ctx context.Context, req entity.CreateAttributeDefinitionRequest) (*entity.AttributeDefinition, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.AttributeDefinition), args.Error(1)
+}
+
+func (m *MockAttributeService) ListDefinitions(ctx context.Context) ([]entity.AttributeDefinition, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.AttributeDefinition), args.Error(1)
+}
+
+func (m *MockAttributeService) UpdateDefinition(ctx context.Context, key string, req entity.UpdateAttributeDefinitionRequest) (*entity.AttributeDefinition, error) {
+	args := m.Called(ctx, key, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.AttributeDefinition), args.Error(1)
+}
+
+func (m *MockAttributeService) DeleteDefinition(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockAttributeService) Schema(ctx context.Context) (map[string]interface{}, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]interface{}), args.Error(1)
+}
+
+func (m *MockAttributeService) ValidateAttributes(ctx context.Context, attrs map[string]interface{}) error {
+	args := m.Called(ctx, attrs)
+	return args.Error(0)
+}
+
+func (m *MockAttributeService) ValidateFilter(ctx context.Context, filter map[string]string) error {
+	args := m.Called(ctx, filter)
+	return args.Error(0)
+}
+
+func setupAttributeTestRouter() (*gin.Engine, *MockAttributeService) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &MockAttributeService{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	handler := NewAttributeHandler(mockService, validator.New(), logger)
+
+	router := gin.New()
+	attributes := router.Group("/api/v1/attributes")
+	{
+		attributes.POST("", handler.CreateDefinition)
+		attributes.GET("", handler.ListDefinitions)
+		attributes.GET("/schema", handler.Schema)
+		attributes.PUT("/:key", handler.UpdateDefinition)
+		attributes.DELETE("/:key", handler.DeleteDefinition)
+	}
+
+	return router, mockService
+}
+
+func TestAttributeHandler_CreateDefinition(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		expectedStatus int
+		setupMock      func(*MockAttributeService)
+	}{
+		{
+			name:           "Successful creation",
+			requestBody:    entity.CreateAttributeDefinitionRequest{Key: "department", Type: "string"},
+			expectedStatus: http.StatusCreated,
+			setupMock: func(mockService *MockAttributeService) {
+				mockService.On("CreateDefinition", mock.Anything, mock.AnythingOfType("entity.CreateAttributeDefinitionRequest")).Return(&entity.AttributeDefinition{ID: 1, Key: "department"}, nil)
+			},
+		},
+		{
+			name:           "Invalid type",
+			requestBody:    entity.CreateAttributeDefinitionRequest{Key: "department", Type: "date"},
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockAttributeService) {},
+		},
+		{
+			name:           "Invalid definition",
+			requestBody:    entity.CreateAttributeDefinitionRequest{Key: "Department", Type: "string"},
+			expectedStatus: http.StatusBadRequest,
+			setupMock: func(mockService *MockAttributeService) {
+				mockService.On("CreateDefinition", mock.Anything, mock.AnythingOfType("entity.CreateAttributeDefinitionRequest")).Return(nil, fmt.Errorf("%w: bad key", service.ErrInvalidAttributeDefinition))
+			},
+		},
+		{
+			name:           "Missing permission",
+			requestBody:    entity.CreateAttributeDefinitionRequest{Key: "department", Type: "string"},
+			expectedStatus: http.StatusForbidden,
+			setupMock: func(mockService *MockAttributeService) {
+				mockService.On("CreateDefinition", mock.Anything, mock.AnythingOfType("entity.CreateAttributeDefinitionRequest")).Return(nil, &auth.PermissionError{Permission: auth.PermissionAttributesManage})
+			},
+		},
+		{
+			name:           "Duplicate key",
+			requestBody:    entity.CreateAttributeDefinitionRequest{Key: "department", Type: "string"},
+			expectedStatus: http.StatusConflict,
+			setupMock: func(mockService *MockAttributeService) {
+				mockService.On("CreateDefinition", mock.Anything, mock.AnythingOfType("entity.CreateAttributeDefinitionRequest")).Return(nil, errors.New("attribute key already exists"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupAttributeTestRouter()
+			tt.setupMock(mockService)
+
+			requestBody, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest("POST", "/api/v1/attributes", bytes.NewBuffer(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAttributeHandler_Schema(t *testing.T) {
+	router, mockService := setupAttributeTestRouter()
+	mockService.On("Schema", mock.Anything).Return(map[string]interface{}{"type": "object"}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/attributes/schema", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/schema+json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"type":"object"}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestAttributeHandler_UpdateAndDeleteDefinition(t *testing.T) {
+	router, mockService := setupAttributeTestRouter()
+	mockService.On("UpdateDefinition", mock.Anything, "missing", mock.AnythingOfType("entity.UpdateAttributeDefinitionRequest")).Return(nil, errors.New("attribute definition not found"))
+	mockService.On("DeleteDefinition", mock.Anything, "department").Return(nil)
+
+	req, _ := http.NewRequest("PUT", "/api/v1/attributes/missing", bytes.NewBufferString(`{"required":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	req, _ = http.NewRequest("DELETE", "/api/v1/attributes/department", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	mockService.AssertExpectations(t)
+}
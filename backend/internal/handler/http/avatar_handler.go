@@ -0,0 +1,166 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// multipartOverhead allows for the multipart headers and boundaries around the avatar file
+const multipartOverhead = 64 << 10
+
+type AvatarHandler struct {
+	avatarService service.AvatarService
+	maxBytes      int64
+	logger        *logrus.Logger
+}
+
+func NewAvatarHandler(avatarService service.AvatarService, maxBytes int64, logger *logrus.Logger) *AvatarHandler {
+	return &AvatarHandler{
+		avatarService: avatarService,
+		maxBytes:      maxBytes,
+		logger:        logger,
+	}
+}
+
+// UploadAvatar replaces a user's avatar
+// @Summary Upload avatar
+// @Description Upload a JPEG, PNG or GIF avatar. The image is cropped to a square, resized to 64, 128 and 256 pixels and re-encoded as JPEG without metadata.
+// @Tags users
+// @Accept multipart/form-data
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param id path int true "User ID"
+// @Param avatar formData file true "Avatar image"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 413 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /users/{id}/avatar [put]
+func (h *AvatarHandler) UploadAvatar(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		invalidParameter(c, "id", "Invalid user ID")
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBytes+multipartOverhead)
+	header, err := c.FormFile("avatar")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			problem.Abort(c, problem.CodeAvatarTooLarge, service.ErrAvatarTooLarge.Error())
+			return
+		}
+		problem.Abort(c, problem.CodeAvatarInvalid, "Avatar file is required")
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to open uploaded avatar")
+		problem.Abort(c, problem.CodeInternalError, "Failed to upload avatar")
+		return
+	}
+	defer file.Close()
+
+	user, err := h.avatarService.UploadAvatar(c.Request.Context(), uint(id), file)
+	if err != nil {
+		if errors.Is(err, service.ErrAvatarTooLarge) {
+			problem.Abort(c, problem.CodeAvatarTooLarge, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidAvatar) {
+			problem.Abort(c, problem.CodeAvatarInvalid, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrAvatarReplaced) {
+			problem.Abort(c, problem.CodeAvatarReplaced, err.Error())
+			return
+		}
+		if err.Error() == "user not found" {
+			problem.Abort(c, problem.CodeUserNotFound, "")
+			return
+		}
+		h.logger.WithError(err).Error("Failed to upload avatar")
+		problem.Abort(c, problem.CodeInternalError, "Failed to upload avatar")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Avatar uploaded successfully",
+		Data:    user,
+	})
+}
+
+// GetAvatar serves a user's avatar
+// @Summary Get avatar
+// @Description Get a user's avatar as JPEG in one of the generated sizes
+// @Tags users
+// @Produce jpeg
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param id path int true "User ID"
+// @Param size query int false "Size in pixels: 64, 128 or 256" default(256)
+// @Success 200 {file} binary
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /users/{id}/avatar [get]
+func (h *AvatarHandler) GetAvatar(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		invalidParameter(c, "id", "Invalid user ID")
+		return
+	}
+
+	size := service.DefaultAvatarSize
+	if sizeStr := c.Query("size"); sizeStr != "" {
+		if size, err = strconv.Atoi(sizeStr); err != nil {
+			invalidParameter(c, "size", "Invalid avatar size")
+			return
+		}
+	}
+
+	rc, err := h.avatarService.GetAvatar(c.Request.Context(), uint(id), size)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAvatar) {
+			invalidParameter(c, "size", err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrAvatarNotFound) {
+			problem.Abort(c, problem.CodeAvatarNotFound, "")
+			return
+		}
+		if err.Error() == "user not found" {
+			problem.Abort(c, problem.CodeUserNotFound, "")
+			return
+		}
+		h.logger.WithError(err).Error("Failed to get avatar")
+		problem.Abort(c, problem.CodeInternalError, "Failed to get avatar")
+		return
+	}
+	defer rc.Close()
+
+	// Avatar URLs are versioned per upload, so the content behind one never changes. Avatars are
+	// only served to callers of the user's organization, so shared caches must not keep them.
+	c.Header("Content-Type", "image/jpeg")
+	c.Header("Cache-Control", "private, max-age=86400")
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, rc); err != nil {
+		h.logger.WithError(err).Warn("Failed to write avatar")
+	}
+}
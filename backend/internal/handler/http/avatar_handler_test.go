@@ -0,0 +1,244 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAvatarService is a mock implementation of the AvatarService interface
+type MockAvatarService struct {
+	mock.Mock
+}
+
+func (m *MockAvatarService) UploadAvatar(ctx context.Context, id uint, r io.Reader) (*entity.User, error) {
+	args := m.Called(ctx, id, r)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockAvatarService) GetAvatar(ctx context.Context, id uint, size int) (io.ReadCloser, error) {
+	args := m.Called(ctx, id, size)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *MockAvatarService) DeleteAvatar(ctx context.Context, user *entity.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func setupAvatarTestRouter() (*gin.Engine, *MockAvatarService) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &MockAvatarService{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	handler := NewAvatarHandler(mockService, 1024, logger)
+
+	router := gin.New()
+	users := router.Group("/api/v1/users")
+	{
+		users.PUT("/:id/avatar", handler.UploadAvatar)
+		users.GET("/:id/avatar", handler.GetAvatar)
+	}
+
+	return router, mockService
+}
+
+func avatarUploadRequest(t *testing.T, url, field string, data []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, "avatar.png")
+	assert.NoError(t, err)
+	part.Write(data)
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPut, url, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestAvatarHandler_UploadAvatar(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		field          string
+		data           []byte
+		expectedStatus int
+		setupMock      func(*MockAvatarService)
+	}{
+		{
+			name:           "Successful upload",
+			url:            "/api/v1/users/1/avatar",
+			field:          "avatar",
+			data:           []byte("image"),
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockAvatarService) {
+				mockService.On("UploadAvatar", mock.Anything, uint(1), mock.Anything).Return(&entity.User{ID: 1, AvatarURL: "/api/v1/users/1/avatar?v=1"}, nil)
+			},
+		},
+		{
+			name:           "Invalid user ID",
+			url:            "/api/v1/users/abc/avatar",
+			field:          "avatar",
+			data:           []byte("image"),
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockAvatarService) {},
+		},
+		{
+			name:           "Missing file",
+			url:            "/api/v1/users/1/avatar",
+			field:          "picture",
+			data:           []byte("image"),
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockAvatarService) {},
+		},
+		{
+			name:           "Request body too large",
+			url:            "/api/v1/users/1/avatar",
+			field:          "avatar",
+			data:           bytes.Repeat([]byte{0}, 2*multipartOverhead),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			setupMock:      func(mockService *MockAvatarService) {},
+		},
+		{
+			name:           "Avatar too large",
+			url:            "/api/v1/users/1/avatar",
+			field:          "avatar",
+			data:           bytes.Repeat([]byte{0}, 2048),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			setupMock: func(mockService *MockAvatarService) {
+				mockService.On("UploadAvatar", mock.Anything, uint(1), mock.Anything).Return(nil, service.ErrAvatarTooLarge)
+			},
+		},
+		{
+			name:           "Replaced by another upload",
+			url:            "/api/v1/users/1/avatar",
+			field:          "avatar",
+			data:           []byte("image"),
+			expectedStatus: http.StatusConflict,
+			setupMock: func(mockService *MockAvatarService) {
+				mockService.On("UploadAvatar", mock.Anything, uint(1), mock.Anything).Return(nil, service.ErrAvatarReplaced)
+			},
+		},
+		{
+			name:           "Invalid image",
+			url:            "/api/v1/users/1/avatar",
+			field:          "avatar",
+			data:           []byte("text"),
+			expectedStatus: http.StatusBadRequest,
+			setupMock: func(mockService *MockAvatarService) {
+				mockService.On("UploadAvatar", mock.Anything, uint(1), mock.Anything).Return(nil, service.ErrInvalidAvatar)
+			},
+		},
+		{
+			name:           "User not found",
+			url:            "/api/v1/users/999/avatar",
+			field:          "avatar",
+			data:           []byte("image"),
+			expectedStatus: http.StatusNotFound,
+			setupMock: func(mockService *MockAvatarService) {
+				mockService.On("UploadAvatar", mock.Anything, uint(999), mock.Anything).Return(nil, errors.New("user not found"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupAvatarTestRouter()
+			tt.setupMock(mockService)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, avatarUploadRequest(t, tt.url, tt.field, tt.data))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAvatarHandler_GetAvatar(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		setupMock      func(*MockAvatarService)
+	}{
+		{
+			name:           "Default size",
+			url:            "/api/v1/users/1/avatar",
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockAvatarService) {
+				mockService.On("GetAvatar", mock.Anything, uint(1), 256).Return(io.NopCloser(bytes.NewReader([]byte("jpeg"))), nil)
+			},
+		},
+		{
+			name:           "Requested size",
+			url:            "/api/v1/users/1/avatar?size=64",
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockAvatarService) {
+				mockService.On("GetAvatar", mock.Anything, uint(1), 64).Return(io.NopCloser(bytes.NewReader([]byte("jpeg"))), nil)
+			},
+		},
+		{
+			name:           "Non-numeric size",
+			url:            "/api/v1/users/1/avatar?size=big",
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockAvatarService) {},
+		},
+		{
+			name:           "Unsupported size",
+			url:            "/api/v1/users/1/avatar?size=100",
+			expectedStatus: http.StatusBadRequest,
+			setupMock: func(mockService *MockAvatarService) {
+				mockService.On("GetAvatar", mock.Anything, uint(1), 100).Return(nil, service.ErrInvalidAvatar)
+			},
+		},
+		{
+			name:           "No avatar",
+			url:            "/api/v1/users/1/avatar",
+			expectedStatus: http.StatusNotFound,
+			setupMock: func(mockService *MockAvatarService) {
+				mockService.On("GetAvatar", mock.Anything, uint(1), 256).Return(nil, service.ErrAvatarNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupAvatarTestRouter()
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "image/jpeg", w.Header().Get("Content-Type"))
+				assert.Equal(t, "private, max-age=86400", w.Header().Get("Cache-Control"))
+				assert.Equal(t, "jpeg", w.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultBirthdayWindow is the range returned when no end date is given
+const defaultBirthdayWindow = 30 * 24 * time.Hour
+
+type BirthdayHandler struct {
+	birthdayService service.BirthdayService
+	logger          *logrus.Logger
+}
+
+func NewBirthdayHandler(birthdayService service.BirthdayService, logger *logrus.Logger) *BirthdayHandler {
+	return &BirthdayHandler{
+		birthdayService: birthdayService,
+		logger:          logger,
+	}
+}
+
+// UpcomingBirthdays lists the birthdays in a date range
+// @Summary List upcoming birthdays
+// @Description Get users whose birthday falls between from and to (inclusive), ordered by date. The range may wrap into the next year but must be shorter than a year. People born on Feb 29 are listed on Feb 28 in common years.
+// @Tags birthdays
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param from query string false "First day, YYYY-MM-DD (default: today)"
+// @Param to query string false "Last day, YYYY-MM-DD (default: 30 days after from)"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /users/birthdays [get]
+func (h *BirthdayHandler) UpcomingBirthdays(c *gin.Context) {
+	from := time.Now()
+	if value := c.Query("from"); value != "" {
+		t, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			invalidParameter(c, "from", "Invalid from date, use YYYY-MM-DD")
+			return
+		}
+		from = t
+	}
+
+	to := from.Add(defaultBirthdayWindow)
+	if value := c.Query("to"); value != "" {
+		t, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			invalidParameter(c, "to", "Invalid to date, use YYYY-MM-DD")
+			return
+		}
+		to = t
+	}
+
+	birthdays, err := h.birthdayService.UpcomingBirthdays(c.Request.Context(), from, to)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidBirthdayRange) {
+			problem.Abort(c, problem.CodeBirthdayRangeInvalid, err.Error())
+			return
+		}
+		h.logger.WithError(err).Error("Failed to list birthdays")
+		problem.Abort(c, problem.CodeInternalError, "Failed to list birthdays")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Birthdays retrieved successfully",
+		Data:    birthdays,
+	})
+}
+
+// IssueFeed creates a birthday calendar subscription link
+// @Summary Create birthday calendar link
+// @Description Create a link to the organization's birthday calendar for mail and calendar clients. The link contains a secret token; anyone who has it can read the calendar until it expires.
+// @Tags birthdays
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Success 201 {object} SuccessResponse
+// @Failure 403 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /users/birthdays/feed [post]
+func (h *BirthdayHandler) IssueFeed(c *gin.Context) {
+	feed, err := h.birthdayService.IssueFeed(c.Request.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to create calendar feed")
+		problem.Abort(c, problem.CodeInternalError, "Failed to create calendar feed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Calendar feed created successfully",
+		Data:    feed,
+	})
+}
+
+// Calendar serves the birthday calendar
+// @Summary Birthday calendar
+// @Description Get the organization's birthdays as an iCalendar (RFC 5545) file with yearly recurring events. Authenticate with the token of a calendar link, as a query parameter or bearer token.
+// @Tags birthdays
+// @Produce text/calendar
+// @Param token query string false "Calendar feed token"
+// @Param Authorization header string false "Bearer calendar feed token"
+// @Success 200 {file} binary
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /users/birthdays.ics [get]
+func (h *BirthdayHandler) Calendar(c *gin.Context) {
+	feedToken := c.Query("token")
+	if feedToken == "" {
+		feedToken = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	}
+	if feedToken == "" {
+		problem.Abort(c, problem.CodeFeedTokenInvalid, "Calendar feed token is required")
+		return
+	}
+
+	ics, err := h.birthdayService.Calendar(c.Request.Context(), feedToken)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidFeedToken) {
+			problem.Abort(c, problem.CodeFeedTokenInvalid, err.Error())
+			return
+		}
+		h.logger.WithError(err).Error("Failed to render birthday calendar")
+		problem.Abort(c, problem.CodeInternalError, "Failed to render birthday calendar")
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="birthdays.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", ics)
+}
@@ -0,0 +1,207 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBirthdayService is a mock implementation of the BirthdayService interface
+type MockBirthdayService struct {
+	mock.Mock
+}
+
+func (m *MockBirthdayService) UpcomingBirthdays(ctx context.Context, from, to time.Time) ([]entity.Birthday, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Birthday), args.Error(1)
+}
+
+func (m *MockBirthdayService) IssueFeed(ctx context.Context) (*entity.BirthdayFeed, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.BirthdayFeed), args.Error(1)
+}
+
+func (m *MockBirthdayService) Calendar(ctx context.Context, feedToken string) ([]byte, error) {
+	args := m.Called(ctx, feedToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func setupBirthdayTestRouter() (*gin.Engine, *MockBirthdayService) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &MockBirthdayService{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	handler := NewBirthdayHandler(mockService, logger)
+
+	router := gin.New()
+	users := router.Group("/api/v1/users")
+	{
+		users.GET("/birthdays", handler.UpcomingBirthdays)
+		users.POST("/birthdays/feed", handler.IssueFeed)
+		users.GET("/birthdays.ics", handler.Calendar)
+		users.GET("/:id", func(c *gin.Context) { c.Status(http.StatusTeapot) })
+	}
+
+	return router, mockService
+}
+
+func TestBirthdayHandler_UpcomingBirthdays(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		setupMock      func(*MockBirthdayService)
+	}{
+		{
+			name:           "Explicit range",
+			url:            "/api/v1/users/birthdays?from=2025-12-20&to=2026-01-10",
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockBirthdayService) {
+				mockService.On("UpcomingBirthdays", mock.Anything, day(2025, 12, 20), day(2026, 1, 10)).Return([]entity.Birthday{{UserID: 1}}, nil)
+			},
+		},
+		{
+			name:           "Default end date",
+			url:            "/api/v1/users/birthdays?from=2025-03-01",
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockBirthdayService) {
+				mockService.On("UpcomingBirthdays", mock.Anything, day(2025, 3, 1), day(2025, 3, 31)).Return([]entity.Birthday{}, nil)
+			},
+		},
+		{
+			name:           "Invalid date",
+			url:            "/api/v1/users/birthdays?from=03/01/2025",
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockBirthdayService) {},
+		},
+		{
+			name:           "Invalid range",
+			url:            "/api/v1/users/birthdays?from=2025-03-01&to=2025-02-01",
+			expectedStatus: http.StatusBadRequest,
+			setupMock: func(mockService *MockBirthdayService) {
+				mockService.On("UpcomingBirthdays", mock.Anything, mock.Anything, mock.Anything).Return(nil, service.ErrInvalidBirthdayRange)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupBirthdayTestRouter()
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBirthdayHandler_IssueFeed(t *testing.T) {
+	router, mockService := setupBirthdayTestRouter()
+	mockService.On("IssueFeed", mock.Anything).Return(&entity.BirthdayFeed{URL: "http://localhost/birthdays.ics?token=t"}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/birthdays/feed", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), "token=t")
+}
+
+func TestBirthdayHandler_Calendar(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		authorization  string
+		expectedStatus int
+		setupMock      func(*MockBirthdayService)
+	}{
+		{
+			name:           "Token in query",
+			url:            "/api/v1/users/birthdays.ics?token=good",
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockBirthdayService) {
+				mockService.On("Calendar", mock.Anything, "good").Return([]byte("BEGIN:VCALENDAR\r\n"), nil)
+			},
+		},
+		{
+			name:           "Bearer token",
+			url:            "/api/v1/users/birthdays.ics",
+			authorization:  "Bearer good",
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockBirthdayService) {
+				mockService.On("Calendar", mock.Anything, "good").Return([]byte("BEGIN:VCALENDAR\r\n"), nil)
+			},
+		},
+		{
+			name:           "Missing token",
+			url:            "/api/v1/users/birthdays.ics",
+			expectedStatus: http.StatusUnauthorized,
+			setupMock:      func(mockService *MockBirthdayService) {},
+		},
+		{
+			name:           "Invalid token",
+			url:            "/api/v1/users/birthdays.ics?token=bad",
+			expectedStatus: http.StatusUnauthorized,
+			setupMock: func(mockService *MockBirthdayService) {
+				mockService.On("Calendar", mock.Anything, "bad").Return(nil, service.ErrInvalidFeedToken)
+			},
+		},
+		{
+			name:           "Calendar fails",
+			url:            "/api/v1/users/birthdays.ics?token=good",
+			expectedStatus: http.StatusInternalServerError,
+			setupMock: func(mockService *MockBirthdayService) {
+				mockService.On("Calendar", mock.Anything, "good").Return(nil, errors.New("database error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupBirthdayTestRouter()
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type EmailVerificationHandler struct {
+	verificationService service.EmailVerificationService
+	validator           *validator.Validate
+	logger              *logrus.Logger
+}
+
+func NewEmailVerificationHandler(verificationService service.EmailVerificationService, validator *validator.Validate, logger *logrus.Logger) *EmailVerificationHandler {
+	return &EmailVerificationHandler{
+		verificationService: verificationService,
+		validator:           validator,
+		logger:              logger,
+	}
+}
+
+// VerifyEmail confirms a user's email address
+// @Summary Verify email
+// @Description Consume an email verification token and mark the user's email as verified
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param request body entity.VerifyEmailRequest true "Verification token"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /users/verify-email [post]
+func (h *EmailVerificationHandler) VerifyEmail(c *gin.Context) {
+	var req entity.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to bind JSON")
+		problem.Abort(c, problem.CodeMalformedRequest, "Invalid request format")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		problem.Respond(c, validationProblem(req, err))
+		return
+	}
+
+	user, err := h.verificationService.VerifyEmail(c.Request.Context(), req.Token)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidVerificationToken) {
+			problem.Abort(c, problem.CodeVerificationTokenInvalid, err.Error())
+			return
+		}
+		h.logger.WithError(err).Error("Failed to verify email")
+		problem.Abort(c, problem.CodeInternalError, "Failed to verify email")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Email verified successfully",
+		Data:    user,
+	})
+}
+
+// ResendVerification sends a new verification email to a user
+// @Summary Resend verification email
+// @Description Send a new verification email. Limited to one email per cooldown window.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param id path int true "User ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 429 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /users/{id}/resend-verification [post]
+func (h *EmailVerificationHandler) ResendVerification(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		invalidParameter(c, "id", "Invalid user ID")
+		return
+	}
+
+	err = h.verificationService.ResendVerification(c.Request.Context(), uint(id))
+	if err != nil {
+		var rateLimited *service.VerificationRateLimitedError
+		if errors.As(err, &rateLimited) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(rateLimited.RetryAfter.Seconds()))))
+			problem.Abort(c, problem.CodeVerificationRateLimited, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrEmailAlreadyVerified) {
+			problem.Abort(c, problem.CodeEmailAlreadyVerified, err.Error())
+			return
+		}
+		if err.Error() == "user not found" {
+			problem.Abort(c, problem.CodeUserNotFound, "")
+			return
+		}
+		h.logger.WithError(err).Error("Failed to resend verification email")
+		problem.Abort(c, problem.CodeInternalError, "Failed to resend verification email")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Verification email sent successfully",
+	})
+}
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockEmailVerificationService is a mock implementation of the EmailVerificationService interface
+type MockEmailVerificationService struct {
+	mock.Mock
+}
+
+func (m *MockEmailVerificationService) SendVerification(ctx context.Context, user *entity.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationService) VerifyEmail(ctx context.Context, token string) (*entity.User, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockEmailVerificationService) ResendVerification(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func setupVerificationTestRouter() (*gin.Engine, *MockEmailVerificationService) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &MockEmailVerificationService{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	handler := NewEmailVerificationHandler(mockService, validator.New(), logger)
+
+	router := gin.New()
+	users := router.Group("/api/v1/users")
+	{
+		users.POST("/verify-email", handler.VerifyEmail)
+		users.POST("/:id/resend-verification", handler.ResendVerification)
+	}
+
+	return router, mockService
+}
+
+func TestEmailVerificationHandler_VerifyEmail(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		expectedStatus int
+		setupMock      func(*MockEmailVerificationService)
+	}{
+		{
+			name:           "Successful verification",
+			requestBody:    entity.VerifyEmailRequest{Token: "valid-token"},
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockEmailVerificationService) {
+				now := time.Now()
+				mockService.On("VerifyEmail", mock.Anything, "valid-token").Return(&entity.User{ID: 1, EmailVerifiedAt: &now}, nil)
+			},
+		},
+		{
+			name:           "Missing token",
+			requestBody:    entity.VerifyEmailRequest{},
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockEmailVerificationService) {},
+		},
+		{
+			name:           "Invalid token",
+			requestBody:    entity.VerifyEmailRequest{Token: "expired-token"},
+			expectedStatus: http.StatusBadRequest,
+			setupMock: func(mockService *MockEmailVerificationService) {
+				mockService.On("VerifyEmail", mock.Anything, "expired-token").Return(nil, service.ErrInvalidVerificationToken)
+			},
+		},
+		{
+			name:           "Service error",
+			requestBody:    entity.VerifyEmailRequest{Token: "valid-token"},
+			expectedStatus: http.StatusInternalServerError,
+			setupMock: func(mockService *MockEmailVerificationService) {
+				mockService.On("VerifyEmail", mock.Anything, "valid-token").Return(nil, errors.New("database error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupVerificationTestRouter()
+			tt.setupMock(mockService)
+
+			requestBody, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest("POST", "/api/v1/users/verify-email", bytes.NewBuffer(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEmailVerificationHandler_ResendVerification(t *testing.T) {
+	tests := []struct {
+		name               string
+		userID             string
+		expectedStatus     int
+		expectedRetryAfter string
+		setupMock          func(*MockEmailVerificationService)
+	}{
+		{
+			name:           "Successful resend",
+			userID:         "1",
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockEmailVerificationService) {
+				mockService.On("ResendVerification", mock.Anything, uint(1)).Return(nil)
+			},
+		},
+		{
+			name:           "Invalid user ID",
+			userID:         "invalid",
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockEmailVerificationService) {},
+		},
+		{
+			name:               "Rate limited",
+			userID:             "1",
+			expectedStatus:     http.StatusTooManyRequests,
+			expectedRetryAfter: "90",
+			setupMock: func(mockService *MockEmailVerificationService) {
+				mockService.On("ResendVerification", mock.Anything, uint(1)).Return(&service.VerificationRateLimitedError{RetryAfter: 90 * time.Second})
+			},
+		},
+		{
+			name:           "Already verified",
+			userID:         "1",
+			expectedStatus: http.StatusConflict,
+			setupMock: func(mockService *MockEmailVerificationService) {
+				mockService.On("ResendVerification", mock.Anything, uint(1)).Return(service.ErrEmailAlreadyVerified)
+			},
+		},
+		{
+			name:           "User not found",
+			userID:         "999",
+			expectedStatus: http.StatusNotFound,
+			setupMock: func(mockService *MockEmailVerificationService) {
+				mockService.On("ResendVerification", mock.Anything, uint(999)).Return(errors.New("user not found"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupVerificationTestRouter()
+			tt.setupMock(mockService)
+
+			req, _ := http.NewRequest("POST", "/api/v1/users/"+tt.userID+"/resend-verification", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedRetryAfter, w.Header().Get("Retry-After"))
+			mockService.AssertExpectations(t)
+		})
+	}
+}
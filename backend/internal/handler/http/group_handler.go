@@ -0,0 +1,149 @@
+package http
+
+import (
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type GroupHandler struct {
+	labels *labelHandler[entity.Group, entity.CreateGroupRequest, entity.UpdateGroupRequest]
+}
+
+func NewGroupHandler(groupService service.GroupService, validator *validator.Validate, logger *logrus.Logger) *GroupHandler {
+	return &GroupHandler{labels: &labelHandler[entity.Group, entity.CreateGroupRequest, entity.UpdateGroupRequest]{
+		labelService: groupService,
+		name:         "group",
+		notFound:     problem.CodeGroupNotFound,
+		nameTaken:    problem.CodeGroupNameTaken,
+		validator:    validator,
+		logger:       logger,
+	}}
+}
+
+// CreateGroup creates a new group
+// @Summary Create group
+// @Description Create a group to organise users into a cohort
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param group body entity.CreateGroupRequest true "Group data"
+// @Success 201 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /groups [post]
+func (h *GroupHandler) CreateGroup(c *gin.Context) {
+	h.labels.create(c)
+}
+
+// ListGroups lists all groups
+// @Summary List groups
+// @Description Get all groups with their member counts, ordered by name
+// @Tags groups
+// @Produce json
+// @Success 200 {object} SuccessResponse
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /groups [get]
+func (h *GroupHandler) ListGroups(c *gin.Context) {
+	h.labels.list(c)
+}
+
+// GetGroup retrieves a group by ID
+// @Summary Get group by ID
+// @Description Get a single group and its member count by its ID
+// @Tags groups
+// @Produce json
+// @Param id path int true "Group ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /groups/{id} [get]
+func (h *GroupHandler) GetGroup(c *gin.Context) {
+	h.labels.get(c)
+}
+
+// UpdateGroup updates a group
+// @Summary Update group
+// @Description Rename a group or change its description
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Group ID"
+// @Param group body entity.UpdateGroupRequest true "Group fields to update"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /groups/{id} [put]
+func (h *GroupHandler) UpdateGroup(c *gin.Context) {
+	h.labels.update(c)
+}
+
+// DeleteGroup deletes a group
+// @Summary Delete group
+// @Description Delete a group and remove all of its members
+// @Tags groups
+// @Produce json
+// @Param id path int true "Group ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /groups/{id} [delete]
+func (h *GroupHandler) DeleteGroup(c *gin.Context) {
+	h.labels.delete(c)
+}
+
+// AddUsers adds users to a group in bulk
+// @Summary Add group members
+// @Description Add up to 1000 users to a group. Users that are already members or do not exist are skipped.
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Group ID"
+// @Param users body entity.MembershipRequest true "User IDs"
+// @Success 200 {object} SuccessResponse{data=entity.MembershipResponse}
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /groups/{id}/users [post]
+func (h *GroupHandler) AddUsers(c *gin.Context) {
+	h.labels.changeMembership(c, h.labels.labelService.AddUsers, "Group members added successfully")
+}
+
+// RemoveUsers removes users from a group in bulk
+// @Summary Remove group members
+// @Description Remove up to 1000 users from a group
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Group ID"
+// @Param users body entity.MembershipRequest true "User IDs"
+// @Success 200 {object} SuccessResponse{data=entity.MembershipResponse}
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /groups/{id}/users [delete]
+func (h *GroupHandler) RemoveUsers(c *gin.Context) {
+	h.labels.changeMembership(c, h.labels.labelService.RemoveUsers, "Group members removed successfully")
+}
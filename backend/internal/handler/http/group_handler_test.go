@@ -0,0 +1,91 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"arritech-user-management/internal/domain/entity"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockGroupService = MockLabelService[entity.Group, entity.CreateGroupRequest, entity.UpdateGroupRequest]
+
+func setupGroupTestRouter() (*gin.Engine, *MockGroupService) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &MockGroupService{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	handler := NewGroupHandler(mockService, validator.New(), logger)
+
+	router := gin.New()
+	groups := router.Group("/api/v1/groups")
+	{
+		groups.POST("", handler.CreateGroup)
+		groups.GET("", handler.ListGroups)
+		groups.GET("/:id", handler.GetGroup)
+		groups.PUT("/:id", handler.UpdateGroup)
+		groups.DELETE("/:id", handler.DeleteGroup)
+		groups.POST("/:id/users", handler.AddUsers)
+		groups.DELETE("/:id/users", handler.RemoveUsers)
+	}
+
+	return router, mockService
+}
+
+func TestGroupHandler_GetGroup(t *testing.T) {
+	t.Run("Includes the member count", func(t *testing.T) {
+		router, mockService := setupGroupTestRouter()
+		mockService.On("Get", mock.Anything, uint(1)).Return(&entity.Group{ID: 1, Name: "cohort-a", MemberCount: 3}, nil)
+
+		req, _ := http.NewRequest("GET", "/api/v1/groups/1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Data entity.Group `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, int64(3), response.Data.MemberCount)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Group not found", func(t *testing.T) {
+		router, mockService := setupGroupTestRouter()
+		mockService.On("Get", mock.Anything, uint(9)).Return(nil, errors.New("group not found"))
+
+		req, _ := http.NewRequest("GET", "/api/v1/groups/9", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), `"GROUP_NOT_FOUND"`)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestGroupHandler_AddUsers(t *testing.T) {
+	router, mockService := setupGroupTestRouter()
+	mockService.On("AddUsers", mock.Anything, uint(1), []uint{1, 2}).Return(int64(2), nil)
+
+	requestBody, _ := json.Marshal(entity.MembershipRequest{UserIDs: []uint{1, 2}})
+	req, _ := http.NewRequest("POST", "/api/v1/groups/1/users", bytes.NewBuffer(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Group members added successfully")
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"net/http"
+
+	"arritech-user-management/pkg/health"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// LivenessResponse is returned by the liveness probe
+type LivenessResponse struct {
+	Status string `json:"status"`
+}
+
+type HealthHandler struct {
+	health *health.Health
+	logger *logrus.Logger
+}
+
+func NewHealthHandler(health *health.Health, logger *logrus.Logger) *HealthHandler {
+	return &HealthHandler{
+		health: health,
+		logger: logger,
+	}
+}
+
+// Livez reports that the process is running and serving HTTP. It doesn't check dependencies,
+// so an outage of the database doesn't get the process restarted. The probes live outside the
+// versioned API and are left out of the Swagger docs.
+func (h *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, LivenessResponse{Status: "ok"})
+}
+
+// Readyz runs the dependency checks and reports each one's status and latency. It returns 503
+// when a required check fails or the server is shutting down.
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	report := h.health.Ready(c.Request.Context())
+	if !report.Ready() {
+		h.logger.WithField("report", report).Warn("Readiness check failed")
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"arritech-user-management/pkg/health"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupHealthTestRouter(checks *health.Health) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	handler := NewHealthHandler(checks, logger)
+
+	router := gin.New()
+	router.GET("/livez", handler.Livez)
+	router.GET("/readyz", handler.Readyz)
+	return router
+}
+
+func TestHealthHandler_Livez(t *testing.T) {
+	checks := health.New(time.Second)
+	checks.Register("database", health.CheckerFunc(func(ctx context.Context) error { return errors.New("down") }))
+	router := setupHealthTestRouter(checks)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+	// Liveness doesn't depend on the database
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status": "ok"}`, w.Body.String())
+}
+
+func TestHealthHandler_Readyz(t *testing.T) {
+	tests := []struct {
+		name           string
+		databaseErr    error
+		drain          bool
+		expectedStatus int
+		expectedReport health.Status
+	}{
+		{name: "Ready", expectedStatus: http.StatusOK, expectedReport: health.StatusReady},
+		{name: "Database down", databaseErr: errors.New("connection refused"), expectedStatus: http.StatusServiceUnavailable, expectedReport: health.StatusNotReady},
+		{name: "Shutting down", drain: true, expectedStatus: http.StatusServiceUnavailable, expectedReport: health.StatusShuttingDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := health.New(time.Second)
+			checks.Register("database", health.CheckerFunc(func(ctx context.Context) error { return tt.databaseErr }))
+			if tt.drain {
+				checks.Drain()
+			}
+			router := setupHealthTestRouter(checks)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			var report health.Report
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+			assert.Equal(t, tt.expectedReport, report.Status)
+			if !tt.drain {
+				assert.Contains(t, report.Checks, "database")
+			}
+		})
+	}
+}
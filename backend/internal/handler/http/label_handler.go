@@ -0,0 +1,185 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+// labelHandler serves the routes shared by labels that collect users, such as tags and groups.
+// The handlers of each kind of label wrap it to document their routes.
+type labelHandler[T, C, U any] struct {
+	labelService service.LabelService[T, C, U]
+	// name is the singular name of the label, e.g. "tag"
+	name string
+	// notFound and nameTaken are the problem codes of unknown labels and duplicate names
+	notFound  problem.Code
+	nameTaken problem.Code
+	validator *validator.Validate
+	logger    *logrus.Logger
+}
+
+// title is the label's name for the start of messages, e.g. Tag
+func (h *labelHandler[T, C, U]) title() string {
+	return strings.ToUpper(h.name[:1]) + h.name[1:]
+}
+
+// id parses the label ID of the path, writing a 400 problem when it is malformed
+func (h *labelHandler[T, C, U]) id(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		invalidParameter(c, "id", "Invalid "+h.name+" ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// bind reads and validates the JSON body into req, writing a 400 problem when it is invalid
+func (h *labelHandler[T, C, U]) bind(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		h.logger.WithError(err).Error("Failed to bind JSON")
+		problem.Abort(c, problem.CodeMalformedRequest, "Invalid request format")
+		return false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		problem.Respond(c, validationProblem(req, err))
+		return false
+	}
+	return true
+}
+
+// respondError writes the problem for err: unknown labels and taken names get their codes, and
+// other errors are logged and reported as failure, e.g. "Failed to update tag"
+func (h *labelHandler[T, C, U]) respondError(c *gin.Context, err error, failure string) {
+	switch err.Error() {
+	case h.name + " not found":
+		problem.Abort(c, h.notFound, "")
+	case h.name + " name already exists":
+		problem.Abort(c, h.nameTaken, "")
+	default:
+		h.logger.WithError(err).Error(failure)
+		problem.Abort(c, problem.CodeInternalError, failure)
+	}
+}
+
+func (h *labelHandler[T, C, U]) create(c *gin.Context) {
+	var req C
+	if !h.bind(c, &req) {
+		return
+	}
+
+	label, err := h.labelService.Create(c.Request.Context(), req)
+	if err != nil {
+		h.respondError(c, err, "Failed to create "+h.name)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: h.title() + " created successfully",
+		Data:    label,
+	})
+}
+
+func (h *labelHandler[T, C, U]) list(c *gin.Context) {
+	labels, err := h.labelService.List(c.Request.Context())
+	if err != nil {
+		h.logger.WithError(err).Errorf("Failed to list %ss", h.name)
+		problem.Abort(c, problem.CodeInternalError, "Failed to list "+h.name+"s")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: h.title() + "s retrieved successfully",
+		Data:    labels,
+	})
+}
+
+func (h *labelHandler[T, C, U]) get(c *gin.Context) {
+	id, ok := h.id(c)
+	if !ok {
+		return
+	}
+
+	label, err := h.labelService.Get(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, err, "Failed to get "+h.name)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: h.title() + " retrieved successfully",
+		Data:    label,
+	})
+}
+
+func (h *labelHandler[T, C, U]) update(c *gin.Context) {
+	id, ok := h.id(c)
+	if !ok {
+		return
+	}
+
+	var req U
+	if !h.bind(c, &req) {
+		return
+	}
+
+	label, err := h.labelService.Update(c.Request.Context(), id, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to update "+h.name)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: h.title() + " updated successfully",
+		Data:    label,
+	})
+}
+
+func (h *labelHandler[T, C, U]) delete(c *gin.Context) {
+	id, ok := h.id(c)
+	if !ok {
+		return
+	}
+
+	if err := h.labelService.Delete(c.Request.Context(), id); err != nil {
+		h.respondError(c, err, "Failed to delete "+h.name)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: h.title() + " deleted successfully",
+	})
+}
+
+// changeMembership handles the shared request flow of bulk membership changes
+func (h *labelHandler[T, C, U]) changeMembership(c *gin.Context, change func(ctx context.Context, id uint, userIDs []uint) (int64, error), message string) {
+	id, ok := h.id(c)
+	if !ok {
+		return
+	}
+
+	var req entity.MembershipRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	affected, err := change(c.Request.Context(), id, req.UserIDs)
+	if err != nil {
+		h.respondError(c, err, "Failed to change "+h.name+" membership")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: message,
+		Data:    entity.MembershipResponse{Affected: affected},
+	})
+}
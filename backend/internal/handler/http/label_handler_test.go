@@ -0,0 +1,275 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"arritech-user-management/internal/domain/entity"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLabelService is a mock implementation of the LabelService interface
+type MockLabelService[T, C, U any] struct {
+	mock.Mock
+}
+
+func (m *MockLabelService[T, C, U]) Create(ctx context.Context, req C) (*T, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*T), args.Error(1)
+}
+
+func (m *MockLabelService[T, C, U]) Get(ctx context.Context, id uint) (*T, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*T), args.Error(1)
+}
+
+func (m *MockLabelService[T, C, U]) List(ctx context.Context) ([]T, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]T), args.Error(1)
+}
+
+func (m *MockLabelService[T, C, U]) Update(ctx context.Context, id uint, req U) (*T, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*T), args.Error(1)
+}
+
+func (m *MockLabelService[T, C, U]) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockLabelService[T, C, U]) AddUsers(ctx context.Context, id uint, userIDs []uint) (int64, error) {
+	args := m.Called(ctx, id, userIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockLabelService[T, C, U]) RemoveUsers(ctx context.Context, id uint, userIDs []uint) (int64, error) {
+	args := m.Called(ctx, id, userIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockTagService = MockLabelService[entity.Tag, entity.CreateTagRequest, entity.UpdateTagRequest]
+
+// The shared request flows of labels are tested through the tag routes
+func setupTagTestRouter() (*gin.Engine, *MockTagService) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &MockTagService{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	handler := NewTagHandler(mockService, validator.New(), logger)
+
+	router := gin.New()
+	tags := router.Group("/api/v1/tags")
+	{
+		tags.POST("", handler.CreateTag)
+		tags.GET("", handler.ListTags)
+		tags.GET("/:id", handler.GetTag)
+		tags.PUT("/:id", handler.UpdateTag)
+		tags.DELETE("/:id", handler.DeleteTag)
+		tags.POST("/:id/users", handler.AddUsers)
+		tags.DELETE("/:id/users", handler.RemoveUsers)
+	}
+
+	return router, mockService
+}
+
+func TestLabelHandler_Create(t *testing.T) {
+	router, mockService := setupTagTestRouter()
+	mockService.On("Create", mock.Anything, entity.CreateTagRequest{Name: "vip"}).
+		Return(nil, errors.New("tag name already exists"))
+
+	requestBody, _ := json.Marshal(entity.CreateTagRequest{Name: "vip"})
+	req, _ := http.NewRequest("POST", "/api/v1/tags", bytes.NewBuffer(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"TAG_NAME_TAKEN"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestLabelHandler_Get(t *testing.T) {
+	tests := []struct {
+		name           string
+		tagID          string
+		expectedStatus int
+		setupMock      func(*MockTagService)
+	}{
+		{
+			name:           "Successful retrieval",
+			tagID:          "1",
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockTagService) {
+				mockService.On("Get", mock.Anything, uint(1)).Return(&entity.Tag{ID: 1, Name: "vip"}, nil)
+			},
+		},
+		{
+			name:           "Invalid ID",
+			tagID:          "abc",
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockTagService) {},
+		},
+		{
+			name:           "Not found",
+			tagID:          "9",
+			expectedStatus: http.StatusNotFound,
+			setupMock: func(mockService *MockTagService) {
+				mockService.On("Get", mock.Anything, uint(9)).Return(nil, errors.New("tag not found"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupTagTestRouter()
+			tt.setupMock(mockService)
+
+			req, _ := http.NewRequest("GET", "/api/v1/tags/"+tt.tagID, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLabelHandler_Update(t *testing.T) {
+	router, mockService := setupTagTestRouter()
+
+	name := "premium"
+	mockService.On("Update", mock.Anything, uint(1), entity.UpdateTagRequest{Name: &name}).
+		Return(nil, errors.New("tag name already exists"))
+
+	requestBody, _ := json.Marshal(entity.UpdateTagRequest{Name: &name})
+	req, _ := http.NewRequest("PUT", "/api/v1/tags/1", bytes.NewBuffer(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestLabelHandler_Delete(t *testing.T) {
+	router, mockService := setupTagTestRouter()
+	mockService.On("Delete", mock.Anything, uint(9)).Return(errors.New("tag not found"))
+
+	req, _ := http.NewRequest("DELETE", "/api/v1/tags/9", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestLabelHandler_ChangeMembership(t *testing.T) {
+	tests := []struct {
+		name             string
+		method           string
+		tagID            string
+		requestBody      interface{}
+		expectedStatus   int
+		expectedAffected int64
+		setupMock        func(*MockTagService)
+	}{
+		{
+			name:             "Successful bulk add",
+			method:           "POST",
+			tagID:            "1",
+			requestBody:      entity.MembershipRequest{UserIDs: []uint{1, 2, 3}},
+			expectedStatus:   http.StatusOK,
+			expectedAffected: 2,
+			setupMock: func(mockService *MockTagService) {
+				mockService.On("AddUsers", mock.Anything, uint(1), []uint{1, 2, 3}).Return(int64(2), nil)
+			},
+		},
+		{
+			name:             "Successful bulk removal",
+			method:           "DELETE",
+			tagID:            "1",
+			requestBody:      entity.MembershipRequest{UserIDs: []uint{4}},
+			expectedStatus:   http.StatusOK,
+			expectedAffected: 1,
+			setupMock: func(mockService *MockTagService) {
+				mockService.On("RemoveUsers", mock.Anything, uint(1), []uint{4}).Return(int64(1), nil)
+			},
+		},
+		{
+			name:           "Empty user list",
+			method:         "POST",
+			tagID:          "1",
+			requestBody:    entity.MembershipRequest{UserIDs: []uint{}},
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockTagService) {},
+		},
+		{
+			name:           "Invalid ID",
+			method:         "POST",
+			tagID:          "abc",
+			requestBody:    entity.MembershipRequest{UserIDs: []uint{1}},
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockTagService) {},
+		},
+		{
+			name:           "Not found",
+			method:         "POST",
+			tagID:          "9",
+			requestBody:    entity.MembershipRequest{UserIDs: []uint{1}},
+			expectedStatus: http.StatusNotFound,
+			setupMock: func(mockService *MockTagService) {
+				mockService.On("AddUsers", mock.Anything, uint(9), []uint{1}).Return(int64(0), errors.New("tag not found"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupTagTestRouter()
+			tt.setupMock(mockService)
+
+			requestBody, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest(tt.method, "/api/v1/tags/"+tt.tagID+"/users", bytes.NewBuffer(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response struct {
+					Data entity.MembershipResponse `json:"data"`
+				}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedAffected, response.Data.Affected)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type OrganizationHandler struct {
+	organizationService service.OrganizationService
+	validator           *validator.Validate
+	logger              *logrus.Logger
+}
+
+func NewOrganizationHandler(organizationService service.OrganizationService, validator *validator.Validate, logger *logrus.Logger) *OrganizationHandler {
+	return &OrganizationHandler{
+		organizationService: organizationService,
+		validator:           validator,
+		logger:              logger,
+	}
+}
+
+// CreateOrganization creates a new organization
+// @Summary Create organization
+// @Description Create a tenant organization with its own users and settings. Needs the platform:admin permission.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param organization body entity.CreateOrganizationRequest true "Organization data"
+// @Success 201 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Router /organizations [post]
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var req entity.CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to bind JSON")
+		problem.Abort(c, problem.CodeMalformedRequest, "Invalid request format")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		problem.Respond(c, validationProblem(req, err))
+		return
+	}
+
+	org, err := h.organizationService.CreateOrganization(c.Request.Context(), req)
+	if err != nil {
+		if handlePermissionError(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrInvalidOrganization) {
+			problem.Abort(c, problem.CodeOrganizationInvalid, err.Error())
+			return
+		}
+		if err.Error() == "organization slug already exists" {
+			problem.Abort(c, problem.CodeOrganizationSlugTaken, "")
+			return
+		}
+		h.logger.WithError(err).Error("Failed to create organization")
+		problem.Abort(c, problem.CodeInternalError, "Failed to create organization")
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Organization created successfully",
+		Data:    org,
+	})
+}
+
+// ListOrganizations lists the organizations the caller can see
+// @Summary List organizations
+// @Description Get the caller's organization, or every organization ordered by name for platform admins
+// @Tags organizations
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Router /organizations [get]
+func (h *OrganizationHandler) ListOrganizations(c *gin.Context) {
+	orgs, err := h.organizationService.ListOrganizations(c.Request.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list organizations")
+		problem.Abort(c, problem.CodeInternalError, "Failed to list organizations")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Organizations retrieved successfully",
+		Data:    orgs,
+	})
+}
+
+// GetOrganization retrieves an organization by ID
+// @Summary Get organization by ID
+// @Description Get a single organization and its settings. Callers other than platform admins can only get their own organization.
+// @Tags organizations
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param id path int true "Organization ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Router /organizations/{id} [get]
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		invalidParameter(c, "id", "Invalid organization ID")
+		return
+	}
+
+	org, err := h.organizationService.GetOrganization(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrOrganizationNotFound) {
+			problem.Abort(c, problem.CodeOrganizationNotFound, "")
+			return
+		}
+		h.logger.WithError(err).Error("Failed to get organization")
+		problem.Abort(c, problem.CodeInternalError, "Failed to get organization")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Organization retrieved successfully",
+		Data:    org,
+	})
+}
+
+// UpdateOrganization updates an organization's settings
+// @Summary Update organization
+// @Description Update an organization's name, minimum user age or allowed email domains. Callers other than platform admins can only update their own organization.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param id path int true "Organization ID"
+// @Param organization body entity.UpdateOrganizationRequest true "Settings to update"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Router /organizations/{id} [put]
+func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		invalidParameter(c, "id", "Invalid organization ID")
+		return
+	}
+
+	var req entity.UpdateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to bind JSON")
+		problem.Abort(c, problem.CodeMalformedRequest, "Invalid request format")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		problem.Respond(c, validationProblem(req, err))
+		return
+	}
+
+	org, err := h.organizationService.UpdateOrganization(c.Request.Context(), uint(id), req)
+	if err != nil {
+		if handlePermissionError(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrOrganizationNotFound) {
+			problem.Abort(c, problem.CodeOrganizationNotFound, "")
+			return
+		}
+		h.logger.WithError(err).Error("Failed to update organization")
+		problem.Abort(c, problem.CodeInternalError, "Failed to update organization")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Organization updated successfully",
+		Data:    org,
+	})
+}
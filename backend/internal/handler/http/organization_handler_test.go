@@ -0,0 +1,188 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOrganizationService is a mock implementation of the OrganizationService interface
+type MockOrganizationService struct {
+	mock.Mock
+}
+
+func (m *MockOrganizationService) CreateOrganization(ctx context.Context, req entity.CreateOrganizationRequest) (*entity.Organization, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationService) GetOrganization(ctx context.Context, id uint) (*entity.Organization, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationService) ListOrganizations(ctx context.Context) ([]entity.Organization, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationService) UpdateOrganization(ctx context.Context, id uint, req entity.UpdateOrganizationRequest) (*entity.Organization, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationService) ResolveOrganization(ctx context.Context, ref string) (*entity.Organization, error) {
+	args := m.Called(ctx, ref)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationService) CurrentOrganization(ctx context.Context) (*entity.Organization, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Organization), args.Error(1)
+}
+
+func setupOrganizationTestRouter() (*gin.Engine, *MockOrganizationService) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &MockOrganizationService{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	handler := NewOrganizationHandler(mockService, validator.New(), logger)
+
+	router := gin.New()
+	organizations := router.Group("/api/v1/organizations")
+	{
+		organizations.POST("", handler.CreateOrganization)
+		organizations.GET("", handler.ListOrganizations)
+		organizations.GET("/:id", handler.GetOrganization)
+		organizations.PUT("/:id", handler.UpdateOrganization)
+	}
+
+	return router, mockService
+}
+
+func TestOrganizationHandler_CreateOrganization(t *testing.T) {
+	validRequest := entity.CreateOrganizationRequest{
+		Slug:                "acme",
+		Name:                "Acme Corp",
+		MinimumAge:          21,
+		AllowedEmailDomains: []string{"acme.com"},
+	}
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		expectedStatus int
+		setupMock      func(*MockOrganizationService)
+	}{
+		{
+			name:           "Successful creation",
+			requestBody:    validRequest,
+			expectedStatus: http.StatusCreated,
+			setupMock: func(mockService *MockOrganizationService) {
+				mockService.On("CreateOrganization", mock.Anything, validRequest).Return(&entity.Organization{ID: 2, Slug: "acme"}, nil)
+			},
+		},
+		{
+			name:           "Invalid email domain",
+			requestBody:    entity.CreateOrganizationRequest{Slug: "acme", Name: "Acme Corp", AllowedEmailDomains: []string{"not a domain"}},
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockOrganizationService) {},
+		},
+		{
+			name:           "Invalid slug",
+			requestBody:    validRequest,
+			expectedStatus: http.StatusBadRequest,
+			setupMock: func(mockService *MockOrganizationService) {
+				mockService.On("CreateOrganization", mock.Anything, validRequest).
+					Return(nil, fmt.Errorf("%w: bad slug", service.ErrInvalidOrganization))
+			},
+		},
+		{
+			name:           "Missing permission",
+			requestBody:    validRequest,
+			expectedStatus: http.StatusForbidden,
+			setupMock: func(mockService *MockOrganizationService) {
+				mockService.On("CreateOrganization", mock.Anything, validRequest).
+					Return(nil, &auth.PermissionError{Permission: auth.PermissionOrganizationsManage})
+			},
+		},
+		{
+			name:           "Duplicate slug",
+			requestBody:    validRequest,
+			expectedStatus: http.StatusConflict,
+			setupMock: func(mockService *MockOrganizationService) {
+				mockService.On("CreateOrganization", mock.Anything, validRequest).
+					Return(nil, errors.New("organization slug already exists"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupOrganizationTestRouter()
+			tt.setupMock(mockService)
+
+			requestBody, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest("POST", "/api/v1/organizations", bytes.NewBuffer(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrganizationHandler_UpdateOrganization(t *testing.T) {
+	router, mockService := setupOrganizationTestRouter()
+
+	minimumAge := 21
+	mockService.On("UpdateOrganization", mock.Anything, uint(9), entity.UpdateOrganizationRequest{MinimumAge: &minimumAge}).
+		Return(nil, fmt.Errorf("failed to update organization: %w", service.ErrOrganizationNotFound))
+
+	requestBody, _ := json.Marshal(entity.UpdateOrganizationRequest{MinimumAge: &minimumAge})
+	req, _ := http.NewRequest("PUT", "/api/v1/organizations/9", bytes.NewBuffer(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
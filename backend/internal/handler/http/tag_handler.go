@@ -0,0 +1,149 @@
+package http
+
+import (
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type TagHandler struct {
+	labels *labelHandler[entity.Tag, entity.CreateTagRequest, entity.UpdateTagRequest]
+}
+
+func NewTagHandler(tagService service.TagService, validator *validator.Validate, logger *logrus.Logger) *TagHandler {
+	return &TagHandler{labels: &labelHandler[entity.Tag, entity.CreateTagRequest, entity.UpdateTagRequest]{
+		labelService: tagService,
+		name:         "tag",
+		notFound:     problem.CodeTagNotFound,
+		nameTaken:    problem.CodeTagNameTaken,
+		validator:    validator,
+		logger:       logger,
+	}}
+}
+
+// CreateTag creates a new tag
+// @Summary Create tag
+// @Description Create a tag that can be attached to users
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param tag body entity.CreateTagRequest true "Tag data"
+// @Success 201 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /tags [post]
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	h.labels.create(c)
+}
+
+// ListTags lists all tags
+// @Summary List tags
+// @Description Get all tags ordered by name
+// @Tags tags
+// @Produce json
+// @Success 200 {object} SuccessResponse
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /tags [get]
+func (h *TagHandler) ListTags(c *gin.Context) {
+	h.labels.list(c)
+}
+
+// GetTag retrieves a tag by ID
+// @Summary Get tag by ID
+// @Description Get a single tag by its ID
+// @Tags tags
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /tags/{id} [get]
+func (h *TagHandler) GetTag(c *gin.Context) {
+	h.labels.get(c)
+}
+
+// UpdateTag updates a tag
+// @Summary Update tag
+// @Description Rename or recolor a tag
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Param tag body entity.UpdateTagRequest true "Tag fields to update"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /tags/{id} [put]
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	h.labels.update(c)
+}
+
+// DeleteTag deletes a tag
+// @Summary Delete tag
+// @Description Delete a tag and detach it from all users
+// @Tags tags
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /tags/{id} [delete]
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	h.labels.delete(c)
+}
+
+// AddUsers attaches a tag to users in bulk
+// @Summary Tag users
+// @Description Attach a tag to up to 1000 users. Users that already have the tag or do not exist are skipped.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Param users body entity.MembershipRequest true "User IDs"
+// @Success 200 {object} SuccessResponse{data=entity.MembershipResponse}
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /tags/{id}/users [post]
+func (h *TagHandler) AddUsers(c *gin.Context) {
+	h.labels.changeMembership(c, h.labels.labelService.AddUsers, "Users tagged successfully")
+}
+
+// RemoveUsers detaches a tag from users in bulk
+// @Summary Untag users
+// @Description Detach a tag from up to 1000 users
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Param users body entity.MembershipRequest true "User IDs"
+// @Success 200 {object} SuccessResponse{data=entity.MembershipResponse}
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /tags/{id}/users [delete]
+func (h *TagHandler) RemoveUsers(c *gin.Context) {
+	h.labels.changeMembership(c, h.labels.labelService.RemoveUsers, "Users untagged successfully")
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"arritech-user-management/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTagHandler_CreateTag(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		expectedStatus int
+		setupMock      func(*MockTagService)
+	}{
+		{
+			name:           "Successful creation",
+			requestBody:    entity.CreateTagRequest{Name: "vip", Color: "#ff0000"},
+			expectedStatus: http.StatusCreated,
+			setupMock: func(mockService *MockTagService) {
+				mockService.On("Create", mock.Anything, entity.CreateTagRequest{Name: "vip", Color: "#ff0000"}).
+					Return(&entity.Tag{ID: 1, Name: "vip", Color: "#ff0000"}, nil)
+			},
+		},
+		{
+			name:           "Invalid color",
+			requestBody:    entity.CreateTagRequest{Name: "vip", Color: "red"},
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockTagService) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupTagTestRouter()
+			tt.setupMock(mockService)
+
+			requestBody, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest("POST", "/api/v1/tags", bytes.NewBuffer(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTagHandler_AddUsers(t *testing.T) {
+	router, mockService := setupTagTestRouter()
+	mockService.On("AddUsers", mock.Anything, uint(1), []uint{1}).Return(int64(1), nil)
+
+	requestBody, _ := json.Marshal(entity.MembershipRequest{UserIDs: []uint{1}})
+	req, _ := http.NewRequest("POST", "/api/v1/tags/1/users", bytes.NewBuffer(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Users tagged successfully")
+	mockService.AssertExpectations(t)
+}
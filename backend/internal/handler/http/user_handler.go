@@ -1,16 +1,28 @@
 package http
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 
 	"arritech-user-management/internal/domain/entity"
 	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/problem"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/sirupsen/logrus"
 )
 
+// maxPatchBytes bounds a PATCH document, which is read whole before it is applied
+const maxPatchBytes = 64 << 10
+
 type UserHandler struct {
 	userService service.UserService
 	validator   *validator.Validate
@@ -25,12 +37,6 @@ func NewUserHandler(userService service.UserService, validator *validator.Valida
 	}
 }
 
-// ErrorResponse represents an error response
-type ErrorResponse struct {
-	Error   string            `json:"error"`
-	Details map[string]string `json:"details,omitempty"`
-}
-
 // SuccessResponse represents a success response
 type SuccessResponse struct {
 	Message string      `json:"message"`
@@ -43,41 +49,41 @@ type SuccessResponse struct {
 // @Tags users
 // @Accept json
 // @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
 // @Param user body entity.CreateUserRequest true "User information"
 // @Success 201 {object} SuccessResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /users [post]
 func (h *UserHandler) CreateUser(c *gin.Context) {
 	var req entity.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.WithError(err).Error("Failed to bind JSON")
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request format",
-		})
+		problem.Abort(c, problem.CodeMalformedRequest, "Invalid request format")
 		return
 	}
 
 	if err := h.validator.Struct(req); err != nil {
-		validationErrors := make(map[string]string)
-		for _, err := range err.(validator.ValidationErrors) {
-			validationErrors[err.Field()] = getValidationMessage(err)
-		}
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Validation failed",
-			Details: validationErrors,
-		})
+		problem.Respond(c, validationProblem(req, err))
 		return
 	}
 
 	user, err := h.userService.CreateUser(c.Request.Context(), req)
 	if err != nil {
-		if err.Error() == "email already exists" || err.Error() == "user must be older than 18 years" {
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		if handlePermissionError(c, err) {
+			return
+		}
+		if h.handleAttributeError(c, err) {
+			return
+		}
+		if h.handleRuleError(c, err) {
 			return
 		}
 		h.logger.WithError(err).Error("Failed to create user")
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create user"})
+		problem.Abort(c, problem.CodeInternalError, "Failed to create user")
 		return
 	}
 
@@ -89,38 +95,59 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 
 // GetUser retrieves a user by ID
 // @Summary Get user by ID
-// @Description Get user information by user ID
+// @Description Get user information by user ID. Phone, address, the day and month of birth and the local part of the email are masked for callers without the users:pii permission.
 // @Tags users
 // @Accept json
 // @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
 // @Param id path int true "User ID"
+// @Param fields query string false "Comma-separated fields to return, e.g. id,name,email,age; all by default"
+// @Param expand query string false "Comma-separated related resources to embed (groups)"
 // @Success 200 {object} SuccessResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /users/{id} [get]
 func (h *UserHandler) GetUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid user ID"})
+		invalidParameter(c, "id", "Invalid user ID")
+		return
+	}
+	projection, ok := parseProjection(c)
+	if !ok {
 		return
 	}
 
-	user, err := h.userService.GetUser(c.Request.Context(), uint(id))
+	user, err := h.userService.GetUserProjected(c.Request.Context(), uint(id), projection)
 	if err != nil {
+		if handlePermissionError(c, err) {
+			return
+		}
 		if err.Error() == "user not found" {
-			c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+			problem.Abort(c, problem.CodeUserNotFound, "")
 			return
 		}
 		h.logger.WithError(err).Error("Failed to get user")
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get user"})
+		problem.Abort(c, problem.CodeInternalError, "Failed to get user")
 		return
 	}
 
+	var data interface{} = user
+	if !projection.IsZero() {
+		if data, err = projectUser(user, projection); err != nil {
+			h.logger.WithError(err).Error("Failed to render user")
+			problem.Abort(c, problem.CodeInternalError, "Failed to get user")
+			return
+		}
+	}
 	c.JSON(http.StatusOK, SuccessResponse{
 		Message: "User retrieved successfully",
-		Data:    user,
+		Data:    data,
 	})
 }
 
@@ -130,55 +157,146 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 // @Tags users
 // @Accept json
 // @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
 // @Param id path int true "User ID"
 // @Param user body entity.UpdateUserRequest true "User information to update"
 // @Success 200 {object} SuccessResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /users/{id} [put]
 func (h *UserHandler) UpdateUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid user ID"})
+		invalidParameter(c, "id", "Invalid user ID")
 		return
 	}
 
 	var req entity.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.WithError(err).Error("Failed to bind JSON")
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request format"})
+		problem.Abort(c, problem.CodeMalformedRequest, "Invalid request format")
 		return
 	}
 
 	if err := h.validator.Struct(req); err != nil {
-		validationErrors := make(map[string]string)
-		for _, err := range err.(validator.ValidationErrors) {
-			validationErrors[err.Field()] = getValidationMessage(err)
-		}
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Validation failed",
-			Details: validationErrors,
-		})
+		problem.Respond(c, validationProblem(req, err))
 		return
 	}
 
-	user, err := h.userService.UpdateUser(c.Request.Context(), uint(id), req)
+	h.applyUpdate(c, uint(id), req)
+}
+
+// PatchUser partially updates a user
+// @Summary Patch user
+// @Description Partially update a user with a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902).
+// @Description The patch applies to name, email, date_of_birth, phone, address and attributes; JSON Patch
+// @Description test operations can guard against concurrent changes. The result is validated like a PUT and
+// @Description only saved if the user didn't change while the patch was applied.
+// @Tags users
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param id path int true "User ID"
+// @Param patch body object true "Merge patch document or array of JSON Patch operations"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 413 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /users/{id} [patch]
+func (h *UserHandler) PatchUser(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		if err.Error() == "user not found" {
-			c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+		invalidParameter(c, "id", "Invalid user ID")
+		return
+	}
+
+	patchType := service.PatchType(c.ContentType())
+	if patchType != service.MergePatch && patchType != service.JSONPatch {
+		problem.Abort(c, problem.CodeUnsupportedMediaType, "Content-Type must be "+string(service.MergePatch)+" or "+string(service.JSONPatch))
+		return
+	}
+
+	patch, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPatchBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			problem.Abort(c, problem.CodeRequestTooLarge, fmt.Sprintf("Patch documents may have at most %d bytes", maxPatchBytes))
 			return
 		}
-		if err.Error() == "email already exists" || err.Error() == "user must be older than 18 years" {
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
-			return
+		h.logger.WithError(err).Error("Failed to read patch")
+		problem.Abort(c, problem.CodeMalformedRequest, "Invalid request format")
+		return
+	}
+
+	user, err := h.userService.PatchUser(c.Request.Context(), uint(id), patchType, patch, func(req entity.UpdateUserRequest) error {
+		return h.validator.Struct(req)
+	})
+	if err != nil {
+		var validationErrors validator.ValidationErrors
+		switch {
+		case errors.As(err, &validationErrors):
+			problem.Respond(c, validationProblem(entity.UpdateUserRequest{}, validationErrors))
+		case errors.Is(err, service.ErrPatchTestFailed):
+			problem.Abort(c, problem.CodePatchTestFailed, err.Error())
+		case errors.Is(err, service.ErrUserModified):
+			problem.Abort(c, problem.CodeUserModified, err.Error())
+		case errors.Is(err, service.ErrInvalidPatchResult):
+			problem.Abort(c, problem.CodePatchResultInvalid, err.Error())
+		case errors.Is(err, service.ErrInvalidPatch), errors.Is(err, service.ErrUnsupportedPatchType):
+			problem.Abort(c, problem.CodePatchInvalid, err.Error())
+		default:
+			h.respondUpdateError(c, err)
 		}
-		h.logger.WithError(err).Error("Failed to update user")
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update user"})
 		return
 	}
 
+	h.respondUpdated(c, user)
+}
+
+// applyUpdate runs a validated update request and writes the response
+func (h *UserHandler) applyUpdate(c *gin.Context, id uint, req entity.UpdateUserRequest) {
+	user, err := h.userService.UpdateUser(c.Request.Context(), id, req)
+	if err != nil {
+		h.respondUpdateError(c, err)
+		return
+	}
+	h.respondUpdated(c, user)
+}
+
+// respondUpdateError writes the problem shared by PUT and PATCH for a failed update
+func (h *UserHandler) respondUpdateError(c *gin.Context, err error) {
+	if handlePermissionError(c, err) {
+		return
+	}
+	if h.handleAttributeError(c, err) {
+		return
+	}
+	if err.Error() == "user not found" {
+		problem.Abort(c, problem.CodeUserNotFound, "")
+		return
+	}
+	if h.handleRuleError(c, err) {
+		return
+	}
+	h.logger.WithError(err).Error("Failed to update user")
+	problem.Abort(c, problem.CodeInternalError, "Failed to update user")
+}
+
+// respondUpdated writes the response shared by PUT and PATCH for an updated user
+func (h *UserHandler) respondUpdated(c *gin.Context, user *entity.User) {
 	c.JSON(http.StatusOK, SuccessResponse{
 		Message: "User updated successfully",
 		Data:    user,
@@ -191,28 +309,35 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 // @Tags users
 // @Accept json
 // @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
 // @Param id path int true "User ID"
 // @Success 200 {object} SuccessResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /users/{id} [delete]
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid user ID"})
+		invalidParameter(c, "id", "Invalid user ID")
 		return
 	}
 
 	err = h.userService.DeleteUser(c.Request.Context(), uint(id))
 	if err != nil {
+		if handlePermissionError(c, err) {
+			return
+		}
 		if err.Error() == "user not found" {
-			c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+			problem.Abort(c, problem.CodeUserNotFound, "")
 			return
 		}
 		h.logger.WithError(err).Error("Failed to delete user")
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to delete user"})
+		problem.Abort(c, problem.CodeInternalError, "Failed to delete user")
 		return
 	}
 
@@ -221,20 +346,143 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	})
 }
 
+// ActivateUser moves a user to the active status
+// @Summary Activate user
+// @Description Activate a pending or suspended user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param id path int true "User ID"
+// @Param request body entity.ChangeUserStatusRequest true "Reason for the status change"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /users/{id}/activate [post]
+func (h *UserHandler) ActivateUser(c *gin.Context) {
+	h.changeUserStatus(c, entity.UserStatusActive, "User activated successfully")
+}
+
+// SuspendUser moves a user to the suspended status
+// @Summary Suspend user
+// @Description Suspend a pending or active user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param id path int true "User ID"
+// @Param request body entity.ChangeUserStatusRequest true "Reason for the status change"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /users/{id}/suspend [post]
+func (h *UserHandler) SuspendUser(c *gin.Context) {
+	h.changeUserStatus(c, entity.UserStatusSuspended, "User suspended successfully")
+}
+
+// ArchiveUser moves a user to the archived status
+// @Summary Archive user
+// @Description Archive a user, taking them out of circulation without deleting them
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param id path int true "User ID"
+// @Param request body entity.ChangeUserStatusRequest true "Reason for the status change"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /users/{id}/archive [post]
+func (h *UserHandler) ArchiveUser(c *gin.Context) {
+	h.changeUserStatus(c, entity.UserStatusArchived, "User archived successfully")
+}
+
+// changeUserStatus handles the shared flow of the status transition endpoints
+func (h *UserHandler) changeUserStatus(c *gin.Context, status entity.UserStatus, message string) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		invalidParameter(c, "id", "Invalid user ID")
+		return
+	}
+
+	var req entity.ChangeUserStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to bind JSON")
+		problem.Abort(c, problem.CodeMalformedRequest, "Invalid request format")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		problem.Respond(c, validationProblem(req, err))
+		return
+	}
+
+	user, err := h.userService.ChangeUserStatus(c.Request.Context(), uint(id), status, req.Reason)
+	if err != nil {
+		if handlePermissionError(c, err) {
+			return
+		}
+		if err.Error() == "user not found" {
+			problem.Abort(c, problem.CodeUserNotFound, "")
+			return
+		}
+		if errors.Is(err, service.ErrInvalidStatusTransition) {
+			problem.Abort(c, problem.CodeUserStatusTransitionInvalid, err.Error())
+			return
+		}
+		h.logger.WithError(err).Error("Failed to change user status")
+		problem.Abort(c, problem.CodeInternalError, "Failed to change user status")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: message,
+		Data:    user,
+	})
+}
+
 // ListUsers retrieves users with pagination and search
 // @Summary List users
-// @Description Get list of users with pagination, search and sorting functionality
+// @Description Get list of users with pagination, search and sorting functionality. Callers without the users:pii permission get masked personal data, search names only and can't sort by email or phone.
 // @Tags users
 // @Accept json
 // @Produce json
-// @Param search query string false "Search term"
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param search query string false "Search term for name, email or phone"
 // @Param page query int false "Page number" default(1)
 // @Param per_page query int false "Items per page" default(10)
 // @Param sort_by query string false "Sort field (name, email, age, phone, created_at, updated_at)" default(created_at)
 // @Param sort_dir query string false "Sort direction (asc, desc)" default(desc)
+// @Param attr[key] query string false "Filter on a custom attribute value, e.g. attr[department]=sales"
+// @Param status query string false "Status filter (pending, active, suspended, archived, all); archived users are hidden by default"
+// @Param tags query string false "Comma-separated tag names to filter on"
+// @Param tag_match query string false "Whether users need any or all of the tags (any, all)" default(any)
+// @Param group query string false "Comma-separated group names to filter on"
+// @Param group_match query string false "Whether users need to be in any or all of the groups (any, all)" default(any)
+// @Param fields query string false "Comma-separated fields to return for each user, e.g. id,name,email,age; all by default"
+// @Param expand query string false "Comma-separated related resources to embed in each user (groups)"
 // @Success 200 {object} SuccessResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /users [get]
 func (h *UserHandler) ListUsers(c *gin.Context) {
 	// Log all query parameters received
@@ -247,6 +495,7 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 		"page":         c.Query("page"),
 		"per_page":     c.Query("per_page"),
 		"search":       c.Query("search"),
+		"status":       c.Query("status"),
 	}).Info("Received query parameters for ListUsers")
 
 	// Log all available query parameters
@@ -256,7 +505,17 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 	var params entity.UserSearchParams
 	if err := c.ShouldBindQuery(&params); err != nil {
 		h.logger.WithError(err).Error("Failed to bind query parameters")
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid query parameters"})
+		problem.Abort(c, problem.CodeInvalidParameter, "Invalid query parameters")
+		return
+	}
+
+	// Custom attribute filters use the attr[key]=value form
+	if attrs := c.QueryMap("attr"); len(attrs) > 0 {
+		params.Attributes = attrs
+	}
+
+	var ok bool
+	if params.Projection, ok = parseProjection(c); !ok {
 		return
 	}
 
@@ -298,20 +557,14 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 	}
 	if !validSortFields[params.SortBy] {
 		h.logger.WithField("invalid_sort_by", params.SortBy).Error("Invalid sort field provided")
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid sort field"})
+		invalidParameter(c, "sortBy", "Invalid sort field")
 		return
 	}
 
 	if err := h.validator.Struct(params); err != nil {
-		validationErrors := make(map[string]string)
-		for _, err := range err.(validator.ValidationErrors) {
-			validationErrors[err.Field()] = getValidationMessage(err)
-		}
-		h.logger.WithError(err).WithField("validation_errors", validationErrors).Error("Validation failed")
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Validation failed",
-			Details: validationErrors,
-		})
+		p := queryValidationProblem(params, err)
+		h.logger.WithError(err).WithField("validation_errors", p.Errors).Error("Validation failed")
+		problem.Respond(c, p)
 		return
 	}
 
@@ -322,17 +575,220 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 
 	result, err := h.userService.ListUsers(c.Request.Context(), params)
 	if err != nil {
+		if handlePermissionError(c, err) {
+			return
+		}
+		if h.handleAttributeFilterError(c, err) {
+			return
+		}
 		h.logger.WithError(err).Error("Failed to list users")
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to list users"})
+		problem.Abort(c, problem.CodeInternalError, "Failed to list users")
 		return
 	}
 
+	if params.Projection.IsZero() {
+		c.JSON(http.StatusOK, SuccessResponse{
+			Message: "Users retrieved successfully",
+			Data:    result,
+		})
+		return
+	}
+	page := ProjectedUserListResponse{
+		Users:      make([]map[string]json.RawMessage, 0, len(result.Users)),
+		Total:      result.Total,
+		Page:       result.Page,
+		PerPage:    result.PerPage,
+		TotalPages: result.TotalPages,
+	}
+	for i := range result.Users {
+		user, err := projectUser(&result.Users[i], params.Projection)
+		if err != nil {
+			h.logger.WithError(err).Error("Failed to render users")
+			problem.Abort(c, problem.CodeInternalError, "Failed to list users")
+			return
+		}
+		page.Users = append(page.Users, user)
+	}
 	c.JSON(http.StatusOK, SuccessResponse{
 		Message: "Users retrieved successfully",
-		Data:    result,
+		Data:    page,
+	})
+}
+
+// handlePermissionError writes a 403 problem naming the missing permission and reports whether it did
+func handlePermissionError(c *gin.Context, err error) bool {
+	var permErr *auth.PermissionError
+	if !errors.As(err, &permErr) {
+		return false
+	}
+	problem.Abort(c, problem.CodePermissionDenied, "Missing permission "+permErr.Permission)
+	return true
+}
+
+// handleRuleError writes a 400 problem for users that break a uniqueness or organization rule
+// and reports whether it did
+func (h *UserHandler) handleRuleError(c *gin.Context, err error) bool {
+	if err.Error() == "email already exists" {
+		problem.Respond(c, problem.New(problem.CodeUserEmailTaken, err.Error()).
+			WithErrors(problem.FieldError{Pointer: "/email", Detail: "Another user has this email address"}))
+		return true
+	}
+
+	var policyErr *service.PolicyError
+	if !errors.As(err, &policyErr) {
+		return false
+	}
+	code, pointer := problem.CodeUserEmailDomainNotAllowed, "/email"
+	if policyErr.Rule == service.PolicyMinimumAge {
+		code, pointer = problem.CodeUserUnderage, "/date_of_birth"
+	}
+	problem.Respond(c, problem.New(code, policyErr.Message).
+		WithErrors(problem.FieldError{Pointer: pointer, Detail: policyErr.Message}))
+	return true
+}
+
+// handleAttributeError writes a 400 problem for invalid custom attributes in the request body
+// and reports whether it did
+func (h *UserHandler) handleAttributeError(c *gin.Context, err error) bool {
+	return h.respondAttributeError(c, err, func(key string) problem.FieldError {
+		if key == "" {
+			return problem.FieldError{Pointer: "/attributes"}
+		}
+		return problem.FieldError{Pointer: "/attributes/" + escapePointer(key)}
+	})
+}
+
+// handleAttributeFilterError writes a 400 problem for invalid attr[key] query filters and
+// reports whether it did
+func (h *UserHandler) handleAttributeFilterError(c *gin.Context, err error) bool {
+	return h.respondAttributeError(c, err, func(key string) problem.FieldError {
+		if key == "" {
+			return problem.FieldError{Parameter: "attr"}
+		}
+		return problem.FieldError{Parameter: "attr[" + key + "]"}
+	})
+}
+
+func (h *UserHandler) respondAttributeError(c *gin.Context, err error, locate func(key string) problem.FieldError) bool {
+	var attrErr *service.AttributeValidationError
+	if !errors.As(err, &attrErr) {
+		return false
+	}
+
+	keys := make([]string, 0, len(attrErr.Fields))
+	for key := range attrErr.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	p := problem.New(problem.CodeValidationFailed, invalidFieldsDetail(len(keys)))
+	for _, key := range keys {
+		fieldErr := locate(key)
+		fieldErr.Detail = attrErr.Fields[key]
+		p.WithErrors(fieldErr)
+	}
+	problem.Respond(c, p)
+	return true
+}
+
+// invalidParameter writes a 400 problem for an invalid path or query parameter
+func invalidParameter(c *gin.Context, parameter, detail string) {
+	problem.Respond(c, problem.New(problem.CodeInvalidParameter, detail).
+		WithErrors(problem.FieldError{Parameter: parameter, Detail: detail}))
+}
+
+// validationProblem describes the fields of the request body req that failed validation,
+// pointing at each with a JSON pointer such as /date_of_birth or /scopes/0
+func validationProblem(req interface{}, err error) *problem.Problem {
+	return fieldProblem(req, err, "json", func(path []string) problem.FieldError {
+		for i, segment := range path {
+			path[i] = escapePointer(segment)
+		}
+		return problem.FieldError{Pointer: "/" + strings.Join(path, "/")}
+	})
+}
+
+// queryValidationProblem describes the query parameters bound to params that failed validation
+func queryValidationProblem(params interface{}, err error) *problem.Problem {
+	return fieldProblem(params, err, "form", func(path []string) problem.FieldError {
+		return problem.FieldError{Parameter: path[0]}
 	})
 }
 
+func fieldProblem(req interface{}, err error, tag string, locate func(path []string) problem.FieldError) *problem.Problem {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return problem.New(problem.CodeValidationFailed, err.Error())
+	}
+
+	p := problem.New(problem.CodeValidationFailed, invalidFieldsDetail(len(validationErrors)))
+	for _, validationErr := range validationErrors {
+		fieldErr := locate(fieldPath(reflect.TypeOf(req), validationErr.StructNamespace(), tag))
+		fieldErr.Detail = getValidationMessage(validationErr)
+		p.WithErrors(fieldErr)
+	}
+	return p
+}
+
+func invalidFieldsDetail(n int) string {
+	if n == 1 {
+		return "The request has 1 invalid field"
+	}
+	return fmt.Sprintf("The request has %d invalid fields", n)
+}
+
+// fieldPath translates a validator namespace such as CreateAPIKeyRequest.Scopes[0] into the
+// names clients use, taken from the tag of each struct field: ["scopes", "0"]
+func fieldPath(t reflect.Type, namespace string, tag string) []string {
+	segments := strings.Split(namespace, ".")[1:]
+	path := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		name, rest, _ := strings.Cut(segment, "[")
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		field, ok := t.FieldByName(name)
+		if !ok {
+			path = append(path, name)
+			continue
+		}
+		t = field.Type
+		if tagName := tagFieldName(field, tag); tagName != "" {
+			path = append(path, tagName)
+		}
+		// Indexes and map keys, e.g. [0] or [0][1]
+		for rest != "" {
+			var index string
+			index, rest, _ = strings.Cut(rest, "]")
+			rest = strings.TrimPrefix(rest, "[")
+			path = append(path, index)
+			for t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			t = t.Elem()
+		}
+	}
+	return path
+}
+
+// tagFieldName returns the name a struct field has under tag, or "" for embedded structs
+// whose fields are flattened into the parent
+func tagFieldName(field reflect.StructField, tag string) string {
+	name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+	if name != "" && name != "-" {
+		return name
+	}
+	if field.Anonymous {
+		return ""
+	}
+	return field.Name
+}
+
+// escapePointer escapes a reference token of a JSON pointer (RFC 6901)
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
 // getValidationMessage returns a user-friendly validation message
 func getValidationMessage(err validator.FieldError) string {
 	switch err.Tag() {
@@ -340,6 +796,12 @@ func getValidationMessage(err validator.FieldError) string {
 		return "This field is required"
 	case "email":
 		return "Must be a valid email address"
+	case "hexcolor":
+		return "Must be a hex color such as #1a2b3c"
+	case "oneof":
+		return "Must be one of: " + err.Param()
+	case "datetime":
+		return "Must be a date in YYYY-MM-DD format"
 	case "min":
 		if err.Type().String() == "int" {
 			return "Must be at least " + err.Param()
@@ -5,13 +5,18 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/problem"
 
 	"github.com/gin-gonic/gin"
 	ut "github.com/go-playground/universal-translator"
@@ -42,6 +47,14 @@ func (m *MockUserService) GetUser(ctx context.Context, id uint) (*entity.User, e
 	return args.Get(0).(*entity.User), args.Error(1)
 }
 
+func (m *MockUserService) GetUserProjected(ctx context.Context, id uint, projection entity.UserProjection) (*entity.User, error) {
+	args := m.Called(ctx, id, projection)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
 func (m *MockUserService) ListUsers(ctx context.Context, params entity.UserSearchParams) (*entity.UserListResponse, error) {
 	args := m.Called(ctx, params)
 	if args.Get(0) == nil {
@@ -58,11 +71,27 @@ func (m *MockUserService) UpdateUser(ctx context.Context, id uint, req entity.Up
 	return args.Get(0).(*entity.User), args.Error(1)
 }
 
+func (m *MockUserService) PatchUser(ctx context.Context, id uint, patchType service.PatchType, patch []byte, validate func(entity.UpdateUserRequest) error) (*entity.User, error) {
+	args := m.Called(ctx, id, patchType, patch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
 func (m *MockUserService) DeleteUser(ctx context.Context, id uint) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockUserService) ChangeUserStatus(ctx context.Context, id uint, status entity.UserStatus, reason string) (*entity.User, error) {
+	args := m.Called(ctx, id, status, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
 func setupTestHandler() (*UserHandler, *MockUserService) {
 	mockService := &MockUserService{}
 	validator := validator.New()
@@ -87,7 +116,11 @@ func setupTestRouter(handler *UserHandler) *gin.Engine {
 			users.GET("", handler.ListUsers)
 			users.GET("/:id", handler.GetUser)
 			users.PUT("/:id", handler.UpdateUser)
+			users.PATCH("/:id", handler.PatchUser)
 			users.DELETE("/:id", handler.DeleteUser)
+			users.POST("/:id/activate", handler.ActivateUser)
+			users.POST("/:id/suspend", handler.SuspendUser)
+			users.POST("/:id/archive", handler.ArchiveUser)
 		}
 	}
 
@@ -99,6 +132,7 @@ func TestUserHandler_CreateUser(t *testing.T) {
 		name           string
 		requestBody    interface{}
 		expectedStatus int
+		expectedCode   problem.Code
 		setupMock      func(*MockUserService)
 	}{
 		{
@@ -129,10 +163,53 @@ func TestUserHandler_CreateUser(t *testing.T) {
 				"email": "invalid-email",
 			},
 			expectedStatus: http.StatusBadRequest,
+			expectedCode:   problem.CodeMalformedRequest,
 			setupMock: func(mockService *MockUserService) {
 				// No mock setup needed for validation failure
 			},
 		},
+		{
+			name: "Organization policy violation",
+			requestBody: entity.CreateUserRequest{
+				Name:        "Test User",
+				Email:       "test@example.com",
+				DateOfBirth: "1990-01-01",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   problem.CodeUserEmailDomainNotAllowed,
+			setupMock: func(mockService *MockUserService) {
+				mockService.On("CreateUser", mock.Anything, mock.AnythingOfType("entity.CreateUserRequest")).
+					Return(nil, &service.PolicyError{Rule: service.PolicyEmailDomain, Message: "email domain is not allowed in this organization"})
+			},
+		},
+		{
+			name: "Underage user",
+			requestBody: entity.CreateUserRequest{
+				Name:        "Test User",
+				Email:       "test@example.com",
+				DateOfBirth: "2015-01-01",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   problem.CodeUserUnderage,
+			setupMock: func(mockService *MockUserService) {
+				mockService.On("CreateUser", mock.Anything, mock.AnythingOfType("entity.CreateUserRequest")).
+					Return(nil, &service.PolicyError{Rule: service.PolicyMinimumAge, Message: "user must be older than 18 years"})
+			},
+		},
+		{
+			name: "Email taken",
+			requestBody: entity.CreateUserRequest{
+				Name:        "Test User",
+				Email:       "test@example.com",
+				DateOfBirth: "1990-01-01",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   problem.CodeUserEmailTaken,
+			setupMock: func(mockService *MockUserService) {
+				mockService.On("CreateUser", mock.Anything, mock.AnythingOfType("entity.CreateUserRequest")).
+					Return(nil, errors.New("email already exists"))
+			},
+		},
 		{
 			name: "Service error",
 			requestBody: entity.CreateUserRequest{
@@ -141,6 +218,7 @@ func TestUserHandler_CreateUser(t *testing.T) {
 				DateOfBirth: "1990-01-01",
 			},
 			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   problem.CodeInternalError,
 			setupMock: func(mockService *MockUserService) {
 				mockService.On("CreateUser", mock.Anything, mock.AnythingOfType("entity.CreateUserRequest")).Return(nil, errors.New("service error"))
 			},
@@ -162,6 +240,13 @@ func TestUserHandler_CreateUser(t *testing.T) {
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedCode != "" {
+				var response problem.Problem
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+				assert.Equal(t, tt.expectedCode, response.Code)
+				assert.Equal(t, tt.expectedStatus, response.Status)
+			}
 			mockService.AssertExpectations(t)
 		})
 	}
@@ -179,7 +264,7 @@ func TestUserHandler_GetUser(t *testing.T) {
 			userID:         "1",
 			expectedStatus: http.StatusOK,
 			setupMock: func(mockService *MockUserService) {
-				mockService.On("GetUser", mock.Anything, uint(1)).Return(&entity.User{
+				mockService.On("GetUserProjected", mock.Anything, uint(1), entity.UserProjection{}).Return(&entity.User{
 					ID:          1,
 					Name:        "Test User",
 					Email:       "test@example.com",
@@ -200,7 +285,7 @@ func TestUserHandler_GetUser(t *testing.T) {
 			userID:         "999",
 			expectedStatus: http.StatusNotFound,
 			setupMock: func(mockService *MockUserService) {
-				mockService.On("GetUser", mock.Anything, uint(999)).Return(nil, errors.New("user not found"))
+				mockService.On("GetUserProjected", mock.Anything, uint(999), entity.UserProjection{}).Return(nil, errors.New("user not found"))
 			},
 		},
 	}
@@ -358,6 +443,153 @@ func TestUserHandler_UpdateUser(t *testing.T) {
 	}
 }
 
+func TestUserHandler_PatchUser(t *testing.T) {
+	patched := &entity.User{ID: 1, Name: "Jane Smith", Email: "jane@example.com"}
+
+	tests := []struct {
+		name           string
+		userID         string
+		contentType    string
+		body           string
+		expectedStatus int
+		setupMock      func(*MockUserService)
+	}{
+		{
+			name:           "Successful merge patch",
+			userID:         "1",
+			contentType:    "application/merge-patch+json",
+			body:           `{"name":"Jane Smith"}`,
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockUserService) {
+				mockService.On("PatchUser", mock.Anything, uint(1), service.MergePatch, []byte(`{"name":"Jane Smith"}`)).Return(patched, nil)
+			},
+		},
+		{
+			name:           "Successful JSON patch with charset",
+			userID:         "1",
+			contentType:    "application/json-patch+json; charset=utf-8",
+			body:           `[{"op":"replace","path":"/name","value":"Jane Smith"}]`,
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockUserService) {
+				mockService.On("PatchUser", mock.Anything, uint(1), service.JSONPatch, mock.Anything).Return(patched, nil)
+			},
+		},
+		{
+			name:           "Unsupported content type",
+			userID:         "1",
+			contentType:    "application/json",
+			body:           `{"name":"Jane Smith"}`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+			setupMock:      func(mockService *MockUserService) {},
+		},
+		{
+			name:           "Invalid user ID",
+			userID:         "invalid",
+			contentType:    "application/merge-patch+json",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockUserService) {},
+		},
+		{
+			name:           "Patch too large",
+			userID:         "1",
+			contentType:    "application/merge-patch+json",
+			body:           `{"address":"` + strings.Repeat("a", maxPatchBytes) + `"}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			setupMock:      func(mockService *MockUserService) {},
+		},
+		{
+			name:           "User not found",
+			userID:         "1",
+			contentType:    "application/merge-patch+json",
+			body:           `{}`,
+			expectedStatus: http.StatusNotFound,
+			setupMock: func(mockService *MockUserService) {
+				mockService.On("PatchUser", mock.Anything, uint(1), service.MergePatch, mock.Anything).Return(nil, errors.New("user not found"))
+			},
+		},
+		{
+			name:           "Malformed patch",
+			userID:         "1",
+			contentType:    "application/json-patch+json",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			setupMock: func(mockService *MockUserService) {
+				mockService.On("PatchUser", mock.Anything, uint(1), service.JSONPatch, mock.Anything).Return(nil, fmt.Errorf("%w: not an array", service.ErrInvalidPatch))
+			},
+		},
+		{
+			name:           "Failed test operation",
+			userID:         "1",
+			contentType:    "application/json-patch+json",
+			body:           `[{"op":"test","path":"/name","value":"John"}]`,
+			expectedStatus: http.StatusConflict,
+			setupMock: func(mockService *MockUserService) {
+				mockService.On("PatchUser", mock.Anything, uint(1), service.JSONPatch, mock.Anything).Return(nil, fmt.Errorf("%w: /name", service.ErrPatchTestFailed))
+			},
+		},
+		{
+			name:           "User changed while the patch was applied",
+			userID:         "1",
+			contentType:    "application/json-patch+json",
+			body:           `[{"op":"test","path":"/name","value":"Jane Doe"}]`,
+			expectedStatus: http.StatusConflict,
+			setupMock: func(mockService *MockUserService) {
+				mockService.On("PatchUser", mock.Anything, uint(1), service.JSONPatch, mock.Anything).Return(nil, service.ErrUserModified)
+			},
+		},
+		{
+			name:           "Patched user is invalid",
+			userID:         "1",
+			contentType:    "application/merge-patch+json",
+			body:           `{"id":2}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			setupMock: func(mockService *MockUserService) {
+				mockService.On("PatchUser", mock.Anything, uint(1), service.MergePatch, mock.Anything).Return(nil, fmt.Errorf("%w: unknown field \"id\"", service.ErrInvalidPatchResult))
+			},
+		},
+		{
+			name:           "Patched fields fail validation",
+			userID:         "1",
+			contentType:    "application/merge-patch+json",
+			body:           `{"email":"not-an-email"}`,
+			expectedStatus: http.StatusBadRequest,
+			setupMock: func(mockService *MockUserService) {
+				invalid := validator.New().Struct(entity.UpdateUserRequest{Email: stringPtr("not-an-email")})
+				mockService.On("PatchUser", mock.Anything, uint(1), service.MergePatch, mock.Anything).Return(nil, fmt.Errorf("%w: %w", service.ErrInvalidPatchResult, invalid))
+			},
+		},
+		{
+			name:           "Business rule violation",
+			userID:         "1",
+			contentType:    "application/merge-patch+json",
+			body:           `{"email":"taken@example.com"}`,
+			expectedStatus: http.StatusBadRequest,
+			setupMock: func(mockService *MockUserService) {
+				mockService.On("PatchUser", mock.Anything, uint(1), service.MergePatch, mock.Anything).Return(nil, errors.New("email already exists"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupTestHandler()
+			router := setupTestRouter(handler)
+
+			tt.setupMock(mockService)
+
+			req, _ := http.NewRequest("PATCH", "/api/v1/users/"+tt.userID, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestUserHandler_DeleteUser(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -408,6 +640,224 @@ func TestUserHandler_DeleteUser(t *testing.T) {
 	}
 }
 
+func TestUserHandler_MissingPermission(t *testing.T) {
+	handler, mockService := setupTestHandler()
+	router := setupTestRouter(handler)
+
+	mockService.On("DeleteUser", mock.Anything, uint(1)).Return(&auth.PermissionError{Permission: auth.PermissionUsersDelete})
+
+	req, _ := http.NewRequest("DELETE", "/api/v1/users/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.JSONEq(t, `{
+		"type": "https://arritech.com/problems/permission-denied",
+		"title": "Permission denied",
+		"status": 403,
+		"detail": "Missing permission users:delete",
+		"code": "PERMISSION_DENIED"
+	}`, w.Body.String())
+}
+
+func TestUserHandler_ValidationProblem(t *testing.T) {
+	handler, _ := setupTestHandler()
+	router := setupTestRouter(handler)
+
+	req, _ := http.NewRequest("POST", "/api/v1/users", bytes.NewBufferString(`{"name":"J","email":"not-an-email","date_of_birth":"1990-01-01"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(problem.RequestIDHeader, "req-42")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "https://arritech.com/problems/validation-failed",
+		"title": "Validation failed",
+		"status": 400,
+		"detail": "The request has 2 invalid fields",
+		"instance": "req-42",
+		"code": "VALIDATION_FAILED",
+		"errors": [
+			{"pointer": "/name", "detail": "Must be at least 2 characters long"},
+			{"pointer": "/email", "detail": "Must be a valid email address"}
+		]
+	}`, w.Body.String())
+}
+
+func TestFieldPath(t *testing.T) {
+	type address struct {
+		Street string `json:"street"`
+	}
+	type embedded struct {
+		Note string `json:"note"`
+	}
+	type request struct {
+		embedded
+		Name      string            `json:"name" form:"full_name"`
+		Scopes    []string          `json:"scopes"`
+		Addresses []*address        `json:"addresses"`
+		Labels    map[string]string `json:"labels"`
+		Untagged  int
+	}
+	typ := reflect.TypeOf(request{})
+
+	tests := []struct {
+		namespace string
+		tag       string
+		expected  []string
+	}{
+		{"request.Name", "json", []string{"name"}},
+		{"request.Name", "form", []string{"full_name"}},
+		{"request.Scopes[0]", "json", []string{"scopes", "0"}},
+		{"request.Addresses[1].Street", "json", []string{"addresses", "1", "street"}},
+		{"request.Labels[team/a]", "json", []string{"labels", "team/a"}},
+		{"request.embedded.Note", "json", []string{"note"}},
+		{"request.Untagged", "json", []string{"Untagged"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.namespace+" "+tt.tag, func(t *testing.T) {
+			assert.Equal(t, tt.expected, fieldPath(typ, tt.namespace, tt.tag))
+		})
+	}
+	assert.Equal(t, "team~1a~0b", escapePointer("team/a~b"))
+}
+
+func TestUserHandler_InvalidAttributes(t *testing.T) {
+	handler, mockService := setupTestHandler()
+	router := setupTestRouter(handler)
+
+	mockService.On("CreateUser", mock.Anything, mock.AnythingOfType("entity.CreateUserRequest")).Return(nil, &service.AttributeValidationError{
+		Fields: map[string]string{"department": "Must be one of: engineering, sales"},
+	})
+	mockService.On("ListUsers", mock.Anything, mock.MatchedBy(func(params entity.UserSearchParams) bool {
+		return params.Attributes["shoe_size"] == "42"
+	})).Return(nil, &service.AttributeValidationError{
+		Fields: map[string]string{"shoe_size": "Unknown attribute"},
+	})
+
+	requestBody, _ := json.Marshal(entity.CreateUserRequest{
+		Name:        "Test User",
+		Email:       "test@example.com",
+		DateOfBirth: "1990-01-01",
+		Attributes:  map[string]interface{}{"department": "marketing"},
+	})
+	req, _ := http.NewRequest("POST", "/api/v1/users", bytes.NewBuffer(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response problem.Problem
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, problem.CodeValidationFailed, response.Code)
+	assert.Equal(t, []problem.FieldError{{Pointer: "/attributes/department", Detail: "Must be one of: engineering, sales"}}, response.Errors)
+
+	req, _ = http.NewRequest("GET", "/api/v1/users?attr[shoe_size]=42", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	response = problem.Problem{}
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []problem.FieldError{{Parameter: "attr[shoe_size]", Detail: "Unknown attribute"}}, response.Errors)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_ChangeUserStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		requestBody    interface{}
+		expectedStatus int
+		setupMock      func(*MockUserService)
+	}{
+		{
+			name:           "Successful activation",
+			path:           "/api/v1/users/1/activate",
+			requestBody:    entity.ChangeUserStatusRequest{Reason: "Onboarding completed"},
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockUserService) {
+				mockService.On("ChangeUserStatus", mock.Anything, uint(1), entity.UserStatusActive, "Onboarding completed").Return(&entity.User{
+					ID:     1,
+					Name:   "Test User",
+					Status: entity.UserStatusActive,
+				}, nil)
+			},
+		},
+		{
+			name:           "Successful suspension",
+			path:           "/api/v1/users/1/suspend",
+			requestBody:    entity.ChangeUserStatusRequest{Reason: "Policy violation"},
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockUserService) {
+				mockService.On("ChangeUserStatus", mock.Anything, uint(1), entity.UserStatusSuspended, "Policy violation").Return(&entity.User{
+					ID:     1,
+					Name:   "Test User",
+					Status: entity.UserStatusSuspended,
+				}, nil)
+			},
+		},
+		{
+			name:           "Missing reason",
+			path:           "/api/v1/users/1/archive",
+			requestBody:    entity.ChangeUserStatusRequest{},
+			expectedStatus: http.StatusBadRequest,
+			setupMock: func(mockService *MockUserService) {
+				// No mock setup needed for validation failure
+			},
+		},
+		{
+			name:           "Invalid user ID",
+			path:           "/api/v1/users/invalid/archive",
+			requestBody:    entity.ChangeUserStatusRequest{Reason: "Left the company"},
+			expectedStatus: http.StatusBadRequest,
+			setupMock: func(mockService *MockUserService) {
+				// No mock setup needed for validation failure
+			},
+		},
+		{
+			name:           "User not found",
+			path:           "/api/v1/users/999/archive",
+			requestBody:    entity.ChangeUserStatusRequest{Reason: "Left the company"},
+			expectedStatus: http.StatusNotFound,
+			setupMock: func(mockService *MockUserService) {
+				mockService.On("ChangeUserStatus", mock.Anything, uint(999), entity.UserStatusArchived, "Left the company").Return(nil, errors.New("user not found"))
+			},
+		},
+		{
+			name:           "Invalid transition",
+			path:           "/api/v1/users/1/activate",
+			requestBody:    entity.ChangeUserStatusRequest{Reason: "Came back"},
+			expectedStatus: http.StatusConflict,
+			setupMock: func(mockService *MockUserService) {
+				mockService.On("ChangeUserStatus", mock.Anything, uint(1), entity.UserStatusActive, "Came back").Return(nil, fmt.Errorf("%w: cannot change status from archived to active", service.ErrInvalidStatusTransition))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupTestHandler()
+			router := setupTestRouter(handler)
+
+			tt.setupMock(mockService)
+
+			requestBody, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest("POST", tt.path, bytes.NewBuffer(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestGetValidationMessage(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,314 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type UserNoteHandler struct {
+	noteService service.UserNoteService
+	validator   *validator.Validate
+	logger      *logrus.Logger
+}
+
+func NewUserNoteHandler(noteService service.UserNoteService, validator *validator.Validate, logger *logrus.Logger) *UserNoteHandler {
+	return &UserNoteHandler{
+		noteService: noteService,
+		validator:   validator,
+		logger:      logger,
+	}
+}
+
+// CreateNote adds an internal note to a user
+// @Summary Create note
+// @Description Add an internal markdown note to a user
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param id path int true "User ID"
+// @Param note body entity.CreateUserNoteRequest true "Note data"
+// @Success 201 {object} SuccessResponse{data=entity.UserNote}
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /users/{id}/notes [post]
+func (h *UserNoteHandler) CreateNote(c *gin.Context) {
+	userID, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	var req entity.CreateUserNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to bind JSON")
+		problem.Abort(c, problem.CodeMalformedRequest, "Invalid request format")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		problem.Respond(c, validationProblem(req, err))
+		return
+	}
+
+	note, err := h.noteService.CreateNote(c.Request.Context(), userID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to create note")
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Note created successfully",
+		Data:    note,
+	})
+}
+
+// ListNotes retrieves the notes of a user
+// @Summary List notes
+// @Description Get all notes of a user, pinned notes first and then newest first
+// @Tags notes
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param id path int true "User ID"
+// @Success 200 {object} SuccessResponse{data=[]entity.UserNote}
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /users/{id}/notes [get]
+func (h *UserNoteHandler) ListNotes(c *gin.Context) {
+	userID, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	notes, err := h.noteService.ListNotes(c.Request.Context(), userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to list notes")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Notes retrieved successfully",
+		Data:    notes,
+	})
+}
+
+// GetNote retrieves a note of a user
+// @Summary Get note
+// @Description Get a single note of a user
+// @Tags notes
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param id path int true "User ID"
+// @Param noteId path int true "Note ID"
+// @Success 200 {object} SuccessResponse{data=entity.UserNote}
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /users/{id}/notes/{noteId} [get]
+func (h *UserNoteHandler) GetNote(c *gin.Context) {
+	userID, noteID, ok := parseNoteIDs(c)
+	if !ok {
+		return
+	}
+
+	note, err := h.noteService.GetNote(c.Request.Context(), userID, noteID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get note")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Note retrieved successfully",
+		Data:    note,
+	})
+}
+
+// UpdateNote updates a note of a user
+// @Summary Update note
+// @Description Edit the body of a note or pin and unpin it
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param id path int true "User ID"
+// @Param noteId path int true "Note ID"
+// @Param note body entity.UpdateUserNoteRequest true "Note fields to update"
+// @Success 200 {object} SuccessResponse{data=entity.UserNote}
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /users/{id}/notes/{noteId} [put]
+func (h *UserNoteHandler) UpdateNote(c *gin.Context) {
+	userID, noteID, ok := parseNoteIDs(c)
+	if !ok {
+		return
+	}
+
+	var req entity.UpdateUserNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to bind JSON")
+		problem.Abort(c, problem.CodeMalformedRequest, "Invalid request format")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		problem.Respond(c, validationProblem(req, err))
+		return
+	}
+
+	note, err := h.noteService.UpdateNote(c.Request.Context(), userID, noteID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to update note")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Note updated successfully",
+		Data:    note,
+	})
+}
+
+// DeleteNote deletes a note of a user
+// @Summary Delete note
+// @Description Delete a note of a user
+// @Tags notes
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param id path int true "User ID"
+// @Param noteId path int true "Note ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /users/{id}/notes/{noteId} [delete]
+func (h *UserNoteHandler) DeleteNote(c *gin.Context) {
+	userID, noteID, ok := parseNoteIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.noteService.DeleteNote(c.Request.Context(), userID, noteID); err != nil {
+		h.respondError(c, err, "Failed to delete note")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Note deleted successfully",
+	})
+}
+
+// Timeline retrieves the activity timeline of a user
+// @Summary Get user timeline
+// @Description Get a user's notes and lifecycle events (created, updated, status changes, deleted), newest first. Pass next_cursor of a page as cursor to get the next one.
+// @Tags notes
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param id path int true "User ID"
+// @Param before query string false "Only entries older than this RFC 3339 time"
+// @Param cursor query string false "Only entries after this next_cursor of a previous page"
+// @Param limit query int false "Entries per page (max 100)" default(20)
+// @Success 200 {object} SuccessResponse{data=entity.TimelineResponse}
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /users/{id}/timeline [get]
+func (h *UserNoteHandler) Timeline(c *gin.Context) {
+	userID, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	var params entity.TimelineParams
+	if before := c.Query("before"); before != "" {
+		t, err := time.Parse(time.RFC3339Nano, before)
+		if err != nil {
+			invalidParameter(c, "before", "Invalid before time, use RFC 3339")
+			return
+		}
+		params.Before = t
+	}
+	if cursor := c.Query("cursor"); cursor != "" {
+		after, err := entity.ParseTimelineCursor(cursor)
+		if err != nil {
+			invalidParameter(c, "cursor", "Invalid cursor")
+			return
+		}
+		params.After = &after
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			invalidParameter(c, "limit", "Invalid limit")
+			return
+		}
+		params.Limit = n
+	}
+
+	timeline, err := h.noteService.Timeline(c.Request.Context(), userID, params)
+	if err != nil {
+		h.respondError(c, err, "Failed to get timeline")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Timeline retrieved successfully",
+		Data:    timeline,
+	})
+}
+
+func (h *UserNoteHandler) respondError(c *gin.Context, err error, message string) {
+	switch err.Error() {
+	case "user not found":
+		problem.Abort(c, problem.CodeUserNotFound, "")
+	case "note not found":
+		problem.Abort(c, problem.CodeNoteNotFound, "")
+	default:
+		h.logger.WithError(err).Error(message)
+		problem.Abort(c, problem.CodeInternalError, message)
+	}
+}
+
+// parseUserID reads the user ID path parameter, responding with 400 when it is invalid
+func parseUserID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		invalidParameter(c, "id", "Invalid user ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// parseNoteIDs reads the user and note ID path parameters, responding with 400 when either is invalid
+func parseNoteIDs(c *gin.Context) (uint, uint, bool) {
+	userID, ok := parseUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	noteID, err := strconv.ParseUint(c.Param("noteId"), 10, 32)
+	if err != nil {
+		invalidParameter(c, "noteId", "Invalid note ID")
+		return 0, 0, false
+	}
+	return userID, uint(noteID), true
+}
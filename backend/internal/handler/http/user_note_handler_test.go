@@ -0,0 +1,319 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockUserNoteService is a mock implementation of the UserNoteService interface
+type MockUserNoteService struct {
+	mock.Mock
+}
+
+func (m *MockUserNoteService) CreateNote(ctx context.Context, userID uint, req entity.CreateUserNoteRequest) (*entity.UserNote, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.UserNote), args.Error(1)
+}
+
+func (m *MockUserNoteService) GetNote(ctx context.Context, userID, id uint) (*entity.UserNote, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.UserNote), args.Error(1)
+}
+
+func (m *MockUserNoteService) ListNotes(ctx context.Context, userID uint) ([]entity.UserNote, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.UserNote), args.Error(1)
+}
+
+func (m *MockUserNoteService) UpdateNote(ctx context.Context, userID, id uint, req entity.UpdateUserNoteRequest) (*entity.UserNote, error) {
+	args := m.Called(ctx, userID, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.UserNote), args.Error(1)
+}
+
+func (m *MockUserNoteService) DeleteNote(ctx context.Context, userID, id uint) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockUserNoteService) Timeline(ctx context.Context, userID uint, params entity.TimelineParams) (*entity.TimelineResponse, error) {
+	args := m.Called(ctx, userID, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.TimelineResponse), args.Error(1)
+}
+
+func setupUserNoteTestRouter() (*gin.Engine, *MockUserNoteService) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &MockUserNoteService{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	handler := NewUserNoteHandler(mockService, validator.New(), logger)
+
+	router := gin.New()
+	users := router.Group("/api/v1/users")
+	{
+		users.POST("/:id/notes", handler.CreateNote)
+		users.GET("/:id/notes", handler.ListNotes)
+		users.GET("/:id/notes/:noteId", handler.GetNote)
+		users.PUT("/:id/notes/:noteId", handler.UpdateNote)
+		users.DELETE("/:id/notes/:noteId", handler.DeleteNote)
+		users.GET("/:id/timeline", handler.Timeline)
+	}
+
+	return router, mockService
+}
+
+func TestUserNoteHandler_CreateNote(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		requestBody    interface{}
+		expectedStatus int
+		setupMock      func(*MockUserNoteService)
+	}{
+		{
+			name:           "Successful creation",
+			url:            "/api/v1/users/1/notes",
+			requestBody:    entity.CreateUserNoteRequest{Author: "alice", Body: "Prefers email"},
+			expectedStatus: http.StatusCreated,
+			setupMock: func(mockService *MockUserNoteService) {
+				mockService.On("CreateNote", mock.Anything, uint(1), entity.CreateUserNoteRequest{Author: "alice", Body: "Prefers email"}).
+					Return(&entity.UserNote{ID: 1, UserID: 1, Author: "alice", Body: "Prefers email"}, nil)
+			},
+		},
+		{
+			name:           "Missing body",
+			url:            "/api/v1/users/1/notes",
+			requestBody:    entity.CreateUserNoteRequest{Author: "alice"},
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockUserNoteService) {},
+		},
+		{
+			name:           "Invalid user ID",
+			url:            "/api/v1/users/abc/notes",
+			requestBody:    entity.CreateUserNoteRequest{Author: "alice", Body: "text"},
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockUserNoteService) {},
+		},
+		{
+			name:           "User not found",
+			url:            "/api/v1/users/999/notes",
+			requestBody:    entity.CreateUserNoteRequest{Author: "alice", Body: "text"},
+			expectedStatus: http.StatusNotFound,
+			setupMock: func(mockService *MockUserNoteService) {
+				mockService.On("CreateNote", mock.Anything, uint(999), mock.Anything).Return(nil, errors.New("user not found"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupUserNoteTestRouter()
+			tt.setupMock(mockService)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, tt.url, bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserNoteHandler_NoteByID(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		expectedStatus int
+		setupMock      func(*MockUserNoteService)
+	}{
+		{
+			name:           "Get note",
+			method:         http.MethodGet,
+			url:            "/api/v1/users/1/notes/2",
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockUserNoteService) {
+				mockService.On("GetNote", mock.Anything, uint(1), uint(2)).Return(&entity.UserNote{ID: 2}, nil)
+			},
+		},
+		{
+			name:           "Get note of other user",
+			method:         http.MethodGet,
+			url:            "/api/v1/users/1/notes/3",
+			expectedStatus: http.StatusNotFound,
+			setupMock: func(mockService *MockUserNoteService) {
+				mockService.On("GetNote", mock.Anything, uint(1), uint(3)).Return(nil, errors.New("note not found"))
+			},
+		},
+		{
+			name:           "Invalid note ID",
+			method:         http.MethodGet,
+			url:            "/api/v1/users/1/notes/abc",
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockUserNoteService) {},
+		},
+		{
+			name:           "Pin note",
+			method:         http.MethodPut,
+			url:            "/api/v1/users/1/notes/2",
+			body:           `{"pinned":true}`,
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockUserNoteService) {
+				mockService.On("UpdateNote", mock.Anything, uint(1), uint(2), mock.MatchedBy(func(req entity.UpdateUserNoteRequest) bool {
+					return req.Pinned != nil && *req.Pinned && req.Body == nil
+				})).Return(&entity.UserNote{ID: 2, Pinned: true}, nil)
+			},
+		},
+		{
+			name:           "Update with empty body",
+			method:         http.MethodPut,
+			url:            "/api/v1/users/1/notes/2",
+			body:           `{"body":""}`,
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockUserNoteService) {},
+		},
+		{
+			name:           "Delete note",
+			method:         http.MethodDelete,
+			url:            "/api/v1/users/1/notes/2",
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockUserNoteService) {
+				mockService.On("DeleteNote", mock.Anything, uint(1), uint(2)).Return(nil)
+			},
+		},
+		{
+			name:           "Delete fails",
+			method:         http.MethodDelete,
+			url:            "/api/v1/users/1/notes/2",
+			expectedStatus: http.StatusInternalServerError,
+			setupMock: func(mockService *MockUserNoteService) {
+				mockService.On("DeleteNote", mock.Anything, uint(1), uint(2)).Return(errors.New("database error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupUserNoteTestRouter()
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserNoteHandler_Timeline(t *testing.T) {
+	before := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cursor := entity.TimelineCursor{Time: before, Kind: entity.TimelineEntryNote, ID: 7}
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		setupMock      func(*MockUserNoteService)
+	}{
+		{
+			name:           "First page",
+			url:            "/api/v1/users/1/timeline",
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockUserNoteService) {
+				mockService.On("Timeline", mock.Anything, uint(1), entity.TimelineParams{}).Return(&entity.TimelineResponse{}, nil)
+			},
+		},
+		{
+			name:           "With cursor and limit",
+			url:            "/api/v1/users/1/timeline?before=2024-05-01T12:00:00Z&limit=5",
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockUserNoteService) {
+				mockService.On("Timeline", mock.Anything, uint(1), entity.TimelineParams{Before: before, Limit: 5}).Return(&entity.TimelineResponse{}, nil)
+			},
+		},
+		{
+			name:           "With next cursor",
+			url:            "/api/v1/users/1/timeline?cursor=" + cursor.String(),
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockUserNoteService) {
+				mockService.On("Timeline", mock.Anything, uint(1), entity.TimelineParams{After: &cursor}).Return(&entity.TimelineResponse{}, nil)
+			},
+		},
+		{
+			name:           "Malformed next cursor",
+			url:            "/api/v1/users/1/timeline?cursor=garbage",
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockUserNoteService) {},
+		},
+		{
+			name:           "Invalid before",
+			url:            "/api/v1/users/1/timeline?before=yesterday",
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockUserNoteService) {},
+		},
+		{
+			name:           "Invalid limit",
+			url:            "/api/v1/users/1/timeline?limit=0",
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(mockService *MockUserNoteService) {},
+		},
+		{
+			name:           "User not found",
+			url:            "/api/v1/users/999/timeline",
+			expectedStatus: http.StatusNotFound,
+			setupMock: func(mockService *MockUserNoteService) {
+				mockService.On("Timeline", mock.Anything, uint(999), entity.TimelineParams{}).Return(nil, errors.New("user not found"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupUserNoteTestRouter()
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+
+	"arritech-user-management/internal/domain/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// ProjectedUserListResponse is a page of users limited to the fields asked for with ?fields=
+type ProjectedUserListResponse struct {
+	Users      []map[string]json.RawMessage `json:"users"`
+	Total      int64                        `json:"total"`
+	Page       int                          `json:"page"`
+	PerPage    int                          `json:"per_page"`
+	TotalPages int                          `json:"total_pages"`
+}
+
+// EmbeddedGroup is a group embedded in a user with ?expand=groups
+type EmbeddedGroup struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// parseProjection reads ?fields= and ?expand=, writing a 400 problem naming the parameter and
+// the valid values when either has an unknown name
+func parseProjection(c *gin.Context) (entity.UserProjection, bool) {
+	projection, err := entity.ParseUserProjection(c.Query("fields"), c.Query("expand"))
+	if err != nil {
+		var fieldErr *entity.UnknownFieldError
+		if errors.As(err, &fieldErr) {
+			invalidParameter(c, fieldErr.Parameter, err.Error())
+		} else {
+			invalidParameter(c, "fields", err.Error())
+		}
+		return entity.UserProjection{}, false
+	}
+	return projection, true
+}
+
+// projectUser renders user with only the fields of projection and its expanded resources.
+// The ID is always kept, as is pii_redacted on masked users.
+func projectUser(user *entity.User, projection entity.UserProjection) (map[string]json.RawMessage, error) {
+	// Rendering through the user's own JSON keeps its masking and formats
+	body, err := json.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+	for key := range fields {
+		if key != "pii_redacted" && !projection.Selects(key) {
+			delete(fields, key)
+		}
+	}
+
+	if projection.Expands("groups") {
+		groups := make([]EmbeddedGroup, 0, len(user.Groups))
+		for _, group := range user.Groups {
+			groups = append(groups, EmbeddedGroup{ID: group.ID, Name: group.Name, Description: group.Description})
+		}
+		if fields["groups"], err = json.Marshal(groups); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
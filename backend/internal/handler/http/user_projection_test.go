@@ -0,0 +1,125 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/problem"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func projectionTestUser() *entity.User {
+	return &entity.User{
+		ID:          1,
+		Name:        "Test User",
+		Email:       "test@example.com",
+		DateOfBirth: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+		Age:         35,
+		Address:     "1 Main St",
+		Status:      entity.UserStatusActive,
+		Groups:      []entity.Group{{ID: 4, Name: "ops", MemberCount: 12}},
+	}
+}
+
+func TestProjectUser(t *testing.T) {
+	t.Run("Keeps the selected fields and the ID", func(t *testing.T) {
+		fields, err := projectUser(projectionTestUser(), entity.UserProjection{Fields: []string{"name", "age"}})
+
+		require.NoError(t, err)
+		body, _ := json.Marshal(fields)
+		assert.JSONEq(t, `{"id": 1, "name": "Test User", "age": 35}`, string(body))
+	})
+
+	t.Run("Keeps the masking of redacted users", func(t *testing.T) {
+		user := projectionTestUser()
+		user.RedactPII()
+
+		fields, err := projectUser(user, entity.UserProjection{Fields: []string{"date_of_birth", "address"}})
+
+		require.NoError(t, err)
+		body, _ := json.Marshal(fields)
+		assert.JSONEq(t, `{"id": 1, "date_of_birth": "1990-**-**", "address": "***", "pii_redacted": true}`, string(body))
+	})
+
+	t.Run("Embeds expanded groups", func(t *testing.T) {
+		fields, err := projectUser(projectionTestUser(), entity.UserProjection{Fields: []string{"name"}, Expand: []string{"groups"}})
+
+		require.NoError(t, err)
+		body, _ := json.Marshal(fields)
+		assert.JSONEq(t, `{"id": 1, "name": "Test User", "groups": [{"id": 4, "name": "ops"}]}`, string(body))
+	})
+}
+
+func TestUserHandler_Projection(t *testing.T) {
+	t.Run("Returns only the requested fields of a user", func(t *testing.T) {
+		handler, mockService := setupTestHandler()
+		router := setupTestRouter(handler)
+		mockService.On("GetUserProjected", mock.Anything, uint(1), entity.UserProjection{Fields: []string{"name", "age"}}).Return(projectionTestUser(), nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users/1?fields=name,age", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, map[string]interface{}{"id": float64(1), "name": "Test User", "age": float64(35)}, response.Data)
+	})
+
+	t.Run("Pushes the projection into the search of a list", func(t *testing.T) {
+		handler, mockService := setupTestHandler()
+		router := setupTestRouter(handler)
+		mockService.On("ListUsers", mock.Anything, mock.MatchedBy(func(params entity.UserSearchParams) bool {
+			return assert.ObjectsAreEqual(entity.UserProjection{Fields: []string{"id", "email"}, Expand: []string{"groups"}}, params.Projection)
+		})).Return(&entity.UserListResponse{Users: []entity.User{*projectionTestUser()}, Total: 1, Page: 1, PerPage: 10, TotalPages: 1}, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users?fields=id,email&expand=groups", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Data ProjectedUserListResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, int64(1), response.Data.Total)
+		require.Len(t, response.Data.Users, 1)
+		body, _ := json.Marshal(response.Data.Users[0])
+		assert.JSONEq(t, `{"id": 1, "email": "test@example.com", "groups": [{"id": 4, "name": "ops"}]}`, string(body))
+	})
+
+	tests := []struct {
+		name      string
+		path      string
+		parameter string
+	}{
+		{name: "Unknown field of a user", path: "/api/v1/users/1?fields=name,password", parameter: "fields"},
+		{name: "Unknown field of a list", path: "/api/v1/users?fields=salary", parameter: "fields"},
+		{name: "Unknown expansion", path: "/api/v1/users?expand=notes", parameter: "expand"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupTestHandler()
+			router := setupTestRouter(handler)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var response problem.Problem
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, problem.CodeInvalidParameter, response.Code)
+			require.Len(t, response.Errors, 1)
+			assert.Equal(t, tt.parameter, response.Errors[0].Parameter)
+			assert.Contains(t, response.Errors[0].Detail, "is not a valid value of "+tt.parameter)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type UserStatsHandler struct {
+	statsService service.UserStatsService
+	validator    *validator.Validate
+	logger       *logrus.Logger
+}
+
+func NewUserStatsHandler(statsService service.UserStatsService, validator *validator.Validate, logger *logrus.Logger) *UserStatsHandler {
+	return &UserStatsHandler{
+		statsService: statsService,
+		validator:    validator,
+		logger:       logger,
+	}
+}
+
+// GetStats retrieves user statistics
+// @Summary Get user statistics
+// @Description Get user totals, counts by status, an age histogram, signups over time, the top email domains and the distribution by phone calling code
+// @Tags users
+// @Produce json
+// @Param X-Organization header string false "Organization ID or slug (defaults to DEFAULT_ORGANIZATION)"
+// @Param age_buckets query string false "Comma-separated ages where histogram buckets start" default(18,25,35,45,55,65)
+// @Param interval query string false "Signup interval: day, week or month" default(day)
+// @Param from query string false "First day of the signup series, YYYY-MM-DD (default: 30 days before to)"
+// @Param to query string false "Last day of the signup series, YYYY-MM-DD (default: today)"
+// @Param top_domains query int false "Number of email domains to return (max 100)" default(10)
+// @Success 200 {object} SuccessResponse{data=entity.UserStats}
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /users/stats [get]
+func (h *UserStatsHandler) GetStats(c *gin.Context) {
+	var params entity.UserStatsParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		h.logger.WithError(err).Error("Failed to bind query parameters")
+		problem.Abort(c, problem.CodeInvalidParameter, "Invalid query parameters")
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		problem.Respond(c, queryValidationProblem(params, err))
+		return
+	}
+
+	stats, err := h.statsService.GetStats(c.Request.Context(), params)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidStatsParams) {
+			problem.Abort(c, problem.CodeStatsParamsInvalid, err.Error())
+			return
+		}
+		h.logger.WithError(err).Error("Failed to get user statistics")
+		problem.Abort(c, problem.CodeInternalError, "Failed to get user statistics")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "User statistics retrieved successfully",
+		Data:    stats,
+	})
+}
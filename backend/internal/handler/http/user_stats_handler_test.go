@@ -0,0 +1,132 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/service"
+	"arritech-user-management/pkg/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockUserStatsService is a mock implementation of the UserStatsService interface
+type MockUserStatsService struct {
+	mock.Mock
+}
+
+func (m *MockUserStatsService) GetStats(ctx context.Context, params entity.UserStatsParams) (*entity.UserStats, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.UserStats), args.Error(1)
+}
+
+func setupUserStatsTestRouter() (*gin.Engine, *MockUserStatsService) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &MockUserStatsService{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	handler := NewUserStatsHandler(mockService, validator.New(), logger)
+
+	router := gin.New()
+	router.GET("/api/v1/users/stats", handler.GetStats)
+
+	return router, mockService
+}
+
+func TestUserStatsHandler_GetStats(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		expectedField  string
+		setupMock      func(*MockUserStatsService)
+	}{
+		{
+			name:           "Default parameters",
+			url:            "/api/v1/users/stats",
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockUserStatsService) {
+				mockService.On("GetStats", mock.Anything, entity.UserStatsParams{}).Return(&entity.UserStats{Total: 2}, nil)
+			},
+		},
+		{
+			name:           "All parameters",
+			url:            "/api/v1/users/stats?age_buckets=20,40&interval=week&from=2025-01-01&to=2025-03-31&top_domains=3",
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockService *MockUserStatsService) {
+				mockService.On("GetStats", mock.Anything, entity.UserStatsParams{
+					AgeBuckets: "20,40",
+					Interval:   "week",
+					From:       "2025-01-01",
+					To:         "2025-03-31",
+					TopDomains: 3,
+				}).Return(&entity.UserStats{}, nil)
+			},
+		},
+		{
+			name:           "Invalid interval",
+			url:            "/api/v1/users/stats?interval=year",
+			expectedStatus: http.StatusBadRequest,
+			expectedField:  "interval",
+			setupMock:      func(mockService *MockUserStatsService) {},
+		},
+		{
+			name:           "Invalid date",
+			url:            "/api/v1/users/stats?from=01/01/2025",
+			expectedStatus: http.StatusBadRequest,
+			expectedField:  "from",
+			setupMock:      func(mockService *MockUserStatsService) {},
+		},
+		{
+			name:           "Invalid buckets",
+			url:            "/api/v1/users/stats?age_buckets=40,20",
+			expectedStatus: http.StatusBadRequest,
+			setupMock: func(mockService *MockUserStatsService) {
+				mockService.On("GetStats", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("%w: age buckets must be increasing", service.ErrInvalidStatsParams))
+			},
+		},
+		{
+			name:           "Service error",
+			url:            "/api/v1/users/stats",
+			expectedStatus: http.StatusInternalServerError,
+			setupMock: func(mockService *MockUserStatsService) {
+				mockService.On("GetStats", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupUserStatsTestRouter()
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedField != "" {
+				var response problem.Problem
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				if assert.Len(t, response.Errors, 1) {
+					assert.Equal(t, tt.expectedField, response.Errors[0].Parameter)
+				}
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
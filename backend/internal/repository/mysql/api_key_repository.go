@@ -0,0 +1,108 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"arritech-user-management/pkg/tenant"
+	"gorm.io/gorm"
+)
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new MySQL API key repository
+func NewAPIKeyRepository(db *gorm.DB) repository.APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// scoped returns a session restricted to the organization carried by ctx
+func (r *apiKeyRepository) scoped(ctx context.Context) (*gorm.DB, error) {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("organization is required")
+	}
+	return r.db.WithContext(ctx).Where("organization_id = ?", orgID), nil
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return fmt.Errorf("organization is required")
+	}
+	key.OrganizationID = orgID
+
+	if err := r.db.WithContext(ctx).Create(key).Error; err != nil {
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) GetByID(ctx context.Context, id uint) (*entity.APIKey, error) {
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var key entity.APIKey
+	if err := db.First(&key, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("API key not found")
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error) {
+	var key entity.APIKey
+	if err := r.db.WithContext(ctx).Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("API key not found")
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) List(ctx context.Context) ([]entity.APIKey, error) {
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []entity.APIKey
+	if err := db.Order("created_at DESC").Order("id DESC").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id uint, at time.Time) error {
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+
+	result := db.Model(&entity.APIKey{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", at)
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke API key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("API key not found")
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	// UpdateColumn leaves updated_at alone so it keeps tracking changes to the key itself
+	err := r.db.WithContext(ctx).Model(&entity.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error
+	if err != nil {
+		return fmt.Errorf("failed to update API key usage: %w", err)
+	}
+	return nil
+}
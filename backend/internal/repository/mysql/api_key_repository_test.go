@@ -0,0 +1,149 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyRepository_Create(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewAPIKeyRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `api_keys`").
+		WithArgs(uint(1), "HR sync", "uk_0a1b2c3d4e5f", "hash", sqlmock.AnyArg(), "", nil, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	key := &entity.APIKey{Name: "HR sync", Prefix: "uk_0a1b2c3d4e5f", SecretHash: "hash", Scopes: entity.StringList{"users:read"}}
+	err := repo.Create(orgContext(), key)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), key.ID)
+	assert.Equal(t, uint(1), key.OrganizationID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAPIKeyRepository_RequiresOrganization(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewAPIKeyRepository(db)
+
+	err := repo.Create(context.Background(), &entity.APIKey{})
+	assert.EqualError(t, err, "organization is required")
+
+	_, err = repo.List(context.Background())
+	assert.EqualError(t, err, "organization is required")
+}
+
+func TestAPIKeyRepository_GetByPrefixIsNotScoped(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewAPIKeyRepository(db)
+
+	mock.ExpectQuery("SELECT \\* FROM `api_keys` WHERE prefix = \\? ORDER BY `api_keys`.`id` LIMIT 1").
+		WithArgs("uk_0a1b2c3d4e5f").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "organization_id", "prefix", "scopes"}).
+			AddRow(4, 2, "uk_0a1b2c3d4e5f", `["users:read"]`))
+
+	key, err := repo.GetByPrefix(context.Background(), "uk_0a1b2c3d4e5f")
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), key.OrganizationID)
+	assert.Equal(t, entity.StringList{"users:read"}, key.Scopes)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAPIKeyRepository_GetByIDNotFound(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewAPIKeyRepository(db)
+
+	mock.ExpectQuery("SELECT \\* FROM `api_keys` WHERE organization_id = \\? AND `api_keys`.`id` = \\?").
+		WithArgs(1, 9).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.GetByID(orgContext(), 9)
+	assert.EqualError(t, err, "API key not found")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAPIKeyRepository_List(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewAPIKeyRepository(db)
+
+	mock.ExpectQuery("SELECT \\* FROM `api_keys` WHERE organization_id = \\? ORDER BY created_at DESC,id DESC").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2).AddRow(1))
+
+	keys, err := repo.List(orgContext())
+	require.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAPIKeyRepository_Revoke(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewAPIKeyRepository(db)
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `api_keys` SET `revoked_at`=\\?,`updated_at`=\\? WHERE organization_id = \\? AND \\(id = \\? AND revoked_at IS NULL\\)").
+		WithArgs(at, sqlmock.AnyArg(), 1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.Revoke(orgContext(), 3, at)
+	require.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAPIKeyRepository_TouchLastUsed(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewAPIKeyRepository(db)
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `api_keys` SET `last_used_at`=\\? WHERE id = \\?").
+		WithArgs(at, 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.TouchLastUsed(context.Background(), 4, at)
+	require.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,124 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"arritech-user-management/pkg/tenant"
+	"gorm.io/gorm"
+)
+
+type attributeDefinitionRepository struct {
+	db *gorm.DB
+}
+
+// NewAttributeDefinitionRepository creates a new MySQL attribute definition repository
+func NewAttributeDefinitionRepository(db *gorm.DB) repository.AttributeDefinitionRepository {
+	return &attributeDefinitionRepository{db: db}
+}
+
+// scoped returns a session restricted to the definitions of the organization carried by ctx
+func (r *attributeDefinitionRepository) scoped(ctx context.Context) (*gorm.DB, uint, error) {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, 0, fmt.Errorf("organization is required")
+	}
+	return r.db.WithContext(ctx).Where("organization_id = ?", orgID), orgID, nil
+}
+
+func (r *attributeDefinitionRepository) Create(ctx context.Context, def *entity.AttributeDefinition) error {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return fmt.Errorf("organization is required")
+	}
+	def.OrganizationID = orgID
+
+	if err := r.db.WithContext(ctx).Create(def).Error; err != nil {
+		if strings.Contains(err.Error(), "Duplicate entry") {
+			return fmt.Errorf("attribute key already exists")
+		}
+		return fmt.Errorf("failed to create attribute definition: %w", err)
+	}
+	return nil
+}
+
+func (r *attributeDefinitionRepository) GetByKey(ctx context.Context, key string) (*entity.AttributeDefinition, error) {
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var def entity.AttributeDefinition
+	if err := db.Where("`key` = ?", key).First(&def).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("attribute definition not found")
+		}
+		return nil, fmt.Errorf("failed to get attribute definition: %w", err)
+	}
+	return &def, nil
+}
+
+func (r *attributeDefinitionRepository) List(ctx context.Context) ([]entity.AttributeDefinition, error) {
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var defs []entity.AttributeDefinition
+	if err := db.Order("`key` ASC").Find(&defs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list attribute definitions: %w", err)
+	}
+	return defs, nil
+}
+
+func (r *attributeDefinitionRepository) Update(ctx context.Context, def *entity.AttributeDefinition) error {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return fmt.Errorf("organization is required")
+	}
+	// Definitions can only be saved back into the organization they were loaded from
+	if def.OrganizationID != orgID {
+		return fmt.Errorf("attribute definition not found")
+	}
+
+	if err := r.db.WithContext(ctx).Save(def).Error; err != nil {
+		return fmt.Errorf("failed to update attribute definition: %w", err)
+	}
+	return nil
+}
+
+func (r *attributeDefinitionRepository) Delete(ctx context.Context, key string) error {
+	db, orgID, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("`key` = ?", key).Delete(&entity.AttributeDefinition{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete attribute definition: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("attribute definition not found")
+		}
+
+		// Drop the values of the deleted attribute so the organization's users stay valid against
+		// its remaining definitions
+		path := attributePath(key)
+		err := tx.Session(&gorm.Session{NewDB: true}).Model(&entity.User{}).
+			Where("organization_id = ? AND JSON_CONTAINS_PATH(attributes, 'one', ?)", orgID, path).
+			Update("attributes", gorm.Expr("JSON_REMOVE(attributes, ?)", path)).Error
+		if err != nil {
+			return fmt.Errorf("failed to remove attribute values: %w", err)
+		}
+		return nil
+	})
+}
+
+// attributePath returns the MySQL JSON path for a top-level attribute key
+func attributePath(key string) string {
+	return `$."` + strings.ReplaceAll(key, `"`, `\"`) + `"`
+}
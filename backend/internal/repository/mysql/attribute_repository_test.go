@@ -0,0 +1,127 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttributeDefinitionRepository_Create(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewAttributeDefinitionRepository(db)
+
+	def := &entity.AttributeDefinition{
+		Key:      "department",
+		Type:     entity.AttributeTypeString,
+		Required: true,
+		Enum:     entity.StringList{"engineering", "sales"},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `attribute_definitions`").
+		WithArgs(1, def.Key, def.Type, def.Required, `["engineering","sales"]`, def.Pattern, def.Description, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := repo.Create(orgContext(), def)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), def.ID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAttributeDefinitionRepository_List(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewAttributeDefinitionRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "key", "type", "required", "enum", "pattern", "created_at", "updated_at"}).
+		AddRow(1, "department", "string", true, `["engineering","sales"]`, "", time.Now(), time.Now()).
+		AddRow(2, "employee_number", "string", false, nil, "^E[0-9]{5}$", time.Now(), time.Now())
+
+	mock.ExpectQuery("SELECT \\* FROM `attribute_definitions` WHERE organization_id = \\? ORDER BY `key` ASC").
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	defs, err := repo.List(orgContext())
+	assert.NoError(t, err)
+	assert.Len(t, defs, 2)
+	assert.Equal(t, entity.StringList{"engineering", "sales"}, defs[0].Enum)
+	assert.Nil(t, defs[1].Enum)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAttributeDefinitionRepository_GetByKeyNotFound(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewAttributeDefinitionRepository(db)
+
+	mock.ExpectQuery("SELECT \\* FROM `attribute_definitions` WHERE organization_id = \\? AND `key` = \\?").
+		WithArgs(1, "missing").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	def, err := repo.GetByKey(orgContext(), "missing")
+	assert.Nil(t, def)
+	assert.EqualError(t, err, "attribute definition not found")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAttributeDefinitionRepository_Delete(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewAttributeDefinitionRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `attribute_definitions` WHERE organization_id = \\? AND `key` = \\?").
+		WithArgs(1, "department").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// Only the values of the organization's users are dropped
+	mock.ExpectExec("UPDATE `users` SET `attributes`=JSON_REMOVE\\(attributes, \\?\\),`updated_at`=\\? WHERE \\(organization_id = \\? AND JSON_CONTAINS_PATH").
+		WithArgs(`$."department"`, sqlmock.AnyArg(), 1, `$."department"`).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	err := repo.Delete(orgContext(), "department")
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAttributeDefinitionRepository_DeleteNotFound(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewAttributeDefinitionRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `attribute_definitions`").
+		WithArgs(1, "missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.Delete(orgContext(), "missing")
+	assert.EqualError(t, err, "attribute definition not found")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
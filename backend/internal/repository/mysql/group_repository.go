@@ -0,0 +1,23 @@
+package mysql
+
+import (
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+// groupMemberCount selects a group's columns together with its member count
+const groupMemberCount = "`groups`.*, (SELECT COUNT(*) FROM `user_groups` WHERE `user_groups`.`group_id` = `groups`.`id`) AS member_count"
+
+// NewGroupRepository creates a new MySQL group repository
+func NewGroupRepository(db *gorm.DB) repository.GroupRepository {
+	return &labelRepository[entity.Group]{
+		db:           db,
+		name:         "group",
+		table:        "groups",
+		organization: func(group *entity.Group) *uint { return &group.OrganizationID },
+		joinTable:    "user_groups",
+		column:       "group_id",
+		columns:      groupMemberCount,
+	}
+}
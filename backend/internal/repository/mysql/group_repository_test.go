@@ -0,0 +1,73 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupRepository_List(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewGroupRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "name", "description", "created_at", "updated_at", "member_count"}).
+		AddRow(1, "cohort-a", "Spring intake", time.Now(), time.Now(), 12).
+		AddRow(2, "cohort-b", "", time.Now(), time.Now(), 0)
+
+	mock.ExpectQuery("SELECT `groups`.\\*, \\(SELECT COUNT\\(\\*\\) FROM `user_groups` WHERE `user_groups`.`group_id` = `groups`.`id`\\) AS member_count FROM `groups` WHERE `groups`.`organization_id` = \\? ORDER BY name ASC").
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	groups, err := repo.List(orgContext())
+	assert.NoError(t, err)
+	assert.Len(t, groups, 2)
+	assert.Equal(t, int64(12), groups[0].MemberCount)
+	assert.Equal(t, int64(0), groups[1].MemberCount)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGroupRepository_GetByIDNotFound(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewGroupRepository(db)
+
+	mock.ExpectQuery("SELECT `groups`.\\*, .* FROM `groups` WHERE `groups`.`organization_id` = \\? AND `groups`.`id` = \\?").
+		WithArgs(1, 99).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	group, err := repo.GetByID(orgContext(), 99)
+	assert.Nil(t, group)
+	assert.EqualError(t, err, "group not found")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGroupRepository_AddUsers(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewGroupRepository(db)
+
+	mock.ExpectExec("INSERT IGNORE INTO `user_groups` \\(`user_id`, `group_id`\\) SELECT `users`.`id`, `groups`.`id` FROM `users` "+
+		"JOIN `groups` ON `groups`.`id` = \\? AND `groups`.`organization_id` = \\?").
+		WithArgs(5, 1, 1, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	affected, err := repo.AddUsers(orgContext(), 5, []uint{1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,69 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+type idempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyKeyRepository creates a new MySQL idempotency key repository
+func NewIdempotencyKeyRepository(db *gorm.DB) repository.IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{db: db}
+}
+
+func (r *idempotencyKeyRepository) Create(ctx context.Context, key *entity.IdempotencyKey) error {
+	if err := r.db.WithContext(ctx).Create(key).Error; err != nil {
+		if strings.Contains(err.Error(), "Duplicate entry") {
+			return fmt.Errorf("idempotency key already exists")
+		}
+		return fmt.Errorf("failed to create idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *idempotencyKeyRepository) GetByKey(ctx context.Context, key string) (*entity.IdempotencyKey, error) {
+	var record entity.IdempotencyKey
+	if err := r.db.WithContext(ctx).Where("`key` = ?", key).First(&record).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("idempotency key not found")
+		}
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	return &record, nil
+}
+
+func (r *idempotencyKeyRepository) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	err := r.db.WithContext(ctx).Model(&entity.IdempotencyKey{}).Where("`key` = ?", key).Updates(map[string]interface{}{
+		"status_code":  statusCode,
+		"content_type": contentType,
+		"body":         body,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+func (r *idempotencyKeyRepository) Delete(ctx context.Context, key string) error {
+	if err := r.db.WithContext(ctx).Where("`key` = ?", key).Delete(&entity.IdempotencyKey{}).Error; err != nil {
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *idempotencyKeyRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at < ?", before).Delete(&entity.IdempotencyKey{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,120 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyKeyRepository_Create(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewIdempotencyKeyRepository(db)
+	expires := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `idempotency_keys`").
+		WithArgs("k1", "fp", 0, "", sqlmock.AnyArg(), sqlmock.AnyArg(), expires).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := repo.Create(context.Background(), &entity.IdempotencyKey{Key: "k1", Fingerprint: "fp", ExpiresAt: expires})
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `idempotency_keys`").
+		WillReturnError(errors.New("Error 1062 (23000): Duplicate entry 'k1' for key 'idx_idempotency_keys_key'"))
+	mock.ExpectRollback()
+
+	err = repo.Create(context.Background(), &entity.IdempotencyKey{Key: "k1", Fingerprint: "fp", ExpiresAt: expires})
+	assert.EqualError(t, err, "idempotency key already exists")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestIdempotencyKeyRepository_GetByKey(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewIdempotencyKeyRepository(db)
+
+	mock.ExpectQuery("SELECT \\* FROM `idempotency_keys` WHERE `key` = \\? ORDER BY `idempotency_keys`.`id` LIMIT 1").
+		WithArgs("k1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "key", "fingerprint", "status_code", "content_type", "body"}).
+			AddRow(1, "k1", "fp", 201, "application/json", []byte(`{"id":1}`)))
+	mock.ExpectQuery("SELECT \\* FROM `idempotency_keys`").
+		WithArgs("k2").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	record, err := repo.GetByKey(context.Background(), "k1")
+	require.NoError(t, err)
+	assert.Equal(t, 201, record.StatusCode)
+	assert.Equal(t, []byte(`{"id":1}`), record.Body)
+
+	_, err = repo.GetByKey(context.Background(), "k2")
+	assert.EqualError(t, err, "idempotency key not found")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestIdempotencyKeyRepository_Complete(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewIdempotencyKeyRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `idempotency_keys` SET `body`=\\?,`content_type`=\\?,`status_code`=\\? WHERE `key` = \\?").
+		WithArgs([]byte(`{}`), "application/json", 201, "k1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.Complete(context.Background(), "k1", 201, "application/json", []byte(`{}`))
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestIdempotencyKeyRepository_DeleteExpired(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewIdempotencyKeyRepository(db)
+	now := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `idempotency_keys` WHERE expires_at < \\?").
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	removed, err := repo.DeleteExpired(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), removed)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `idempotency_keys` WHERE `key` = \\?").
+		WithArgs("k1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.Delete(context.Background(), "k1"))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,152 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"arritech-user-management/pkg/tenant"
+	"gorm.io/gorm"
+)
+
+// labelRepository stores a kind of label that collects users of its organization through a
+// join table
+type labelRepository[T any] struct {
+	db *gorm.DB
+	// name is the singular name of the label used in errors, e.g. "tag"
+	name string
+	// table is the label's table, which qualifies its organization_id in selects with subqueries
+	table string
+	// organization returns the label's organization ID field
+	organization func(*T) *uint
+	// joinTable links users to labels through its user_id and column columns
+	joinTable string
+	column    string
+	// columns selects the label's columns when loading it; empty selects the stored ones
+	columns string
+}
+
+// scoped returns a session restricted to the labels of the organization carried by ctx
+func (r *labelRepository[T]) scoped(ctx context.Context) (*gorm.DB, uint, error) {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, 0, fmt.Errorf("organization is required")
+	}
+	return r.db.WithContext(ctx).Where(fmt.Sprintf("`%s`.`organization_id` = ?", r.table), orgID), orgID, nil
+}
+
+// query starts a query of the organization's labels with their selected columns
+func (r *labelRepository[T]) query(ctx context.Context) (*gorm.DB, error) {
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.columns != "" {
+		db = db.Select(r.columns)
+	}
+	return db, nil
+}
+
+func (r *labelRepository[T]) Create(ctx context.Context, label *T) error {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return fmt.Errorf("organization is required")
+	}
+	*r.organization(label) = orgID
+
+	if err := r.db.WithContext(ctx).Create(label).Error; err != nil {
+		if strings.Contains(err.Error(), "Duplicate entry") {
+			return fmt.Errorf("%s name already exists", r.name)
+		}
+		return fmt.Errorf("failed to create %s: %w", r.name, err)
+	}
+	return nil
+}
+
+func (r *labelRepository[T]) GetByID(ctx context.Context, id uint) (*T, error) {
+	db, err := r.query(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var label T
+	if err := db.First(&label, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("%s not found", r.name)
+		}
+		return nil, fmt.Errorf("failed to get %s: %w", r.name, err)
+	}
+	return &label, nil
+}
+
+func (r *labelRepository[T]) List(ctx context.Context) ([]T, error) {
+	db, err := r.query(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var labels []T
+	if err := db.Order("name ASC").Find(&labels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list %ss: %w", r.name, err)
+	}
+	return labels, nil
+}
+
+func (r *labelRepository[T]) Update(ctx context.Context, label *T) error {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return fmt.Errorf("organization is required")
+	}
+	// Labels can only be saved back into the organization they were loaded from
+	if *r.organization(label) != orgID {
+		return fmt.Errorf("%s not found", r.name)
+	}
+
+	if err := r.db.WithContext(ctx).Save(label).Error; err != nil {
+		if strings.Contains(err.Error(), "Duplicate entry") {
+			return fmt.Errorf("%s name already exists", r.name)
+		}
+		return fmt.Errorf("failed to update %s: %w", r.name, err)
+	}
+	return nil
+}
+
+func (r *labelRepository[T]) Delete(ctx context.Context, id uint) error {
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// The label is removed first so the links of another organization's label are never touched
+		result := tx.Delete(new(T), id)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete %s: %w", r.name, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%s not found", r.name)
+		}
+
+		if err := tx.Session(&gorm.Session{NewDB: true}).
+			Exec(fmt.Sprintf("DELETE FROM `%s` WHERE `%s` = ?", r.joinTable, r.column), id).Error; err != nil {
+			return fmt.Errorf("failed to detach %s: %w", r.name, err)
+		}
+		return nil
+	})
+}
+
+func (r *labelRepository[T]) AddUsers(ctx context.Context, id uint, userIDs []uint) (int64, error) {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return 0, fmt.Errorf("organization is required")
+	}
+	return addMembers(ctx, r.db, r.joinTable, r.table, r.column, orgID, id, userIDs)
+}
+
+func (r *labelRepository[T]) RemoveUsers(ctx context.Context, id uint, userIDs []uint) (int64, error) {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return 0, fmt.Errorf("organization is required")
+	}
+	return removeMembers(ctx, r.db, r.joinTable, r.table, r.column, orgID, id, userIDs)
+}
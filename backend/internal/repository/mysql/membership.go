@@ -0,0 +1,38 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// addMembers inserts (user_id, column) rows into a join table for the users of an organization
+// whose label with the given ID belongs to that organization too. Rows that already exist or
+// reference unknown users or users of another organization are skipped; the number inserted
+// is returned.
+func addMembers(ctx context.Context, db *gorm.DB, joinTable, labelTable, column string, orgID, id uint, userIDs []uint) (int64, error) {
+	result := db.WithContext(ctx).Exec(fmt.Sprintf(
+		"INSERT IGNORE INTO `%s` (`user_id`, `%s`) "+
+			"SELECT `users`.`id`, `%s`.`id` FROM `users` JOIN `%s` ON `%s`.`id` = ? AND `%s`.`organization_id` = ? "+
+			"WHERE `users`.`id` IN ? AND `users`.`organization_id` = ? AND `users`.`deleted_at` IS NULL",
+		joinTable, column, labelTable, labelTable, labelTable, labelTable), id, orgID, userIDs, orgID)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to add members: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// removeMembers deletes (user_id, column) rows from a join table for a label of an organization
+// and returns the number removed
+func removeMembers(ctx context.Context, db *gorm.DB, joinTable, labelTable, column string, orgID, id uint, userIDs []uint) (int64, error) {
+	result := db.WithContext(ctx).Exec(fmt.Sprintf(
+		"DELETE `%s` FROM `%s` JOIN `%s` ON `%s`.`id` = `%s`.`%s` "+
+			"WHERE `%s`.`%s` = ? AND `%s`.`organization_id` = ? AND `%s`.`user_id` IN ?",
+		joinTable, joinTable, labelTable, labelTable, joinTable, column,
+		joinTable, column, labelTable, joinTable), id, orgID, userIDs)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to remove members: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
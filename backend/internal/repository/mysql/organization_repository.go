@@ -0,0 +1,67 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+// NewOrganizationRepository creates a new MySQL organization repository
+func NewOrganizationRepository(db *gorm.DB) repository.OrganizationRepository {
+	return &organizationRepository{db: db}
+}
+
+func (r *organizationRepository) Create(ctx context.Context, org *entity.Organization) error {
+	if err := r.db.WithContext(ctx).Create(org).Error; err != nil {
+		if strings.Contains(err.Error(), "Duplicate entry") {
+			return fmt.Errorf("organization slug already exists")
+		}
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+	return nil
+}
+
+func (r *organizationRepository) GetByID(ctx context.Context, id uint) (*entity.Organization, error) {
+	var org entity.Organization
+	if err := r.db.WithContext(ctx).First(&org, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repository.ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	return &org, nil
+}
+
+func (r *organizationRepository) GetBySlug(ctx context.Context, slug string) (*entity.Organization, error) {
+	var org entity.Organization
+	if err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&org).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repository.ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("failed to get organization by slug: %w", err)
+	}
+	return &org, nil
+}
+
+func (r *organizationRepository) List(ctx context.Context) ([]entity.Organization, error) {
+	var orgs []entity.Organization
+	if err := r.db.WithContext(ctx).Order("name ASC").Find(&orgs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	return orgs, nil
+}
+
+func (r *organizationRepository) Update(ctx context.Context, org *entity.Organization) error {
+	if err := r.db.WithContext(ctx).Save(org).Error; err != nil {
+		return fmt.Errorf("failed to update organization: %w", err)
+	}
+	return nil
+}
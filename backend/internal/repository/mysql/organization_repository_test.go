@@ -0,0 +1,56 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganizationRepository_GetBySlug(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewOrganizationRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "slug", "name", "minimum_age", "allowed_email_domains", "created_at", "updated_at"}).
+		AddRow(2, "acme", "Acme Corp", 21, `["acme.com"]`, time.Now(), time.Now())
+
+	mock.ExpectQuery("SELECT \\* FROM `organizations` WHERE slug = \\?").
+		WithArgs("acme").
+		WillReturnRows(rows)
+
+	org, err := repo.GetBySlug(context.Background(), "acme")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), org.ID)
+	assert.Equal(t, 21, org.MinimumAge)
+	assert.Equal(t, entity.StringList{"acme.com"}, org.AllowedEmailDomains)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestOrganizationRepository_GetByIDNotFound(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewOrganizationRepository(db)
+
+	mock.ExpectQuery("SELECT \\* FROM `organizations` WHERE `organizations`.`id` = \\?").
+		WithArgs(99).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	org, err := repo.GetByID(context.Background(), 99)
+	assert.Nil(t, org)
+	assert.ErrorIs(t, err, repository.ErrOrganizationNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
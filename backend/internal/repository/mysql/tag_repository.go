@@ -0,0 +1,19 @@
+package mysql
+
+import (
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+// NewTagRepository creates a new MySQL tag repository
+func NewTagRepository(db *gorm.DB) repository.TagRepository {
+	return &labelRepository[entity.Tag]{
+		db:           db,
+		name:         "tag",
+		table:        "tags",
+		organization: func(tag *entity.Tag) *uint { return &tag.OrganizationID },
+		joinTable:    "user_tags",
+		column:       "tag_id",
+	}
+}
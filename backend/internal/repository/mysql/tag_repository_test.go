@@ -0,0 +1,148 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"arritech-user-management/internal/domain/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagRepository_CreateDuplicate(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTagRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `tags`").
+		WithArgs(1, "vip", "", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnError(errors.New("Error 1062: Duplicate entry 'vip' for key 'tags.idx_tags_name'"))
+	mock.ExpectRollback()
+
+	err := repo.Create(orgContext(), &entity.Tag{Name: "vip"})
+	assert.EqualError(t, err, "tag name already exists")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTagRepository_Delete(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTagRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `tags` WHERE `tags`.`organization_id` = \\? AND `tags`.`id` = \\?").
+		WithArgs(1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `user_tags` WHERE `tag_id` = \\?").
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := repo.Delete(orgContext(), 3)
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTagRepository_DeleteNotFound(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTagRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `tags`").
+		WithArgs(1, 99).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.Delete(orgContext(), 99)
+	assert.EqualError(t, err, "tag not found")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTagRepository_AddUsers(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTagRepository(db)
+
+	// Only users and tags of the caller's organization are linked
+	mock.ExpectExec("INSERT IGNORE INTO `user_tags` \\(`user_id`, `tag_id`\\) SELECT `users`.`id`, `tags`.`id` FROM `users` "+
+		"JOIN `tags` ON `tags`.`id` = \\? AND `tags`.`organization_id` = \\? "+
+		"WHERE `users`.`id` IN \\(\\?,\\?\\) AND `users`.`organization_id` = \\? AND `users`.`deleted_at` IS NULL").
+		WithArgs(3, 1, 1, 2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	affected, err := repo.AddUsers(orgContext(), 3, []uint{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTagRepository_RemoveUsers(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTagRepository(db)
+
+	mock.ExpectExec("DELETE `user_tags` FROM `user_tags` JOIN `tags` ON `tags`.`id` = `user_tags`.`tag_id` "+
+		"WHERE `user_tags`.`tag_id` = \\? AND `tags`.`organization_id` = \\? AND `user_tags`.`user_id` IN \\(\\?,\\?\\)").
+		WithArgs(3, 1, 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	affected, err := repo.RemoveUsers(orgContext(), 3, []uint{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), affected)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTagRepository_UpdateOtherOrganization(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTagRepository(db)
+
+	err := repo.Update(orgContext(), &entity.Tag{ID: 3, OrganizationID: 2, Name: "vip"})
+	assert.EqualError(t, err, "tag not found")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTagRepository_RequiresOrganization(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTagRepository(db)
+
+	_, err := repo.List(context.Background())
+	assert.EqualError(t, err, "organization is required")
+
+	_, err = repo.AddUsers(context.Background(), 3, []uint{1})
+	assert.EqualError(t, err, "organization is required")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,53 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"gorm.io/gorm/clause"
+)
+
+func (r *userRepository) ListBirthdays(ctx context.Context, from, to time.Time) ([]entity.User, error) {
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Birthdays are compared as MMDD numbers. People born on Feb 29 celebrate on Feb 28
+	// when the February covered by the range is not in a leap year.
+	monthDay := "MONTH(users.date_of_birth) * 100 + DAY(users.date_of_birth)"
+	februaryYear := from.Year()
+	if from.Month() > time.February {
+		februaryYear++
+	}
+	if !isLeapYear(februaryYear) {
+		monthDay = "CASE WHEN MONTH(users.date_of_birth) = 2 AND DAY(users.date_of_birth) = 29 THEN 228 ELSE " + monthDay + " END"
+	}
+
+	fromMD := int(from.Month())*100 + from.Day()
+	toMD := int(to.Month())*100 + to.Day()
+
+	query := db.Where("status <> ?", entity.UserStatusArchived)
+	if fromMD <= toMD {
+		query = query.Where(monthDay+" BETWEEN ? AND ?", fromMD, toMD)
+	} else {
+		// The range wraps around the end of the year, e.g. December into January
+		query = query.Where("("+monthDay+" >= ? OR "+monthDay+" <= ?)", fromMD, toMD)
+	}
+
+	var users []entity.User
+	order := clause.Expr{
+		SQL:  "CASE WHEN " + monthDay + " < ? THEN 1 ELSE 0 END, " + monthDay + ", users.name",
+		Vars: []interface{}{fromMD},
+	}
+	if err := query.Clauses(clause.OrderBy{Expression: order}).Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to list birthdays: %w", err)
+	}
+	return users, nil
+}
+
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
@@ -0,0 +1,62 @@
+package mysql
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository_ListBirthdays_WrapsYear(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db, logrus.New())
+
+	// December into January of a common year: Feb 29 birthdays count as Feb 28
+	monthDay := "CASE WHEN MONTH(users.date_of_birth) = 2 AND DAY(users.date_of_birth) = 29 THEN 228 ELSE MONTH(users.date_of_birth) * 100 + DAY(users.date_of_birth) END"
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` WHERE users.organization_id = ? AND status <> ? AND (("+monthDay+" >= ? OR "+monthDay+" <= ?)) AND `users`.`deleted_at` IS NULL ORDER BY CASE WHEN "+monthDay+" < ? THEN 1 ELSE 0 END, "+monthDay+", users.name")).
+		WithArgs(1, entity.UserStatusArchived, 1220, 110, 1220).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "December").AddRow(2, "January"))
+
+	users, err := repo.ListBirthdays(orgContext(), time.Date(2025, 12, 20, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Len(t, users, 2)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserRepository_ListBirthdays_LeapYear(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db, logrus.New())
+
+	monthDay := "MONTH(users.date_of_birth) * 100 + DAY(users.date_of_birth)"
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` WHERE users.organization_id = ? AND status <> ? AND ("+monthDay+" BETWEEN ? AND ?) AND `users`.`deleted_at` IS NULL ORDER BY CASE WHEN "+monthDay+" < ? THEN 1 ELSE 0 END, "+monthDay+", users.name")).
+		WithArgs(1, entity.UserStatusArchived, 201, 310, 201).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	users, err := repo.ListBirthdays(orgContext(), time.Date(2028, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2028, 3, 10, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Empty(t, users)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestIsLeapYear(t *testing.T) {
+	assert.True(t, isLeapYear(2024))
+	assert.True(t, isLeapYear(2000))
+	assert.False(t, isLeapYear(1900))
+	assert.False(t, isLeapYear(2025))
+}
@@ -0,0 +1,52 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"arritech-user-management/pkg/tenant"
+	"gorm.io/gorm"
+)
+
+type userEventRepository struct {
+	db *gorm.DB
+}
+
+// NewUserEventRepository creates a new MySQL user event repository
+func NewUserEventRepository(db *gorm.DB) repository.UserEventRepository {
+	return &userEventRepository{db: db}
+}
+
+func (r *userEventRepository) Create(ctx context.Context, event *entity.UserEvent) error {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return fmt.Errorf("organization is required")
+	}
+	event.OrganizationID = orgID
+
+	if err := r.db.WithContext(ctx).Create(event).Error; err != nil {
+		return fmt.Errorf("failed to create event: %w", err)
+	}
+	return nil
+}
+
+func (r *userEventRepository) ListBefore(ctx context.Context, userID uint, before time.Time, beforeID uint, limit int) ([]entity.UserEvent, error) {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("organization is required")
+	}
+
+	db := r.db.WithContext(ctx).Where("organization_id = ? AND user_id = ?", orgID, userID)
+	if !before.IsZero() {
+		db = db.Where("(created_at, id) < (?, ?)", before, beforeID)
+	}
+
+	var events []entity.UserEvent
+	if err := db.Order("created_at DESC").Order("id DESC").Limit(limit).Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	return events, nil
+}
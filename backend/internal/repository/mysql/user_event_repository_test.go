@@ -0,0 +1,54 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserEventRepository_Create(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserEventRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `user_events`").
+		WithArgs(uint(1), uint(5), entity.UserEventCreated, "User created", "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	event := &entity.UserEvent{UserID: 5, Type: entity.UserEventCreated, Description: "User created"}
+	err := repo.Create(orgContext(), event)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), event.OrganizationID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserEventRepository_ListBefore(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserEventRepository(db)
+
+	mock.ExpectQuery("SELECT \\* FROM `user_events` WHERE organization_id = \\? AND user_id = \\? ORDER BY created_at DESC,id DESC LIMIT 21").
+		WithArgs(1, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "type"}).AddRow(1, 5, "created"))
+
+	events, err := repo.ListBefore(orgContext(), 5, time.Time{}, 0, 21)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, entity.UserEventCreated, events[0].Type)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,119 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"arritech-user-management/pkg/tenant"
+	"gorm.io/gorm"
+)
+
+type userNoteRepository struct {
+	db *gorm.DB
+}
+
+// NewUserNoteRepository creates a new MySQL user note repository
+func NewUserNoteRepository(db *gorm.DB) repository.UserNoteRepository {
+	return &userNoteRepository{db: db}
+}
+
+// scoped returns a session restricted to the notes of one user in the organization carried by ctx
+func (r *userNoteRepository) scoped(ctx context.Context, userID uint) (*gorm.DB, error) {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("organization is required")
+	}
+	return r.db.WithContext(ctx).Where("organization_id = ? AND user_id = ?", orgID, userID), nil
+}
+
+func (r *userNoteRepository) Create(ctx context.Context, note *entity.UserNote) error {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return fmt.Errorf("organization is required")
+	}
+	note.OrganizationID = orgID
+
+	if err := r.db.WithContext(ctx).Create(note).Error; err != nil {
+		return fmt.Errorf("failed to create note: %w", err)
+	}
+	return nil
+}
+
+func (r *userNoteRepository) GetByID(ctx context.Context, userID, id uint) (*entity.UserNote, error) {
+	db, err := r.scoped(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var note entity.UserNote
+	if err := db.First(&note, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("note not found")
+		}
+		return nil, fmt.Errorf("failed to get note: %w", err)
+	}
+	return &note, nil
+}
+
+func (r *userNoteRepository) ListByUser(ctx context.Context, userID uint) ([]entity.UserNote, error) {
+	db, err := r.scoped(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var notes []entity.UserNote
+	if err := db.Order("pinned DESC").Order("created_at DESC").Order("id DESC").Find(&notes).Error; err != nil {
+		return nil, fmt.Errorf("failed to list notes: %w", err)
+	}
+	return notes, nil
+}
+
+func (r *userNoteRepository) ListBefore(ctx context.Context, userID uint, before time.Time, beforeID uint, limit int) ([]entity.UserNote, error) {
+	db, err := r.scoped(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !before.IsZero() {
+		db = db.Where("(created_at, id) < (?, ?)", before, beforeID)
+	}
+
+	var notes []entity.UserNote
+	if err := db.Order("created_at DESC").Order("id DESC").Limit(limit).Find(&notes).Error; err != nil {
+		return nil, fmt.Errorf("failed to list notes: %w", err)
+	}
+	return notes, nil
+}
+
+func (r *userNoteRepository) Update(ctx context.Context, note *entity.UserNote) error {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return fmt.Errorf("organization is required")
+	}
+	if note.OrganizationID != orgID {
+		return fmt.Errorf("note not found")
+	}
+
+	if err := r.db.WithContext(ctx).Save(note).Error; err != nil {
+		return fmt.Errorf("failed to update note: %w", err)
+	}
+	return nil
+}
+
+func (r *userNoteRepository) Delete(ctx context.Context, userID, id uint) error {
+	db, err := r.scoped(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	result := db.Delete(&entity.UserNote{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete note: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("note not found")
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserNoteRepository_Create(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserNoteRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `user_notes`").
+		WithArgs(uint(1), uint(5), "alice", "Called about billing", true, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	note := &entity.UserNote{UserID: 5, Author: "alice", Body: "Called about billing", Pinned: true}
+	err := repo.Create(orgContext(), note)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), note.ID)
+	assert.Equal(t, uint(1), note.OrganizationID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserNoteRepository_RequiresOrganization(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserNoteRepository(db)
+
+	err := repo.Create(context.Background(), &entity.UserNote{UserID: 5})
+	assert.EqualError(t, err, "organization is required")
+
+	_, err = repo.ListByUser(context.Background(), 5)
+	assert.EqualError(t, err, "organization is required")
+}
+
+func TestUserNoteRepository_GetByIDNotFound(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserNoteRepository(db)
+
+	mock.ExpectQuery("SELECT \\* FROM `user_notes` WHERE \\(organization_id = \\? AND user_id = \\?\\) AND `user_notes`.`id` = \\?").
+		WithArgs(1, 5, 9).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.GetByID(orgContext(), 5, 9)
+	assert.EqualError(t, err, "note not found")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserNoteRepository_ListByUser(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserNoteRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "organization_id", "user_id", "author", "body", "pinned"}).
+		AddRow(2, 1, 5, "bob", "Pinned", true).
+		AddRow(1, 1, 5, "alice", "Older", false)
+	mock.ExpectQuery("SELECT \\* FROM `user_notes` WHERE organization_id = \\? AND user_id = \\? ORDER BY pinned DESC,created_at DESC,id DESC").
+		WithArgs(1, 5).
+		WillReturnRows(rows)
+
+	notes, err := repo.ListByUser(orgContext(), 5)
+	require.NoError(t, err)
+	assert.Len(t, notes, 2)
+	assert.True(t, notes[0].Pinned)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserNoteRepository_ListBefore(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserNoteRepository(db)
+	before := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT \\* FROM `user_notes` WHERE \\(organization_id = \\? AND user_id = \\?\\) AND \\(created_at, id\\) < \\(\\?, \\?\\) ORDER BY created_at DESC,id DESC LIMIT 11").
+		WithArgs(1, 5, before, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	notes, err := repo.ListBefore(orgContext(), 5, before, 7, 11)
+	require.NoError(t, err)
+	assert.Len(t, notes, 1)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserNoteRepository_UpdateOtherOrganization(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserNoteRepository(db)
+
+	err := repo.Update(orgContext(), &entity.UserNote{ID: 1, OrganizationID: 2})
+	assert.EqualError(t, err, "note not found")
+}
+
+func TestUserNoteRepository_DeleteNotFound(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserNoteRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `user_notes` WHERE \\(organization_id = \\? AND user_id = \\?\\) AND `user_notes`.`id` = \\?").
+		WithArgs(1, 5, 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := repo.Delete(orgContext(), 5, 9)
+	assert.EqualError(t, err, "note not found")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
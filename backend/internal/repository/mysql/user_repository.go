@@ -4,25 +4,52 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"arritech-user-management/internal/domain/entity"
 	"arritech-user-management/internal/domain/repository"
+	"arritech-user-management/pkg/logger"
+	"arritech-user-management/pkg/tenant"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type userRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	logger *logrus.Logger
 }
 
 // NewUserRepository creates a new MySQL user repository
-func NewUserRepository(db *gorm.DB) repository.UserRepository {
-	return &userRepository{db: db}
+func NewUserRepository(db *gorm.DB, logger *logrus.Logger) repository.UserRepository {
+	return &userRepository{db: db, logger: logger}
+}
+
+// log returns the log entry of the request behind ctx
+func (r *userRepository) log(ctx context.Context) *logrus.Entry {
+	return logger.FromContext(ctx, r.logger)
+}
+
+// scoped returns a session restricted to the organization carried by ctx
+func (r *userRepository) scoped(ctx context.Context) (*gorm.DB, uint, error) {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, 0, fmt.Errorf("organization is required")
+	}
+	return r.db.WithContext(ctx).Where("users.organization_id = ?", orgID), orgID, nil
 }
 
 func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
-	if err := r.db.WithContext(ctx).Create(user).Error; err != nil {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return fmt.Errorf("organization is required")
+	}
+	user.OrganizationID = orgID
+
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).Create(user).Error; err != nil {
 		if strings.Contains(err.Error(), "Duplicate entry") && strings.Contains(err.Error(), "email") {
 			return fmt.Errorf("email already exists")
 		}
@@ -32,8 +59,17 @@ func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
 }
 
 func (r *userRepository) GetByID(ctx context.Context, id uint) (*entity.User, error) {
+	return r.GetByIDProjected(ctx, id, entity.UserProjection{})
+}
+
+func (r *userRepository) GetByIDProjected(ctx context.Context, id uint, projection entity.UserProjection) (*entity.User, error) {
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var user entity.User
-	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
+	if err := project(db, projection).First(&user, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("user not found")
 		}
@@ -43,8 +79,13 @@ func (r *userRepository) GetByID(ctx context.Context, id uint) (*entity.User, er
 }
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var user entity.User
-	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
+	if err := db.Where("email = ?", email).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("user not found")
 		}
@@ -54,7 +95,16 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.
 }
 
 func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
-	if err := r.db.WithContext(ctx).Save(user).Error; err != nil {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return fmt.Errorf("organization is required")
+	}
+	// Users can only be saved back into the organization they were loaded from
+	if user.OrganizationID != orgID {
+		return fmt.Errorf("user not found")
+	}
+
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).Save(user).Error; err != nil {
 		if strings.Contains(err.Error(), "Duplicate entry") && strings.Contains(err.Error(), "email") {
 			return fmt.Errorf("email already exists")
 		}
@@ -63,8 +113,51 @@ func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
 	return nil
 }
 
+func (r *userRepository) UpdateUnmodifiedSince(ctx context.Context, user *entity.User, updatedAt time.Time) (bool, error) {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return false, fmt.Errorf("organization is required")
+	}
+	if user.OrganizationID != orgID {
+		return false, fmt.Errorf("user not found")
+	}
+
+	result := r.db.WithContext(ctx).Model(user).Omit(clause.Associations).Select("*").
+		Where("organization_id = ? AND updated_at = ?", orgID, updatedAt).Updates(user)
+	if err := result.Error; err != nil {
+		if strings.Contains(err.Error(), "Duplicate entry") && strings.Contains(err.Error(), "email") {
+			return false, fmt.Errorf("email already exists")
+		}
+		return false, fmt.Errorf("failed to update user: %w", err)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *userRepository) UpdateAvatarKey(ctx context.Context, id uint, oldKey, key string) (bool, error) {
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	result := db.Model(&entity.User{}).Where("users.id = ? AND users.avatar_key = ?", id, oldKey).Update("avatar_key", key)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update avatar: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (r *userRepository) Delete(ctx context.Context, id uint) error {
-	result := r.db.WithContext(ctx).Delete(&entity.User{}, id)
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The avatar key is cleared in the same write, since the service deletes the avatar images
+	// once the user is gone and the deleted row mustn't keep pointing at them
+	result := db.Model(&entity.User{}).Where("users.id = ?", id).Updates(map[string]interface{}{
+		"deleted_at": time.Now(),
+		"avatar_key": "",
+	})
 	if result.Error != nil {
 		return fmt.Errorf("failed to delete user: %w", result.Error)
 	}
@@ -77,15 +170,16 @@ func (r *userRepository) Delete(ctx context.Context, id uint) error {
 func (r *userRepository) List(ctx context.Context, params entity.UserSearchParams) (*entity.UserListResponse, error) {
 	var users []entity.User
 	var total int64
+	log := r.log(ctx)
 
 	// Log received parameters
-	logrus.WithFields(logrus.Fields{
+	log.WithFields(logrus.Fields{
 		"sort_by":  params.SortBy,
 		"sort_dir": params.SortDir,
 		"page":     params.Page,
 		"per_page": params.PerPage,
 		"search":   params.Search,
-	}).Info("Repository: Starting List operation with parameters")
+	}).Debug("Repository: Starting List operation with parameters")
 
 	// Set default pagination values
 	if params.Page == 0 {
@@ -103,73 +197,112 @@ func (r *userRepository) List(ctx context.Context, params entity.UserSearchParam
 		params.SortDir = "desc"
 	}
 
-	logrus.WithFields(logrus.Fields{
+	log.WithFields(logrus.Fields{
 		"final_sort_by":  params.SortBy,
 		"final_sort_dir": params.SortDir,
 		"final_page":     params.Page,
 		"final_per_page": params.PerPage,
-	}).Info("Repository: Parameters after setting defaults")
+	}).Debug("Repository: Parameters after setting defaults")
 
-	query := r.db.WithContext(ctx).Model(&entity.User{})
+	db, orgID, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+	log.WithField("organization_id", orgID).Debug("Repository: Scoped to organization")
+
+	query := db.Model(&entity.User{})
 
 	// Apply search filter
 	if params.Search != "" {
 		searchTerm := "%" + params.Search + "%"
-		query = query.Where("name LIKE ? OR email LIKE ? OR phone LIKE ?", searchTerm, searchTerm, searchTerm)
-		logrus.WithField("search_term", searchTerm).Info("Repository: Applied search filter")
+		if params.SearchNameOnly {
+			query = query.Where("name LIKE ?", searchTerm)
+		} else {
+			query = query.Where("name LIKE ? OR email LIKE ? OR phone LIKE ?", searchTerm, searchTerm, searchTerm)
+		}
+		log.WithField("search_term", searchTerm).Debug("Repository: Applied search filter")
+	}
+
+	// Apply status filter (archived users are hidden unless explicitly requested)
+	switch params.Status {
+	case "":
+		query = query.Where("status <> ?", entity.UserStatusArchived)
+	case "all":
+	default:
+		query = query.Where("status = ?", params.Status)
+	}
+	log.WithField("status", params.Status).Debug("Repository: Applied status filter")
+
+	// Apply custom attribute filters (values are compared as unquoted JSON text)
+	for _, key := range sortedKeys(params.Attributes) {
+		query = query.Where("JSON_UNQUOTE(JSON_EXTRACT(attributes, ?)) = ?", attributePath(key), params.Attributes[key])
+	}
+	if len(params.Attributes) > 0 {
+		log.WithField("attributes", params.Attributes).Debug("Repository: Applied attribute filters")
+	}
+
+	// Apply tag and group membership filters
+	if names := splitNames(params.Tags); len(names) > 0 {
+		query = query.Where("users.id IN (?)", membershipSubquery(r.db, "user_tags", "tags", "tag_id", names, params.TagMatch))
+		log.WithFields(logrus.Fields{"tags": names, "tag_match": params.TagMatch}).Debug("Repository: Applied tag filter")
+	}
+	if names := splitNames(params.Group); len(names) > 0 {
+		query = query.Where("users.id IN (?)", membershipSubquery(r.db, "user_groups", "groups", "group_id", names, params.GroupMatch))
+		log.WithFields(logrus.Fields{"groups": names, "group_match": params.GroupMatch}).Debug("Repository: Applied group filter")
 	}
 
 	// Get total count
 	if err := query.Count(&total).Error; err != nil {
-		logrus.WithError(err).Error("Repository: Failed to count users")
+		log.WithError(err).Error("Repository: Failed to count users")
 		return nil, fmt.Errorf("failed to count users: %w", err)
 	}
 
-	logrus.WithField("total_users", total).Info("Repository: Total users count retrieved")
+	log.WithField("total_users", total).Debug("Repository: Total users count retrieved")
 
 	// Apply sorting
 	sortField := getSortField(params.SortBy)
-	logrus.WithFields(logrus.Fields{
+	log.WithFields(logrus.Fields{
 		"original_sort_by":  params.SortBy,
 		"mapped_sort_field": sortField,
 		"sort_direction":    params.SortDir,
-	}).Info("Repository: Applying sorting")
+	}).Debug("Repository: Applying sorting")
 
 	// Build the ORDER BY clause
 	if params.SortBy == "age" {
 		// For age sorting, we need to order by date_of_birth in reverse order
 		if params.SortDir == "asc" {
 			query = query.Order("date_of_birth DESC") // Older people first
-			logrus.Info("Repository: Applied age sorting ASC (date_of_birth DESC)")
+			log.Debug("Repository: Applied age sorting ASC (date_of_birth DESC)")
 		} else {
 			query = query.Order("date_of_birth ASC") // Younger people first
-			logrus.Info("Repository: Applied age sorting DESC (date_of_birth ASC)")
+			log.Debug("Repository: Applied age sorting DESC (date_of_birth ASC)")
 		}
 	} else {
 		// Sanitize the sort direction
 		dir := strings.ToUpper(params.SortDir)
 		if dir != "ASC" && dir != "DESC" {
 			dir = "DESC"
-			logrus.WithField("original_dir", params.SortDir).Warn("Repository: Invalid sort direction, defaulting to DESC")
+			log.WithField("original_dir", params.SortDir).Warn("Repository: Invalid sort direction, defaulting to DESC")
 		}
 		orderClause := fmt.Sprintf("%s %s", sortField, dir)
 		query = query.Order(orderClause)
-		logrus.WithField("order_clause", orderClause).Info("Repository: Applied standard sorting")
+		log.WithField("order_clause", orderClause).Debug("Repository: Applied standard sorting")
 	}
 
 	// Apply pagination
 	offset := (params.Page - 1) * params.PerPage
-	logrus.WithFields(logrus.Fields{
+	log.WithFields(logrus.Fields{
 		"offset": offset,
 		"limit":  params.PerPage,
-	}).Info("Repository: Applying pagination")
+	}).Debug("Repository: Applying pagination")
 
-	if err := query.Offset(offset).Limit(params.PerPage).Find(&users).Error; err != nil {
-		logrus.WithError(err).Error("Repository: Failed to find users")
+	// Tags and expanded resources are loaded with a single extra query each for the whole page
+	if err := project(query, params.Projection).Offset(offset).Limit(params.PerPage).Find(&users).Error; err != nil {
+		log.WithError(err).Error("Repository: Failed to find users")
 		return nil, fmt.Errorf("failed to list users: %w", err)
 	}
 
-	logrus.WithField("users_returned", len(users)).Info("Repository: Successfully retrieved users from database")
+	log.WithField("users_returned", len(users)).Debug("Repository: Successfully retrieved users from database")
 
 	totalPages := int(math.Ceil(float64(total) / float64(params.PerPage)))
 
@@ -182,10 +315,97 @@ func (r *userRepository) List(ctx context.Context, params entity.UserSearchParam
 	}, nil
 }
 
+// orderTagsByName keeps preloaded tags in a stable order
+func orderTagsByName(db *gorm.DB) *gorm.DB {
+	return db.Order("tags.name ASC")
+}
+
+// orderGroupsByName keeps preloaded groups in a stable order
+func orderGroupsByName(db *gorm.DB) *gorm.DB {
+	return db.Order("groups.name ASC")
+}
+
+// project selects the columns behind the fields of projection and preloads the tags and
+// expanded resources it asks for
+func project(db *gorm.DB, projection entity.UserProjection) *gorm.DB {
+	if columns := projectionColumns(projection); columns != nil {
+		db = db.Select(columns)
+	}
+	if projection.Selects("tags") {
+		db = db.Preload("Tags", orderTagsByName)
+	}
+	if projection.Expands("groups") {
+		db = db.Preload("Groups", orderGroupsByName)
+	}
+	return db
+}
+
+// projectionColumns returns the columns to select for the fields of projection, or nil for all
+// of them. The ID is always selected, as preloads match on it.
+func projectionColumns(projection entity.UserProjection) []string {
+	if len(projection.Fields) == 0 {
+		return nil
+	}
+	columns := []string{"users.id"}
+	for _, field := range projection.Fields {
+		column := ""
+		switch field {
+		case "age":
+			column = "date_of_birth" // Age is computed from the date of birth
+		case "avatar_url":
+			column = "avatar_key" // The URL is built from the stored key
+		case "id", "tags":
+			continue
+		default:
+			column = field
+		}
+		if column = "users." + column; !slices.Contains(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// splitNames splits a comma-separated filter value, dropping blanks and duplicates
+func splitNames(value string) []string {
+	seen := make(map[string]bool)
+	names := []string{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// membershipSubquery selects the IDs of users linked to the named rows of table through joinTable.
+// With match "all" a user must be linked to every name, otherwise to at least one.
+func membershipSubquery(db *gorm.DB, joinTable, table, column string, names []string, match string) *gorm.DB {
+	sub := db.Table(fmt.Sprintf("`%s` AS m", joinTable)).
+		Select("m.user_id").
+		Joins(fmt.Sprintf("JOIN `%s` AS t ON t.id = m.%s", table, column)).
+		Where("t.name IN ?", names)
+	if match == "all" {
+		sub = sub.Group("m.user_id").Having("COUNT(DISTINCT t.id) = ?", len(names))
+	}
+	return sub
+}
+
+// sortedKeys returns the keys of m in a stable order so generated SQL is deterministic
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // getSortField returns the database field name for sorting
 func getSortField(sortBy string) string {
-	logrus.WithField("input_sort_by", sortBy).Info("Repository: getSortField called")
-
 	result := ""
 	switch sortBy {
 	case "name":
@@ -206,17 +426,17 @@ func getSortField(sortBy string) string {
 		result = "created_at"
 	}
 
-	logrus.WithFields(logrus.Fields{
-		"input_sort_by": sortBy,
-		"mapped_field":  result,
-	}).Info("Repository: getSortField mapping completed")
-
 	return result
 }
 
 func (r *userRepository) EmailExists(ctx context.Context, email string, excludeID uint) (bool, error) {
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return false, err
+	}
+
 	var count int64
-	query := r.db.WithContext(ctx).Model(&entity.User{}).Where("email = ?", email)
+	query := db.Model(&entity.User{}).Where("email = ?", email)
 
 	if excludeID > 0 {
 		query = query.Where("id != ?", excludeID)
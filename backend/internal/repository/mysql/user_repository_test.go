@@ -2,12 +2,15 @@ package mysql
 
 import (
 	"context"
+	"database/sql/driver"
 	"testing"
 	"time"
 
 	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/tenant"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/mysql"
@@ -31,11 +34,16 @@ func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, func()) {
 	}
 }
 
+// orgContext returns a context scoped to the default organization
+func orgContext() context.Context {
+	return tenant.NewContext(context.Background(), 1)
+}
+
 func TestUserRepository_Create(t *testing.T) {
 	db, mock, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	repo := NewUserRepository(db)
+	repo := NewUserRepository(db, logrus.New())
 
 	user := &entity.User{
 		Name:        "Test User",
@@ -43,16 +51,17 @@ func TestUserRepository_Create(t *testing.T) {
 		DateOfBirth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
 		Phone:       "1234567890",
 		Address:     "Test Address",
+		Status:      entity.UserStatusPending,
 	}
 
 	// GORM uses transactions, so we need to expect begin and commit
 	mock.ExpectBegin()
 	mock.ExpectExec("INSERT INTO `users`").
-		WithArgs(user.Name, user.Email, user.DateOfBirth, user.Phone, user.Address, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WithArgs(uint(1), user.Name, user.Email, sqlmock.AnyArg(), sqlmock.AnyArg(), user.DateOfBirth, user.Phone, user.Address, user.AvatarKey, sqlmock.AnyArg(), user.Status, user.StatusReason, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
-	err := repo.Create(context.Background(), user)
+	err := repo.Create(orgContext(), user)
 	assert.NoError(t, err)
 	assert.Equal(t, uint(1), user.ID)
 	assert.False(t, user.CreatedAt.IsZero())
@@ -67,7 +76,7 @@ func TestUserRepository_GetByID(t *testing.T) {
 	db, mock, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	repo := NewUserRepository(db)
+	repo := NewUserRepository(db, logrus.New())
 
 	expectedUser := &entity.User{
 		ID:          1,
@@ -84,10 +93,11 @@ func TestUserRepository_GetByID(t *testing.T) {
 		AddRow(expectedUser.ID, expectedUser.Name, expectedUser.Email, expectedUser.DateOfBirth, expectedUser.Phone, expectedUser.Address, expectedUser.CreatedAt, expectedUser.UpdatedAt)
 
 	mock.ExpectQuery("SELECT \\* FROM `users`").
-		WithArgs(1).
+		WithArgs(1, 1).
 		WillReturnRows(rows)
+	expectTagPreload(mock)
 
-	user, err := repo.GetByID(context.Background(), 1)
+	user, err := repo.GetByID(orgContext(), 1)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser.ID, user.ID)
 	assert.Equal(t, expectedUser.Name, user.Name)
@@ -102,7 +112,7 @@ func TestUserRepository_List(t *testing.T) {
 	db, mock, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	repo := NewUserRepository(db)
+	repo := NewUserRepository(db, logrus.New())
 
 	params := entity.UserSearchParams{
 		Page:    1,
@@ -124,8 +134,9 @@ func TestUserRepository_List(t *testing.T) {
 
 	mock.ExpectQuery("SELECT \\* FROM `users`").
 		WillReturnRows(userRows)
+	expectTagPreload(mock)
 
-	result, err := repo.List(context.Background(), params)
+	result, err := repo.List(orgContext(), params)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(2), result.Total)
 	assert.Len(t, result.Users, 2)
@@ -141,7 +152,7 @@ func TestUserRepository_ListWithSearch(t *testing.T) {
 	db, mock, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	repo := NewUserRepository(db)
+	repo := NewUserRepository(db, logrus.New())
 
 	params := entity.UserSearchParams{
 		Page:    1,
@@ -162,8 +173,9 @@ func TestUserRepository_ListWithSearch(t *testing.T) {
 
 	mock.ExpectQuery("SELECT \\* FROM `users`").
 		WillReturnRows(userRows)
+	expectTagPreload(mock)
 
-	result, err := repo.List(context.Background(), params)
+	result, err := repo.List(orgContext(), params)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(1), result.Total)
 	assert.Len(t, result.Users, 1)
@@ -174,11 +186,206 @@ func TestUserRepository_ListWithSearch(t *testing.T) {
 	}
 }
 
+func TestUserRepository_ListWithNameOnlySearch(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db, logrus.New())
+
+	params := entity.UserSearchParams{
+		Page:           1,
+		PerPage:        10,
+		SortBy:         "name",
+		SortDir:        "asc",
+		Search:         "alice@",
+		SearchNameOnly: true,
+	}
+
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `users` WHERE users.organization_id = \\? AND name LIKE \\? AND status").
+		WithArgs(1, "%alice@%", entity.UserStatusArchived).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery("SELECT \\* FROM `users` WHERE users.organization_id = \\? AND name LIKE \\? AND status").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	result, err := repo.List(orgContext(), params)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), result.Total)
+	assert.Empty(t, result.Users)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserRepository_ListWithStatusFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    string
+		countSQL  string
+		countArgs []driver.Value
+	}{
+		{
+			name:      "Archived users hidden by default",
+			status:    "",
+			countSQL:  "SELECT count\\(\\*\\) FROM `users` WHERE users.organization_id = \\? AND status <> \\?",
+			countArgs: []driver.Value{1, entity.UserStatusArchived},
+		},
+		{
+			name:      "Explicit status",
+			status:    "suspended",
+			countSQL:  "SELECT count\\(\\*\\) FROM `users` WHERE users.organization_id = \\? AND status = \\?",
+			countArgs: []driver.Value{1, "suspended"},
+		},
+		{
+			name:      "All statuses",
+			status:    "all",
+			countSQL:  "SELECT count\\(\\*\\) FROM `users` WHERE users.organization_id = \\? AND `users`.`deleted_at` IS NULL",
+			countArgs: []driver.Value{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewUserRepository(db, logrus.New())
+
+			params := entity.UserSearchParams{
+				Page:    1,
+				PerPage: 10,
+				SortBy:  "name",
+				SortDir: "asc",
+				Status:  tt.status,
+			}
+
+			countRows := sqlmock.NewRows([]string{"count"}).AddRow(0)
+			mock.ExpectQuery(tt.countSQL).
+				WithArgs(tt.countArgs...).
+				WillReturnRows(countRows)
+
+			userRows := sqlmock.NewRows([]string{"id", "name", "email", "date_of_birth", "phone", "address", "status", "created_at", "updated_at"})
+			mock.ExpectQuery("SELECT \\* FROM `users`").
+				WillReturnRows(userRows)
+
+			result, err := repo.List(orgContext(), params)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(0), result.Total)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestUserRepository_ListWithAttributeFilter(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db, logrus.New())
+
+	params := entity.UserSearchParams{
+		Page:    1,
+		PerPage: 10,
+		SortBy:  "name",
+		SortDir: "asc",
+		Status:  "all",
+		Attributes: map[string]string{
+			"tshirt_size": "M",
+			"department":  "sales",
+		},
+	}
+
+	// Filters are applied in key order
+	countRows := sqlmock.NewRows([]string{"count"}).AddRow(1)
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `users` WHERE users.organization_id = \\? AND JSON_UNQUOTE\\(JSON_EXTRACT\\(attributes, \\?\\)\\) = \\? AND JSON_UNQUOTE\\(JSON_EXTRACT\\(attributes, \\?\\)\\) = \\?").
+		WithArgs(1, `$."department"`, "sales", `$."tshirt_size"`, "M").
+		WillReturnRows(countRows)
+
+	userRows := sqlmock.NewRows([]string{"id", "name", "email", "date_of_birth", "attributes", "created_at", "updated_at"}).
+		AddRow(1, "Alice", "alice@example.com", time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), `{"department":"sales","tshirt_size":"M"}`, time.Now(), time.Now())
+	mock.ExpectQuery("SELECT \\* FROM `users`").
+		WillReturnRows(userRows)
+	expectTagPreload(mock)
+
+	result, err := repo.List(orgContext(), params)
+	assert.NoError(t, err)
+	assert.Len(t, result.Users, 1)
+	assert.Equal(t, "sales", result.Users[0].Attributes["department"])
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserRepository_ListWithMembershipFilters(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   entity.UserSearchParams
+		countSQL string
+		args     []driver.Value
+	}{
+		{
+			name:     "Any of the tags",
+			params:   entity.UserSearchParams{Tags: "vip, beta,vip", Status: "all"},
+			countSQL: "SELECT count\\(\\*\\) FROM `users` WHERE users.organization_id = \\? AND users.id IN \\(SELECT m.user_id FROM `user_tags` AS m JOIN `tags` AS t ON t.id = m.tag_id WHERE t.name IN \\(\\?,\\?\\)\\)",
+			args:     []driver.Value{1, "vip", "beta"},
+		},
+		{
+			name:     "All of the tags",
+			params:   entity.UserSearchParams{Tags: "vip,beta", TagMatch: "all", Status: "all"},
+			countSQL: "SELECT count\\(\\*\\) FROM `users` WHERE users.organization_id = \\? AND users.id IN \\(SELECT m.user_id FROM `user_tags` AS m JOIN `tags` AS t ON t.id = m.tag_id WHERE t.name IN \\(\\?,\\?\\) GROUP BY `m`.`user_id` HAVING COUNT\\(DISTINCT t.id\\) = \\?\\)",
+			args:     []driver.Value{1, "vip", "beta", 2},
+		},
+		{
+			name:     "All of the groups",
+			params:   entity.UserSearchParams{Group: "cohort-a", GroupMatch: "all", Status: "all"},
+			countSQL: "SELECT count\\(\\*\\) FROM `users` WHERE users.organization_id = \\? AND users.id IN \\(SELECT m.user_id FROM `user_groups` AS m JOIN `groups` AS t ON t.id = m.group_id WHERE t.name IN \\(\\?\\) GROUP BY `m`.`user_id` HAVING COUNT\\(DISTINCT t.id\\) = \\?\\)",
+			args:     []driver.Value{1, "cohort-a", 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewUserRepository(db, logrus.New())
+
+			countRows := sqlmock.NewRows([]string{"count"}).AddRow(1)
+			mock.ExpectQuery(tt.countSQL).
+				WithArgs(tt.args...).
+				WillReturnRows(countRows)
+
+			userRows := sqlmock.NewRows([]string{"id", "name", "email", "date_of_birth", "created_at", "updated_at"}).
+				AddRow(1, "Alice", "alice@example.com", time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), time.Now(), time.Now())
+			mock.ExpectQuery("SELECT \\* FROM `users`").
+				WillReturnRows(userRows)
+			mock.ExpectQuery("SELECT \\* FROM `user_tags` WHERE `user_tags`.`user_id` = \\?").
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"user_id", "tag_id"}).AddRow(1, 7))
+			mock.ExpectQuery("SELECT \\* FROM `tags` WHERE `tags`.`id` = \\? ORDER BY tags.name ASC").
+				WithArgs(7).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "color"}).AddRow(7, "vip", "#ff0000"))
+
+			result, err := repo.List(orgContext(), tt.params)
+			assert.NoError(t, err)
+			assert.Len(t, result.Users, 1)
+			assert.Equal(t, []entity.Tag{{ID: 7, Name: "vip", Color: "#ff0000"}}, result.Users[0].Tags)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
 func TestUserRepository_ListWithAgeSorting(t *testing.T) {
 	db, mock, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	repo := NewUserRepository(db)
+	repo := NewUserRepository(db, logrus.New())
 
 	params := entity.UserSearchParams{
 		Page:    1,
@@ -200,8 +407,9 @@ func TestUserRepository_ListWithAgeSorting(t *testing.T) {
 
 	mock.ExpectQuery("SELECT \\* FROM `users`").
 		WillReturnRows(userRows)
+	expectTagPreload(mock)
 
-	result, err := repo.List(context.Background(), params)
+	result, err := repo.List(orgContext(), params)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(2), result.Total)
 	assert.Len(t, result.Users, 2)
@@ -215,25 +423,27 @@ func TestUserRepository_Update(t *testing.T) {
 	db, mock, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	repo := NewUserRepository(db)
+	repo := NewUserRepository(db, logrus.New())
 
 	user := &entity.User{
-		ID:          1,
-		Name:        "Updated User",
-		Email:       "updated@example.com",
-		DateOfBirth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
-		Phone:       "1234567890",
-		Address:     "Updated Address",
+		ID:             1,
+		OrganizationID: 1,
+		Name:           "Updated User",
+		Email:          "updated@example.com",
+		DateOfBirth:    time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		Phone:          "1234567890",
+		Address:        "Updated Address",
+		Status:         entity.UserStatusActive,
 	}
 
 	// GORM uses transactions, so we need to expect begin and commit
 	mock.ExpectBegin()
 	mock.ExpectExec("UPDATE `users`").
-		WithArgs(user.Name, user.Email, user.DateOfBirth, user.Phone, user.Address, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), user.ID).
+		WithArgs(uint(1), user.Name, user.Email, sqlmock.AnyArg(), sqlmock.AnyArg(), user.DateOfBirth, user.Phone, user.Address, user.AvatarKey, sqlmock.AnyArg(), user.Status, user.StatusReason, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), user.ID).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectCommit()
 
-	err := repo.Update(context.Background(), user)
+	err := repo.Update(orgContext(), user)
 	assert.NoError(t, err)
 
 	if err := mock.ExpectationsWereMet(); err != nil {
@@ -241,20 +451,127 @@ func TestUserRepository_Update(t *testing.T) {
 	}
 }
 
+func TestUserRepository_UpdateAvatarKey(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo := NewUserRepository(db, logrus.New())
+
+	// Only the avatar key is written, and only over the key the upload started from
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `users` SET `avatar_key`=\\?,`updated_at`=\\? WHERE users.organization_id = \\? AND \\(users.id = \\? AND users.avatar_key = \\?\\) AND `users`.`deleted_at` IS NULL").
+		WithArgs("avatars/1/7/42", sqlmock.AnyArg(), 1, 7, "avatars/1/7/1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	saved, err := repo.UpdateAvatarKey(orgContext(), 7, "avatars/1/7/1", "avatars/1/7/42")
+
+	require.NoError(t, err)
+	assert.True(t, saved)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_UpdateUnmodifiedSince(t *testing.T) {
+	loadedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	user := func() *entity.User {
+		return &entity.User{ID: 1, OrganizationID: 1, Name: "Updated User", Email: "updated@example.com", UpdatedAt: loadedAt}
+	}
+
+	t.Run("Saves a user that is unchanged", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+		repo := NewUserRepository(db, logrus.New())
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `users` SET .*`updated_at`=\\?.* WHERE \\(organization_id = \\? AND updated_at = \\?\\) AND `users`.`deleted_at` IS NULL AND `id` = \\?").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		saved, err := repo.UpdateUnmodifiedSince(orgContext(), user(), loadedAt)
+
+		require.NoError(t, err)
+		assert.True(t, saved)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Leaves a user that changed since it was loaded", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+		repo := NewUserRepository(db, logrus.New())
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `users`").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		saved, err := repo.UpdateUnmodifiedSince(orgContext(), user(), loadedAt)
+
+		require.NoError(t, err)
+		assert.False(t, saved)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Refuses users of another organization", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+		repo := NewUserRepository(db, logrus.New())
+
+		other := user()
+		other.OrganizationID = 2
+		_, err := repo.UpdateUnmodifiedSince(orgContext(), other, loadedAt)
+
+		assert.EqualError(t, err, "user not found")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserRepository_RequiresOrganization(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db, logrus.New())
+
+	_, err := repo.GetByID(context.Background(), 1)
+	assert.EqualError(t, err, "organization is required")
+
+	err = repo.Create(context.Background(), &entity.User{Name: "Test User"})
+	assert.EqualError(t, err, "organization is required")
+
+	_, err = repo.EmailExists(context.Background(), "test@example.com", 0)
+	assert.EqualError(t, err, "organization is required")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserRepository_UpdateOtherOrganization(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db, logrus.New())
+
+	// A user loaded in another organization must not be written through this one
+	err := repo.Update(orgContext(), &entity.User{ID: 1, OrganizationID: 2, Name: "Other"})
+	assert.EqualError(t, err, "user not found")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
 func TestUserRepository_Delete(t *testing.T) {
 	db, mock, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	repo := NewUserRepository(db)
+	repo := NewUserRepository(db, logrus.New())
 
-	// GORM uses soft delete by default, so it's actually an UPDATE
+	// A soft delete is an UPDATE, which clears the avatar key in the same statement
 	mock.ExpectBegin()
-	mock.ExpectExec("UPDATE `users`").
-		WithArgs(sqlmock.AnyArg(), uint(1)).
+	mock.ExpectExec("UPDATE `users` SET `avatar_key`=\\?,`deleted_at`=\\?,`updated_at`=\\? WHERE users.organization_id = \\? AND users.id = \\? AND `users`.`deleted_at` IS NULL").
+		WithArgs("", sqlmock.AnyArg(), sqlmock.AnyArg(), 1, uint(1)).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectCommit()
 
-	err := repo.Delete(context.Background(), 1)
+	err := repo.Delete(orgContext(), 1)
 	assert.NoError(t, err)
 
 	if err := mock.ExpectationsWereMet(); err != nil {
@@ -266,25 +583,25 @@ func TestUserRepository_EmailExists(t *testing.T) {
 	db, mock, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	repo := NewUserRepository(db)
+	repo := NewUserRepository(db, logrus.New())
 
 	// Test email exists
 	countRows := sqlmock.NewRows([]string{"count"}).AddRow(1)
 	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `users`").
-		WithArgs("test@example.com").
+		WithArgs(1, "test@example.com").
 		WillReturnRows(countRows)
 
-	exists, err := repo.EmailExists(context.Background(), "test@example.com", 0)
+	exists, err := repo.EmailExists(orgContext(), "test@example.com", 0)
 	assert.NoError(t, err)
 	assert.True(t, exists)
 
 	// Test email doesn't exist
 	countRows = sqlmock.NewRows([]string{"count"}).AddRow(0)
 	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `users`").
-		WithArgs("nonexistent@example.com").
+		WithArgs(1, "nonexistent@example.com").
 		WillReturnRows(countRows)
 
-	exists, err = repo.EmailExists(context.Background(), "nonexistent@example.com", 0)
+	exists, err = repo.EmailExists(orgContext(), "nonexistent@example.com", 0)
 	assert.NoError(t, err)
 	assert.False(t, exists)
 
@@ -293,6 +610,73 @@ func TestUserRepository_EmailExists(t *testing.T) {
 	}
 }
 
+func TestUserRepository_GetByIDProjected(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db, logrus.New())
+
+	// Age loads the date of birth, and without tags nothing is preloaded
+	mock.ExpectQuery("SELECT users.id,users.name,users.date_of_birth FROM `users` WHERE users.organization_id = \\? AND `users`.`id` = \\?").
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "date_of_birth"}).AddRow(1, "Alice", time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	user, err := repo.GetByIDProjected(orgContext(), 1, entity.UserProjection{Fields: []string{"name", "age"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", user.Name)
+	assert.Equal(t, 1990, user.DateOfBirth.Year())
+	assert.Empty(t, user.Email)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserRepository_ListWithProjection(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db, logrus.New())
+
+	params := entity.UserSearchParams{
+		Page:       1,
+		PerPage:    10,
+		SortBy:     "name",
+		SortDir:    "asc",
+		Projection: entity.UserProjection{Fields: []string{"email", "avatar_url", "tags"}, Expand: []string{"groups"}},
+	}
+
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("SELECT users.id,users.email,users.avatar_key FROM `users` WHERE .* ORDER BY name ASC LIMIT 10").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "avatar_key"}).AddRow(1, "alice@example.com", ""))
+	mock.ExpectQuery("SELECT \\* FROM `user_groups` WHERE `user_groups`.`user_id` = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "group_id"}).AddRow(1, 4))
+	mock.ExpectQuery("SELECT \\* FROM `groups` WHERE `groups`.`id` = \\? ORDER BY groups.name ASC").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(4, "ops"))
+	expectTagPreload(mock)
+
+	result, err := repo.List(orgContext(), params)
+	require.NoError(t, err)
+	require.Len(t, result.Users, 1)
+	assert.Equal(t, "alice@example.com", result.Users[0].Email)
+	require.Len(t, result.Users[0].Groups, 1)
+	assert.Equal(t, "ops", result.Users[0].Groups[0].Name)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestProjectionColumns(t *testing.T) {
+	assert.Nil(t, projectionColumns(entity.UserProjection{}))
+	assert.Nil(t, projectionColumns(entity.UserProjection{Expand: []string{"groups"}}))
+	assert.Equal(t, []string{"users.id"}, projectionColumns(entity.UserProjection{Fields: []string{"id", "tags"}}))
+	assert.Equal(t,
+		[]string{"users.id", "users.date_of_birth", "users.avatar_key", "users.status"},
+		projectionColumns(entity.UserProjection{Fields: []string{"age", "date_of_birth", "avatar_url", "status"}}))
+}
+
 func TestGetSortField(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -317,3 +701,9 @@ func TestGetSortField(t *testing.T) {
 		})
 	}
 }
+
+// expectTagPreload expects the query that preloads the tags of the returned users
+func expectTagPreload(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT \\* FROM `user_tags`").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "tag_id"}))
+}
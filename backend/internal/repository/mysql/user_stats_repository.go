@@ -0,0 +1,248 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"arritech-user-management/pkg/phone"
+	"arritech-user-management/pkg/tenant"
+	"gorm.io/gorm"
+)
+
+// ageExpression computes a user's age in full years like entity.User.AgeAt: someone born on
+// February 29 turns a year older on February 28 in common years (MAKEDATE(year, 59) is Feb 28).
+// TIMESTAMPDIFF would wait for March 1.
+const ageExpression = "YEAR(CURDATE()) - YEAR(users.date_of_birth) - (DATE_FORMAT(CURDATE(), '%m%d') < " +
+	"IF(DATE_FORMAT(users.date_of_birth, '%m%d') = '0229' AND DAY(LAST_DAY(MAKEDATE(YEAR(CURDATE()), 59))) = 28, " +
+	"'0228', DATE_FORMAT(users.date_of_birth, '%m%d')))"
+
+// phoneHeadExpression keeps the first characters of a phone number without separators,
+// enough to hold an international prefix and a three-digit calling code
+const phoneHeadExpression = "LEFT(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(users.phone, ' ', ''), '-', ''), '.', ''), '(', ''), ')', ''), 5)"
+
+// signupFormats are the MySQL DATE_FORMAT patterns of each signup interval
+var signupFormats = map[string]string{
+	entity.StatsIntervalDay:   "%Y-%m-%d",
+	entity.StatsIntervalWeek:  "%x-W%v",
+	entity.StatsIntervalMonth: "%Y-%m",
+}
+
+type userStatsRepository struct {
+	db *gorm.DB
+}
+
+// NewUserStatsRepository creates a new MySQL user statistics repository
+func NewUserStatsRepository(db *gorm.DB) repository.UserStatsRepository {
+	return &userStatsRepository{db: db}
+}
+
+// scoped returns a query over the users of the organization carried by ctx, soft-deleted users excluded
+func (r *userStatsRepository) scoped(ctx context.Context) (*gorm.DB, error) {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("organization is required")
+	}
+	return r.db.WithContext(ctx).Model(&entity.User{}).Where("users.organization_id = ?", orgID), nil
+}
+
+func (r *userStatsRepository) Stats(ctx context.Context, query entity.UserStatsQuery) (*entity.UserStats, error) {
+	format, ok := signupFormats[query.Interval]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signup interval %q", query.Interval)
+	}
+
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats := &entity.UserStats{ByStatus: make(map[string]int64)}
+
+	var totals struct {
+		Total  int64
+		Active int64
+	}
+	if err := db.Session(&gorm.Session{}).Unscoped().
+		Select("COUNT(*) AS total, COALESCE(SUM(CASE WHEN users.deleted_at IS NULL THEN 1 ELSE 0 END), 0) AS active").
+		Scan(&totals).Error; err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+	stats.Total = totals.Total
+	stats.Active = totals.Active
+	stats.Deleted = totals.Total - totals.Active
+
+	var statuses []struct {
+		Status string
+		Count  int64
+	}
+	if err := db.Session(&gorm.Session{}).
+		Select("users.status AS status, COUNT(*) AS count").
+		Group("users.status").
+		Scan(&statuses).Error; err != nil {
+		return nil, fmt.Errorf("failed to count users by status: %w", err)
+	}
+	for _, s := range statuses {
+		stats.ByStatus[s.Status] = s.Count
+	}
+
+	if stats.AgeHistogram, err = r.ageHistogram(db.Session(&gorm.Session{}), query.AgeBuckets); err != nil {
+		return nil, err
+	}
+	if stats.Signups, err = r.signups(db.Session(&gorm.Session{}), format, query); err != nil {
+		return nil, err
+	}
+
+	stats.TopEmailDomains = []entity.DomainCount{}
+	if err := db.Session(&gorm.Session{}).
+		Select("LOWER(SUBSTRING_INDEX(users.email, '@', -1)) AS domain, COUNT(*) AS count").
+		Group("domain").
+		Order("count DESC, domain").
+		Limit(query.TopDomains).
+		Scan(&stats.TopEmailDomains).Error; err != nil {
+		return nil, fmt.Errorf("failed to count email domains: %w", err)
+	}
+
+	if stats.PhoneCountries, err = r.phoneCountries(db.Session(&gorm.Session{})); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// ageHistogram counts users per age bucket. Buckets start at each boundary, plus one below the first.
+func (r *userStatsRepository) ageHistogram(db *gorm.DB, boundaries []int) ([]entity.AgeBucket, error) {
+	var bucketExpr strings.Builder
+	args := make([]interface{}, 0, len(boundaries))
+	bucketExpr.WriteString("CASE")
+	for i, boundary := range boundaries {
+		fmt.Fprintf(&bucketExpr, " WHEN %s < ? THEN %d", ageExpression, i)
+		args = append(args, boundary)
+	}
+	fmt.Fprintf(&bucketExpr, " ELSE %d END AS bucket, COUNT(*) AS count", len(boundaries))
+
+	var rows []struct {
+		Bucket int
+		Count  int64
+	}
+	if err := db.Select(bucketExpr.String(), args...).Group("bucket").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to compute age histogram: %w", err)
+	}
+
+	histogram := make([]entity.AgeBucket, len(boundaries)+1)
+	for i := range histogram {
+		histogram[i] = ageBucket(boundaries, i)
+	}
+	for _, row := range rows {
+		if row.Bucket >= 0 && row.Bucket < len(histogram) {
+			histogram[row.Bucket].Count = row.Count
+		}
+	}
+	return histogram, nil
+}
+
+// signups counts users created per period between query.From and query.To, including periods without signups
+func (r *userStatsRepository) signups(db *gorm.DB, format string, query entity.UserStatsQuery) ([]entity.SignupBucket, error) {
+	var rows []entity.SignupBucket
+	if err := db.Unscoped().
+		Select("DATE_FORMAT(users.created_at, ?) AS period, COUNT(*) AS count", format).
+		Where("users.created_at >= ? AND users.created_at < ?", query.From, query.To.AddDate(0, 0, 1)).
+		Group("period").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count signups: %w", err)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Period] = row.Count
+	}
+
+	var series []entity.SignupBucket
+	for _, period := range signupPeriods(query.Interval, query.From, query.To) {
+		series = append(series, entity.SignupBucket{Period: period, Count: counts[period]})
+	}
+	return series, nil
+}
+
+// phoneCountries counts users per phone calling code; numbers without one count as "unknown"
+func (r *userStatsRepository) phoneCountries(db *gorm.DB) ([]entity.PhoneCount, error) {
+	var rows []struct {
+		Head  string
+		Count int64
+	}
+	if err := db.Select(phoneHeadExpression + " AS head, COUNT(*) AS count").
+		Where("users.phone <> ''").
+		Group("head").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count phone prefixes: %w", err)
+	}
+
+	counts := make(map[string]int64)
+	for _, row := range rows {
+		prefix, ok := phone.CountryCode(row.Head)
+		if !ok {
+			prefix = "unknown"
+		}
+		counts[prefix] += row.Count
+	}
+
+	result := make([]entity.PhoneCount, 0, len(counts))
+	for prefix, count := range counts {
+		result = append(result, entity.PhoneCount{Prefix: prefix, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Prefix < result[j].Prefix
+	})
+	return result, nil
+}
+
+// ageBucket describes the i-th bucket of a histogram with the given boundaries
+func ageBucket(boundaries []int, i int) entity.AgeBucket {
+	var bucket entity.AgeBucket
+	if i > 0 {
+		lower := boundaries[i-1]
+		bucket.Min = &lower
+	}
+	if i < len(boundaries) {
+		upper := boundaries[i] - 1
+		bucket.Max = &upper
+	}
+
+	switch {
+	case bucket.Min == nil:
+		bucket.Label = fmt.Sprintf("<%d", boundaries[0])
+	case bucket.Max == nil:
+		bucket.Label = fmt.Sprintf("%d+", *bucket.Min)
+	default:
+		bucket.Label = fmt.Sprintf("%d-%d", *bucket.Min, *bucket.Max)
+	}
+	return bucket
+}
+
+// signupPeriods lists the periods between from and to, formatted like signupFormats
+func signupPeriods(interval string, from, to time.Time) []string {
+	var periods []string
+	seen := make(map[string]bool)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		var period string
+		switch interval {
+		case entity.StatsIntervalWeek:
+			year, week := day.ISOWeek()
+			period = fmt.Sprintf("%d-W%02d", year, week)
+		case entity.StatsIntervalMonth:
+			period = day.Format("2006-01")
+		default:
+			period = day.Format("2006-01-02")
+		}
+		if !seen[period] {
+			seen[period] = true
+			periods = append(periods, period)
+		}
+	}
+	return periods
+}
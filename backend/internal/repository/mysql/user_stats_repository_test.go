@@ -0,0 +1,105 @@
+package mysql
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserStatsRepository_Stats(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserStatsRepository(db)
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) AS total, COALESCE\\(SUM\\(CASE WHEN users.deleted_at IS NULL THEN 1 ELSE 0 END\\), 0\\) AS active FROM `users` WHERE users.organization_id = \\?$").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"total", "active"}).AddRow(10, 8))
+	mock.ExpectQuery("SELECT users.status AS status, COUNT\\(\\*\\) AS count FROM `users` WHERE users.organization_id = \\? AND `users`.`deleted_at` IS NULL GROUP BY `users`.`status`").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "count"}).AddRow("active", 6).AddRow("pending", 2))
+	age := regexp.QuoteMeta(ageExpression)
+	mock.ExpectQuery("SELECT CASE WHEN "+age+" < \\? THEN 0 WHEN "+age+" < \\? THEN 1 ELSE 2 END AS bucket, COUNT\\(\\*\\) AS count FROM `users` WHERE users.organization_id = \\? AND `users`.`deleted_at` IS NULL GROUP BY `bucket`").
+		WithArgs(18, 65, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "count"}).AddRow(1, 7).AddRow(2, 1))
+	mock.ExpectQuery("SELECT DATE_FORMAT\\(users.created_at, \\?\\) AS period, COUNT\\(\\*\\) AS count FROM `users` WHERE users.organization_id = \\? AND \\(users.created_at >= \\? AND users.created_at < \\?\\) GROUP BY `period`").
+		WithArgs("%Y-%m-%d", 1, from, to.AddDate(0, 0, 1)).
+		WillReturnRows(sqlmock.NewRows([]string{"period", "count"}).AddRow("2025-03-02", 4))
+	mock.ExpectQuery("SELECT LOWER\\(SUBSTRING_INDEX\\(users.email, '@', -1\\)\\) AS domain, COUNT\\(\\*\\) AS count FROM `users` WHERE users.organization_id = \\? AND `users`.`deleted_at` IS NULL GROUP BY `domain` ORDER BY count DESC, domain LIMIT 5").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"domain", "count"}).AddRow("example.com", 5))
+	mock.ExpectQuery("SELECT LEFT\\(REPLACE\\(.+\\), 5\\) AS head, COUNT\\(\\*\\) AS count FROM `users` WHERE users.organization_id = \\? AND users.phone <> '' AND `users`.`deleted_at` IS NULL GROUP BY `head`").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"head", "count"}).
+			AddRow("+4420", 2).
+			AddRow("+4479", 1).
+			AddRow("+1415", 3).
+			AddRow("11912", 1))
+
+	stats, err := repo.Stats(orgContext(), entity.UserStatsQuery{
+		AgeBuckets: []int{18, 65},
+		Interval:   entity.StatsIntervalDay,
+		From:       from,
+		To:         to,
+		TopDomains: 5,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(10), stats.Total)
+	assert.Equal(t, int64(8), stats.Active)
+	assert.Equal(t, int64(2), stats.Deleted)
+	assert.Equal(t, map[string]int64{"active": 6, "pending": 2}, stats.ByStatus)
+
+	require.Len(t, stats.AgeHistogram, 3)
+	assert.Equal(t, "<18", stats.AgeHistogram[0].Label)
+	assert.Equal(t, int64(0), stats.AgeHistogram[0].Count)
+	assert.Equal(t, "18-64", stats.AgeHistogram[1].Label)
+	assert.Equal(t, int64(7), stats.AgeHistogram[1].Count)
+	assert.Equal(t, "65+", stats.AgeHistogram[2].Label)
+	assert.Nil(t, stats.AgeHistogram[2].Max)
+
+	assert.Equal(t, []entity.SignupBucket{
+		{Period: "2025-03-01", Count: 0},
+		{Period: "2025-03-02", Count: 4},
+		{Period: "2025-03-03", Count: 0},
+	}, stats.Signups)
+
+	assert.Equal(t, []entity.DomainCount{{Domain: "example.com", Count: 5}}, stats.TopEmailDomains)
+	assert.Equal(t, []entity.PhoneCount{
+		{Prefix: "+1", Count: 3},
+		{Prefix: "+44", Count: 3},
+		{Prefix: "unknown", Count: 1},
+	}, stats.PhoneCountries)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserStatsRepository_RequiresOrganization(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserStatsRepository(db)
+
+	_, err := repo.Stats(context.Background(), entity.UserStatsQuery{Interval: entity.StatsIntervalDay})
+	assert.EqualError(t, err, "organization is required")
+}
+
+func TestSignupPeriods(t *testing.T) {
+	from := time.Date(2024, 12, 28, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, []string{"2024-W52", "2025-W01", "2025-W02"}, signupPeriods(entity.StatsIntervalWeek, from, to))
+	assert.Equal(t, []string{"2024-12", "2025-01"}, signupPeriods(entity.StatsIntervalMonth, from, to))
+	assert.Len(t, signupPeriods(entity.StatsIntervalDay, from, to), 11)
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/auth"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAPIKeyRepository is a mock implementation of the APIKeyRepository interface
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) GetByID(ctx context.Context, id uint) (*entity.APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error) {
+	args := m.Called(ctx, prefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) List(ctx context.Context) ([]entity.APIKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id uint, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+var apiKeyTestNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func setupAPIKeyService() (*apiKeyService, *MockAPIKeyRepository) {
+	mockRepo := &MockAPIKeyRepository{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	service := NewAPIKeyService(mockRepo, logger).(*apiKeyService)
+	service.now = func() time.Time { return apiKeyTestNow }
+	return service, mockRepo
+}
+
+func TestAPIKeyService_CreateAndAuthenticate(t *testing.T) {
+	service, mockRepo := setupAPIKeyService()
+
+	var stored *entity.APIKey
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.APIKey")).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*entity.APIKey)
+		stored.ID = 7
+		stored.OrganizationID = 2
+	}).Return(nil)
+
+	ctx := auth.NewContext(context.Background(), &auth.Claims{Subject: "admin@example.com"})
+	created, err := service.CreateKey(ctx, entity.CreateAPIKeyRequest{Name: " HR sync ", Scopes: []string{entity.ScopeUsersRead}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "HR sync", created.Name)
+	assert.Equal(t, "admin@example.com", created.CreatedBy)
+	assert.True(t, strings.HasPrefix(created.Key, created.Prefix+"."))
+	assert.NotContains(t, stored.SecretHash, strings.TrimPrefix(created.Key, created.Prefix+"."))
+
+	mockRepo.On("GetByPrefix", mock.Anything, created.Prefix).Return(stored, nil)
+	mockRepo.On("TouchLastUsed", mock.Anything, uint(7), apiKeyTestNow).Return(nil)
+
+	key, err := service.Authenticate(context.Background(), created.Key)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), key.OrganizationID)
+	assert.Equal(t, &apiKeyTestNow, key.LastUsedAt)
+
+	_, err = service.Authenticate(context.Background(), created.Prefix+".wrong-secret")
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+}
+
+func TestAPIKeyService_CreateKeyRejectsPastExpiry(t *testing.T) {
+	service, mockRepo := setupAPIKeyService()
+	past := apiKeyTestNow.Add(-time.Hour)
+
+	_, err := service.CreateKey(context.Background(), entity.CreateAPIKeyRequest{Name: "old", Scopes: []string{entity.ScopeUsersRead}, ExpiresAt: &past})
+
+	assert.ErrorIs(t, err, ErrInvalidAPIKeyExpiry)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	secret := "s3cret"
+	expired := apiKeyTestNow.Add(-time.Second)
+	revoked := apiKeyTestNow.Add(-time.Hour)
+	recent := apiKeyTestNow.Add(-10 * time.Second)
+
+	tests := []struct {
+		name     string
+		raw      string
+		stored   *entity.APIKey
+		lookup   error
+		expected error
+		touched  bool
+	}{
+		{name: "Malformed key", raw: "not-a-key", expected: ErrInvalidAPIKey},
+		{name: "Foreign prefix", raw: "gh_123.s3cret", expected: ErrInvalidAPIKey},
+		{name: "Unknown prefix", raw: "uk_000000000000.s3cret", lookup: errors.New("API key not found"), expected: ErrInvalidAPIKey},
+		{name: "Lookup failure", raw: "uk_000000000000.s3cret", lookup: errors.New("connection refused"), expected: errors.New("connection refused")},
+		{name: "Revoked key", raw: "uk_000000000000.s3cret", stored: &entity.APIKey{ID: 1, SecretHash: hashAPIKeySecret(secret), RevokedAt: &revoked}, expected: ErrAPIKeyRevoked},
+		{name: "Expired key", raw: "uk_000000000000.s3cret", stored: &entity.APIKey{ID: 1, SecretHash: hashAPIKeySecret(secret), ExpiresAt: &expired}, expected: ErrAPIKeyExpired},
+		{name: "Recently used key is not touched", raw: "uk_000000000000.s3cret", stored: &entity.APIKey{ID: 1, SecretHash: hashAPIKeySecret(secret), LastUsedAt: &recent}},
+		{name: "Usage is recorded", raw: "uk_000000000000.s3cret", stored: &entity.APIKey{ID: 1, SecretHash: hashAPIKeySecret(secret)}, touched: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockRepo := setupAPIKeyService()
+			if tt.stored != nil || tt.lookup != nil {
+				mockRepo.On("GetByPrefix", mock.Anything, "uk_000000000000").Return(tt.stored, tt.lookup)
+			}
+			if tt.touched {
+				mockRepo.On("TouchLastUsed", mock.Anything, uint(1), apiKeyTestNow).Return(nil)
+			}
+
+			key, err := service.Authenticate(context.Background(), tt.raw)
+
+			if tt.expected != nil {
+				assert.EqualError(t, err, tt.expected.Error())
+				assert.Nil(t, key)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+			if !tt.touched {
+				mockRepo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_RevokeKey(t *testing.T) {
+	t.Run("Revokes an active key", func(t *testing.T) {
+		service, mockRepo := setupAPIKeyService()
+		mockRepo.On("GetByID", mock.Anything, uint(3)).Return(&entity.APIKey{ID: 3}, nil)
+		mockRepo.On("Revoke", mock.Anything, uint(3), apiKeyTestNow).Return(nil)
+
+		key, err := service.RevokeKey(context.Background(), 3)
+
+		require.NoError(t, err)
+		assert.Equal(t, &apiKeyTestNow, key.RevokedAt)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Revoking twice keeps the original time", func(t *testing.T) {
+		service, mockRepo := setupAPIKeyService()
+		earlier := apiKeyTestNow.Add(-time.Hour)
+		mockRepo.On("GetByID", mock.Anything, uint(3)).Return(&entity.APIKey{ID: 3, RevokedAt: &earlier}, nil)
+
+		key, err := service.RevokeKey(context.Background(), 3)
+
+		require.NoError(t, err)
+		assert.Equal(t, &earlier, key.RevokedAt)
+		mockRepo.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown key", func(t *testing.T) {
+		service, mockRepo := setupAPIKeyService()
+		mockRepo.On("GetByID", mock.Anything, uint(9)).Return(nil, errors.New("API key not found"))
+
+		_, err := service.RevokeKey(context.Background(), 9)
+
+		assert.EqualError(t, err, "API key not found")
+	})
+}
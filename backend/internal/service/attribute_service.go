@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"arritech-user-management/pkg/auth"
+	"github.com/sirupsen/logrus"
+)
+
+// attributeKeyPattern restricts keys to identifiers that are safe to embed in JSON paths
+var attributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// ErrInvalidAttributeDefinition is returned when an attribute definition is malformed
+var ErrInvalidAttributeDefinition = errors.New("invalid attribute definition")
+
+// AttributeValidationError reports invalid custom attribute values, keyed by attribute.
+// An empty key refers to the attributes as a whole.
+type AttributeValidationError struct {
+	Fields map[string]string
+}
+
+func (e *AttributeValidationError) Error() string {
+	return "invalid attributes"
+}
+
+type AttributeService interface {
+	CreateDefinition(ctx context.Context, req entity.CreateAttributeDefinitionRequest) (*entity.AttributeDefinition, error)
+	ListDefinitions(ctx context.Context) ([]entity.AttributeDefinition, error)
+	UpdateDefinition(ctx context.Context, key string, req entity.UpdateAttributeDefinitionRequest) (*entity.AttributeDefinition, error)
+	DeleteDefinition(ctx context.Context, key string) error
+	Schema(ctx context.Context) (map[string]interface{}, error)
+	ValidateAttributes(ctx context.Context, attrs map[string]interface{}) error
+	ValidateFilter(ctx context.Context, filter map[string]string) error
+}
+
+type attributeService struct {
+	attrRepo   repository.AttributeDefinitionRepository
+	authorizer Authorizer
+	logger     *logrus.Logger
+}
+
+// AttributeServiceOption configures optional collaborators of the attribute service
+type AttributeServiceOption func(*attributeService)
+
+// WithAttributeAuthorization requires the attributes:manage permission to change attribute
+// definitions. Reading definitions and validating values stay open to every caller.
+func WithAttributeAuthorization(authorizer Authorizer) AttributeServiceOption {
+	return func(s *attributeService) {
+		s.authorizer = authorizer
+	}
+}
+
+func NewAttributeService(attrRepo repository.AttributeDefinitionRepository, logger *logrus.Logger, opts ...AttributeServiceOption) AttributeService {
+	s := &attributeService{
+		attrRepo: attrRepo,
+		logger:   logger,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *attributeService) authorize(ctx context.Context, permission string) error {
+	if s.authorizer == nil {
+		return nil
+	}
+	if err := s.authorizer.Authorize(ctx, permission); err != nil {
+		s.logger.WithError(err).WithField("permission", permission).Warn("Attribute definition operation denied")
+		return err
+	}
+	return nil
+}
+
+func (s *attributeService) CreateDefinition(ctx context.Context, req entity.CreateAttributeDefinitionRequest) (*entity.AttributeDefinition, error) {
+	if err := s.authorize(ctx, auth.PermissionAttributesManage); err != nil {
+		return nil, err
+	}
+	s.logger.WithField("key", req.Key).Info("Creating attribute definition")
+
+	if !attributeKeyPattern.MatchString(req.Key) {
+		return nil, fmt.Errorf("%w: key must start with a lowercase letter and contain only lowercase letters, digits and underscores", ErrInvalidAttributeDefinition)
+	}
+
+	def := &entity.AttributeDefinition{
+		Key:         req.Key,
+		Type:        entity.AttributeType(req.Type),
+		Required:    req.Required,
+		Enum:        req.Enum,
+		Pattern:     req.Pattern,
+		Description: strings.TrimSpace(req.Description),
+	}
+	if err := checkDefinition(def); err != nil {
+		return nil, err
+	}
+
+	if err := s.attrRepo.Create(ctx, def); err != nil {
+		s.logger.WithError(err).Error("Failed to create attribute definition")
+		return nil, err
+	}
+
+	s.logger.WithField("key", def.Key).Info("Attribute definition created successfully")
+	return def, nil
+}
+
+func (s *attributeService) ListDefinitions(ctx context.Context) ([]entity.AttributeDefinition, error) {
+	defs, err := s.attrRepo.List(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list attribute definitions")
+		return nil, err
+	}
+	return defs, nil
+}
+
+func (s *attributeService) UpdateDefinition(ctx context.Context, key string, req entity.UpdateAttributeDefinitionRequest) (*entity.AttributeDefinition, error) {
+	if err := s.authorize(ctx, auth.PermissionAttributesManage); err != nil {
+		return nil, err
+	}
+	s.logger.WithField("key", key).Info("Updating attribute definition")
+
+	def, err := s.attrRepo.GetByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Required != nil {
+		def.Required = *req.Required
+	}
+	if req.Enum != nil {
+		def.Enum = *req.Enum
+	}
+	if req.Pattern != nil {
+		def.Pattern = *req.Pattern
+	}
+	if req.Description != nil {
+		def.Description = strings.TrimSpace(*req.Description)
+	}
+	if err := checkDefinition(def); err != nil {
+		return nil, err
+	}
+
+	if err := s.attrRepo.Update(ctx, def); err != nil {
+		s.logger.WithError(err).WithField("key", key).Error("Failed to update attribute definition")
+		return nil, err
+	}
+
+	s.logger.WithField("key", key).Info("Attribute definition updated successfully")
+	return def, nil
+}
+
+func (s *attributeService) DeleteDefinition(ctx context.Context, key string) error {
+	if err := s.authorize(ctx, auth.PermissionAttributesManage); err != nil {
+		return err
+	}
+	s.logger.WithField("key", key).Info("Deleting attribute definition")
+
+	if err := s.attrRepo.Delete(ctx, key); err != nil {
+		s.logger.WithError(err).WithField("key", key).Error("Failed to delete attribute definition")
+		return err
+	}
+
+	s.logger.WithField("key", key).Info("Attribute definition deleted successfully")
+	return nil
+}
+
+// Schema renders the attribute definitions as a JSON Schema document for clients
+func (s *attributeService) Schema(ctx context.Context) (map[string]interface{}, error) {
+	defs, err := s.attrRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := make(map[string]interface{}, len(defs))
+	required := []string{}
+	for _, def := range defs {
+		prop := map[string]interface{}{"type": string(def.Type)}
+		if def.Description != "" {
+			prop["description"] = def.Description
+		}
+		if len(def.Enum) > 0 {
+			prop["enum"] = def.Enum
+		}
+		if def.Pattern != "" {
+			prop["pattern"] = def.Pattern
+		}
+		properties[def.Key] = prop
+		if def.Required {
+			required = append(required, def.Key)
+		}
+	}
+
+	return map[string]interface{}{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"title":                "User attributes",
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}, nil
+}
+
+// ValidateAttributes checks a full set of attribute values against the definitions
+func (s *attributeService) ValidateAttributes(ctx context.Context, attrs map[string]interface{}) error {
+	defs, err := s.definitionsByKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	fields := make(map[string]string)
+	for key, value := range attrs {
+		def, ok := defs[key]
+		if !ok {
+			fields[key] = "Unknown attribute"
+			continue
+		}
+		if msg := checkValue(def, value); msg != "" {
+			fields[key] = msg
+		}
+	}
+	for key, def := range defs {
+		if _, ok := attrs[key]; !ok && def.Required {
+			fields[key] = "This field is required"
+		}
+	}
+
+	if len(fields) > 0 {
+		return &AttributeValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// ValidateFilter checks that list filters only reference defined attributes
+func (s *attributeService) ValidateFilter(ctx context.Context, filter map[string]string) error {
+	if len(filter) == 0 {
+		return nil
+	}
+
+	defs, err := s.definitionsByKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	fields := make(map[string]string)
+	for key := range filter {
+		if _, ok := defs[key]; !ok {
+			fields[key] = "Unknown attribute"
+		}
+	}
+
+	if len(fields) > 0 {
+		return &AttributeValidationError{Fields: fields}
+	}
+	return nil
+}
+
+func (s *attributeService) definitionsByKey(ctx context.Context) (map[string]entity.AttributeDefinition, error) {
+	defs, err := s.attrRepo.List(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to load attribute definitions")
+		return nil, fmt.Errorf("failed to load attribute definitions: %w", err)
+	}
+
+	byKey := make(map[string]entity.AttributeDefinition, len(defs))
+	for _, def := range defs {
+		byKey[def.Key] = def
+	}
+	return byKey, nil
+}
+
+// checkDefinition validates the constraints of a definition itself
+func checkDefinition(def *entity.AttributeDefinition) error {
+	switch def.Type {
+	case entity.AttributeTypeString, entity.AttributeTypeNumber, entity.AttributeTypeInteger, entity.AttributeTypeBoolean:
+	default:
+		return fmt.Errorf("%w: unknown type %s", ErrInvalidAttributeDefinition, def.Type)
+	}
+
+	if def.Type != entity.AttributeTypeString && (len(def.Enum) > 0 || def.Pattern != "") {
+		return fmt.Errorf("%w: enum and pattern are only supported for string attributes", ErrInvalidAttributeDefinition)
+	}
+
+	if def.Pattern != "" {
+		if _, err := regexp.Compile(def.Pattern); err != nil {
+			return fmt.Errorf("%w: invalid pattern: %v", ErrInvalidAttributeDefinition, err)
+		}
+	}
+
+	if len(def.Enum) == 0 {
+		def.Enum = nil
+	}
+	sort.Strings(def.Enum)
+
+	return nil
+}
+
+// checkValue validates a single attribute value and returns a user-friendly message if it is invalid
+func checkValue(def entity.AttributeDefinition, value interface{}) string {
+	switch def.Type {
+	case entity.AttributeTypeString:
+		str, ok := value.(string)
+		if !ok {
+			return "Must be a string"
+		}
+		if len(def.Enum) > 0 {
+			idx := sort.SearchStrings(def.Enum, str)
+			if idx == len(def.Enum) || def.Enum[idx] != str {
+				return "Must be one of: " + strings.Join(def.Enum, ", ")
+			}
+		}
+		if def.Pattern != "" {
+			re, err := regexp.Compile(def.Pattern)
+			if err != nil || !re.MatchString(str) {
+				return "Must match pattern " + def.Pattern
+			}
+		}
+	case entity.AttributeTypeNumber:
+		if _, ok := value.(float64); !ok {
+			return "Must be a number"
+		}
+	case entity.AttributeTypeInteger:
+		f, ok := value.(float64)
+		if !ok || f != math.Trunc(f) {
+			return "Must be an integer"
+		}
+	case entity.AttributeTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return "Must be a boolean"
+		}
+	}
+	return ""
+}
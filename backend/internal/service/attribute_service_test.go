@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/auth"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAttributeDefinitionRepository is a mock implementation of the AttributeDefinitionRepository interface
+type MockAttributeDefinitionRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttributeDefinitionRepository) Create(ctx context.Context, def *entity.AttributeDefinition) error {
+	args := m.Called(ctx, def)
+	return args.Error(0)
+}
+
+func (m *MockAttributeDefinitionRepository) GetByKey(ctx context.Context, key string) (*entity.AttributeDefinition, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.AttributeDefinition), args.Error(1)
+}
+
+func (m *MockAttributeDefinitionRepository) List(ctx context.Context) ([]entity.AttributeDefinition, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.AttributeDefinition), args.Error(1)
+}
+
+func (m *MockAttributeDefinitionRepository) Update(ctx context.Context, def *entity.AttributeDefinition) error {
+	args := m.Called(ctx, def)
+	return args.Error(0)
+}
+
+func (m *MockAttributeDefinitionRepository) Delete(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func setupTestAttributeService() (*attributeService, *MockAttributeDefinitionRepository) {
+	mockRepo := &MockAttributeDefinitionRepository{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	return &attributeService{attrRepo: mockRepo, logger: logger}, mockRepo
+}
+
+func testDefinitions() []entity.AttributeDefinition {
+	return []entity.AttributeDefinition{
+		{Key: "department", Type: entity.AttributeTypeString, Required: true, Enum: entity.StringList{"engineering", "sales"}},
+		{Key: "employee_number", Type: entity.AttributeTypeString, Pattern: `^E[0-9]{5}$`},
+		{Key: "desk_count", Type: entity.AttributeTypeInteger},
+		{Key: "fte", Type: entity.AttributeTypeNumber},
+		{Key: "remote", Type: entity.AttributeTypeBoolean},
+	}
+}
+
+func TestAttributeService_ValidateAttributes(t *testing.T) {
+	tests := []struct {
+		name           string
+		attrs          map[string]interface{}
+		expectedFields map[string]string
+	}{
+		{
+			name: "Valid attributes",
+			attrs: map[string]interface{}{
+				"department":      "sales",
+				"employee_number": "E12345",
+				"desk_count":      float64(2),
+				"fte":             0.8,
+				"remote":          true,
+			},
+		},
+		{
+			name:           "Missing required attribute",
+			attrs:          map[string]interface{}{"remote": false},
+			expectedFields: map[string]string{"department": "This field is required"},
+		},
+		{
+			name:           "Unknown attribute",
+			attrs:          map[string]interface{}{"department": "sales", "shoe_size": "42"},
+			expectedFields: map[string]string{"shoe_size": "Unknown attribute"},
+		},
+		{
+			name: "Type and constraint violations",
+			attrs: map[string]interface{}{
+				"department":      "marketing",
+				"employee_number": "12345",
+				"desk_count":      1.5,
+				"fte":             "full",
+				"remote":          "yes",
+			},
+			expectedFields: map[string]string{
+				"department":      "Must be one of: engineering, sales",
+				"employee_number": "Must match pattern ^E[0-9]{5}$",
+				"desk_count":      "Must be an integer",
+				"fte":             "Must be a number",
+				"remote":          "Must be a boolean",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockRepo := setupTestAttributeService()
+			mockRepo.On("List", mock.Anything).Return(testDefinitions(), nil)
+
+			err := service.ValidateAttributes(context.Background(), tt.attrs)
+
+			if tt.expectedFields == nil {
+				assert.NoError(t, err)
+			} else {
+				var attrErr *AttributeValidationError
+				require.ErrorAs(t, err, &attrErr)
+				assert.Equal(t, tt.expectedFields, attrErr.Fields)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAttributeService_ValidateFilter(t *testing.T) {
+	service, mockRepo := setupTestAttributeService()
+	mockRepo.On("List", mock.Anything).Return(testDefinitions(), nil)
+
+	assert.NoError(t, service.ValidateFilter(context.Background(), map[string]string{"department": "sales"}))
+
+	var attrErr *AttributeValidationError
+	err := service.ValidateFilter(context.Background(), map[string]string{"unknown": "x"})
+	require.ErrorAs(t, err, &attrErr)
+	assert.Equal(t, map[string]string{"unknown": "Unknown attribute"}, attrErr.Fields)
+}
+
+func TestAttributeService_CreateDefinition(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       entity.CreateAttributeDefinitionRequest
+		expectedError error
+		setupMock     func(*MockAttributeDefinitionRepository)
+	}{
+		{
+			name:    "Successful creation",
+			request: entity.CreateAttributeDefinitionRequest{Key: "tshirt_size", Type: "string", Enum: []string{"M", "L", "S"}},
+			setupMock: func(mockRepo *MockAttributeDefinitionRepository) {
+				mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.AttributeDefinition")).Return(nil)
+			},
+		},
+		{
+			name:          "Invalid key",
+			request:       entity.CreateAttributeDefinitionRequest{Key: "T-Shirt Size", Type: "string"},
+			expectedError: ErrInvalidAttributeDefinition,
+			setupMock:     func(mockRepo *MockAttributeDefinitionRepository) {},
+		},
+		{
+			name:          "Enum on non-string type",
+			request:       entity.CreateAttributeDefinitionRequest{Key: "level", Type: "integer", Enum: []string{"1", "2"}},
+			expectedError: ErrInvalidAttributeDefinition,
+			setupMock:     func(mockRepo *MockAttributeDefinitionRepository) {},
+		},
+		{
+			name:          "Invalid pattern",
+			request:       entity.CreateAttributeDefinitionRequest{Key: "code", Type: "string", Pattern: "[a-"},
+			expectedError: ErrInvalidAttributeDefinition,
+			setupMock:     func(mockRepo *MockAttributeDefinitionRepository) {},
+		},
+		{
+			name:          "Duplicate key",
+			request:       entity.CreateAttributeDefinitionRequest{Key: "department", Type: "string"},
+			expectedError: errors.New("attribute key already exists"),
+			setupMock: func(mockRepo *MockAttributeDefinitionRepository) {
+				mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.AttributeDefinition")).Return(errors.New("attribute key already exists"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockRepo := setupTestAttributeService()
+			tt.setupMock(mockRepo)
+
+			def, err := service.CreateDefinition(context.Background(), tt.request)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Nil(t, def)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, entity.StringList{"L", "M", "S"}, def.Enum)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAttributeService_UpdateDefinition(t *testing.T) {
+	service, mockRepo := setupTestAttributeService()
+
+	mockRepo.On("GetByKey", mock.Anything, "department").Return(&entity.AttributeDefinition{
+		Key:       "department",
+		Type:      entity.AttributeTypeString,
+		CreatedAt: time.Now(),
+	}, nil)
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.AttributeDefinition")).Return(nil)
+
+	required := true
+	enum := []string{"sales", "engineering"}
+	def, err := service.UpdateDefinition(context.Background(), "department", entity.UpdateAttributeDefinitionRequest{
+		Required: &required,
+		Enum:     &enum,
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, def.Required)
+	assert.Equal(t, entity.StringList{"engineering", "sales"}, def.Enum)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAttributeService_Authorization(t *testing.T) {
+	service, mockRepo := setupTestAttributeService()
+	service.authorizer = auth.DefaultPolicy()
+
+	editor := auth.NewContext(context.Background(), &auth.Claims{Subject: "lead", Roles: []string{auth.RoleEditor}})
+	required := true
+
+	_, err := service.CreateDefinition(editor, entity.CreateAttributeDefinitionRequest{Key: "department", Type: "string"})
+	assert.EqualError(t, err, "missing permission attributes:manage")
+	_, err = service.UpdateDefinition(editor, "department", entity.UpdateAttributeDefinitionRequest{Required: &required})
+	assert.EqualError(t, err, "missing permission attributes:manage")
+	assert.EqualError(t, service.DeleteDefinition(editor, "department"), "missing permission attributes:manage")
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "GetByKey", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+
+	// Validating values only needs the definitions, whoever the caller is
+	mockRepo.On("List", mock.Anything).Return(testDefinitions(), nil)
+	assert.NoError(t, service.ValidateFilter(editor, map[string]string{"department": "sales"}))
+
+	admin := auth.NewContext(context.Background(), &auth.Claims{Subject: "root", Roles: []string{auth.RoleAdmin}})
+	mockRepo.On("Delete", mock.Anything, "department").Return(nil)
+	assert.NoError(t, service.DeleteDefinition(admin, "department"))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAttributeService_Schema(t *testing.T) {
+	service, mockRepo := setupTestAttributeService()
+	mockRepo.On("List", mock.Anything).Return(testDefinitions(), nil)
+
+	schema, err := service.Schema(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "object", schema["type"])
+	assert.Equal(t, []string{"department"}, schema["required"])
+	properties := schema["properties"].(map[string]interface{})
+	assert.Len(t, properties, 5)
+	assert.Equal(t, map[string]interface{}{"type": "string", "pattern": `^E[0-9]{5}$`}, properties["employee_number"])
+}
+
+func TestUserService_CreateUserValidatesAttributes(t *testing.T) {
+	mockRepo := &MockUserRepository{}
+	attrService, attrRepo := setupTestAttributeService()
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	service := NewUserService(mockRepo, logger, WithAttributeValidation(attrService))
+
+	mockRepo.On("EmailExists", mock.Anything, "test@example.com", uint(0)).Return(false, nil)
+	attrRepo.On("List", mock.Anything).Return(testDefinitions(), nil)
+
+	user, err := service.CreateUser(context.Background(), entity.CreateUserRequest{
+		Name:        "Test User",
+		Email:       "test@example.com",
+		DateOfBirth: "1990-01-01",
+		Attributes:  map[string]interface{}{"department": "marketing"},
+	})
+
+	var attrErr *AttributeValidationError
+	assert.ErrorAs(t, err, &attrErr)
+	assert.Nil(t, user)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_AttributesWithoutDefinitions(t *testing.T) {
+	service, mockRepo := setupTestService()
+
+	mockRepo.On("EmailExists", mock.Anything, "test@example.com", uint(0)).Return(false, nil)
+
+	_, err := service.CreateUser(context.Background(), entity.CreateUserRequest{
+		Name:        "Test User",
+		Email:       "test@example.com",
+		DateOfBirth: "1990-01-01",
+		Attributes:  map[string]interface{}{"department": "sales"},
+	})
+
+	var attrErr *AttributeValidationError
+	assert.ErrorAs(t, err, &attrErr)
+
+	_, err = service.ListUsers(context.Background(), entity.UserSearchParams{Attributes: map[string]string{"department": "sales"}})
+	assert.ErrorAs(t, err, &attrErr)
+	mockRepo.AssertExpectations(t)
+}
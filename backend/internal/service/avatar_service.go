@@ -0,0 +1,266 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // register decoder
+	"image/jpeg"
+	_ "image/png" // register decoder
+	"io"
+	"net/http"
+	"path"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"arritech-user-management/pkg/blob"
+	"github.com/sirupsen/logrus"
+	xdraw "golang.org/x/image/draw"
+)
+
+// AvatarSizes are the square thumbnail sizes, in pixels, generated for every avatar
+var AvatarSizes = []int{64, 128, 256}
+
+const (
+	// DefaultAvatarSize is served when no size is requested
+	DefaultAvatarSize = 256
+	// maxAvatarPixels bounds the decoded image size so small files cannot expand into huge bitmaps
+	maxAvatarPixels = 25_000_000
+)
+
+var (
+	// ErrInvalidAvatar is returned when an upload is not a supported image
+	ErrInvalidAvatar = errors.New("invalid avatar")
+	// ErrAvatarTooLarge is returned when an upload exceeds the configured size limit
+	ErrAvatarTooLarge = errors.New("avatar is too large")
+	// ErrAvatarNotFound is returned when a user has no avatar of the requested size
+	ErrAvatarNotFound = errors.New("avatar not found")
+	// ErrAvatarReplaced is returned when another upload replaced the avatar while one was processed
+	ErrAvatarReplaced = errors.New("avatar was replaced by another upload")
+)
+
+// AvatarConfig holds avatar upload settings
+type AvatarConfig struct {
+	// MaxBytes limits the size of uploaded files
+	MaxBytes int64
+}
+
+type AvatarService interface {
+	UploadAvatar(ctx context.Context, id uint, r io.Reader) (*entity.User, error)
+	GetAvatar(ctx context.Context, id uint, size int) (io.ReadCloser, error)
+	DeleteAvatar(ctx context.Context, user *entity.User) error
+}
+
+type avatarService struct {
+	userRepo repository.UserRepository
+	store    blob.BlobStore
+	config   AvatarConfig
+	redactor *PIIRedactor
+	changes  *UserChangeFeed
+	logger   *logrus.Logger
+	now      func() time.Time
+}
+
+// AvatarServiceOption configures optional collaborators of the avatar service
+type AvatarServiceOption func(*avatarService)
+
+// WithAvatarPIIRedaction masks personal data in the user returned after an upload, like WithPIIRedaction
+func WithAvatarPIIRedaction(redactor *PIIRedactor) AvatarServiceOption {
+	return func(s *avatarService) {
+		s.redactor = redactor
+	}
+}
+
+// WithAvatarChanges publishes the users whose avatar changed to feed
+func WithAvatarChanges(feed *UserChangeFeed) AvatarServiceOption {
+	return func(s *avatarService) {
+		s.changes = feed
+	}
+}
+
+func NewAvatarService(userRepo repository.UserRepository, store blob.BlobStore, config AvatarConfig, logger *logrus.Logger, opts ...AvatarServiceOption) AvatarService {
+	s := &avatarService{
+		userRepo: userRepo,
+		store:    store,
+		config:   config,
+		logger:   logger,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *avatarService) UploadAvatar(ctx context.Context, id uint, r io.Reader) (*entity.User, error) {
+	s.logger.WithField("user_id", id).Info("Uploading avatar")
+
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := s.decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every upload gets a new prefix so cached URLs of the previous avatar never show the new one
+	oldKey := user.AvatarKey
+	key := fmt.Sprintf("avatars/%d/%d/%d", user.OrganizationID, user.ID, s.now().UnixNano())
+
+	// Re-encoding the pixels drops EXIF and any other metadata of the original file
+	for _, size := range AvatarSizes {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, thumbnail(img, size), &jpeg.Options{Quality: 85}); err != nil {
+			return nil, fmt.Errorf("failed to encode avatar: %w", err)
+		}
+		if err := s.store.Put(ctx, avatarBlobKey(key, size), buf.Bytes(), "image/jpeg"); err != nil {
+			s.logger.WithError(err).WithField("user_id", id).Error("Failed to store avatar")
+			return nil, fmt.Errorf("failed to store avatar: %w", err)
+		}
+	}
+
+	// Only the avatar key is saved, so changes made to the user during the upload are kept
+	saved, err := s.userRepo.UpdateAvatarKey(ctx, user.ID, oldKey, key)
+	if err != nil {
+		s.logger.WithError(err).WithField("user_id", id).Error("Failed to save avatar")
+		s.deleteBlobs(ctx, key)
+		return nil, err
+	}
+	if !saved {
+		s.logger.WithField("user_id", id).Warn("Avatar was replaced during the upload")
+		s.deleteBlobs(ctx, key)
+		return nil, ErrAvatarReplaced
+	}
+	if oldKey != "" {
+		s.deleteBlobs(ctx, oldKey)
+	}
+	s.changes.publishUser(ctx, entity.UserEventUpdated, id)
+
+	if user, err = s.userRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	user.Age = user.CalculateAge()
+	user.AvatarURL = avatarURL(user)
+	s.redactor.Redact(ctx, user)
+
+	s.logger.WithField("user_id", id).Info("Avatar uploaded successfully")
+	return user, nil
+}
+
+func (s *avatarService) GetAvatar(ctx context.Context, id uint, size int) (io.ReadCloser, error) {
+	if !validAvatarSize(size) {
+		return nil, fmt.Errorf("%w: size must be one of %v", ErrInvalidAvatar, AvatarSizes)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.AvatarKey == "" {
+		return nil, ErrAvatarNotFound
+	}
+
+	rc, err := s.store.Get(ctx, avatarBlobKey(user.AvatarKey, size))
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			return nil, ErrAvatarNotFound
+		}
+		s.logger.WithError(err).WithField("user_id", id).Error("Failed to read avatar")
+		return nil, fmt.Errorf("failed to read avatar: %w", err)
+	}
+	return rc, nil
+}
+
+// DeleteAvatar removes the stored thumbnails of a user's avatar
+func (s *avatarService) DeleteAvatar(ctx context.Context, user *entity.User) error {
+	if user.AvatarKey == "" {
+		return nil
+	}
+	for _, size := range AvatarSizes {
+		if err := s.store.Delete(ctx, avatarBlobKey(user.AvatarKey, size)); err != nil {
+			return fmt.Errorf("failed to delete avatar: %w", err)
+		}
+	}
+	return nil
+}
+
+// decode reads an upload and checks its size, content type and dimensions before decoding it
+func (s *avatarService) decode(r io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(io.LimitReader(r, s.config.MaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read avatar: %w", err)
+	}
+	if int64(len(data)) > s.config.MaxBytes {
+		return nil, ErrAvatarTooLarge
+	}
+
+	// The content type is sniffed from the data; the client's declaration is not trusted
+	switch contentType := http.DetectContentType(data); contentType {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		return nil, fmt.Errorf("%w: unsupported content type %s", ErrInvalidAvatar, contentType)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAvatar, err)
+	}
+	if config.Width == 0 || config.Height == 0 || config.Width*config.Height > maxAvatarPixels {
+		return nil, fmt.Errorf("%w: image dimensions %dx%d are not supported", ErrInvalidAvatar, config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAvatar, err)
+	}
+	return img, nil
+}
+
+// deleteBlobs removes avatar thumbnails on a best-effort basis
+func (s *avatarService) deleteBlobs(ctx context.Context, key string) {
+	if err := s.DeleteAvatar(ctx, &entity.User{AvatarKey: key}); err != nil {
+		s.logger.WithError(err).WithField("avatar_key", key).Warn("Failed to delete avatar blobs")
+	}
+}
+
+// thumbnail crops the center square of src and scales it to size x size on a white background
+func thumbnail(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x := b.Min.X + (b.Dx()-side)/2
+	y := b.Min.Y + (b.Dy()-side)/2
+	crop := image.Rect(x, y, x+side, y+side)
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Over, nil)
+	return dst
+}
+
+func validAvatarSize(size int) bool {
+	for _, s := range AvatarSizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
+
+func avatarBlobKey(key string, size int) string {
+	return fmt.Sprintf("%s/%d.jpg", key, size)
+}
+
+// avatarURL returns the API path of a user's avatar, versioned so browsers refetch it after an upload
+func avatarURL(user *entity.User) string {
+	if user.AvatarKey == "" {
+		return ""
+	}
+	return fmt.Sprintf("/api/v1/users/%d/avatar?v=%s", user.ID, path.Base(user.AvatarKey))
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/blob"
+	"arritech-user-management/pkg/tenant"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAvatarService() (*avatarService, *MockUserRepository, *blob.MemoryStore) {
+	mockRepo := &MockUserRepository{}
+	store := blob.NewMemoryStore()
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	service := &avatarService{
+		userRepo: mockRepo,
+		store:    store,
+		config:   AvatarConfig{MaxBytes: 1 << 20},
+		logger:   logger,
+		now:      func() time.Time { return time.Unix(0, 42) },
+	}
+	return service, mockRepo, store
+}
+
+func pngImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestAvatarService_UploadAvatar(t *testing.T) {
+	service, mockRepo, store := setupAvatarService()
+	dateOfBirth := time.Now().AddDate(-30, 0, 0)
+	mockRepo.On("GetByID", mock.Anything, uint(7)).Return(&entity.User{ID: 7, OrganizationID: 1, DateOfBirth: dateOfBirth}, nil).Once()
+	mockRepo.On("UpdateAvatarKey", mock.Anything, uint(7), "", "avatars/1/7/42").Return(true, nil)
+	// The saved user is loaded again, with what others changed during the upload
+	mockRepo.On("GetByID", mock.Anything, uint(7)).Return(&entity.User{ID: 7, OrganizationID: 1, Name: "Renamed", DateOfBirth: dateOfBirth, AvatarKey: "avatars/1/7/42"}, nil).Once()
+
+	result, err := service.UploadAvatar(context.Background(), 7, bytes.NewReader(pngImage(t, 300, 200)))
+
+	require.NoError(t, err)
+	assert.Equal(t, "avatars/1/7/42", result.AvatarKey)
+	assert.Equal(t, "Renamed", result.Name)
+	assert.Equal(t, "/api/v1/users/7/avatar?v=42", result.AvatarURL)
+	assert.Equal(t, 30, result.Age)
+	assert.ElementsMatch(t, []string{"avatars/1/7/42/64.jpg", "avatars/1/7/42/128.jpg", "avatars/1/7/42/256.jpg"}, store.Keys())
+
+	for _, size := range AvatarSizes {
+		rc, err := store.Get(context.Background(), avatarBlobKey(result.AvatarKey, size))
+		require.NoError(t, err)
+		img, err := jpeg.Decode(rc)
+		rc.Close()
+		require.NoError(t, err)
+		assert.Equal(t, size, img.Bounds().Dx())
+		assert.Equal(t, size, img.Bounds().Dy())
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAvatarService_UploadAvatar_ReplacesPrevious(t *testing.T) {
+	service, mockRepo, store := setupAvatarService()
+	for _, size := range AvatarSizes {
+		require.NoError(t, store.Put(context.Background(), avatarBlobKey("avatars/1/7/1", size), []byte("old"), "image/jpeg"))
+	}
+	mockRepo.On("GetByID", mock.Anything, uint(7)).Return(&entity.User{ID: 7, OrganizationID: 1, AvatarKey: "avatars/1/7/1"}, nil).Once()
+	mockRepo.On("UpdateAvatarKey", mock.Anything, uint(7), "avatars/1/7/1", "avatars/1/7/42").Return(true, nil)
+	mockRepo.On("GetByID", mock.Anything, uint(7)).Return(&entity.User{ID: 7, OrganizationID: 1, AvatarKey: "avatars/1/7/42"}, nil).Once()
+
+	_, err := service.UploadAvatar(context.Background(), 7, bytes.NewReader(pngImage(t, 64, 64)))
+
+	require.NoError(t, err)
+	for _, key := range store.Keys() {
+		assert.True(t, strings.HasPrefix(key, "avatars/1/7/42/"), key)
+	}
+	assert.Len(t, store.Keys(), len(AvatarSizes))
+}
+
+func TestAvatarService_UploadAvatar_PublishesChange(t *testing.T) {
+	service, mockRepo, _ := setupAvatarService()
+	service.changes = NewUserChangeFeed(4)
+	ctx, cancel := context.WithCancel(tenant.NewContext(context.Background(), 1))
+	defer cancel()
+	changes := service.changes.Watch(ctx)
+	mockRepo.On("GetByID", mock.Anything, uint(7)).Return(&entity.User{ID: 7, OrganizationID: 1}, nil)
+	mockRepo.On("UpdateAvatarKey", mock.Anything, uint(7), "", "avatars/1/7/42").Return(true, nil)
+
+	_, err := service.UploadAvatar(ctx, 7, bytes.NewReader(pngImage(t, 8, 8)))
+
+	require.NoError(t, err)
+	change := <-changes
+	assert.Equal(t, entity.UserEventUpdated, change.Type)
+	assert.Equal(t, uint(7), change.UserID)
+	assert.Equal(t, uint(1), change.OrganizationID)
+}
+
+func TestAvatarService_UploadAvatar_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          []byte
+		expectedError error
+	}{
+		{
+			name:          "Not an image",
+			data:          []byte("hello, this is plain text"),
+			expectedError: ErrInvalidAvatar,
+		},
+		{
+			name:          "Truncated image",
+			data:          pngImage(t, 32, 32)[:40],
+			expectedError: ErrInvalidAvatar,
+		},
+		{
+			name:          "Too large",
+			data:          bytes.Repeat([]byte{0}, 1<<20+1),
+			expectedError: ErrAvatarTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockRepo, store := setupAvatarService()
+			mockRepo.On("GetByID", mock.Anything, uint(7)).Return(&entity.User{ID: 7}, nil)
+
+			_, err := service.UploadAvatar(context.Background(), 7, bytes.NewReader(tt.data))
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Empty(t, store.Keys())
+			mockRepo.AssertNotCalled(t, "UpdateAvatarKey", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAvatarService_UploadAvatar_UserNotFound(t *testing.T) {
+	service, mockRepo, _ := setupAvatarService()
+	mockRepo.On("GetByID", mock.Anything, uint(999)).Return(nil, errors.New("user not found"))
+
+	_, err := service.UploadAvatar(context.Background(), 999, bytes.NewReader(pngImage(t, 8, 8)))
+
+	assert.EqualError(t, err, "user not found")
+}
+
+func TestAvatarService_UploadAvatar_UpdateFails(t *testing.T) {
+	service, mockRepo, store := setupAvatarService()
+	mockRepo.On("GetByID", mock.Anything, uint(7)).Return(&entity.User{ID: 7, OrganizationID: 1}, nil)
+	mockRepo.On("UpdateAvatarKey", mock.Anything, uint(7), "", mock.Anything).Return(false, errors.New("database error"))
+
+	_, err := service.UploadAvatar(context.Background(), 7, bytes.NewReader(pngImage(t, 8, 8)))
+
+	assert.Error(t, err)
+	assert.Empty(t, store.Keys())
+}
+
+func TestAvatarService_UploadAvatar_ReplacedConcurrently(t *testing.T) {
+	service, mockRepo, store := setupAvatarService()
+	for _, size := range AvatarSizes {
+		require.NoError(t, store.Put(context.Background(), avatarBlobKey("avatars/1/7/9", size), []byte("other"), "image/jpeg"))
+	}
+	mockRepo.On("GetByID", mock.Anything, uint(7)).Return(&entity.User{ID: 7, OrganizationID: 1}, nil)
+	// Another upload saved avatars/1/7/9 after this one loaded the user
+	mockRepo.On("UpdateAvatarKey", mock.Anything, uint(7), "", "avatars/1/7/42").Return(false, nil)
+
+	_, err := service.UploadAvatar(context.Background(), 7, bytes.NewReader(pngImage(t, 8, 8)))
+
+	assert.ErrorIs(t, err, ErrAvatarReplaced)
+	for _, key := range store.Keys() {
+		assert.True(t, strings.HasPrefix(key, "avatars/1/7/9/"), key)
+	}
+	assert.Len(t, store.Keys(), len(AvatarSizes))
+}
+
+func TestAvatarService_GetAvatar(t *testing.T) {
+	service, mockRepo, store := setupAvatarService()
+	require.NoError(t, store.Put(context.Background(), "avatars/1/7/42/128.jpg", []byte("jpeg"), "image/jpeg"))
+	mockRepo.On("GetByID", mock.Anything, uint(7)).Return(&entity.User{ID: 7, AvatarKey: "avatars/1/7/42"}, nil)
+	mockRepo.On("GetByID", mock.Anything, uint(8)).Return(&entity.User{ID: 8}, nil)
+
+	rc, err := service.GetAvatar(context.Background(), 7, 128)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, "jpeg", string(data))
+
+	_, err = service.GetAvatar(context.Background(), 7, 64)
+	assert.ErrorIs(t, err, ErrAvatarNotFound)
+
+	_, err = service.GetAvatar(context.Background(), 8, 128)
+	assert.ErrorIs(t, err, ErrAvatarNotFound)
+
+	_, err = service.GetAvatar(context.Background(), 7, 100)
+	assert.ErrorIs(t, err, ErrInvalidAvatar)
+}
+
+func TestUserService_DeleteUser_PurgesAvatar(t *testing.T) {
+	avatars, mockRepo, store := setupAvatarService()
+	require.NoError(t, store.Put(context.Background(), "avatars/1/7/42/64.jpg", []byte("jpeg"), "image/jpeg"))
+	service := &userService{userRepo: mockRepo, avatars: avatars, logger: avatars.logger}
+	mockRepo.On("GetByID", mock.Anything, uint(7)).Return(&entity.User{ID: 7, AvatarKey: "avatars/1/7/42"}, nil)
+	mockRepo.On("Delete", mock.Anything, uint(7)).Return(nil)
+
+	err := service.DeleteUser(context.Background(), 7)
+
+	require.NoError(t, err)
+	assert.Empty(t, store.Keys())
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"arritech-user-management/pkg/ical"
+	"arritech-user-management/pkg/tenant"
+	"arritech-user-management/pkg/token"
+	"github.com/sirupsen/logrus"
+)
+
+// birthdayFeedPurpose scopes calendar feed tokens so they can't be reused elsewhere
+const birthdayFeedPurpose = "birthday-feed"
+
+var (
+	// ErrInvalidBirthdayRange is returned when a birthday range is reversed or spans a year or more
+	ErrInvalidBirthdayRange = errors.New("invalid birthday range")
+	// ErrInvalidFeedToken is returned when a calendar feed token is malformed or expired
+	ErrInvalidFeedToken = errors.New("invalid or expired calendar feed token")
+)
+
+// BirthdayConfig holds calendar feed settings
+type BirthdayConfig struct {
+	// FeedTTL is how long a calendar subscription link stays valid
+	FeedTTL time.Duration
+	// FeedURL is the public URL of the calendar feed endpoint
+	FeedURL string
+}
+
+type BirthdayService interface {
+	UpcomingBirthdays(ctx context.Context, from, to time.Time) ([]entity.Birthday, error)
+	IssueFeed(ctx context.Context) (*entity.BirthdayFeed, error)
+	Calendar(ctx context.Context, feedToken string) ([]byte, error)
+}
+
+type birthdayService struct {
+	userRepo repository.UserRepository
+	signer   *token.Signer
+	config   BirthdayConfig
+	logger   *logrus.Logger
+	now      func() time.Time
+}
+
+func NewBirthdayService(userRepo repository.UserRepository, signer *token.Signer, config BirthdayConfig, logger *logrus.Logger) BirthdayService {
+	return &birthdayService{
+		userRepo: userRepo,
+		signer:   signer,
+		config:   config,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+func (s *birthdayService) UpcomingBirthdays(ctx context.Context, from, to time.Time) ([]entity.Birthday, error) {
+	from = dateOf(from)
+	to = dateOf(to)
+
+	// Business rule: every birthday occurs at most once in the range
+	if to.Before(from) || !to.Before(from.AddDate(1, 0, 0)) {
+		return nil, fmt.Errorf("%w: to must not be before from and the range must be shorter than a year", ErrInvalidBirthdayRange)
+	}
+
+	users, err := s.userRepo.ListBirthdays(ctx, from, to)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list birthdays")
+		return nil, err
+	}
+
+	birthdays := make([]entity.Birthday, 0, len(users))
+	for _, user := range users {
+		date := nextBirthday(user.DateOfBirth, from)
+		birthdays = append(birthdays, entity.Birthday{
+			UserID:      user.ID,
+			Name:        user.Name,
+			Email:       user.Email,
+			DateOfBirth: user.DateOfBirth,
+			Date:        date.Format("2006-01-02"),
+			TurningAge:  date.Year() - user.DateOfBirth.Year(),
+		})
+	}
+	return birthdays, nil
+}
+
+// IssueFeed creates a subscription link to the birthday calendar of the current organization.
+// Calendar clients cannot send headers, so the link carries a signed token instead.
+func (s *birthdayService) IssueFeed(ctx context.Context) (*entity.BirthdayFeed, error) {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("organization is required")
+	}
+
+	tok, err := s.signer.Sign(birthdayFeedPurpose, strconv.FormatUint(uint64(orgID), 10), s.config.FeedTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign calendar feed token: %w", err)
+	}
+
+	link, err := url.Parse(s.config.FeedURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar feed URL: %w", err)
+	}
+	q := link.Query()
+	q.Set("token", tok)
+	link.RawQuery = q.Encode()
+
+	return &entity.BirthdayFeed{
+		URL:       link.String(),
+		ExpiresAt: s.now().Add(s.config.FeedTTL).Truncate(time.Second),
+	}, nil
+}
+
+// Calendar renders the birthdays of the organization named by the feed token as an iCalendar file
+func (s *birthdayService) Calendar(ctx context.Context, feedToken string) ([]byte, error) {
+	subject, err := s.signer.Verify(birthdayFeedPurpose, feedToken)
+	if err != nil {
+		s.logger.WithError(err).Warn("Rejected calendar feed token")
+		return nil, ErrInvalidFeedToken
+	}
+	orgID, err := strconv.ParseUint(subject, 10, 32)
+	if err != nil {
+		return nil, ErrInvalidFeedToken
+	}
+
+	// The token decides the organization, whatever the request carried
+	ctx = tenant.NewContext(ctx, uint(orgID))
+
+	today := dateOf(s.now())
+	users, err := s.userRepo.ListBirthdays(ctx, today, today.AddDate(1, 0, -1))
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list birthdays for calendar")
+		return nil, err
+	}
+
+	cal := &ical.Calendar{
+		ProdID: "-//Arritech//User Management//EN",
+		Name:   "Birthdays",
+	}
+	for _, user := range users {
+		dob := dateOf(user.DateOfBirth)
+		rrule := "FREQ=YEARLY"
+		if dob.Month() == time.February && dob.Day() == 29 {
+			// A plain yearly rule skips non-leap years; the last day of February keeps the event every year
+			rrule = "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=-1"
+		}
+		cal.Events = append(cal.Events, ical.Event{
+			UID:     fmt.Sprintf("birthday-%d-%d@arritech.com", orgID, user.ID),
+			Summary: fmt.Sprintf("%s's birthday", user.Name),
+			Date:    dob,
+			RRule:   rrule,
+			Stamp:   user.UpdatedAt,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := cal.Encode(&buf); err != nil {
+		return nil, fmt.Errorf("failed to encode calendar: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// nextBirthday returns the first birthday on or after from. Feb 29 birthdays fall on Feb 28 in
+// other years, the day the user's age changes.
+func nextBirthday(dob, from time.Time) time.Time {
+	date := entity.BirthdayIn(dob, from.Year())
+	if date.Before(from) {
+		date = entity.BirthdayIn(dob, from.Year()+1)
+	}
+	return date
+}
+
+// dateOf drops the time of day, keeping the calendar date
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/tenant"
+	"arritech-user-management/pkg/token"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupBirthdayService(now time.Time) (*birthdayService, *MockUserRepository) {
+	mockRepo := &MockUserRepository{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	service := &birthdayService{
+		userRepo: mockRepo,
+		signer:   token.NewSigner([]byte("secret")),
+		config:   BirthdayConfig{FeedTTL: 24 * time.Hour, FeedURL: "http://localhost:8080/api/v1/users/birthdays.ics"},
+		logger:   logger,
+		now:      func() time.Time { return now },
+	}
+	return service, mockRepo
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestBirthdayService_UpcomingBirthdays(t *testing.T) {
+	service, mockRepo := setupBirthdayService(time.Now())
+	from, to := date(2025, 12, 20), date(2026, 1, 10)
+	mockRepo.On("ListBirthdays", mock.Anything, from, to).Return([]entity.User{
+		{ID: 1, Name: "December", DateOfBirth: date(1990, 12, 25)},
+		{ID: 2, Name: "January", DateOfBirth: date(2000, 1, 3)},
+	}, nil)
+
+	birthdays, err := service.UpcomingBirthdays(context.Background(), from, to)
+
+	require.NoError(t, err)
+	require.Len(t, birthdays, 2)
+	assert.Equal(t, "2025-12-25", birthdays[0].Date)
+	assert.Equal(t, 35, birthdays[0].TurningAge)
+	assert.Equal(t, "2026-01-03", birthdays[1].Date)
+	assert.Equal(t, 26, birthdays[1].TurningAge)
+}
+
+func TestBirthdayService_UpcomingBirthdays_InvalidRange(t *testing.T) {
+	service, _ := setupBirthdayService(time.Now())
+
+	_, err := service.UpcomingBirthdays(context.Background(), date(2025, 2, 1), date(2025, 1, 1))
+	assert.ErrorIs(t, err, ErrInvalidBirthdayRange)
+
+	_, err = service.UpcomingBirthdays(context.Background(), date(2025, 2, 1), date(2026, 2, 1))
+	assert.ErrorIs(t, err, ErrInvalidBirthdayRange)
+}
+
+func TestNextBirthday(t *testing.T) {
+	tests := []struct {
+		name     string
+		dob      time.Time
+		from     time.Time
+		expected time.Time
+	}{
+		{"Later this year", date(1990, 6, 15), date(2025, 3, 1), date(2025, 6, 15)},
+		{"Today", date(1990, 3, 1), date(2025, 3, 1), date(2025, 3, 1)},
+		{"Wraps into next year", date(1990, 1, 5), date(2025, 12, 20), date(2026, 1, 5)},
+		{"Leap day in common year", date(2000, 2, 29), date(2025, 2, 1), date(2025, 2, 28)},
+		{"Leap day in leap year", date(2000, 2, 29), date(2028, 2, 1), date(2028, 2, 29)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, nextBirthday(tt.dob, tt.from))
+		})
+	}
+}
+
+func TestNextBirthday_MatchesAge(t *testing.T) {
+	// The birthday listed and put in the calendar is the day the age used by policies changes
+	for _, dob := range []time.Time{date(2000, 2, 29), date(1990, 6, 15), date(1990, 3, 1)} {
+		for _, year := range []int{2025, 2028} {
+			birthday := nextBirthday(dob, date(year, 1, 1))
+			user := &entity.User{DateOfBirth: dob}
+
+			assert.Equal(t, birthday.Year()-dob.Year(), user.AgeAt(birthday), "%s in %d", dob.Format("01-02"), year)
+			assert.Equal(t, birthday.Year()-dob.Year()-1, user.AgeAt(birthday.AddDate(0, 0, -1)), "%s in %d", dob.Format("01-02"), year)
+		}
+	}
+}
+
+func TestBirthdayService_Feed(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	service, mockRepo := setupBirthdayService(now)
+
+	feed, err := service.IssueFeed(tenant.NewContext(context.Background(), 3))
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(24*time.Hour), feed.ExpiresAt)
+
+	link, err := url.Parse(feed.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/users/birthdays.ics", link.Path)
+
+	// The calendar is read from the organization in the token
+	mockRepo.On("ListBirthdays", mock.MatchedBy(func(ctx context.Context) bool {
+		orgID, _ := tenant.FromContext(ctx)
+		return orgID == 3
+	}), date(2025, 3, 1), date(2026, 2, 28)).Return([]entity.User{
+		{ID: 1, Name: "Jane Doe", DateOfBirth: date(1990, 3, 14)},
+		{ID: 2, Name: "Leap", DateOfBirth: date(2000, 2, 29)},
+	}, nil)
+
+	ics, err := service.Calendar(tenant.NewContext(context.Background(), 1), link.Query().Get("token"))
+	require.NoError(t, err)
+
+	content := string(ics)
+	assert.True(t, strings.HasPrefix(content, "BEGIN:VCALENDAR\r\n"))
+	assert.Contains(t, content, "UID:birthday-3-1@arritech.com\r\n")
+	assert.Contains(t, content, "DTSTART;VALUE=DATE:19900314\r\nRRULE:FREQ=YEARLY\r\nSUMMARY:Jane Doe's birthday\r\n")
+	assert.Contains(t, content, "DTSTART;VALUE=DATE:20000229\r\nRRULE:FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=-1\r\n")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBirthdayService_Calendar_InvalidToken(t *testing.T) {
+	service, _ := setupBirthdayService(time.Now())
+
+	_, err := service.Calendar(context.Background(), "forged.token")
+	assert.ErrorIs(t, err, ErrInvalidFeedToken)
+
+	// Tokens issued for another purpose are rejected
+	other, _ := service.signer.Sign(emailVerificationPurpose, "1", time.Hour)
+	_, err = service.Calendar(context.Background(), other)
+	assert.True(t, errors.Is(err, ErrInvalidFeedToken))
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"arritech-user-management/pkg/mailer"
+	"arritech-user-management/pkg/tenant"
+	"arritech-user-management/pkg/token"
+	"github.com/sirupsen/logrus"
+)
+
+// emailVerificationPurpose scopes verification tokens so they can't be reused elsewhere
+const emailVerificationPurpose = "email_verification"
+
+var (
+	// ErrInvalidVerificationToken is returned when a token is malformed, expired or no longer matches the user's email
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+
+	// ErrEmailAlreadyVerified is returned when a verification email is requested for a verified address
+	ErrEmailAlreadyVerified = errors.New("email already verified")
+)
+
+// VerificationRateLimitedError is returned when a verification email was sent too recently
+type VerificationRateLimitedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *VerificationRateLimitedError) Error() string {
+	return "verification email recently sent, try again later"
+}
+
+// EmailVerificationConfig holds email verification settings
+type EmailVerificationConfig struct {
+	TokenTTL       time.Duration
+	ResendCooldown time.Duration
+	VerifyURL      string
+}
+
+type EmailVerificationService interface {
+	SendVerification(ctx context.Context, user *entity.User) error
+	VerifyEmail(ctx context.Context, token string) (*entity.User, error)
+	ResendVerification(ctx context.Context, id uint) error
+}
+
+type emailVerificationService struct {
+	userRepo repository.UserRepository
+	mailer   mailer.Mailer
+	signer   *token.Signer
+	config   EmailVerificationConfig
+	changes  *UserChangeFeed
+	logger   *logrus.Logger
+}
+
+// EmailVerificationServiceOption configures optional collaborators of the email verification service
+type EmailVerificationServiceOption func(*emailVerificationService)
+
+// WithVerificationChanges publishes the users whose email was verified to feed
+func WithVerificationChanges(feed *UserChangeFeed) EmailVerificationServiceOption {
+	return func(s *emailVerificationService) {
+		s.changes = feed
+	}
+}
+
+func NewEmailVerificationService(userRepo repository.UserRepository, m mailer.Mailer, signer *token.Signer, config EmailVerificationConfig, logger *logrus.Logger, opts ...EmailVerificationServiceOption) EmailVerificationService {
+	s := &emailVerificationService{
+		userRepo: userRepo,
+		mailer:   m,
+		signer:   signer,
+		config:   config,
+		logger:   logger,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *emailVerificationService) SendVerification(ctx context.Context, user *entity.User) error {
+	s.logger.WithField("user_id", user.ID).Info("Sending verification email")
+
+	// The email is part of the subject so tokens issued for a previous address stop working.
+	// The organization is included because the link is opened without any tenant context.
+	subject := fmt.Sprintf("%d:%d:%s", user.OrganizationID, user.ID, user.Email)
+	tok, err := s.signer.Sign(emailVerificationPurpose, subject, s.config.TokenTTL)
+	if err != nil {
+		return fmt.Errorf("failed to sign verification token: %w", err)
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf("Hello %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nThe link expires in %s.\n",
+			user.Name, s.verifyLink(tok), s.config.TokenTTL),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		s.logger.WithError(err).WithField("user_id", user.ID).Error("Failed to send verification email")
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
+
+	now := time.Now()
+	user.VerificationSentAt = &now
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		s.logger.WithError(err).WithField("user_id", user.ID).Error("Failed to record verification email")
+		return err
+	}
+
+	s.logger.WithField("user_id", user.ID).Info("Verification email sent successfully")
+	return nil
+}
+
+func (s *emailVerificationService) VerifyEmail(ctx context.Context, tok string) (*entity.User, error) {
+	subject, err := s.signer.Verify(emailVerificationPurpose, tok)
+	if err != nil {
+		s.logger.WithError(err).Warn("Rejected verification token")
+		return nil, ErrInvalidVerificationToken
+	}
+
+	parts := strings.SplitN(subject, ":", 3)
+	if len(parts) != 3 {
+		return nil, ErrInvalidVerificationToken
+	}
+	orgID, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return nil, ErrInvalidVerificationToken
+	}
+	id, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, ErrInvalidVerificationToken
+	}
+	email := parts[2]
+
+	// The token decides the organization, whatever the request carried
+	ctx = tenant.NewContext(ctx, uint(orgID))
+
+	user, err := s.userRepo.GetByID(ctx, uint(id))
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, ErrInvalidVerificationToken
+		}
+		return nil, err
+	}
+
+	// Business rule: the token only verifies the address it was issued for
+	if user.Email != email {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	if user.EmailVerifiedAt == nil {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+
+		// Business rule: confirming the email completes a pending registration
+		change := entity.UserEventUpdated
+		if user.Status == entity.UserStatusPending {
+			user.Status = entity.UserStatusActive
+			user.StatusReason = "Email address verified"
+			user.StatusChangedAt = &now
+			change = entity.UserEventStatusChanged
+		}
+
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			s.logger.WithError(err).WithField("user_id", user.ID).Error("Failed to mark email as verified")
+			return nil, err
+		}
+		s.changes.publishUser(ctx, change, user.ID)
+	}
+
+	user.Age = user.CalculateAge()
+	user.AvatarURL = avatarURL(user)
+	// Anyone holding the link can call this endpoint, so it never shows personal data
+	user.RedactPII()
+
+	s.logger.WithField("user_id", user.ID).Info("Email verified successfully")
+	return user, nil
+}
+
+func (s *emailVerificationService) ResendVerification(ctx context.Context, id uint) error {
+	s.logger.WithField("user_id", id).Info("Resending verification email")
+
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+
+	// Business rule: at most one verification email per cooldown window
+	if user.VerificationSentAt != nil {
+		if elapsed := time.Since(*user.VerificationSentAt); elapsed < s.config.ResendCooldown {
+			return &VerificationRateLimitedError{RetryAfter: s.config.ResendCooldown - elapsed}
+		}
+	}
+
+	return s.SendVerification(ctx, user)
+}
+
+// verifyLink builds the link sent to the user, appending the token to the configured URL
+func (s *emailVerificationService) verifyLink(tok string) string {
+	if s.config.VerifyURL == "" {
+		return tok
+	}
+
+	u, err := url.Parse(s.config.VerifyURL)
+	if err != nil {
+		return s.config.VerifyURL + tok
+	}
+	q := u.Query()
+	q.Set("token", tok)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
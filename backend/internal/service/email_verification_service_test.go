@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/mailer"
+	"arritech-user-management/pkg/tenant"
+	"arritech-user-management/pkg/token"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestVerificationService() (*emailVerificationService, *MockUserRepository, *mailer.MemoryMailer) {
+	mockRepo := &MockUserRepository{}
+	mail := mailer.NewMemoryMailer()
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	service := &emailVerificationService{
+		userRepo: mockRepo,
+		mailer:   mail,
+		signer:   token.NewSigner([]byte("test-secret")),
+		config: EmailVerificationConfig{
+			TokenTTL:       time.Hour,
+			ResendCooldown: 5 * time.Minute,
+			VerifyURL:      "http://localhost:5173/verify-email",
+		},
+		logger: logger,
+	}
+
+	return service, mockRepo, mail
+}
+
+// tokenFromMessage extracts the verification token from the link in a sent message
+func tokenFromMessage(t *testing.T, msg mailer.Message) string {
+	for _, line := range strings.Split(msg.Body, "\n") {
+		if strings.HasPrefix(line, "http") {
+			u, err := url.Parse(line)
+			require.NoError(t, err)
+			return u.Query().Get("token")
+		}
+	}
+	t.Fatal("no verification link found in message")
+	return ""
+}
+
+func TestEmailVerificationService_SendVerification(t *testing.T) {
+	service, mockRepo, mail := setupTestVerificationService()
+
+	user := &entity.User{ID: 1, Name: "Test User", Email: "test@example.com"}
+	mockRepo.On("Update", mock.Anything, user).Return(nil)
+
+	err := service.SendVerification(context.Background(), user)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, user.VerificationSentAt)
+
+	messages := mail.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "test@example.com", messages[0].To)
+	assert.Contains(t, messages[0].Body, "http://localhost:5173/verify-email?token=")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestEmailVerificationService_VerifyEmail(t *testing.T) {
+	t.Run("Verifies email and activates pending user", func(t *testing.T) {
+		service, mockRepo, mail := setupTestVerificationService()
+
+		user := &entity.User{ID: 1, Name: "Test User", Email: "test@example.com", Status: entity.UserStatusPending}
+		mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil)
+		require.NoError(t, service.SendVerification(context.Background(), user))
+
+		mockRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{
+			ID:     1,
+			Email:  "test@example.com",
+			Status: entity.UserStatusPending,
+		}, nil)
+
+		verified, err := service.VerifyEmail(context.Background(), tokenFromMessage(t, mail.Messages()[0]))
+
+		require.NoError(t, err)
+		assert.NotNil(t, verified.EmailVerifiedAt)
+		assert.Equal(t, entity.UserStatusActive, verified.Status)
+		assert.Equal(t, "***@example.com", verified.Email, "the anonymous caller sees masked personal data")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Publishes the activation to watchers", func(t *testing.T) {
+		service, mockRepo, mail := setupTestVerificationService()
+		service.changes = NewUserChangeFeed(4)
+		watchCtx, cancel := context.WithCancel(tenant.NewContext(context.Background(), 2))
+		defer cancel()
+		changes := service.changes.Watch(watchCtx)
+
+		user := &entity.User{ID: 1, OrganizationID: 2, Email: "test@example.com", Status: entity.UserStatusPending}
+		mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil)
+		require.NoError(t, service.SendVerification(context.Background(), user))
+		mockRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{
+			ID:             1,
+			OrganizationID: 2,
+			Email:          "test@example.com",
+			Status:         entity.UserStatusPending,
+		}, nil)
+
+		_, err := service.VerifyEmail(context.Background(), tokenFromMessage(t, mail.Messages()[0]))
+
+		require.NoError(t, err)
+		change := <-changes
+		assert.Equal(t, entity.UserEventStatusChanged, change.Type)
+		assert.Equal(t, uint(1), change.UserID)
+		assert.Equal(t, uint(2), change.OrganizationID)
+	})
+
+	t.Run("Rejects token issued for a previous email", func(t *testing.T) {
+		service, mockRepo, _ := setupTestVerificationService()
+
+		tok, err := service.signer.Sign(emailVerificationPurpose, "1:1:old@example.com", time.Hour)
+		require.NoError(t, err)
+
+		mockRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1, Email: "new@example.com"}, nil)
+
+		user, err := service.VerifyEmail(context.Background(), tok)
+
+		assert.ErrorIs(t, err, ErrInvalidVerificationToken)
+		assert.Nil(t, user)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Looks the user up in the token's organization", func(t *testing.T) {
+		service, mockRepo, _ := setupTestVerificationService()
+
+		tok, err := service.signer.Sign(emailVerificationPurpose, "2:1:test@example.com", time.Hour)
+		require.NoError(t, err)
+
+		inOrganization := mock.MatchedBy(func(ctx context.Context) bool {
+			id, ok := tenant.FromContext(ctx)
+			return ok && id == 2
+		})
+		mockRepo.On("GetByID", inOrganization, uint(1)).Return(&entity.User{ID: 1, OrganizationID: 2, Email: "test@example.com"}, nil)
+		mockRepo.On("Update", inOrganization, mock.AnythingOfType("*entity.User")).Return(nil)
+
+		user, err := service.VerifyEmail(tenant.NewContext(context.Background(), 1), tok)
+
+		require.NoError(t, err)
+		assert.NotNil(t, user.EmailVerifiedAt)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects malformed token", func(t *testing.T) {
+		service, mockRepo, _ := setupTestVerificationService()
+
+		user, err := service.VerifyEmail(context.Background(), "garbage")
+
+		assert.ErrorIs(t, err, ErrInvalidVerificationToken)
+		assert.Nil(t, user)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects token for deleted user", func(t *testing.T) {
+		service, mockRepo, _ := setupTestVerificationService()
+
+		tok, err := service.signer.Sign(emailVerificationPurpose, "1:999:test@example.com", time.Hour)
+		require.NoError(t, err)
+
+		mockRepo.On("GetByID", mock.Anything, uint(999)).Return(nil, errors.New("user not found"))
+
+		_, err = service.VerifyEmail(context.Background(), tok)
+
+		assert.ErrorIs(t, err, ErrInvalidVerificationToken)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestEmailVerificationService_ResendVerification(t *testing.T) {
+	recently := time.Now().Add(-time.Minute)
+	longAgo := time.Now().Add(-time.Hour)
+	verifiedAt := time.Now()
+
+	tests := []struct {
+		name          string
+		user          *entity.User
+		expectedError error
+		expectSent    bool
+	}{
+		{
+			name:       "Sends when never sent",
+			user:       &entity.User{ID: 1, Email: "test@example.com"},
+			expectSent: true,
+		},
+		{
+			name:       "Sends after cooldown",
+			user:       &entity.User{ID: 1, Email: "test@example.com", VerificationSentAt: &longAgo},
+			expectSent: true,
+		},
+		{
+			name:          "Rate limited within cooldown",
+			user:          &entity.User{ID: 1, Email: "test@example.com", VerificationSentAt: &recently},
+			expectedError: &VerificationRateLimitedError{},
+		},
+		{
+			name:          "Already verified",
+			user:          &entity.User{ID: 1, Email: "test@example.com", EmailVerifiedAt: &verifiedAt},
+			expectedError: ErrEmailAlreadyVerified,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockRepo, mail := setupTestVerificationService()
+
+			mockRepo.On("GetByID", mock.Anything, uint(1)).Return(tt.user, nil)
+			if tt.expectSent {
+				mockRepo.On("Update", mock.Anything, tt.user).Return(nil)
+			}
+
+			err := service.ResendVerification(context.Background(), 1)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.IsType(t, tt.expectedError, err)
+				assert.Empty(t, mail.Messages())
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, mail.Messages(), 1)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_CreateUserSendsVerification(t *testing.T) {
+	mockRepo := &MockUserRepository{}
+	mockVerifier := &MockEmailVerificationService{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	service := NewUserService(mockRepo, logger, WithEmailVerification(mockVerifier))
+
+	mockRepo.On("EmailExists", mock.Anything, "test@example.com", uint(0)).Return(false, nil)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil)
+	mockVerifier.On("SendVerification", mock.Anything, mock.AnythingOfType("*entity.User")).Return(errors.New("smtp down"))
+
+	user, err := service.CreateUser(context.Background(), entity.CreateUserRequest{
+		Name:        "Test User",
+		Email:       "test@example.com",
+		DateOfBirth: "1990-01-01",
+	})
+
+	// A mailer failure must not fail the creation itself
+	assert.NoError(t, err)
+	assert.Equal(t, entity.UserStatusPending, user.Status)
+	mockRepo.AssertExpectations(t)
+	mockVerifier.AssertExpectations(t)
+}
+
+func TestUserService_UpdateUserEmailResetsVerification(t *testing.T) {
+	mockRepo := &MockUserRepository{}
+	mockVerifier := &MockEmailVerificationService{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	service := NewUserService(mockRepo, logger, WithEmailVerification(mockVerifier))
+
+	verifiedAt := time.Now()
+	mockRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{
+		ID:              1,
+		Email:           "old@example.com",
+		DateOfBirth:     time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		EmailVerifiedAt: &verifiedAt,
+	}, nil)
+	mockRepo.On("EmailExists", mock.Anything, "new@example.com", uint(1)).Return(false, nil)
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil)
+	mockVerifier.On("SendVerification", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil)
+
+	email := "new@example.com"
+	user, err := service.UpdateUser(context.Background(), 1, entity.UpdateUserRequest{Email: &email})
+
+	assert.NoError(t, err)
+	assert.Nil(t, user.EmailVerifiedAt)
+	mockRepo.AssertExpectations(t)
+	mockVerifier.AssertExpectations(t)
+}
+
+// MockEmailVerificationService is a mock implementation of the EmailVerificationService interface
+type MockEmailVerificationService struct {
+	mock.Mock
+}
+
+func (m *MockEmailVerificationService) SendVerification(ctx context.Context, user *entity.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationService) VerifyEmail(ctx context.Context, token string) (*entity.User, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockEmailVerificationService) ResendVerification(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
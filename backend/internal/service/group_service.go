@@ -0,0 +1,33 @@
+package service
+
+import (
+	"strings"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"github.com/sirupsen/logrus"
+)
+
+// GroupService manages groups, which collect users into described cohorts
+type GroupService = LabelService[entity.Group, entity.CreateGroupRequest, entity.UpdateGroupRequest]
+
+func NewGroupService(groupRepo repository.GroupRepository, logger *logrus.Logger) GroupService {
+	return newLabelService(groupRepo, labelKind[entity.Group, entity.CreateGroupRequest, entity.UpdateGroupRequest]{
+		name: "group",
+		build: func(req entity.CreateGroupRequest) *entity.Group {
+			return &entity.Group{
+				Name:        strings.TrimSpace(req.Name),
+				Description: strings.TrimSpace(req.Description),
+			}
+		},
+		apply: func(group *entity.Group, req entity.UpdateGroupRequest) {
+			if req.Name != nil {
+				group.Name = strings.TrimSpace(*req.Name)
+			}
+			if req.Description != nil {
+				group.Description = strings.TrimSpace(*req.Description)
+			}
+		},
+		id: func(group *entity.Group) uint { return group.ID },
+	}, logger)
+}
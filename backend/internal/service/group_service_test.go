@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"arritech-user-management/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupTestGroupService() (GroupService, *MockLabelRepository[entity.Group]) {
+	mockRepo := &MockLabelRepository[entity.Group]{}
+	return NewGroupService(mockRepo, testLogger()), mockRepo
+}
+
+func TestGroupService_Create(t *testing.T) {
+	service, mockRepo := setupTestGroupService()
+
+	mockRepo.On("Create", mock.Anything, &entity.Group{Name: "cohort-a", Description: "Spring intake"}).Return(nil)
+
+	group, err := service.Create(context.Background(), entity.CreateGroupRequest{Name: "cohort-a ", Description: " Spring intake"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "cohort-a", group.Name)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGroupService_Update(t *testing.T) {
+	service, mockRepo := setupTestGroupService()
+
+	mockRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Group{ID: 1, Name: "cohort-a", Description: "Spring intake"}, nil)
+	mockRepo.On("Update", mock.Anything, &entity.Group{ID: 1, Name: "cohort-a", Description: "Autumn intake"}).Return(nil)
+
+	description := " Autumn intake "
+	group, err := service.Update(context.Background(), 1, entity.UpdateGroupRequest{Description: &description})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Autumn intake", group.Description)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"arritech-user-management/pkg/idempotency"
+	"github.com/sirupsen/logrus"
+)
+
+// IdempotencyConfig holds idempotency key settings
+type IdempotencyConfig struct {
+	// TTL is how long a key and its response are kept
+	TTL time.Duration
+	// LockTimeout frees keys of requests that never finished, e.g. because the server crashed
+	LockTimeout time.Duration
+}
+
+// IdempotencyService stores responses to requests made with an Idempotency-Key header
+type IdempotencyService interface {
+	idempotency.Store
+
+	// PurgeExpired removes expired keys and returns how many were removed
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type idempotencyService struct {
+	repo   repository.IdempotencyKeyRepository
+	config IdempotencyConfig
+	logger *logrus.Logger
+	now    func() time.Time
+}
+
+func NewIdempotencyService(repo repository.IdempotencyKeyRepository, config IdempotencyConfig, logger *logrus.Logger) IdempotencyService {
+	return &idempotencyService{
+		repo:   repo,
+		config: config,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+func (s *idempotencyService) Reserve(ctx context.Context, key, fingerprint string) (*idempotency.Record, error) {
+	now := s.now()
+
+	// A second attempt follows freeing a stale key; if another request claims it first, that one wins
+	for attempt := 0; attempt < 2; attempt++ {
+		err := s.repo.Create(ctx, &entity.IdempotencyKey{
+			Key:         key,
+			Fingerprint: fingerprint,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(s.config.TTL),
+		})
+		if err == nil {
+			return nil, nil
+		}
+		if err.Error() != "idempotency key already exists" {
+			return nil, err
+		}
+
+		existing, err := s.repo.GetByKey(ctx, key)
+		if err != nil {
+			if err.Error() == "idempotency key not found" {
+				continue
+			}
+			return nil, err
+		}
+
+		abandoned := existing.StatusCode == 0 && now.Sub(existing.CreatedAt) > s.config.LockTimeout
+		if !now.Before(existing.ExpiresAt) || abandoned {
+			s.logger.WithField("abandoned", abandoned).Info("Freeing stale idempotency key")
+			if err := s.repo.Delete(ctx, key); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		record := &idempotency.Record{Fingerprint: existing.Fingerprint}
+		if existing.StatusCode != 0 {
+			record.Response = &idempotency.Response{
+				StatusCode:  existing.StatusCode,
+				ContentType: existing.ContentType,
+				Body:        existing.Body,
+			}
+		}
+		return record, nil
+	}
+	return nil, fmt.Errorf("failed to reserve idempotency key")
+}
+
+func (s *idempotencyService) Complete(ctx context.Context, key string, response idempotency.Response) error {
+	return s.repo.Complete(ctx, key, response.StatusCode, response.ContentType, response.Body)
+}
+
+func (s *idempotencyService) Release(ctx context.Context, key string) error {
+	return s.repo.Delete(ctx, key)
+}
+
+func (s *idempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	removed, err := s.repo.DeleteExpired(ctx, s.now())
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to purge expired idempotency keys")
+		return 0, err
+	}
+	if removed > 0 {
+		s.logger.WithField("removed", removed).Info("Purged expired idempotency keys")
+	}
+	return removed, nil
+}
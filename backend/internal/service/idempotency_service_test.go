@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/idempotency"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockIdempotencyKeyRepository is a mock implementation of the IdempotencyKeyRepository interface
+type MockIdempotencyKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyKeyRepository) Create(ctx context.Context, key *entity.IdempotencyKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyKeyRepository) GetByKey(ctx context.Context, key string) (*entity.IdempotencyKey, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.IdempotencyKey), args.Error(1)
+}
+
+func (m *MockIdempotencyKeyRepository) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	args := m.Called(ctx, key, statusCode, contentType, body)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyKeyRepository) Delete(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyKeyRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+var idempotencyTestNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func setupIdempotencyService() (*idempotencyService, *MockIdempotencyKeyRepository) {
+	mockRepo := &MockIdempotencyKeyRepository{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	service := NewIdempotencyService(mockRepo, IdempotencyConfig{TTL: 24 * time.Hour, LockTimeout: time.Minute}, logger).(*idempotencyService)
+	service.now = func() time.Time { return idempotencyTestNow }
+	return service, mockRepo
+}
+
+func TestIdempotencyService_Reserve(t *testing.T) {
+	keyExists := errors.New("idempotency key already exists")
+
+	t.Run("Claims a free key", func(t *testing.T) {
+		service, mockRepo := setupIdempotencyService()
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(key *entity.IdempotencyKey) bool {
+			return key.Key == "k1" && key.Fingerprint == "fp" && key.ExpiresAt.Equal(idempotencyTestNow.Add(24*time.Hour))
+		})).Return(nil)
+
+		record, err := service.Reserve(context.Background(), "k1", "fp")
+
+		require.NoError(t, err)
+		assert.Nil(t, record)
+	})
+
+	t.Run("Returns the stored response", func(t *testing.T) {
+		service, mockRepo := setupIdempotencyService()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(keyExists)
+		mockRepo.On("GetByKey", mock.Anything, "k1").Return(&entity.IdempotencyKey{
+			Key: "k1", Fingerprint: "fp", StatusCode: 201, ContentType: "application/json", Body: []byte(`{}`),
+			CreatedAt: idempotencyTestNow.Add(-time.Hour), ExpiresAt: idempotencyTestNow.Add(time.Hour),
+		}, nil)
+
+		record, err := service.Reserve(context.Background(), "k1", "fp")
+
+		require.NoError(t, err)
+		assert.Equal(t, &idempotency.Record{
+			Fingerprint: "fp",
+			Response:    &idempotency.Response{StatusCode: 201, ContentType: "application/json", Body: []byte(`{}`)},
+		}, record)
+	})
+
+	t.Run("Reports a request in progress", func(t *testing.T) {
+		service, mockRepo := setupIdempotencyService()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(keyExists)
+		mockRepo.On("GetByKey", mock.Anything, "k1").Return(&entity.IdempotencyKey{
+			Key: "k1", Fingerprint: "fp", CreatedAt: idempotencyTestNow.Add(-time.Second), ExpiresAt: idempotencyTestNow.Add(time.Hour),
+		}, nil)
+
+		record, err := service.Reserve(context.Background(), "k1", "fp")
+
+		require.NoError(t, err)
+		assert.Equal(t, &idempotency.Record{Fingerprint: "fp"}, record)
+	})
+
+	t.Run("Frees expired and abandoned keys", func(t *testing.T) {
+		stale := []*entity.IdempotencyKey{
+			{Key: "k1", Fingerprint: "old", StatusCode: 201, CreatedAt: idempotencyTestNow.Add(-25 * time.Hour), ExpiresAt: idempotencyTestNow.Add(-time.Hour)},
+			{Key: "k1", Fingerprint: "old", CreatedAt: idempotencyTestNow.Add(-2 * time.Minute), ExpiresAt: idempotencyTestNow.Add(time.Hour)},
+		}
+		for _, existing := range stale {
+			service, mockRepo := setupIdempotencyService()
+			mockRepo.On("Create", mock.Anything, mock.Anything).Return(keyExists).Once()
+			mockRepo.On("GetByKey", mock.Anything, "k1").Return(existing, nil)
+			mockRepo.On("Delete", mock.Anything, "k1").Return(nil)
+			mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+			record, err := service.Reserve(context.Background(), "k1", "fp")
+
+			require.NoError(t, err)
+			assert.Nil(t, record)
+			mockRepo.AssertExpectations(t)
+		}
+	})
+
+	t.Run("Fails on repository errors", func(t *testing.T) {
+		service, mockRepo := setupIdempotencyService()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("connection refused"))
+
+		_, err := service.Reserve(context.Background(), "k1", "fp")
+
+		assert.EqualError(t, err, "connection refused")
+	})
+}
+
+func TestIdempotencyService_CompleteAndRelease(t *testing.T) {
+	service, mockRepo := setupIdempotencyService()
+	mockRepo.On("Complete", mock.Anything, "k1", 201, "application/json", []byte(`{}`)).Return(nil)
+	mockRepo.On("Delete", mock.Anything, "k2").Return(nil)
+
+	assert.NoError(t, service.Complete(context.Background(), "k1", idempotency.Response{StatusCode: 201, ContentType: "application/json", Body: []byte(`{}`)}))
+	assert.NoError(t, service.Release(context.Background(), "k2"))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIdempotencyService_PurgeExpired(t *testing.T) {
+	service, mockRepo := setupIdempotencyService()
+	mockRepo.On("DeleteExpired", mock.Anything, idempotencyTestNow).Return(int64(4), nil)
+
+	removed, err := service.PurgeExpired(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), removed)
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"arritech-user-management/internal/domain/repository"
+	"github.com/sirupsen/logrus"
+)
+
+// LabelService manages labels that collect users, such as tags and groups, with C and U the
+// requests that create and update a label of type T
+type LabelService[T, C, U any] interface {
+	Create(ctx context.Context, req C) (*T, error)
+	Get(ctx context.Context, id uint) (*T, error)
+	List(ctx context.Context) ([]T, error)
+	Update(ctx context.Context, id uint, req U) (*T, error)
+	Delete(ctx context.Context, id uint) error
+	AddUsers(ctx context.Context, id uint, userIDs []uint) (int64, error)
+	RemoveUsers(ctx context.Context, id uint, userIDs []uint) (int64, error)
+}
+
+// labelKind holds what differs between kinds of labels
+type labelKind[T, C, U any] struct {
+	// name is the singular name of the label used in logs, e.g. "tag"
+	name string
+	// build makes a label from a create request
+	build func(req C) *T
+	// apply changes a label by an update request
+	apply func(label *T, req U)
+	// id returns the ID of a label
+	id func(label *T) uint
+}
+
+type labelService[T, C, U any] struct {
+	repo   repository.LabelRepository[T]
+	kind   labelKind[T, C, U]
+	logger *logrus.Logger
+}
+
+func newLabelService[T, C, U any](repo repository.LabelRepository[T], kind labelKind[T, C, U], logger *logrus.Logger) LabelService[T, C, U] {
+	return &labelService[T, C, U]{
+		repo:   repo,
+		kind:   kind,
+		logger: logger,
+	}
+}
+
+// idField is the log field of the label's ID, e.g. tag_id
+func (s *labelService[T, C, U]) idField() string {
+	return s.kind.name + "_id"
+}
+
+// title is the label's name for the start of log messages, e.g. Tag
+func (s *labelService[T, C, U]) title() string {
+	return strings.ToUpper(s.kind.name[:1]) + s.kind.name[1:]
+}
+
+func (s *labelService[T, C, U]) Create(ctx context.Context, req C) (*T, error) {
+	label := s.kind.build(req)
+	s.logger.Info("Creating " + s.kind.name)
+
+	if err := s.repo.Create(ctx, label); err != nil {
+		s.logger.WithError(err).Error("Failed to create " + s.kind.name)
+		return nil, err
+	}
+
+	s.logger.WithField(s.idField(), s.kind.id(label)).Info(s.title() + " created successfully")
+	return label, nil
+}
+
+func (s *labelService[T, C, U]) Get(ctx context.Context, id uint) (*T, error) {
+	label, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.WithError(err).WithField(s.idField(), id).Error("Failed to get " + s.kind.name)
+		return nil, err
+	}
+	return label, nil
+}
+
+func (s *labelService[T, C, U]) List(ctx context.Context) ([]T, error) {
+	labels, err := s.repo.List(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list " + s.kind.name + "s")
+		return nil, err
+	}
+	return labels, nil
+}
+
+func (s *labelService[T, C, U]) Update(ctx context.Context, id uint, req U) (*T, error) {
+	s.logger.WithField(s.idField(), id).Info("Updating " + s.kind.name)
+
+	label, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.kind.apply(label, req)
+
+	if err := s.repo.Update(ctx, label); err != nil {
+		s.logger.WithError(err).WithField(s.idField(), id).Error("Failed to update " + s.kind.name)
+		return nil, err
+	}
+
+	s.logger.WithField(s.idField(), id).Info(s.title() + " updated successfully")
+	return label, nil
+}
+
+func (s *labelService[T, C, U]) Delete(ctx context.Context, id uint) error {
+	s.logger.WithField(s.idField(), id).Info("Deleting " + s.kind.name)
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		s.logger.WithError(err).WithField(s.idField(), id).Error("Failed to delete " + s.kind.name)
+		return err
+	}
+
+	s.logger.WithField(s.idField(), id).Info(s.title() + " deleted successfully")
+	return nil
+}
+
+func (s *labelService[T, C, U]) AddUsers(ctx context.Context, id uint, userIDs []uint) (int64, error) {
+	return s.changeMembership(ctx, id, userIDs, s.repo.AddUsers, "added to")
+}
+
+func (s *labelService[T, C, U]) RemoveUsers(ctx context.Context, id uint, userIDs []uint) (int64, error) {
+	return s.changeMembership(ctx, id, userIDs, s.repo.RemoveUsers, "removed from")
+}
+
+// changeMembership checks that the label exists before adding or removing users, so an unknown
+// label is reported rather than changing nothing
+func (s *labelService[T, C, U]) changeMembership(ctx context.Context, id uint, userIDs []uint, change func(ctx context.Context, id uint, userIDs []uint) (int64, error), verb string) (int64, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return 0, err
+	}
+
+	affected, err := change(ctx, id, userIDs)
+	if err != nil {
+		s.logger.WithError(err).WithField(s.idField(), id).Errorf("Failed to change %s membership", s.kind.name)
+		return 0, err
+	}
+
+	s.logger.WithFields(logrus.Fields{s.idField(): id, "affected": affected}).Infof("Users %s %s successfully", verb, s.kind.name)
+	return affected, nil
+}
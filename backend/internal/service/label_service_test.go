@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"arritech-user-management/internal/domain/entity"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLabelRepository is a mock implementation of the LabelRepository interface
+type MockLabelRepository[T any] struct {
+	mock.Mock
+}
+
+func (m *MockLabelRepository[T]) Create(ctx context.Context, label *T) error {
+	args := m.Called(ctx, label)
+	return args.Error(0)
+}
+
+func (m *MockLabelRepository[T]) GetByID(ctx context.Context, id uint) (*T, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*T), args.Error(1)
+}
+
+func (m *MockLabelRepository[T]) List(ctx context.Context) ([]T, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]T), args.Error(1)
+}
+
+func (m *MockLabelRepository[T]) Update(ctx context.Context, label *T) error {
+	args := m.Called(ctx, label)
+	return args.Error(0)
+}
+
+func (m *MockLabelRepository[T]) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockLabelRepository[T]) AddUsers(ctx context.Context, id uint, userIDs []uint) (int64, error) {
+	args := m.Called(ctx, id, userIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockLabelRepository[T]) RemoveUsers(ctx context.Context, id uint, userIDs []uint) (int64, error) {
+	args := m.Called(ctx, id, userIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func testLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	return logger
+}
+
+// The shared behavior of labels is tested through tags
+func setupTestTagService() (TagService, *MockLabelRepository[entity.Tag]) {
+	mockRepo := &MockLabelRepository[entity.Tag]{}
+	return NewTagService(mockRepo, testLogger()), mockRepo
+}
+
+func TestLabelService_Update(t *testing.T) {
+	service, mockRepo := setupTestTagService()
+
+	mockRepo.On("GetByID", mock.Anything, uint(9)).Return(nil, errors.New("tag not found"))
+
+	name := "premium"
+	_, err := service.Update(context.Background(), 9, entity.UpdateTagRequest{Name: &name})
+
+	assert.EqualError(t, err, "tag not found")
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestLabelService_Delete(t *testing.T) {
+	service, mockRepo := setupTestTagService()
+
+	mockRepo.On("Delete", mock.Anything, uint(7)).Return(errors.New("tag not found"))
+
+	err := service.Delete(context.Background(), 7)
+
+	assert.EqualError(t, err, "tag not found")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLabelService_AddUsers(t *testing.T) {
+	t.Run("Adds users to an existing label", func(t *testing.T) {
+		service, mockRepo := setupTestTagService()
+
+		mockRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Tag{ID: 1, Name: "vip"}, nil)
+		mockRepo.On("AddUsers", mock.Anything, uint(1), []uint{1, 2, 3}).Return(int64(2), nil)
+
+		affected, err := service.AddUsers(context.Background(), 1, []uint{1, 2, 3})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), affected)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown label", func(t *testing.T) {
+		service, mockRepo := setupTestTagService()
+
+		mockRepo.On("GetByID", mock.Anything, uint(9)).Return(nil, errors.New("tag not found"))
+
+		_, err := service.AddUsers(context.Background(), 9, []uint{1})
+
+		assert.EqualError(t, err, "tag not found")
+		mockRepo.AssertNotCalled(t, "AddUsers", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestLabelService_RemoveUsers(t *testing.T) {
+	service, mockRepo := setupTestTagService()
+
+	mockRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Tag{ID: 1, Name: "vip"}, nil)
+	mockRepo.On("RemoveUsers", mock.Anything, uint(1), []uint{4}).Return(int64(1), nil)
+
+	affected, err := service.RemoveUsers(context.Background(), 1, []uint{4})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+	mockRepo.AssertExpectations(t)
+}
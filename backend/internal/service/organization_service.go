@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/tenant"
+	"github.com/sirupsen/logrus"
+)
+
+// organizationSlugPattern keeps slugs usable in headers and URLs and distinct from numeric IDs
+var organizationSlugPattern = regexp.MustCompile(`^[a-z][a-z0-9-]{1,63}$`)
+
+var (
+	// ErrInvalidOrganization is returned when organization settings are malformed
+	ErrInvalidOrganization = errors.New("invalid organization")
+
+	// ErrOrganizationNotFound is returned when an organization doesn't exist or the caller can't see it
+	ErrOrganizationNotFound = repository.ErrOrganizationNotFound
+)
+
+type OrganizationService interface {
+	CreateOrganization(ctx context.Context, req entity.CreateOrganizationRequest) (*entity.Organization, error)
+	GetOrganization(ctx context.Context, id uint) (*entity.Organization, error)
+	// ListOrganizations returns every organization to platform admins and the caller's own one
+	// to everyone else
+	ListOrganizations(ctx context.Context) ([]entity.Organization, error)
+	UpdateOrganization(ctx context.Context, id uint, req entity.UpdateOrganizationRequest) (*entity.Organization, error)
+	// ResolveOrganization looks up an organization by numeric ID or slug
+	ResolveOrganization(ctx context.Context, ref string) (*entity.Organization, error)
+	// CurrentOrganization returns the organization carried by ctx
+	CurrentOrganization(ctx context.Context) (*entity.Organization, error)
+}
+
+type organizationService struct {
+	orgRepo    repository.OrganizationRepository
+	authorizer Authorizer
+	logger     *logrus.Logger
+}
+
+// OrganizationServiceOption configures optional collaborators of the organization service
+type OrganizationServiceOption func(*organizationService)
+
+// WithOrganizationAuthorization requires the organizations:manage permission to create and
+// change organizations, like WithAuthorization does for users, and limits callers to the
+// organization carried by the context unless they have the platform:admin permission. Creating
+// organizations needs platform:admin as well.
+func WithOrganizationAuthorization(authorizer Authorizer) OrganizationServiceOption {
+	return func(s *organizationService) {
+		s.authorizer = authorizer
+	}
+}
+
+func NewOrganizationService(orgRepo repository.OrganizationRepository, logger *logrus.Logger, opts ...OrganizationServiceOption) OrganizationService {
+	s := &organizationService{
+		orgRepo: orgRepo,
+		logger:  logger,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *organizationService) authorize(ctx context.Context, permission string) error {
+	if s.authorizer == nil {
+		return nil
+	}
+	if err := s.authorizer.Authorize(ctx, permission); err != nil {
+		s.logger.WithError(err).WithField("permission", permission).Warn("Organization operation denied")
+		return err
+	}
+	return nil
+}
+
+func (s *organizationService) CreateOrganization(ctx context.Context, req entity.CreateOrganizationRequest) (*entity.Organization, error) {
+	if err := s.authorize(ctx, auth.PermissionOrganizationsManage); err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, auth.PermissionPlatformAdmin); err != nil {
+		return nil, err
+	}
+	s.logger.WithField("slug", req.Slug).Info("Creating organization")
+
+	if !organizationSlugPattern.MatchString(req.Slug) {
+		return nil, fmt.Errorf("%w: slug must start with a lowercase letter and contain only lowercase letters, digits and hyphens", ErrInvalidOrganization)
+	}
+
+	org := &entity.Organization{
+		Slug:                req.Slug,
+		Name:                strings.TrimSpace(req.Name),
+		MinimumAge:          req.MinimumAge,
+		AllowedEmailDomains: normalizeDomains(req.AllowedEmailDomains),
+	}
+
+	if err := s.orgRepo.Create(ctx, org); err != nil {
+		s.logger.WithError(err).Error("Failed to create organization")
+		return nil, err
+	}
+
+	s.logger.WithField("organization_id", org.ID).Info("Organization created successfully")
+	return org, nil
+}
+
+func (s *organizationService) GetOrganization(ctx context.Context, id uint) (*entity.Organization, error) {
+	if !s.canAccess(ctx, id) {
+		return nil, ErrOrganizationNotFound
+	}
+	org, err := s.orgRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.WithError(err).WithField("organization_id", id).Error("Failed to get organization")
+		return nil, err
+	}
+	return org, nil
+}
+
+func (s *organizationService) ListOrganizations(ctx context.Context) ([]entity.Organization, error) {
+	if !s.isPlatformAdmin(ctx) {
+		org, err := s.CurrentOrganization(ctx)
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to list organizations")
+			return nil, err
+		}
+		return []entity.Organization{*org}, nil
+	}
+	orgs, err := s.orgRepo.List(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list organizations")
+		return nil, err
+	}
+	return orgs, nil
+}
+
+func (s *organizationService) UpdateOrganization(ctx context.Context, id uint, req entity.UpdateOrganizationRequest) (*entity.Organization, error) {
+	if err := s.authorize(ctx, auth.PermissionOrganizationsManage); err != nil {
+		return nil, err
+	}
+	s.logger.WithField("organization_id", id).Info("Updating organization")
+
+	if !s.canAccess(ctx, id) {
+		return nil, ErrOrganizationNotFound
+	}
+	org, err := s.orgRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		org.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.MinimumAge != nil {
+		org.MinimumAge = *req.MinimumAge
+	}
+	if req.AllowedEmailDomains != nil {
+		org.AllowedEmailDomains = normalizeDomains(*req.AllowedEmailDomains)
+	}
+
+	if err := s.orgRepo.Update(ctx, org); err != nil {
+		s.logger.WithError(err).WithField("organization_id", id).Error("Failed to update organization")
+		return nil, err
+	}
+
+	s.logger.WithField("organization_id", id).Info("Organization updated successfully")
+	return org, nil
+}
+
+func (s *organizationService) ResolveOrganization(ctx context.Context, ref string) (*entity.Organization, error) {
+	if id, err := strconv.ParseUint(ref, 10, 32); err == nil {
+		return s.orgRepo.GetByID(ctx, uint(id))
+	}
+	return s.orgRepo.GetBySlug(ctx, strings.ToLower(ref))
+}
+
+func (s *organizationService) CurrentOrganization(ctx context.Context) (*entity.Organization, error) {
+	id, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("organization is required")
+	}
+	return s.orgRepo.GetByID(ctx, id)
+}
+
+// isPlatformAdmin reports whether the caller may act in every organization, which is the case
+// for all callers without authorization
+func (s *organizationService) isPlatformAdmin(ctx context.Context) bool {
+	return s.authorizer == nil || s.authorizer.Authorize(ctx, auth.PermissionPlatformAdmin) == nil
+}
+
+// canAccess reports whether the caller may see the organization: its own one, or any for
+// platform admins. Other organizations are reported as not found rather than forbidden, so
+// their IDs don't leak.
+func (s *organizationService) canAccess(ctx context.Context, id uint) bool {
+	if s.isPlatformAdmin(ctx) {
+		return true
+	}
+	current, ok := tenant.FromContext(ctx)
+	return ok && current == id
+}
+
+// normalizeDomains lowercases and de-duplicates email domains, keeping nil for "no restriction"
+func normalizeDomains(domains []string) entity.StringList {
+	seen := make(map[string]bool)
+	var result entity.StringList
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain == "" || seen[domain] {
+			continue
+		}
+		seen[domain] = true
+		result = append(result, domain)
+	}
+	return result
+}
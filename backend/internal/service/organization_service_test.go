@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/tenant"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOrganizationRepository is a mock implementation of the OrganizationRepository interface
+type MockOrganizationRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrganizationRepository) Create(ctx context.Context, org *entity.Organization) error {
+	args := m.Called(ctx, org)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) GetByID(ctx context.Context, id uint) (*entity.Organization, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) GetBySlug(ctx context.Context, slug string) (*entity.Organization, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) List(ctx context.Context) ([]entity.Organization, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) Update(ctx context.Context, org *entity.Organization) error {
+	args := m.Called(ctx, org)
+	return args.Error(0)
+}
+
+func setupTestOrganizationService() (OrganizationService, *MockOrganizationRepository) {
+	mockRepo := &MockOrganizationRepository{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	return NewOrganizationService(mockRepo, logger), mockRepo
+}
+
+func TestOrganizationService_CreateOrganization(t *testing.T) {
+	t.Run("Normalizes email domains", func(t *testing.T) {
+		service, mockRepo := setupTestOrganizationService()
+
+		mockRepo.On("Create", mock.Anything, &entity.Organization{
+			Slug:                "acme",
+			Name:                "Acme Corp",
+			MinimumAge:          21,
+			AllowedEmailDomains: entity.StringList{"acme.com", "acme.io"},
+		}).Return(nil)
+
+		org, err := service.CreateOrganization(context.Background(), entity.CreateOrganizationRequest{
+			Slug:                "acme",
+			Name:                " Acme Corp ",
+			MinimumAge:          21,
+			AllowedEmailDomains: []string{"ACME.com", "acme.io", "acme.com "},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Acme Corp", org.Name)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects invalid slug", func(t *testing.T) {
+		service, mockRepo := setupTestOrganizationService()
+
+		_, err := service.CreateOrganization(context.Background(), entity.CreateOrganizationRequest{Slug: "42", Name: "Numbers"})
+
+		assert.ErrorIs(t, err, ErrInvalidOrganization)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestOrganizationService_Authorization(t *testing.T) {
+	mockRepo := &MockOrganizationRepository{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	service := NewOrganizationService(mockRepo, logger, WithOrganizationAuthorization(auth.DefaultPolicy()))
+
+	editor := auth.NewContext(context.Background(), &auth.Claims{Subject: "lead", Roles: []string{auth.RoleEditor}})
+	name := "Renamed"
+
+	_, err := service.CreateOrganization(editor, entity.CreateOrganizationRequest{Slug: "acme", Name: "Acme"})
+	assert.EqualError(t, err, "missing permission organizations:manage")
+	_, err = service.UpdateOrganization(editor, 2, entity.UpdateOrganizationRequest{Name: &name})
+	assert.EqualError(t, err, "missing permission organizations:manage")
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+
+	// Creating organizations is left to platform admins
+	admin := auth.NewContext(context.Background(), &auth.Claims{Subject: "root", Roles: []string{auth.RoleAdmin}})
+	_, err = service.CreateOrganization(admin, entity.CreateOrganizationRequest{Slug: "acme", Name: "Acme"})
+	assert.EqualError(t, err, "missing permission platform:admin")
+
+	platformAdmin := auth.NewContext(context.Background(), &auth.Claims{Subject: "ops", Roles: []string{auth.RolePlatformAdmin}})
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Organization")).Return(nil)
+
+	_, err = service.CreateOrganization(platformAdmin, entity.CreateOrganizationRequest{Slug: "acme", Name: "Acme"})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestOrganizationService_Access(t *testing.T) {
+	mockRepo := &MockOrganizationRepository{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	service := NewOrganizationService(mockRepo, logger, WithOrganizationAuthorization(auth.DefaultPolicy()))
+
+	mockRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Organization{ID: 2, Slug: "acme"}, nil)
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Organization")).Return(nil)
+	mockRepo.On("List", mock.Anything).Return([]entity.Organization{{ID: 1, Slug: "default"}, {ID: 2, Slug: "acme"}}, nil)
+	name := "Acme"
+
+	t.Run("Members see only their own organization", func(t *testing.T) {
+		ctx := tenant.NewContext(auth.NewContext(context.Background(), &auth.Claims{Subject: "jane", Roles: []string{auth.RoleAdmin}}), 2)
+
+		orgs, err := service.ListOrganizations(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.Organization{{ID: 2, Slug: "acme"}}, orgs)
+
+		org, err := service.GetOrganization(ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, "acme", org.Slug)
+		_, err = service.UpdateOrganization(ctx, 2, entity.UpdateOrganizationRequest{Name: &name})
+		assert.NoError(t, err)
+
+		_, err = service.GetOrganization(ctx, 1)
+		assert.ErrorIs(t, err, ErrOrganizationNotFound)
+		_, err = service.UpdateOrganization(ctx, 1, entity.UpdateOrganizationRequest{Name: &name})
+		assert.ErrorIs(t, err, ErrOrganizationNotFound)
+		mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, uint(1))
+		mockRepo.AssertNotCalled(t, "List", mock.Anything)
+	})
+
+	t.Run("Platform admins see every organization", func(t *testing.T) {
+		ctx := tenant.NewContext(auth.NewContext(context.Background(), &auth.Claims{Subject: "ops", Roles: []string{auth.RolePlatformAdmin}}), 1)
+
+		orgs, err := service.ListOrganizations(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, orgs, 2)
+
+		org, err := service.GetOrganization(ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(2), org.ID)
+	})
+}
+
+func TestOrganizationService_ResolveOrganization(t *testing.T) {
+	service, mockRepo := setupTestOrganizationService()
+
+	mockRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Organization{ID: 2, Slug: "acme"}, nil)
+	mockRepo.On("GetBySlug", mock.Anything, "acme").Return(&entity.Organization{ID: 2, Slug: "acme"}, nil)
+	mockRepo.On("GetBySlug", mock.Anything, "missing").Return(nil, ErrOrganizationNotFound)
+
+	byID, err := service.ResolveOrganization(context.Background(), "2")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), byID.ID)
+
+	bySlug, err := service.ResolveOrganization(context.Background(), "ACME")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), bySlug.ID)
+
+	_, err = service.ResolveOrganization(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrOrganizationNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestOrganizationService_CurrentOrganization(t *testing.T) {
+	service, mockRepo := setupTestOrganizationService()
+
+	_, err := service.CurrentOrganization(context.Background())
+	assert.EqualError(t, err, "organization is required")
+
+	mockRepo.On("GetByID", mock.Anything, uint(3)).Return(&entity.Organization{ID: 3}, nil)
+	org, err := service.CurrentOrganization(tenant.NewContext(context.Background(), 3))
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), org.ID)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/auth"
+)
+
+// PIIRedactor masks the personal data of users returned to callers without the users:pii permission.
+// A nil *PIIRedactor masks nothing.
+type PIIRedactor struct {
+	authorizer Authorizer
+}
+
+func NewPIIRedactor(authorizer Authorizer) *PIIRedactor {
+	return &PIIRedactor{authorizer: authorizer}
+}
+
+// CanSeePII reports whether the caller in ctx may see personal data
+func (r *PIIRedactor) CanSeePII(ctx context.Context) bool {
+	return r == nil || r.authorizer.Authorize(ctx, auth.PermissionUsersPII) == nil
+}
+
+// Redact masks the personal data of users unless the caller may see it. Computed fields such as
+// Age must be set before.
+func (r *PIIRedactor) Redact(ctx context.Context, users ...*entity.User) {
+	if r.CanSeePII(ctx) {
+		return
+	}
+	for _, user := range users {
+		user.RedactPII()
+	}
+}
+
+// WithPIIRedaction masks personal data in the users returned to callers without the users:pii
+// permission and keeps those callers from searching or sorting on it
+func WithPIIRedaction(redactor *PIIRedactor) UserServiceOption {
+	return func(s *userService) {
+		s.redactor = redactor
+	}
+}
+
+// dropMaskedValues removes the fields of an update that repeat the masked values a caller without
+// users:pii was shown, so writing back a fetched user doesn't replace personal data with masks
+func dropMaskedValues(current *entity.User, req *entity.UpdateUserRequest) {
+	masked := *current
+	masked.RedactPII()
+
+	if req.Email != nil && strings.ToLower(strings.TrimSpace(*req.Email)) == masked.Email {
+		req.Email = nil
+	}
+	if req.DateOfBirth != nil && *req.DateOfBirth == masked.DateOfBirth.Format(entity.RedactedDateLayout) {
+		req.DateOfBirth = nil
+	}
+	if req.Phone != nil && *req.Phone == masked.Phone {
+		req.Phone = nil
+	}
+	if req.Address != nil && *req.Address == masked.Address {
+		req.Address = nil
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/auth"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func piiTestUser() *entity.User {
+	return &entity.User{
+		ID:          1,
+		Name:        "Jane Doe",
+		Email:       "jane@example.com",
+		DateOfBirth: time.Date(1990, 6, 15, 0, 0, 0, 0, time.UTC),
+		Phone:       "+1 555 123 4567",
+		Address:     "1 Main Street",
+	}
+}
+
+func TestPIIRedactor_Redact(t *testing.T) {
+	redactor := NewPIIRedactor(auth.DefaultPolicy())
+	viewer := auth.NewContext(context.Background(), &auth.Claims{Subject: "agent", Roles: []string{auth.RoleViewer}})
+	editor := auth.NewContext(context.Background(), &auth.Claims{Subject: "lead", Roles: []string{auth.RoleEditor}})
+	key := auth.NewContext(context.Background(), &auth.Claims{APIKeyID: 1, Scopes: []string{auth.PermissionUsersRead, auth.PermissionUsersPII}})
+
+	t.Run("Masks personal data for callers without users:pii", func(t *testing.T) {
+		user := piiTestUser()
+		user.Age = 35
+
+		redactor.Redact(viewer, user)
+
+		assert.True(t, user.PIIRedacted)
+		assert.Equal(t, "***@example.com", user.Email)
+		assert.Equal(t, "+* *** *** **67", user.Phone)
+		assert.Equal(t, "***", user.Address)
+		assert.Equal(t, 1990, user.DateOfBirth.Year())
+		assert.Equal(t, 35, user.Age)
+		assert.Equal(t, "Jane Doe", user.Name)
+	})
+
+	t.Run("Keeps personal data for callers with users:pii", func(t *testing.T) {
+		for _, ctx := range []context.Context{editor, key} {
+			user := piiTestUser()
+			redactor.Redact(ctx, user)
+			assert.Equal(t, piiTestUser(), user)
+		}
+	})
+
+	t.Run("Masks data for anonymous callers", func(t *testing.T) {
+		user := piiTestUser()
+		redactor.Redact(context.Background(), user)
+		assert.True(t, user.PIIRedacted)
+	})
+
+	t.Run("A nil redactor masks nothing", func(t *testing.T) {
+		var none *PIIRedactor
+		user := piiTestUser()
+		none.Redact(viewer, user)
+		assert.True(t, none.CanSeePII(viewer))
+		assert.Equal(t, piiTestUser(), user)
+	})
+}
+
+func TestUserService_PIIRedaction(t *testing.T) {
+	viewer := auth.NewContext(context.Background(), &auth.Claims{Subject: "agent", Roles: []string{auth.RoleViewer}})
+	writer := auth.NewContext(context.Background(), &auth.Claims{APIKeyID: 1, Scopes: []string{auth.PermissionUsersRead, auth.PermissionUsersWrite}})
+
+	t.Run("GetUser masks personal data but keeps the age", func(t *testing.T) {
+		service, mockRepo := setupTestService()
+		service.redactor = NewPIIRedactor(auth.DefaultPolicy())
+		mockRepo.On("GetByID", mock.Anything, uint(1)).Return(piiTestUser(), nil)
+
+		user, err := service.GetUser(viewer, 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, "***", user.Address)
+		assert.Equal(t, piiTestUser().CalculateAge(), user.Age)
+	})
+
+	t.Run("ListUsers searches names only and masks results", func(t *testing.T) {
+		service, mockRepo := setupTestService()
+		service.redactor = NewPIIRedactor(auth.DefaultPolicy())
+		mockRepo.On("List", mock.Anything, mock.MatchedBy(func(params entity.UserSearchParams) bool {
+			return params.SearchNameOnly && params.Search == "jane@"
+		})).Return(&entity.UserListResponse{Users: []entity.User{*piiTestUser()}, Total: 1}, nil)
+
+		result, err := service.ListUsers(viewer, entity.UserSearchParams{Search: "jane@", Page: 1, PerPage: 10})
+
+		require.NoError(t, err)
+		assert.Equal(t, "***@example.com", result.Users[0].Email)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ListUsers rejects sorting on masked fields", func(t *testing.T) {
+		service, mockRepo := setupTestService()
+		service.redactor = NewPIIRedactor(auth.DefaultPolicy())
+
+		for _, sortBy := range []string{"email", "phone"} {
+			_, err := service.ListUsers(viewer, entity.UserSearchParams{SortBy: sortBy, Page: 1, PerPage: 10})
+
+			var permErr *auth.PermissionError
+			require.True(t, errors.As(err, &permErr))
+			assert.Equal(t, auth.PermissionUsersPII, permErr.Permission)
+		}
+		mockRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+	})
+
+	t.Run("UpdateUser ignores masked values sent back unchanged", func(t *testing.T) {
+		service, mockRepo := setupTestService()
+		service.redactor = NewPIIRedactor(auth.DefaultPolicy())
+		mockRepo.On("GetByID", mock.Anything, uint(1)).Return(piiTestUser(), nil)
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+			return user.Name == "Jane Roe" && user.Email == "jane@example.com" && user.Phone == "+1 555 123 4567" &&
+				user.Address == "1 Main Street" && user.DateOfBirth.Equal(piiTestUser().DateOfBirth)
+		})).Return(nil)
+
+		name, email, dob, phone, address := "Jane Roe", "***@example.com", "1990-**-**", "+* *** *** **67", "***"
+		user, err := service.UpdateUser(writer, 1, entity.UpdateUserRequest{
+			Name: &name, Email: &email, DateOfBirth: &dob, Phone: &phone, Address: &address,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Jane Roe", user.Name)
+		assert.True(t, user.PIIRedacted)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("PatchUser tests against masked values", func(t *testing.T) {
+		service, mockRepo := setupTestService()
+		service.redactor = NewPIIRedactor(auth.DefaultPolicy())
+		mockRepo.On("GetByID", mock.Anything, uint(1)).Return(piiTestUser(), nil)
+
+		_, err := patchRequestOf(writer, service, JSONPatch, `[{"op":"test","path":"/address","value":"1 Main Street"}]`)
+		assert.ErrorIs(t, err, ErrPatchTestFailed)
+
+		req, err := patchRequestOf(writer, service, MergePatch, `{"name":"Jane Roe"}`)
+		require.NoError(t, err)
+		assert.Equal(t, entity.UpdateUserRequest{Name: req.Name}, req)
+	})
+}
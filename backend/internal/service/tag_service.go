@@ -0,0 +1,33 @@
+package service
+
+import (
+	"strings"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"github.com/sirupsen/logrus"
+)
+
+// TagService manages tags, which label users with a name and color
+type TagService = LabelService[entity.Tag, entity.CreateTagRequest, entity.UpdateTagRequest]
+
+func NewTagService(tagRepo repository.TagRepository, logger *logrus.Logger) TagService {
+	return newLabelService(tagRepo, labelKind[entity.Tag, entity.CreateTagRequest, entity.UpdateTagRequest]{
+		name: "tag",
+		build: func(req entity.CreateTagRequest) *entity.Tag {
+			return &entity.Tag{
+				Name:  strings.TrimSpace(req.Name),
+				Color: strings.ToLower(req.Color),
+			}
+		},
+		apply: func(tag *entity.Tag, req entity.UpdateTagRequest) {
+			if req.Name != nil {
+				tag.Name = strings.TrimSpace(*req.Name)
+			}
+			if req.Color != nil {
+				tag.Color = strings.ToLower(*req.Color)
+			}
+		},
+		id: func(tag *entity.Tag) uint { return tag.ID },
+	}, logger)
+}
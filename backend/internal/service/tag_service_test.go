@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"arritech-user-management/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTagService_Create(t *testing.T) {
+	service, mockRepo := setupTestTagService()
+
+	mockRepo.On("Create", mock.Anything, &entity.Tag{Name: "vip", Color: "#ff00aa"}).Return(nil)
+
+	tag, err := service.Create(context.Background(), entity.CreateTagRequest{Name: " vip ", Color: "#FF00AA"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "vip", tag.Name)
+	assert.Equal(t, "#ff00aa", tag.Color)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTagService_Update(t *testing.T) {
+	service, mockRepo := setupTestTagService()
+
+	mockRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Tag{ID: 1, Name: "vip", Color: "#ff0000"}, nil)
+	mockRepo.On("Update", mock.Anything, &entity.Tag{ID: 1, Name: "premium", Color: "#ff0000"}).Return(nil)
+
+	name := "premium"
+	tag, err := service.Update(context.Background(), 1, entity.UpdateTagRequest{Name: &name})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "premium", tag.Name)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"arritech-user-management/pkg/auth"
+	"github.com/sirupsen/logrus"
+)
+
+// WithActivityLog records lifecycle events of users for their activity timeline
+func WithActivityLog(events repository.UserEventRepository) UserServiceOption {
+	return func(s *userService) {
+		s.events = events
+	}
+}
+
+// recordEvent stores a lifecycle event when the activity log is enabled.
+// Failures are logged rather than returned: the change itself has already been saved.
+func (s *userService) recordEvent(ctx context.Context, userID uint, eventType entity.UserEventType, description string) {
+	if s.events == nil {
+		return
+	}
+	event := &entity.UserEvent{UserID: userID, Type: eventType, Description: description}
+	if claims, ok := auth.FromContext(ctx); ok {
+		event.Actor = claims.Subject
+	}
+	if err := s.events.Create(ctx, event); err != nil {
+		s.log(ctx).WithError(err).WithFields(logrus.Fields{
+			"user_id": userID,
+			"type":    eventType,
+		}).Warn("Failed to record user event")
+	}
+}
+
+// changedFields lists the profile fields that differ between two versions of a user
+func changedFields(before, after *entity.User) []string {
+	var fields []string
+	if before.Name != after.Name {
+		fields = append(fields, "name")
+	}
+	if before.Email != after.Email {
+		fields = append(fields, "email")
+	}
+	if !before.DateOfBirth.Equal(after.DateOfBirth) {
+		fields = append(fields, "date_of_birth")
+	}
+	if before.Phone != after.Phone {
+		fields = append(fields, "phone")
+	}
+	if before.Address != after.Address {
+		fields = append(fields, "address")
+	}
+	if !reflect.DeepEqual(before.Attributes, after.Attributes) {
+		fields = append(fields, "attributes")
+	}
+	return fields
+}
+
+// updateDescription describes a profile update for the activity timeline
+func updateDescription(fields []string) string {
+	return fmt.Sprintf("Updated %s", strings.Join(fields, ", "))
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/auth"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupActivityService() (*userService, *MockUserRepository, *MockUserEventRepository) {
+	service, mockRepo := setupTestService()
+	events := &MockUserEventRepository{}
+	service.events = events
+	return service, mockRepo, events
+}
+
+func eventOfType(eventType entity.UserEventType, description string) interface{} {
+	return mock.MatchedBy(func(event *entity.UserEvent) bool {
+		return event.Type == eventType && event.Description == description
+	})
+}
+
+func TestUserService_RecordsCreatedEvent(t *testing.T) {
+	service, mockRepo, events := setupActivityService()
+	mockRepo.On("EmailExists", mock.Anything, "test@example.com", uint(0)).Return(false, nil)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil)
+	events.On("Create", mock.Anything, eventOfType(entity.UserEventCreated, "User created")).Return(nil)
+
+	_, err := service.CreateUser(context.Background(), entity.CreateUserRequest{
+		Name:        "Test User",
+		Email:       "test@example.com",
+		DateOfBirth: "1990-01-01",
+	})
+
+	assert.NoError(t, err)
+	events.AssertExpectations(t)
+}
+
+func TestUserService_RecordsEventActor(t *testing.T) {
+	service, mockRepo, events := setupActivityService()
+	user := &entity.User{ID: 1, Status: entity.UserStatusActive}
+	mockRepo.On("GetByID", mock.Anything, uint(1)).Return(user, nil)
+	mockRepo.On("Update", mock.Anything, user).Return(nil)
+	events.On("Create", mock.Anything, mock.MatchedBy(func(event *entity.UserEvent) bool {
+		return event.Actor == "agent@example.com"
+	})).Return(nil)
+
+	ctx := auth.NewContext(context.Background(), &auth.Claims{Subject: "agent@example.com"})
+	_, err := service.ChangeUserStatus(ctx, 1, entity.UserStatusSuspended, "chargeback")
+
+	assert.NoError(t, err)
+	events.AssertExpectations(t)
+}
+
+func TestUserService_RecordsUpdatedFields(t *testing.T) {
+	service, mockRepo, events := setupActivityService()
+	user := &entity.User{ID: 1, Name: "Old", Email: "a@example.com", Phone: "1234567890", DateOfBirth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)}
+	mockRepo.On("GetByID", mock.Anything, uint(1)).Return(user, nil)
+	mockRepo.On("Update", mock.Anything, user).Return(nil)
+	events.On("Create", mock.Anything, eventOfType(entity.UserEventUpdated, "Updated name, address")).Return(nil)
+
+	name, phone, address := "New", "1234567890", "Main Street"
+	_, err := service.UpdateUser(context.Background(), 1, entity.UpdateUserRequest{Name: &name, Phone: &phone, Address: &address})
+
+	assert.NoError(t, err)
+	events.AssertExpectations(t)
+}
+
+func TestUserService_SkipsEventWithoutChanges(t *testing.T) {
+	service, mockRepo, events := setupActivityService()
+	user := &entity.User{ID: 1, Name: "Same"}
+	mockRepo.On("GetByID", mock.Anything, uint(1)).Return(user, nil)
+	mockRepo.On("Update", mock.Anything, user).Return(nil)
+
+	name := "Same"
+	_, err := service.UpdateUser(context.Background(), 1, entity.UpdateUserRequest{Name: &name})
+
+	assert.NoError(t, err)
+	events.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUserService_RecordsStatusAndDeleteEvents(t *testing.T) {
+	service, mockRepo, events := setupActivityService()
+	user := &entity.User{ID: 1, Status: entity.UserStatusActive}
+	mockRepo.On("GetByID", mock.Anything, uint(1)).Return(user, nil)
+	mockRepo.On("Update", mock.Anything, user).Return(nil)
+	mockRepo.On("Delete", mock.Anything, uint(1)).Return(nil)
+	events.On("Create", mock.Anything, eventOfType(entity.UserEventStatusChanged, "Status changed from active to suspended: chargeback")).Return(nil)
+	events.On("Create", mock.Anything, eventOfType(entity.UserEventDeleted, "User deleted")).Return(errors.New("database error"))
+
+	_, err := service.ChangeUserStatus(context.Background(), 1, entity.UserStatusSuspended, "chargeback")
+	assert.NoError(t, err)
+
+	// A failure to record the event does not fail the deletion
+	err = service.DeleteUser(context.Background(), 1)
+	assert.NoError(t, err)
+
+	events.AssertExpectations(t)
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Authorizer decides whether the caller carried by a context may perform an operation
+type Authorizer interface {
+	// Authorize returns a *auth.PermissionError when the caller lacks permission
+	Authorize(ctx context.Context, permission string) error
+}
+
+// WithAuthorization checks the caller's permission on every user service operation, so callers
+// other than the HTTP handlers are held to the same role policy. Contexts without a caller are denied.
+func WithAuthorization(authorizer Authorizer) UserServiceOption {
+	return func(s *userService) {
+		s.authorizer = authorizer
+	}
+}
+
+func (s *userService) authorize(ctx context.Context, permission string) error {
+	if s.authorizer == nil {
+		return nil
+	}
+	if err := s.authorizer.Authorize(ctx, permission); err != nil {
+		s.log(ctx).WithError(err).WithFields(logrus.Fields{"permission": permission}).Warn("User operation denied")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/auth"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUserService_Authorization(t *testing.T) {
+	service, mockRepo := setupTestService()
+	service.authorizer = auth.DefaultPolicy()
+
+	viewer := auth.NewContext(context.Background(), &auth.Claims{Subject: "intern", Roles: []string{auth.RoleViewer}})
+	mockRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1, Name: "Jane"}, nil)
+
+	user, err := service.GetUser(viewer, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane", user.Name)
+
+	name := "John"
+	denied := []struct {
+		name       string
+		permission string
+		call       func(ctx context.Context) error
+	}{
+		{"CreateUser", auth.PermissionUsersWrite, func(ctx context.Context) error {
+			_, err := service.CreateUser(ctx, entity.CreateUserRequest{Name: "John"})
+			return err
+		}},
+		{"UpdateUser", auth.PermissionUsersWrite, func(ctx context.Context) error {
+			_, err := service.UpdateUser(ctx, 1, entity.UpdateUserRequest{Name: &name})
+			return err
+		}},
+		{"PatchUser", auth.PermissionUsersWrite, func(ctx context.Context) error {
+			_, err := service.PatchUser(ctx, 1, MergePatch, []byte(`{}`), func(entity.UpdateUserRequest) error { return nil })
+			return err
+		}},
+		{"ChangeUserStatus", auth.PermissionUsersWrite, func(ctx context.Context) error {
+			_, err := service.ChangeUserStatus(ctx, 1, entity.UserStatusSuspended, "test")
+			return err
+		}},
+		{"DeleteUser", auth.PermissionUsersDelete, func(ctx context.Context) error {
+			return service.DeleteUser(ctx, 1)
+		}},
+	}
+
+	for _, tt := range denied {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(viewer)
+
+			var permErr *auth.PermissionError
+			assert.True(t, errors.As(err, &permErr))
+			assert.Equal(t, tt.permission, permErr.Permission)
+		})
+	}
+
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestUserService_AuthorizationDeniesAnonymousCallers(t *testing.T) {
+	service, mockRepo := setupTestService()
+	service.authorizer = auth.DefaultPolicy()
+
+	_, err := service.ListUsers(context.Background(), entity.UserSearchParams{Page: 1, PerPage: 10})
+
+	assert.EqualError(t, err, "missing permission users:read")
+	mockRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+}
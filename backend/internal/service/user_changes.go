@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/tenant"
+)
+
+// UserChange reports that a user was created, updated, changed status or was deleted. It
+// carries no user data, so watchers load the user themselves with their own permissions.
+type UserChange struct {
+	Type           entity.UserEventType
+	UserID         uint
+	OrganizationID uint
+	OccurredAt     time.Time
+}
+
+// UserChangeFeed fans out the changes made through a user service wrapped by
+// NewPublishingUserService, and by the avatar and email verification services given the feed,
+// to the watchers of the changed user's organization. Changes are only seen by watchers in the
+// same process.
+type UserChangeFeed struct {
+	mu       sync.Mutex
+	watchers map[*userWatcher]struct{}
+	buffer   int
+}
+
+type userWatcher struct {
+	organizationID uint
+	changes        chan UserChange
+}
+
+// NewUserChangeFeed creates a feed that buffers up to buffer changes per watcher
+func NewUserChangeFeed(buffer int) *UserChangeFeed {
+	return &UserChangeFeed{watchers: make(map[*userWatcher]struct{}), buffer: buffer}
+}
+
+// Watch returns the changes to users of the organization carried by ctx until ctx is done, when
+// the channel is closed. The channel is also closed, with ctx still active, when the watcher
+// falls more than the buffer size behind.
+func (f *UserChangeFeed) Watch(ctx context.Context) <-chan UserChange {
+	organizationID, _ := tenant.FromContext(ctx)
+	w := &userWatcher{organizationID: organizationID, changes: make(chan UserChange, f.buffer)}
+
+	f.mu.Lock()
+	f.watchers[w] = struct{}{}
+	f.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		f.remove(w)
+	}()
+	return w.changes
+}
+
+// Publish delivers a change to the watchers of its organization without blocking
+func (f *UserChangeFeed) Publish(change UserChange) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for w := range f.watchers {
+		if w.organizationID != change.OrganizationID {
+			continue
+		}
+		select {
+		case w.changes <- change:
+		default:
+			// A missed change can't be told apart from no change, so drop the watcher instead
+			delete(f.watchers, w)
+			close(w.changes)
+		}
+	}
+}
+
+// publishUser reports a change to a user in the organization the repository scoped the operation
+// to. A nil feed drops the change, so services publish whether or not they were given one.
+func (f *UserChangeFeed) publishUser(ctx context.Context, changeType entity.UserEventType, id uint) {
+	if f == nil {
+		return
+	}
+	organizationID, _ := tenant.FromContext(ctx)
+	f.Publish(UserChange{
+		Type:           changeType,
+		UserID:         id,
+		OrganizationID: organizationID,
+		OccurredAt:     time.Now(),
+	})
+}
+
+func (f *UserChangeFeed) remove(w *userWatcher) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.watchers[w]; ok {
+		delete(f.watchers, w)
+		close(w.changes)
+	}
+}
+
+// publishingUserService publishes the successful changes made through the wrapped service
+type publishingUserService struct {
+	next UserService
+	feed *UserChangeFeed
+}
+
+// NewPublishingUserService wraps a user service so that every change is published to feed
+func NewPublishingUserService(next UserService, feed *UserChangeFeed) UserService {
+	return &publishingUserService{next: next, feed: feed}
+}
+
+func (s *publishingUserService) CreateUser(ctx context.Context, req entity.CreateUserRequest) (*entity.User, error) {
+	user, err := s.next.CreateUser(ctx, req)
+	if err == nil {
+		s.publish(ctx, entity.UserEventCreated, user.ID)
+	}
+	return user, err
+}
+
+func (s *publishingUserService) GetUser(ctx context.Context, id uint) (*entity.User, error) {
+	return s.next.GetUser(ctx, id)
+}
+
+func (s *publishingUserService) GetUserProjected(ctx context.Context, id uint, projection entity.UserProjection) (*entity.User, error) {
+	return s.next.GetUserProjected(ctx, id, projection)
+}
+
+func (s *publishingUserService) UpdateUser(ctx context.Context, id uint, req entity.UpdateUserRequest) (*entity.User, error) {
+	user, err := s.next.UpdateUser(ctx, id, req)
+	if err == nil {
+		s.publish(ctx, entity.UserEventUpdated, id)
+	}
+	return user, err
+}
+
+func (s *publishingUserService) PatchUser(ctx context.Context, id uint, patchType PatchType, patch []byte, validate func(entity.UpdateUserRequest) error) (*entity.User, error) {
+	user, err := s.next.PatchUser(ctx, id, patchType, patch, validate)
+	if err == nil {
+		s.publish(ctx, entity.UserEventUpdated, id)
+	}
+	return user, err
+}
+
+func (s *publishingUserService) DeleteUser(ctx context.Context, id uint) error {
+	err := s.next.DeleteUser(ctx, id)
+	if err == nil {
+		s.publish(ctx, entity.UserEventDeleted, id)
+	}
+	return err
+}
+
+func (s *publishingUserService) ListUsers(ctx context.Context, params entity.UserSearchParams) (*entity.UserListResponse, error) {
+	return s.next.ListUsers(ctx, params)
+}
+
+func (s *publishingUserService) ChangeUserStatus(ctx context.Context, id uint, status entity.UserStatus, reason string) (*entity.User, error) {
+	user, err := s.next.ChangeUserStatus(ctx, id, status, reason)
+	if err == nil {
+		s.publish(ctx, entity.UserEventStatusChanged, id)
+	}
+	return user, err
+}
+
+func (s *publishingUserService) publish(ctx context.Context, changeType entity.UserEventType, id uint) {
+	s.feed.publishUser(ctx, changeType, id)
+}
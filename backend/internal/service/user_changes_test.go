@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/tenant"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserChangeFeed(t *testing.T) {
+	t.Run("Delivers changes to watchers of the same organization", func(t *testing.T) {
+		feed := NewUserChangeFeed(4)
+		ctx, cancel := context.WithCancel(tenant.NewContext(context.Background(), 1))
+		defer cancel()
+		changes := feed.Watch(ctx)
+		other := feed.Watch(tenant.NewContext(ctx, 2))
+
+		feed.Publish(UserChange{Type: entity.UserEventCreated, UserID: 7, OrganizationID: 1})
+
+		change := <-changes
+		assert.Equal(t, entity.UserEventCreated, change.Type)
+		assert.Equal(t, uint(7), change.UserID)
+		assert.Empty(t, other)
+	})
+
+	t.Run("Closes the channel when the context is done", func(t *testing.T) {
+		feed := NewUserChangeFeed(4)
+		ctx, cancel := context.WithCancel(tenant.NewContext(context.Background(), 1))
+		changes := feed.Watch(ctx)
+
+		cancel()
+
+		_, ok := <-changes
+		assert.False(t, ok)
+	})
+
+	t.Run("Drops watchers that fall behind", func(t *testing.T) {
+		feed := NewUserChangeFeed(1)
+		ctx, cancel := context.WithCancel(tenant.NewContext(context.Background(), 1))
+		defer cancel()
+		changes := feed.Watch(ctx)
+
+		feed.Publish(UserChange{Type: entity.UserEventUpdated, UserID: 1, OrganizationID: 1})
+		feed.Publish(UserChange{Type: entity.UserEventUpdated, UserID: 2, OrganizationID: 1})
+
+		<-changes
+		_, ok := <-changes
+		assert.False(t, ok)
+		assert.NoError(t, ctx.Err())
+	})
+}
+
+func TestPublishingUserService(t *testing.T) {
+	ctx := tenant.NewContext(context.Background(), 3)
+
+	t.Run("Publishes successful changes", func(t *testing.T) {
+		svc, mockRepo := setupTestService()
+		feed := NewUserChangeFeed(4)
+		watchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		changes := feed.Watch(watchCtx)
+		mockRepo.On("Delete", mock.Anything, uint(5)).Return(nil)
+
+		err := NewPublishingUserService(svc, feed).DeleteUser(ctx, 5)
+
+		require.NoError(t, err)
+		change := <-changes
+		assert.Equal(t, entity.UserEventDeleted, change.Type)
+		assert.Equal(t, uint(5), change.UserID)
+		assert.Equal(t, uint(3), change.OrganizationID)
+		assert.False(t, change.OccurredAt.IsZero())
+	})
+
+	t.Run("Doesn't publish failed changes", func(t *testing.T) {
+		svc, mockRepo := setupTestService()
+		feed := NewUserChangeFeed(4)
+		watchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		changes := feed.Watch(watchCtx)
+		mockRepo.On("Delete", mock.Anything, uint(5)).Return(errors.New("user not found"))
+
+		err := NewPublishingUserService(svc, feed).DeleteUser(ctx, 5)
+
+		assert.Error(t, err)
+		assert.Empty(t, changes)
+	})
+}
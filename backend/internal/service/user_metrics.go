@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/auth"
+)
+
+// UserMetrics counts user operations
+type UserMetrics interface {
+	// ObserveUserOperation counts an operation, e.g. "create", with its outcome, e.g. "email_taken"
+	ObserveUserOperation(operation, outcome string)
+}
+
+// Outcomes of failed user operations. Successful operations report their own outcome,
+// such as "created" or "deleted".
+const (
+	OutcomeRejectedUnderage    = "rejected_underage"
+	OutcomeRejectedEmailDomain = "rejected_email_domain"
+	OutcomeEmailTaken          = "email_taken"
+	OutcomeNotFound            = "not_found"
+	OutcomeDenied              = "denied"
+	OutcomeInvalid             = "invalid"
+	OutcomeInvalidTransition   = "invalid_transition"
+	OutcomeInvalidPatch        = "invalid_patch"
+	OutcomeError               = "error"
+)
+
+// meteredUserService counts the outcome of every call to the wrapped service
+type meteredUserService struct {
+	next    UserService
+	metrics UserMetrics
+}
+
+// NewMeteredUserService wraps a user service so that every operation is counted by outcome
+func NewMeteredUserService(next UserService, metrics UserMetrics) UserService {
+	return &meteredUserService{next: next, metrics: metrics}
+}
+
+func (s *meteredUserService) CreateUser(ctx context.Context, req entity.CreateUserRequest) (*entity.User, error) {
+	user, err := s.next.CreateUser(ctx, req)
+	s.observe("create", "created", err)
+	return user, err
+}
+
+func (s *meteredUserService) GetUser(ctx context.Context, id uint) (*entity.User, error) {
+	user, err := s.next.GetUser(ctx, id)
+	s.observe("get", "found", err)
+	return user, err
+}
+
+func (s *meteredUserService) GetUserProjected(ctx context.Context, id uint, projection entity.UserProjection) (*entity.User, error) {
+	user, err := s.next.GetUserProjected(ctx, id, projection)
+	s.observe("get", "found", err)
+	return user, err
+}
+
+func (s *meteredUserService) UpdateUser(ctx context.Context, id uint, req entity.UpdateUserRequest) (*entity.User, error) {
+	user, err := s.next.UpdateUser(ctx, id, req)
+	s.observe("update", "updated", err)
+	return user, err
+}
+
+func (s *meteredUserService) PatchUser(ctx context.Context, id uint, patchType PatchType, patch []byte, validate func(entity.UpdateUserRequest) error) (*entity.User, error) {
+	user, err := s.next.PatchUser(ctx, id, patchType, patch, validate)
+	s.observe("patch", "updated", err)
+	return user, err
+}
+
+func (s *meteredUserService) DeleteUser(ctx context.Context, id uint) error {
+	err := s.next.DeleteUser(ctx, id)
+	s.observe("delete", "deleted", err)
+	return err
+}
+
+func (s *meteredUserService) ListUsers(ctx context.Context, params entity.UserSearchParams) (*entity.UserListResponse, error) {
+	result, err := s.next.ListUsers(ctx, params)
+	s.observe("list", "listed", err)
+	return result, err
+}
+
+func (s *meteredUserService) ChangeUserStatus(ctx context.Context, id uint, status entity.UserStatus, reason string) (*entity.User, error) {
+	user, err := s.next.ChangeUserStatus(ctx, id, status, reason)
+	s.observe("change_status", "status_changed", err)
+	return user, err
+}
+
+func (s *meteredUserService) observe(operation, success string, err error) {
+	if err == nil {
+		s.metrics.ObserveUserOperation(operation, success)
+		return
+	}
+	s.metrics.ObserveUserOperation(operation, FailureOutcome(err))
+}
+
+// FailureOutcome classifies an error of the user service, for metrics and for the gRPC status codes
+func FailureOutcome(err error) string {
+	var policyErr *PolicyError
+	var permissionErr *auth.PermissionError
+	var attributeErr *AttributeValidationError
+	switch {
+	case errors.As(err, &policyErr):
+		if policyErr.Rule == PolicyMinimumAge {
+			return OutcomeRejectedUnderage
+		}
+		return OutcomeRejectedEmailDomain
+	case errors.As(err, &permissionErr):
+		return OutcomeDenied
+	case errors.As(err, &attributeErr):
+		return OutcomeInvalid
+	case errors.Is(err, ErrInvalidStatusTransition):
+		return OutcomeInvalidTransition
+	case errors.Is(err, ErrUnsupportedPatchType), errors.Is(err, ErrInvalidPatch),
+		errors.Is(err, ErrPatchTestFailed), errors.Is(err, ErrInvalidPatchResult), errors.Is(err, ErrUserModified):
+		return OutcomeInvalidPatch
+	}
+
+	switch msg := err.Error(); {
+	case msg == "email already exists":
+		return OutcomeEmailTaken
+	case msg == "user not found":
+		return OutcomeNotFound
+	case strings.HasPrefix(msg, "invalid date of birth format"), msg == "status change reason is required":
+		return OutcomeInvalid
+	}
+	return OutcomeError
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/auth"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserMetrics struct {
+	mock.Mock
+}
+
+func (m *MockUserMetrics) ObserveUserOperation(operation, outcome string) {
+	m.Called(operation, outcome)
+}
+
+func TestMeteredUserService(t *testing.T) {
+	t.Run("Counts successful operations", func(t *testing.T) {
+		service, mockRepo := setupTestService()
+		metrics := &MockUserMetrics{}
+		metrics.On("ObserveUserOperation", "get", "found").Once()
+		mockRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{
+			ID:          1,
+			DateOfBirth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		}, nil)
+
+		_, err := NewMeteredUserService(service, metrics).GetUser(context.Background(), 1)
+
+		assert.NoError(t, err)
+		metrics.AssertExpectations(t)
+	})
+
+	t.Run("Counts failures by outcome", func(t *testing.T) {
+		service, mockRepo := setupTestService()
+		metrics := &MockUserMetrics{}
+		metrics.On("ObserveUserOperation", "create", OutcomeEmailTaken).Once()
+		mockRepo.On("EmailExists", mock.Anything, "taken@example.com", uint(0)).Return(true, nil)
+
+		_, err := NewMeteredUserService(service, metrics).CreateUser(context.Background(), entity.CreateUserRequest{
+			Name:        "Taken",
+			Email:       "taken@example.com",
+			DateOfBirth: "1990-01-01",
+		})
+
+		assert.Error(t, err)
+		metrics.AssertExpectations(t)
+	})
+}
+
+func TestFailureOutcome(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "Underage", err: &PolicyError{Rule: PolicyMinimumAge, Message: "too young"}, expected: OutcomeRejectedUnderage},
+		{name: "Email domain", err: &PolicyError{Rule: PolicyEmailDomain, Message: "domain"}, expected: OutcomeRejectedEmailDomain},
+		{name: "Email taken", err: errors.New("email already exists"), expected: OutcomeEmailTaken},
+		{name: "Not found", err: errors.New("user not found"), expected: OutcomeNotFound},
+		{name: "Permission", err: &auth.PermissionError{Permission: auth.PermissionUsersWrite}, expected: OutcomeDenied},
+		{name: "Attributes", err: &AttributeValidationError{Fields: map[string]string{"level": "required"}}, expected: OutcomeInvalid},
+		{name: "Date of birth", err: fmt.Errorf("invalid date of birth format, use YYYY-MM-DD: %w", errors.New("bad")), expected: OutcomeInvalid},
+		{name: "Transition", err: fmt.Errorf("%w: cannot change status from active to pending", ErrInvalidStatusTransition), expected: OutcomeInvalidTransition},
+		{name: "Patch", err: fmt.Errorf("%w: bad pointer", ErrInvalidPatch), expected: OutcomeInvalidPatch},
+		{name: "Other", err: errors.New("connection refused"), expected: OutcomeError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FailureOutcome(tt.err))
+		})
+	}
+}
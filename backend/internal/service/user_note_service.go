@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultTimelineLimit = 20
+	maxTimelineLimit     = 100
+)
+
+type UserNoteService interface {
+	CreateNote(ctx context.Context, userID uint, req entity.CreateUserNoteRequest) (*entity.UserNote, error)
+	GetNote(ctx context.Context, userID, id uint) (*entity.UserNote, error)
+	ListNotes(ctx context.Context, userID uint) ([]entity.UserNote, error)
+	UpdateNote(ctx context.Context, userID, id uint, req entity.UpdateUserNoteRequest) (*entity.UserNote, error)
+	DeleteNote(ctx context.Context, userID, id uint) error
+	Timeline(ctx context.Context, userID uint, params entity.TimelineParams) (*entity.TimelineResponse, error)
+}
+
+type userNoteService struct {
+	noteRepo  repository.UserNoteRepository
+	eventRepo repository.UserEventRepository
+	userRepo  repository.UserRepository
+	logger    *logrus.Logger
+}
+
+func NewUserNoteService(noteRepo repository.UserNoteRepository, eventRepo repository.UserEventRepository, userRepo repository.UserRepository, logger *logrus.Logger) UserNoteService {
+	return &userNoteService{
+		noteRepo:  noteRepo,
+		eventRepo: eventRepo,
+		userRepo:  userRepo,
+		logger:    logger,
+	}
+}
+
+func (s *userNoteService) CreateNote(ctx context.Context, userID uint, req entity.CreateUserNoteRequest) (*entity.UserNote, error) {
+	s.logger.WithField("user_id", userID).Info("Creating user note")
+
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	note := &entity.UserNote{
+		UserID: userID,
+		Author: strings.TrimSpace(req.Author),
+		Body:   req.Body,
+		Pinned: req.Pinned,
+	}
+	if err := s.noteRepo.Create(ctx, note); err != nil {
+		s.logger.WithError(err).WithField("user_id", userID).Error("Failed to create user note")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{"user_id": userID, "note_id": note.ID}).Info("User note created successfully")
+	return note, nil
+}
+
+func (s *userNoteService) GetNote(ctx context.Context, userID, id uint) (*entity.UserNote, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.noteRepo.GetByID(ctx, userID, id)
+}
+
+func (s *userNoteService) ListNotes(ctx context.Context, userID uint) ([]entity.UserNote, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.noteRepo.ListByUser(ctx, userID)
+}
+
+func (s *userNoteService) UpdateNote(ctx context.Context, userID, id uint, req entity.UpdateUserNoteRequest) (*entity.UserNote, error) {
+	s.logger.WithFields(logrus.Fields{"user_id": userID, "note_id": id}).Info("Updating user note")
+
+	note, err := s.GetNote(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Body != nil {
+		note.Body = *req.Body
+	}
+	if req.Pinned != nil {
+		note.Pinned = *req.Pinned
+	}
+
+	if err := s.noteRepo.Update(ctx, note); err != nil {
+		s.logger.WithError(err).WithField("note_id", id).Error("Failed to update user note")
+		return nil, err
+	}
+	return note, nil
+}
+
+func (s *userNoteService) DeleteNote(ctx context.Context, userID, id uint) error {
+	s.logger.WithFields(logrus.Fields{"user_id": userID, "note_id": id}).Info("Deleting user note")
+
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return err
+	}
+	return s.noteRepo.Delete(ctx, userID, id)
+}
+
+// Timeline merges a user's notes and lifecycle events, newest first
+func (s *userNoteService) Timeline(ctx context.Context, userID uint, params entity.TimelineParams) (*entity.TimelineResponse, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultTimelineLimit
+	}
+	if limit > maxTimelineLimit {
+		limit = maxTimelineLimit
+	}
+
+	// Each source continues after the cursor in (time, id) order. Events of the cursor's time come
+	// before its notes, so after an event every note of that time is still to come, and after a
+	// note none of the events of that time are.
+	before := params.Before
+	var eventsBeforeID, notesBeforeID uint
+	if cursor := params.After; cursor != nil {
+		before = cursor.Time
+		if cursor.Kind == entity.TimelineEntryEvent {
+			eventsBeforeID, notesBeforeID = cursor.ID, math.MaxInt64
+		} else {
+			notesBeforeID = cursor.ID
+		}
+	}
+
+	// One extra entry from each source tells whether another page exists
+	events, err := s.eventRepo.ListBefore(ctx, userID, before, eventsBeforeID, limit+1)
+	if err != nil {
+		s.logger.WithError(err).WithField("user_id", userID).Error("Failed to list user events")
+		return nil, err
+	}
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		// A deleted user's timeline stays available through its recorded events
+		if err.Error() != "user not found" || len(events) == 0 {
+			return nil, err
+		}
+	}
+
+	notes, err := s.noteRepo.ListBefore(ctx, userID, before, notesBeforeID, limit+1)
+	if err != nil {
+		s.logger.WithError(err).WithField("user_id", userID).Error("Failed to list user notes")
+		return nil, err
+	}
+
+	entries := make([]entity.TimelineEntry, 0, len(notes)+len(events))
+	for i := range notes {
+		entries = append(entries, entity.TimelineEntry{Kind: entity.TimelineEntryNote, Time: notes[i].CreatedAt, Note: &notes[i]})
+	}
+	for i := range events {
+		entries = append(entries, entity.TimelineEntry{Kind: entity.TimelineEntryEvent, Time: events[i].CreatedAt, Event: &events[i]})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i].Cursor(), entries[j].Cursor()
+		if !a.Time.Equal(b.Time) {
+			return a.Time.After(b.Time)
+		}
+		if a.Kind != b.Kind {
+			return a.Kind == entity.TimelineEntryEvent
+		}
+		return a.ID > b.ID
+	})
+
+	response := &entity.TimelineResponse{Entries: entries}
+	if len(entries) > limit {
+		response.Entries = entries[:limit]
+		response.NextCursor = entries[limit-1].Cursor().String()
+	}
+	return response, nil
+}
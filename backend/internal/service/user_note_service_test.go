@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockUserNoteRepository is a mock implementation of the UserNoteRepository interface
+type MockUserNoteRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserNoteRepository) Create(ctx context.Context, note *entity.UserNote) error {
+	args := m.Called(ctx, note)
+	return args.Error(0)
+}
+
+func (m *MockUserNoteRepository) GetByID(ctx context.Context, userID, id uint) (*entity.UserNote, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.UserNote), args.Error(1)
+}
+
+func (m *MockUserNoteRepository) ListByUser(ctx context.Context, userID uint) ([]entity.UserNote, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.UserNote), args.Error(1)
+}
+
+func (m *MockUserNoteRepository) ListBefore(ctx context.Context, userID uint, before time.Time, beforeID uint, limit int) ([]entity.UserNote, error) {
+	args := m.Called(ctx, userID, before, beforeID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.UserNote), args.Error(1)
+}
+
+func (m *MockUserNoteRepository) Update(ctx context.Context, note *entity.UserNote) error {
+	args := m.Called(ctx, note)
+	return args.Error(0)
+}
+
+func (m *MockUserNoteRepository) Delete(ctx context.Context, userID, id uint) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+// MockUserEventRepository is a mock implementation of the UserEventRepository interface
+type MockUserEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserEventRepository) Create(ctx context.Context, event *entity.UserEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockUserEventRepository) ListBefore(ctx context.Context, userID uint, before time.Time, beforeID uint, limit int) ([]entity.UserEvent, error) {
+	args := m.Called(ctx, userID, before, beforeID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.UserEvent), args.Error(1)
+}
+
+func setupUserNoteService() (*userNoteService, *MockUserNoteRepository, *MockUserEventRepository, *MockUserRepository) {
+	noteRepo := &MockUserNoteRepository{}
+	eventRepo := &MockUserEventRepository{}
+	userRepo := &MockUserRepository{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	service := &userNoteService{
+		noteRepo:  noteRepo,
+		eventRepo: eventRepo,
+		userRepo:  userRepo,
+		logger:    logger,
+	}
+	return service, noteRepo, eventRepo, userRepo
+}
+
+func TestUserNoteService_CreateNote(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        uint
+		expectedError error
+		setupMock     func(*MockUserNoteRepository, *MockUserRepository)
+	}{
+		{
+			name:   "Successful creation",
+			userID: 1,
+			setupMock: func(noteRepo *MockUserNoteRepository, userRepo *MockUserRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				noteRepo.On("Create", mock.Anything, mock.MatchedBy(func(note *entity.UserNote) bool {
+					return note.UserID == 1 && note.Author == "alice" && note.Body == "**VIP**" && note.Pinned
+				})).Return(nil)
+			},
+		},
+		{
+			name:          "User not found",
+			userID:        999,
+			expectedError: errors.New("user not found"),
+			setupMock: func(noteRepo *MockUserNoteRepository, userRepo *MockUserRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(999)).Return(nil, errors.New("user not found"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, noteRepo, _, userRepo := setupUserNoteService()
+			tt.setupMock(noteRepo, userRepo)
+
+			req := entity.CreateUserNoteRequest{Author: " alice ", Body: "**VIP**", Pinned: true}
+			note, err := service.CreateNote(context.Background(), tt.userID, req)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				assert.Nil(t, note)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, note)
+			}
+			noteRepo.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserNoteService_UpdateNote(t *testing.T) {
+	service, noteRepo, _, userRepo := setupUserNoteService()
+	userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+	noteRepo.On("GetByID", mock.Anything, uint(1), uint(3)).Return(&entity.UserNote{ID: 3, UserID: 1, Body: "old"}, nil)
+	noteRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.UserNote")).Return(nil)
+
+	pinned := true
+	note, err := service.UpdateNote(context.Background(), 1, 3, entity.UpdateUserNoteRequest{Pinned: &pinned})
+
+	require.NoError(t, err)
+	assert.Equal(t, "old", note.Body)
+	assert.True(t, note.Pinned)
+}
+
+func TestUserNoteService_DeleteNote(t *testing.T) {
+	service, noteRepo, _, userRepo := setupUserNoteService()
+	userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+	noteRepo.On("Delete", mock.Anything, uint(1), uint(3)).Return(errors.New("note not found"))
+
+	err := service.DeleteNote(context.Background(), 1, 3)
+
+	assert.EqualError(t, err, "note not found")
+}
+
+func TestUserNoteService_Timeline(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	notes := []entity.UserNote{
+		{ID: 2, CreatedAt: base.Add(3 * time.Minute)},
+		{ID: 1, CreatedAt: base.Add(1 * time.Minute)},
+	}
+	events := []entity.UserEvent{
+		{ID: 3, Type: entity.UserEventUpdated, CreatedAt: base.Add(2 * time.Minute)},
+		{ID: 1, Type: entity.UserEventCreated, CreatedAt: base},
+	}
+
+	t.Run("Merges newest first with a cursor", func(t *testing.T) {
+		service, noteRepo, eventRepo, userRepo := setupUserNoteService()
+		userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+		eventRepo.On("ListBefore", mock.Anything, uint(1), time.Time{}, uint(0), 4).Return(events, nil)
+		noteRepo.On("ListBefore", mock.Anything, uint(1), time.Time{}, uint(0), 4).Return(notes, nil)
+
+		result, err := service.Timeline(context.Background(), 1, entity.TimelineParams{Limit: 3})
+
+		require.NoError(t, err)
+		require.Len(t, result.Entries, 3)
+		assert.Equal(t, entity.TimelineEntryNote, result.Entries[0].Kind)
+		assert.Equal(t, uint(2), result.Entries[0].Note.ID)
+		assert.Equal(t, entity.TimelineEntryEvent, result.Entries[1].Kind)
+		assert.Equal(t, entity.UserEventUpdated, result.Entries[1].Event.Type)
+		assert.Equal(t, uint(1), result.Entries[2].Note.ID)
+		assert.Equal(t, entity.TimelineCursor{Time: base.Add(time.Minute), Kind: entity.TimelineEntryNote, ID: 1}.String(), result.NextCursor)
+	})
+
+	t.Run("Last page has no cursor", func(t *testing.T) {
+		service, noteRepo, eventRepo, userRepo := setupUserNoteService()
+		userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+		eventRepo.On("ListBefore", mock.Anything, uint(1), base, uint(0), defaultTimelineLimit+1).Return(events[1:], nil)
+		noteRepo.On("ListBefore", mock.Anything, uint(1), base, uint(0), defaultTimelineLimit+1).Return([]entity.UserNote{}, nil)
+
+		result, err := service.Timeline(context.Background(), 1, entity.TimelineParams{Before: base})
+
+		require.NoError(t, err)
+		assert.Len(t, result.Entries, 1)
+		assert.Empty(t, result.NextCursor)
+	})
+
+	t.Run("Pages through entries sharing a timestamp", func(t *testing.T) {
+		// A note written with the user and bulk events share the time of the page boundary
+		tied := []entity.UserEvent{
+			{ID: 5, Type: entity.UserEventUpdated, CreatedAt: base},
+			{ID: 4, Type: entity.UserEventCreated, CreatedAt: base},
+			{ID: 1, Type: entity.UserEventCreated, CreatedAt: base.Add(-time.Minute)},
+		}
+		tiedNotes := []entity.UserNote{{ID: 7, CreatedAt: base}, {ID: 6, CreatedAt: base}}
+
+		service, noteRepo, eventRepo, userRepo := setupUserNoteService()
+		userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+		// Each call returns what the (created_at, id) < (before, beforeID) predicate selects
+		eventRepo.On("ListBefore", mock.Anything, uint(1), time.Time{}, uint(0), 3).Return(tied, nil)
+		noteRepo.On("ListBefore", mock.Anything, uint(1), time.Time{}, uint(0), 3).Return(tiedNotes, nil)
+		eventRepo.On("ListBefore", mock.Anything, uint(1), base, uint(4), 3).Return(tied[2:], nil)
+		noteRepo.On("ListBefore", mock.Anything, uint(1), base, uint(math.MaxInt64), 3).Return(tiedNotes, nil)
+		eventRepo.On("ListBefore", mock.Anything, uint(1), base, uint(0), 3).Return(tied[2:], nil)
+		noteRepo.On("ListBefore", mock.Anything, uint(1), base, uint(6), 3).Return([]entity.UserNote{}, nil)
+
+		var seen []string
+		params := entity.TimelineParams{Limit: 2}
+		for page := 0; page < 3; page++ {
+			result, err := service.Timeline(context.Background(), 1, params)
+			require.NoError(t, err)
+			for _, entry := range result.Entries {
+				cursor := entry.Cursor()
+				seen = append(seen, fmt.Sprintf("%s-%d", cursor.Kind, cursor.ID))
+			}
+			if result.NextCursor == "" {
+				break
+			}
+			after, err := entity.ParseTimelineCursor(result.NextCursor)
+			require.NoError(t, err)
+			params.After = &after
+		}
+
+		assert.Equal(t, []string{"event-5", "event-4", "note-7", "note-6", "event-1"}, seen)
+	})
+
+	t.Run("Deleted user with events", func(t *testing.T) {
+		service, noteRepo, eventRepo, userRepo := setupUserNoteService()
+		deleted := []entity.UserEvent{{ID: 4, Type: entity.UserEventDeleted, CreatedAt: base}}
+		userRepo.On("GetByID", mock.Anything, uint(1)).Return(nil, errors.New("user not found"))
+		eventRepo.On("ListBefore", mock.Anything, uint(1), time.Time{}, uint(0), maxTimelineLimit+1).Return(deleted, nil)
+		noteRepo.On("ListBefore", mock.Anything, uint(1), time.Time{}, uint(0), maxTimelineLimit+1).Return([]entity.UserNote{}, nil)
+
+		result, err := service.Timeline(context.Background(), 1, entity.TimelineParams{Limit: 500})
+
+		require.NoError(t, err)
+		assert.Len(t, result.Entries, 1)
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		service, _, eventRepo, userRepo := setupUserNoteService()
+		userRepo.On("GetByID", mock.Anything, uint(999)).Return(nil, errors.New("user not found"))
+		eventRepo.On("ListBefore", mock.Anything, uint(999), time.Time{}, uint(0), defaultTimelineLimit+1).Return([]entity.UserEvent{}, nil)
+
+		_, err := service.Timeline(context.Background(), 999, entity.TimelineParams{})
+
+		assert.EqualError(t, err, "user not found")
+	})
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/jsonpatch"
+)
+
+// PatchType identifies the format of a patch document by its media type
+type PatchType string
+
+const (
+	// MergePatch is a JSON Merge Patch (RFC 7396)
+	MergePatch PatchType = "application/merge-patch+json"
+	// JSONPatch is a JSON Patch (RFC 6902)
+	JSONPatch PatchType = "application/json-patch+json"
+)
+
+var (
+	// ErrUnsupportedPatchType is returned for patch formats other than MergePatch and JSONPatch
+	ErrUnsupportedPatchType = errors.New("unsupported patch type")
+
+	// ErrInvalidPatch is returned when a patch is malformed or cannot be applied to the user
+	ErrInvalidPatch = errors.New("invalid patch")
+
+	// ErrPatchTestFailed is returned when a JSON Patch test operation does not match the user
+	ErrPatchTestFailed = errors.New("patch test failed")
+
+	// ErrInvalidPatchResult is returned when the patched document is not a valid user
+	ErrInvalidPatchResult = errors.New("patched user is invalid")
+
+	// ErrUserModified is returned when the user changed between applying a patch and saving it
+	ErrUserModified = errors.New("user was modified while the patch was applied")
+)
+
+// userDocument is the editable view of a user that patches are applied to
+type userDocument struct {
+	Name        *string                `json:"name"`
+	Email       *string                `json:"email"`
+	DateOfBirth *string                `json:"date_of_birth"`
+	Phone       *string                `json:"phone"`
+	Address     *string                `json:"address"`
+	Attributes  map[string]interface{} `json:"attributes"`
+}
+
+func newUserDocument(user *entity.User) userDocument {
+	layout := "2006-01-02"
+	if user.PIIRedacted {
+		layout = entity.RedactedDateLayout
+	}
+	dateOfBirth := user.DateOfBirth.Format(layout)
+	attributes := map[string]interface{}(user.Attributes)
+	if attributes == nil {
+		attributes = map[string]interface{}{}
+	}
+	return userDocument{
+		Name:        &user.Name,
+		Email:       &user.Email,
+		DateOfBirth: &dateOfBirth,
+		Phone:       &user.Phone,
+		Address:     &user.Address,
+		Attributes:  attributes,
+	}
+}
+
+// PatchUser applies a patch to the current state of a user and saves the result through the same
+// validation and business rules as UpdateUser. The user is loaded once and only saved if it is
+// still unchanged, so test operations guard against concurrent updates.
+func (s *userService) PatchUser(ctx context.Context, id uint, patchType PatchType, patch []byte, validate func(entity.UpdateUserRequest) error) (*entity.User, error) {
+	if err := s.authorize(ctx, auth.PermissionUsersWrite); err != nil {
+		return nil, err
+	}
+
+	s.log(ctx).WithField("user_id", id).WithField("patch_type", patchType).Info("Applying patch to user")
+
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.buildPatchRequest(ctx, user, patchType, patch)
+	if err != nil {
+		return nil, err
+	}
+	if err := validate(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPatchResult, err)
+	}
+
+	loadedAt := user.UpdatedAt
+	return s.update(ctx, user, req, func(ctx context.Context, user *entity.User) error {
+		saved, err := s.userRepo.UpdateUnmodifiedSince(ctx, user, loadedAt)
+		if err == nil && !saved {
+			return ErrUserModified
+		}
+		return err
+	})
+}
+
+// buildPatchRequest applies a patch to user and returns the equivalent update request
+func (s *userService) buildPatchRequest(ctx context.Context, user *entity.User, patchType PatchType, patch []byte) (entity.UpdateUserRequest, error) {
+	// Callers who can't see personal data patch the masked user, so test operations can't probe it
+	view := *user
+	s.redactor.Redact(ctx, &view)
+	current := newUserDocument(&view)
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return entity.UpdateUserRequest{}, fmt.Errorf("failed to encode user: %w", err)
+	}
+
+	var patched []byte
+	switch patchType {
+	case MergePatch:
+		patched, err = jsonpatch.MergePatch(doc, patch)
+	case JSONPatch:
+		patched, err = jsonpatch.ApplyPatch(doc, patch)
+	default:
+		return entity.UpdateUserRequest{}, ErrUnsupportedPatchType
+	}
+	if errors.Is(err, jsonpatch.ErrTestFailed) {
+		return entity.UpdateUserRequest{}, fmt.Errorf("%w: %v", ErrPatchTestFailed, err)
+	}
+	if err != nil {
+		return entity.UpdateUserRequest{}, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	var result userDocument
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&result); err != nil {
+		return entity.UpdateUserRequest{}, fmt.Errorf("%w: %v", ErrInvalidPatchResult, err)
+	}
+
+	return patchRequest(current, result)
+}
+
+// patchRequest converts a patched document into an update request that only carries changed fields.
+// Required fields can't be removed; removing an optional field clears it.
+func patchRequest(current, patched userDocument) (entity.UpdateUserRequest, error) {
+	var req entity.UpdateUserRequest
+
+	required := []struct {
+		field   string
+		current *string
+		patched *string
+		target  **string
+	}{
+		{"name", current.Name, patched.Name, &req.Name},
+		{"email", current.Email, patched.Email, &req.Email},
+		{"date_of_birth", current.DateOfBirth, patched.DateOfBirth, &req.DateOfBirth},
+	}
+	for _, f := range required {
+		if f.patched == nil {
+			return entity.UpdateUserRequest{}, fmt.Errorf("%w: %s is required", ErrInvalidPatchResult, f.field)
+		}
+		if *f.patched != *f.current {
+			*f.target = f.patched
+		}
+	}
+
+	optional := []struct {
+		current *string
+		patched *string
+		target  **string
+	}{
+		{current.Phone, patched.Phone, &req.Phone},
+		{current.Address, patched.Address, &req.Address},
+	}
+	for _, f := range optional {
+		value := ""
+		if f.patched != nil {
+			value = *f.patched
+		}
+		if value != *f.current {
+			*f.target = &value
+		}
+	}
+
+	attributes := patched.Attributes
+	if attributes == nil {
+		attributes = map[string]interface{}{}
+	}
+	// Compare the encoded forms so numbers read from the database match decoded ones
+	before, _ := json.Marshal(current.Attributes)
+	after, _ := json.Marshal(attributes)
+	if !bytes.Equal(before, after) {
+		req.Attributes = attributes
+	}
+
+	return req, nil
+}
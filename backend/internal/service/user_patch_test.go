@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func patchTestUser() *entity.User {
+	return &entity.User{
+		ID:          1,
+		Name:        "Jane Doe",
+		Email:       "jane@example.com",
+		DateOfBirth: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+		Phone:       "1234567890",
+		Address:     "Main Street 1",
+		Attributes:  entity.Attributes{"department": "sales", "level": float64(3)},
+	}
+}
+
+// errCaptured stops PatchUser once it has handed the update request to validate
+var errCaptured = errors.New("captured")
+
+// patchRequestOf applies patch with PatchUser and returns the update request it amounts to,
+// without saving anything
+func patchRequestOf(ctx context.Context, service *userService, patchType PatchType, patch string) (entity.UpdateUserRequest, error) {
+	var req entity.UpdateUserRequest
+	_, err := service.PatchUser(ctx, 1, patchType, []byte(patch), func(r entity.UpdateUserRequest) error {
+		req = r
+		return errCaptured
+	})
+	if errors.Is(err, errCaptured) {
+		return req, nil
+	}
+	return entity.UpdateUserRequest{}, err
+}
+
+func TestUserService_PatchRequest(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+
+	tests := []struct {
+		name      string
+		patchType PatchType
+		patch     string
+		expected  entity.UpdateUserRequest
+	}{
+		{
+			name:      "merge patch changes a field",
+			patchType: MergePatch,
+			patch:     `{"name":"Jane Smith"}`,
+			expected:  entity.UpdateUserRequest{Name: strPtr("Jane Smith")},
+		},
+		{
+			name:      "merge patch null clears optional fields",
+			patchType: MergePatch,
+			patch:     `{"phone":null,"address":null}`,
+			expected:  entity.UpdateUserRequest{Phone: strPtr(""), Address: strPtr("")},
+		},
+		{
+			name:      "merge patch merges attributes",
+			patchType: MergePatch,
+			patch:     `{"attributes":{"level":null,"team":"emea"}}`,
+			expected:  entity.UpdateUserRequest{Attributes: map[string]interface{}{"department": "sales", "team": "emea"}},
+		},
+		{
+			name:      "merge patch null attributes clears them",
+			patchType: MergePatch,
+			patch:     `{"attributes":null}`,
+			expected:  entity.UpdateUserRequest{Attributes: map[string]interface{}{}},
+		},
+		{
+			name:      "unchanged values are left out",
+			patchType: MergePatch,
+			patch:     `{"email":"jane@example.com","date_of_birth":"1990-05-17"}`,
+			expected:  entity.UpdateUserRequest{},
+		},
+		{
+			name:      "json patch with passing test",
+			patchType: JSONPatch,
+			patch:     `[{"op":"test","path":"/email","value":"jane@example.com"},{"op":"replace","path":"/email","value":"jane@example.org"},{"op":"remove","path":"/attributes/department"}]`,
+			expected: entity.UpdateUserRequest{
+				Email:      strPtr("jane@example.org"),
+				Attributes: map[string]interface{}{"level": float64(3)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockRepo := setupTestService()
+			mockRepo.On("GetByID", mock.Anything, uint(1)).Return(patchTestUser(), nil)
+
+			req, err := patchRequestOf(context.Background(), service, tt.patchType, tt.patch)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, req)
+		})
+	}
+}
+
+func TestUserService_PatchRequestErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		patchType PatchType
+		patch     string
+		expected  error
+	}{
+		{"unsupported type", PatchType("application/json"), `{}`, ErrUnsupportedPatchType},
+		{"malformed merge patch", MergePatch, `{"name":`, ErrInvalidPatch},
+		{"path does not exist", JSONPatch, `[{"op":"replace","path":"/nickname","value":"JD"}]`, ErrInvalidPatch},
+		{"failed test", JSONPatch, `[{"op":"test","path":"/name","value":"John"},{"op":"replace","path":"/name","value":"Jim"}]`, ErrPatchTestFailed},
+		{"read-only field", MergePatch, `{"id":2}`, ErrInvalidPatchResult},
+		{"status changes need their own endpoint", JSONPatch, `[{"op":"add","path":"/status","value":"active"}]`, ErrInvalidPatchResult},
+		{"required field removed", JSONPatch, `[{"op":"remove","path":"/name"}]`, ErrInvalidPatchResult},
+		{"wrong type", MergePatch, `{"name":42}`, ErrInvalidPatchResult},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockRepo := setupTestService()
+			mockRepo.On("GetByID", mock.Anything, uint(1)).Return(patchTestUser(), nil)
+
+			_, err := patchRequestOf(context.Background(), service, tt.patchType, tt.patch)
+
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+
+	t.Run("user not found", func(t *testing.T) {
+		service, mockRepo := setupTestService()
+		mockRepo.On("GetByID", mock.Anything, uint(1)).Return(nil, errors.New("user not found"))
+
+		_, err := patchRequestOf(context.Background(), service, MergePatch, `{}`)
+
+		assert.EqualError(t, err, "user not found")
+	})
+}
+
+func TestUserService_PatchUser(t *testing.T) {
+	loadedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	loaded := func() *entity.User {
+		user := patchTestUser()
+		user.OrganizationID = 1
+		user.UpdatedAt = loadedAt
+		return user
+	}
+	valid := func(entity.UpdateUserRequest) error { return nil }
+
+	t.Run("Saves the patched user loaded once", func(t *testing.T) {
+		service, mockRepo := setupTestService()
+		mockRepo.On("GetByID", mock.Anything, uint(1)).Return(loaded(), nil).Once()
+		mockRepo.On("UpdateUnmodifiedSince", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+			return user.Name == "Jane Smith" && user.Email == "jane@example.com"
+		}), loadedAt).Return(true, nil)
+
+		user, err := service.PatchUser(context.Background(), 1, JSONPatch, []byte(`[{"op":"test","path":"/name","value":"Jane Doe"},{"op":"replace","path":"/name","value":"Jane Smith"}]`), valid)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Jane Smith", user.Name)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Refuses to save a user changed since its test passed", func(t *testing.T) {
+		service, mockRepo := setupTestService()
+		mockRepo.On("GetByID", mock.Anything, uint(1)).Return(loaded(), nil)
+		mockRepo.On("UpdateUnmodifiedSince", mock.Anything, mock.Anything, loadedAt).Return(false, nil)
+
+		user, err := service.PatchUser(context.Background(), 1, JSONPatch, []byte(`[{"op":"test","path":"/name","value":"Jane Doe"},{"op":"replace","path":"/name","value":"Jane Smith"}]`), valid)
+
+		assert.ErrorIs(t, err, ErrUserModified)
+		assert.Nil(t, user)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Saves nothing when the result fails validation", func(t *testing.T) {
+		service, mockRepo := setupTestService()
+		mockRepo.On("GetByID", mock.Anything, uint(1)).Return(loaded(), nil)
+		invalid := errors.New("email must be a valid email address")
+
+		_, err := service.PatchUser(context.Background(), 1, MergePatch, []byte(`{"email":"not-an-email"}`), func(entity.UpdateUserRequest) error { return invalid })
+
+		assert.ErrorIs(t, err, ErrInvalidPatchResult)
+		assert.ErrorIs(t, err, invalid)
+		mockRepo.AssertNotCalled(t, "UpdateUnmodifiedSince", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
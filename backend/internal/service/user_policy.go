@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+)
+
+// defaultMinimumAge is the age users must exceed when their organization sets no minimum
+const defaultMinimumAge = 18
+
+// PolicyRule names a business rule that organizations enforce on their users
+type PolicyRule string
+
+const (
+	// PolicyMinimumAge requires users to be old enough
+	PolicyMinimumAge PolicyRule = "minimum_age"
+	// PolicyEmailDomain restricts the domains of user email addresses
+	PolicyEmailDomain PolicyRule = "email_domain"
+)
+
+// PolicyError reports a user that violates a business rule of its organization
+type PolicyError struct {
+	Rule    PolicyRule
+	Message string
+}
+
+func (e *PolicyError) Error() string {
+	return e.Message
+}
+
+// WithOrganizationPolicy enforces the age and email domain settings of the request's organization
+func WithOrganizationPolicy(organizations OrganizationService) UserServiceOption {
+	return func(s *userService) {
+		s.organizations = organizations
+	}
+}
+
+// checkPolicy validates an email and date of birth against the current organization.
+// Empty values are skipped so updates only check the fields that change.
+func (s *userService) checkPolicy(ctx context.Context, email string, dateOfBirth *time.Time) error {
+	var org *entity.Organization
+	if s.organizations != nil {
+		current, err := s.organizations.CurrentOrganization(ctx)
+		if err != nil {
+			s.log(ctx).WithError(err).Error("Failed to load organization policy")
+			return fmt.Errorf("failed to load organization: %w", err)
+		}
+		org = current
+	}
+
+	if dateOfBirth != nil {
+		age := (&entity.User{DateOfBirth: *dateOfBirth}).CalculateAge()
+		if org != nil && org.MinimumAge > 0 {
+			if age < org.MinimumAge {
+				return &PolicyError{Rule: PolicyMinimumAge, Message: fmt.Sprintf("user must be at least %d years old", org.MinimumAge)}
+			}
+		} else if age <= defaultMinimumAge {
+			return &PolicyError{Rule: PolicyMinimumAge, Message: fmt.Sprintf("user must be older than %d years", defaultMinimumAge)}
+		}
+	}
+
+	if email != "" && org != nil && !org.AllowsEmail(email) {
+		return &PolicyError{Rule: PolicyEmailDomain, Message: "email domain is not allowed in this organization"}
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/tenant"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUserService_OrganizationPolicy(t *testing.T) {
+	twentyYearsAgo := time.Now().AddDate(-20, 0, -1).Format("2006-01-02")
+	// Users born on these dates turn 18 and 19 today
+	eighteenToday := time.Now().AddDate(-18, 0, 0).Format("2006-01-02")
+	nineteenToday := time.Now().AddDate(-19, 0, 0).Format("2006-01-02")
+
+	tests := []struct {
+		name          string
+		org           *entity.Organization
+		email         string
+		dateOfBirth   string
+		expectedError string
+		expectedRule  PolicyRule
+	}{
+		{
+			name:        "Default rules",
+			org:         &entity.Organization{ID: 2},
+			email:       "john@example.com",
+			dateOfBirth: twentyYearsAgo,
+		},
+		{
+			name:          "Default minimum age on the 18th birthday",
+			org:           &entity.Organization{ID: 2},
+			email:         "john@example.com",
+			dateOfBirth:   eighteenToday,
+			expectedError: "user must be older than 18 years",
+			expectedRule:  PolicyMinimumAge,
+		},
+		{
+			name:        "Default minimum age on the 19th birthday",
+			org:         &entity.Organization{ID: 2},
+			email:       "john@example.com",
+			dateOfBirth: nineteenToday,
+		},
+		{
+			name:        "Organization minimum age on the day it is reached",
+			org:         &entity.Organization{ID: 2, MinimumAge: 18},
+			email:       "john@example.com",
+			dateOfBirth: eighteenToday,
+		},
+		{
+			name:          "Organization minimum age",
+			org:           &entity.Organization{ID: 2, MinimumAge: 21},
+			email:         "john@example.com",
+			dateOfBirth:   twentyYearsAgo,
+			expectedError: "user must be at least 21 years old",
+			expectedRule:  PolicyMinimumAge,
+		},
+		{
+			name:          "Disallowed email domain",
+			org:           &entity.Organization{ID: 2, AllowedEmailDomains: entity.StringList{"acme.com"}},
+			email:         "john@example.com",
+			dateOfBirth:   twentyYearsAgo,
+			expectedError: "email domain is not allowed in this organization",
+			expectedRule:  PolicyEmailDomain,
+		},
+		{
+			name:        "Allowed email domain",
+			org:         &entity.Organization{ID: 2, AllowedEmailDomains: entity.StringList{"acme.com"}},
+			email:       "john@acme.com",
+			dateOfBirth: twentyYearsAgo,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockUserRepository{}
+			mockOrgs := &MockOrganizationService{}
+			logger := logrus.New()
+			logger.SetLevel(logrus.ErrorLevel)
+
+			service := NewUserService(mockRepo, logger, WithOrganizationPolicy(mockOrgs))
+			ctx := tenant.NewContext(context.Background(), 2)
+
+			mockOrgs.On("CurrentOrganization", ctx).Return(tt.org, nil)
+			mockRepo.On("EmailExists", ctx, tt.email, uint(0)).Return(false, nil)
+			if tt.expectedError == "" {
+				mockRepo.On("Create", ctx, mock.AnythingOfType("*entity.User")).Return(nil)
+			}
+
+			_, err := service.CreateUser(ctx, entity.CreateUserRequest{
+				Name:        "John Doe",
+				Email:       tt.email,
+				DateOfBirth: tt.dateOfBirth,
+			})
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				var policyErr *PolicyError
+				assert.ErrorAs(t, err, &policyErr)
+				assert.Equal(t, tt.expectedRule, policyErr.Rule)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+			mockOrgs.AssertExpectations(t)
+		})
+	}
+}
+
+// MockOrganizationService is a mock implementation of the OrganizationService interface
+type MockOrganizationService struct {
+	mock.Mock
+}
+
+func (m *MockOrganizationService) CreateOrganization(ctx context.Context, req entity.CreateOrganizationRequest) (*entity.Organization, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationService) GetOrganization(ctx context.Context, id uint) (*entity.Organization, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationService) ListOrganizations(ctx context.Context) ([]entity.Organization, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationService) UpdateOrganization(ctx context.Context, id uint, req entity.UpdateOrganizationRequest) (*entity.Organization, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationService) ResolveOrganization(ctx context.Context, ref string) (*entity.Organization, error) {
+	args := m.Called(ctx, ref)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationService) CurrentOrganization(ctx context.Context) (*entity.Organization, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Organization), args.Error(1)
+}
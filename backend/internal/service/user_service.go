@@ -8,36 +8,88 @@ import (
 
 	"arritech-user-management/internal/domain/entity"
 	"arritech-user-management/internal/domain/repository"
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/logger"
 	"github.com/sirupsen/logrus"
 )
 
 type UserService interface {
 	CreateUser(ctx context.Context, req entity.CreateUserRequest) (*entity.User, error)
 	GetUser(ctx context.Context, id uint) (*entity.User, error)
+	// GetUserProjected loads only the fields and related resources of projection
+	GetUserProjected(ctx context.Context, id uint, projection entity.UserProjection) (*entity.User, error)
 	UpdateUser(ctx context.Context, id uint, req entity.UpdateUserRequest) (*entity.User, error)
+	// PatchUser applies a patch to a user and saves it unless the user changed in the meantime.
+	// validate checks the update the patch amounts to before the business rules run.
+	PatchUser(ctx context.Context, id uint, patchType PatchType, patch []byte, validate func(entity.UpdateUserRequest) error) (*entity.User, error)
 	DeleteUser(ctx context.Context, id uint) error
 	ListUsers(ctx context.Context, params entity.UserSearchParams) (*entity.UserListResponse, error)
+	ChangeUserStatus(ctx context.Context, id uint, status entity.UserStatus, reason string) (*entity.User, error)
 }
 
 type userService struct {
-	userRepo repository.UserRepository
-	logger   *logrus.Logger
+	userRepo      repository.UserRepository
+	verifier      EmailVerificationService
+	attributes    AttributeService
+	organizations OrganizationService
+	avatars       AvatarService
+	events        repository.UserEventRepository
+	authorizer    Authorizer
+	redactor      *PIIRedactor
+	logger        *logrus.Logger
 }
 
-func NewUserService(userRepo repository.UserRepository, logger *logrus.Logger) UserService {
-	return &userService{
+// UserServiceOption configures optional collaborators of the user service
+type UserServiceOption func(*userService)
+
+// WithEmailVerification sends a verification email whenever a user is created or changes email
+func WithEmailVerification(verifier EmailVerificationService) UserServiceOption {
+	return func(s *userService) {
+		s.verifier = verifier
+	}
+}
+
+// WithAttributeValidation validates custom attributes against the admin-managed definitions
+func WithAttributeValidation(attributes AttributeService) UserServiceOption {
+	return func(s *userService) {
+		s.attributes = attributes
+	}
+}
+
+// WithAvatarCleanup removes a user's avatar from blob storage when the user is deleted
+func WithAvatarCleanup(avatars AvatarService) UserServiceOption {
+	return func(s *userService) {
+		s.avatars = avatars
+	}
+}
+
+func NewUserService(userRepo repository.UserRepository, logger *logrus.Logger, opts ...UserServiceOption) UserService {
+	s := &userService{
 		userRepo: userRepo,
 		logger:   logger,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// log returns the log entry of the request behind ctx, so service and repository lines share its request ID
+func (s *userService) log(ctx context.Context) *logrus.Entry {
+	return logger.FromContext(ctx, s.logger)
 }
 
 func (s *userService) CreateUser(ctx context.Context, req entity.CreateUserRequest) (*entity.User, error) {
-	s.logger.WithField("email", req.Email).Info("Creating new user")
+	if err := s.authorize(ctx, auth.PermissionUsersWrite); err != nil {
+		return nil, err
+	}
+
+	s.log(ctx).WithField("email", req.Email).Info("Creating new user")
 
 	// Business rule: Email must be unique
 	exists, err := s.userRepo.EmailExists(ctx, req.Email, 0)
 	if err != nil {
-		s.logger.WithError(err).Error("Failed to check email existence")
+		s.log(ctx).WithError(err).Error("Failed to check email existence")
 		return nil, fmt.Errorf("failed to validate email: %w", err)
 	}
 	if exists {
@@ -50,10 +102,15 @@ func (s *userService) CreateUser(ctx context.Context, req entity.CreateUserReque
 		return nil, fmt.Errorf("invalid date of birth format, use YYYY-MM-DD: %w", err)
 	}
 
-	// Business rule: User must be older than 18 years
-	age := calculateAge(dateOfBirth)
-	if age <= 18 {
-		return nil, fmt.Errorf("user must be older than 18 years")
+	// Business rule: the organization's age and email domain requirements
+	if err := s.checkPolicy(ctx, req.Email, &dateOfBirth); err != nil {
+		return nil, err
+	}
+	age := (&entity.User{DateOfBirth: dateOfBirth}).CalculateAge()
+
+	// Business rule: custom attributes must match their definitions
+	if err := s.validateAttributes(ctx, req.Attributes); err != nil {
+		return nil, err
 	}
 
 	// Sanitize input
@@ -66,56 +123,105 @@ func (s *userService) CreateUser(ctx context.Context, req entity.CreateUserReque
 		DateOfBirth: dateOfBirth,
 		Phone:       req.Phone,
 		Address:     req.Address,
+		Attributes:  req.Attributes,
+		Status:      entity.UserStatusPending,
 	}
 
 	// Set computed age for response
 	user.Age = age
 
 	if err := s.userRepo.Create(ctx, user); err != nil {
-		s.logger.WithError(err).Error("Failed to create user")
+		s.log(ctx).WithError(err).Error("Failed to create user")
 		return nil, err
 	}
 
-	s.logger.WithField("user_id", user.ID).Info("User created successfully")
+	s.log(ctx).WithField("user_id", user.ID).Info("User created successfully")
+
+	s.recordEvent(ctx, user.ID, entity.UserEventCreated, "User created")
+	s.sendVerification(ctx, user)
+	s.redactor.Redact(ctx, user)
 	return user, nil
 }
 
 func (s *userService) GetUser(ctx context.Context, id uint) (*entity.User, error) {
-	s.logger.WithField("user_id", id).Info("Getting user")
+	return s.GetUserProjected(ctx, id, entity.UserProjection{})
+}
 
-	user, err := s.userRepo.GetByID(ctx, id)
+func (s *userService) GetUserProjected(ctx context.Context, id uint, projection entity.UserProjection) (*entity.User, error) {
+	if err := s.authorize(ctx, auth.PermissionUsersRead); err != nil {
+		return nil, err
+	}
+
+	s.log(ctx).WithField("user_id", id).Info("Getting user")
+
+	var user *entity.User
+	var err error
+	if projection.IsZero() {
+		user, err = s.userRepo.GetByID(ctx, id)
+	} else {
+		user, err = s.userRepo.GetByIDProjected(ctx, id, projection)
+	}
 	if err != nil {
-		s.logger.WithError(err).WithField("user_id", id).Error("Failed to get user")
+		s.log(ctx).WithError(err).WithField("user_id", id).Error("Failed to get user")
 		return nil, err
 	}
 
 	// Set computed age
 	user.Age = user.CalculateAge()
+	user.AvatarURL = avatarURL(user)
+	s.redactor.Redact(ctx, user)
 
 	return user, nil
 }
 
 func (s *userService) UpdateUser(ctx context.Context, id uint, req entity.UpdateUserRequest) (*entity.User, error) {
-	s.logger.WithField("user_id", id).Info("Updating user")
+	if err := s.authorize(ctx, auth.PermissionUsersWrite); err != nil {
+		return nil, err
+	}
+
+	s.log(ctx).WithField("user_id", id).Info("Updating user")
 
 	// Get existing user
 	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
-		s.logger.WithError(err).WithField("user_id", id).Error("Failed to get user for update")
+		s.log(ctx).WithError(err).WithField("user_id", id).Error("Failed to get user for update")
 		return nil, err
 	}
+	return s.update(ctx, user, req, s.userRepo.Update)
+}
+
+// update applies req to user, as loaded from the repository, and stores it with save
+func (s *userService) update(ctx context.Context, user *entity.User, req entity.UpdateUserRequest, save func(context.Context, *entity.User) error) (*entity.User, error) {
+	id := user.ID
+	before := *user
+
+	// Callers who were shown masked values may send them back unchanged
+	if !s.redactor.CanSeePII(ctx) {
+		dropMaskedValues(user, &req)
+	}
 
 	// Business rule: Email must be unique (if being updated)
+	emailChanged := false
 	if req.Email != nil {
 		email := strings.ToLower(strings.TrimSpace(*req.Email))
 		exists, err := s.userRepo.EmailExists(ctx, email, id)
 		if err != nil {
-			s.logger.WithError(err).Error("Failed to check email existence")
+			s.log(ctx).WithError(err).Error("Failed to check email existence")
 			return nil, fmt.Errorf("failed to validate email: %w", err)
 		}
 		if exists {
 			return nil, fmt.Errorf("email already exists")
 		}
+
+		// Business rule: a new address must be verified again
+		if email != user.Email {
+			if err := s.checkPolicy(ctx, email, nil); err != nil {
+				return nil, err
+			}
+			emailChanged = true
+			user.EmailVerifiedAt = nil
+			user.VerificationSentAt = nil
+		}
 		user.Email = email
 	}
 
@@ -126,9 +232,8 @@ func (s *userService) UpdateUser(ctx context.Context, id uint, req entity.Update
 			return nil, fmt.Errorf("invalid date of birth format, use YYYY-MM-DD: %w", err)
 		}
 
-		age := calculateAge(dateOfBirth)
-		if age <= 18 {
-			return nil, fmt.Errorf("user must be older than 18 years")
+		if err := s.checkPolicy(ctx, "", &dateOfBirth); err != nil {
+			return nil, err
 		}
 
 		user.DateOfBirth = dateOfBirth
@@ -144,74 +249,142 @@ func (s *userService) UpdateUser(ctx context.Context, id uint, req entity.Update
 	if req.Address != nil {
 		user.Address = *req.Address
 	}
+	if req.Attributes != nil {
+		if err := s.validateAttributes(ctx, req.Attributes); err != nil {
+			return nil, err
+		}
+		user.Attributes = req.Attributes
+	}
 
 	// Set computed age
 	user.Age = user.CalculateAge()
+	user.AvatarURL = avatarURL(user)
 
-	if err := s.userRepo.Update(ctx, user); err != nil {
-		s.logger.WithError(err).WithField("user_id", id).Error("Failed to update user")
+	if err := save(ctx, user); err != nil {
+		s.log(ctx).WithError(err).WithField("user_id", id).Error("Failed to update user")
 		return nil, err
 	}
 
-	s.logger.WithField("user_id", id).Info("User updated successfully")
+	s.log(ctx).WithField("user_id", id).Info("User updated successfully")
+
+	if fields := changedFields(&before, user); len(fields) > 0 {
+		s.recordEvent(ctx, id, entity.UserEventUpdated, updateDescription(fields))
+	}
+
+	if emailChanged {
+		s.sendVerification(ctx, user)
+	}
+	s.redactor.Redact(ctx, user)
 	return user, nil
 }
 
 func (s *userService) DeleteUser(ctx context.Context, id uint) error {
-	s.logger.WithField("user_id", id).Info("Deleting user")
+	if err := s.authorize(ctx, auth.PermissionUsersDelete); err != nil {
+		return err
+	}
+
+	s.log(ctx).WithField("user_id", id).Info("Deleting user")
+
+	// Load the user first so its avatar can be purged once the row is gone
+	var user *entity.User
+	if s.avatars != nil {
+		var err error
+		if user, err = s.userRepo.GetByID(ctx, id); err != nil {
+			return err
+		}
+	}
 
 	if err := s.userRepo.Delete(ctx, id); err != nil {
-		s.logger.WithError(err).WithField("user_id", id).Error("Failed to delete user")
+		s.log(ctx).WithError(err).WithField("user_id", id).Error("Failed to delete user")
 		return err
 	}
 
-	s.logger.WithField("user_id", id).Info("User deleted successfully")
+	// The user is already deleted; a leftover avatar is logged rather than failing the request
+	if user != nil {
+		if err := s.avatars.DeleteAvatar(ctx, user); err != nil {
+			s.log(ctx).WithError(err).WithField("user_id", id).Warn("Failed to delete avatar")
+		}
+	}
+
+	s.log(ctx).WithField("user_id", id).Info("User deleted successfully")
+	s.recordEvent(ctx, id, entity.UserEventDeleted, "User deleted")
 	return nil
 }
 
 func (s *userService) ListUsers(ctx context.Context, params entity.UserSearchParams) (*entity.UserListResponse, error) {
-	s.logger.WithFields(logrus.Fields{
+	if err := s.authorize(ctx, auth.PermissionUsersRead); err != nil {
+		return nil, err
+	}
+
+	s.log(ctx).WithFields(logrus.Fields{
 		"search":   params.Search,
 		"page":     params.Page,
 		"per_page": params.PerPage,
 		"sort_by":  params.SortBy,
 		"sort_dir": params.SortDir,
+		"status":   params.Status,
 	}).Info("Service: Listing users with parameters")
 
+	// Business rule: callers who can't see personal data can't search or sort on it either
+	if !s.redactor.CanSeePII(ctx) {
+		if params.SortBy == "email" || params.SortBy == "phone" {
+			return nil, &auth.PermissionError{Permission: auth.PermissionUsersPII}
+		}
+		params.SearchNameOnly = true
+	}
+
+	// Business rule: attribute filters must reference defined attributes
+	if len(params.Attributes) > 0 {
+		if s.attributes == nil {
+			return nil, &AttributeValidationError{Fields: map[string]string{"": "Attribute filters are not supported"}}
+		}
+		if err := s.attributes.ValidateFilter(ctx, params.Attributes); err != nil {
+			return nil, err
+		}
+	}
+
 	result, err := s.userRepo.List(ctx, params)
 	if err != nil {
-		s.logger.WithError(err).Error("Service: Failed to list users from repository")
+		s.log(ctx).WithError(err).Error("Service: Failed to list users from repository")
 		return nil, err
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	s.log(ctx).WithFields(logrus.Fields{
 		"total_users": result.Total,
 		"sort_by":     params.SortBy,
 		"sort_dir":    params.SortDir,
-	}).Info("Service: Repository returned users, calculating ages")
+	}).Debug("Service: Repository returned users, calculating ages")
 
 	// Calculate age for all users
 	for i := range result.Users {
 		result.Users[i].Age = result.Users[i].CalculateAge()
+		result.Users[i].AvatarURL = avatarURL(&result.Users[i])
+		s.redactor.Redact(ctx, &result.Users[i])
 	}
 
-	s.logger.WithField("total_users", result.Total).Info("Service: Users listed successfully with ages calculated")
+	s.log(ctx).WithField("total_users", result.Total).Info("Service: Users listed successfully with ages calculated")
 	return result, nil
 }
 
-// Helper function to calculate age from date of birth
-func calculateAge(birthDate time.Time) int {
-	if birthDate.IsZero() {
-		return 0
+// validateAttributes checks custom attributes when attribute validation is enabled.
+// Without definitions there is nothing to validate against, so any attributes are rejected.
+func (s *userService) validateAttributes(ctx context.Context, attrs map[string]interface{}) error {
+	if s.attributes == nil {
+		if len(attrs) > 0 {
+			return &AttributeValidationError{Fields: map[string]string{"": "Custom attributes are not supported"}}
+		}
+		return nil
 	}
+	return s.attributes.ValidateAttributes(ctx, attrs)
+}
 
-	today := time.Now()
-	age := today.Year() - birthDate.Year()
-
-	// Adjust if birthday hasn't occurred this year
-	if today.YearDay() < birthDate.YearDay() {
-		age--
+// sendVerification issues a verification email when verification is enabled.
+// Failures are logged rather than returned: the user is already saved and the email can be resent.
+func (s *userService) sendVerification(ctx context.Context, user *entity.User) {
+	if s.verifier == nil {
+		return
+	}
+	if err := s.verifier.SendVerification(ctx, user); err != nil {
+		s.log(ctx).WithError(err).WithField("user_id", user.ID).Warn("Failed to send verification email")
 	}
-
-	return age
 }
@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/logger"
 
 	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -31,6 +33,14 @@ func (m *MockUserRepository) GetByID(ctx context.Context, id uint) (*entity.User
 	return args.Get(0).(*entity.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetByIDProjected(ctx context.Context, id uint, projection entity.UserProjection) (*entity.User, error) {
+	args := m.Called(ctx, id, projection)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
 func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
 	args := m.Called(ctx, email)
 	if args.Get(0) == nil {
@@ -52,6 +62,16 @@ func (m *MockUserRepository) Update(ctx context.Context, user *entity.User) erro
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) UpdateUnmodifiedSince(ctx context.Context, user *entity.User, updatedAt time.Time) (bool, error) {
+	args := m.Called(ctx, user, updatedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateAvatarKey(ctx context.Context, id uint, oldKey, key string) (bool, error) {
+	args := m.Called(ctx, id, oldKey, key)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockUserRepository) Delete(ctx context.Context, id uint) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -62,6 +82,14 @@ func (m *MockUserRepository) EmailExists(ctx context.Context, email string, excl
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockUserRepository) ListBirthdays(ctx context.Context, from, to time.Time) ([]entity.User, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.User), args.Error(1)
+}
+
 func setupTestService() (*userService, *MockUserRepository) {
 	mockRepo := &MockUserRepository{}
 	logger := logrus.New()
@@ -241,6 +269,41 @@ func TestUserService_GetUser(t *testing.T) {
 	}
 }
 
+func TestUserService_GetUserProjected(t *testing.T) {
+	service, mockRepo := setupTestService()
+	projection := entity.UserProjection{Fields: []string{"name", "age"}}
+	mockRepo.On("GetByIDProjected", mock.Anything, uint(1), projection).Return(&entity.User{
+		ID:          1,
+		Name:        "Test User",
+		DateOfBirth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, nil)
+
+	user, err := service.GetUserProjected(context.Background(), 1, projection)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Test User", user.Name)
+	assert.Greater(t, user.Age, 0)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestUserService_LogsThroughRequestEntry(t *testing.T) {
+	service, mockRepo := setupTestService()
+	requestLogger, hook := test.NewNullLogger()
+	mockRepo.On("GetByID", mock.Anything, uint(999)).Return(nil, errors.New("user not found"))
+
+	ctx := logger.NewContext(context.Background(), requestLogger.WithField("request_id", "req-1"))
+	_, err := service.GetUser(ctx, 999)
+
+	assert.Error(t, err)
+	entry := hook.LastEntry()
+	if assert.NotNil(t, entry) {
+		assert.Equal(t, "Failed to get user", entry.Message)
+		assert.Equal(t, "req-1", entry.Data["request_id"])
+		assert.Equal(t, uint(999), entry.Data["user_id"])
+	}
+}
+
 func TestUserService_ListUsers(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -462,55 +525,6 @@ func TestUserService_DeleteUser(t *testing.T) {
 	}
 }
 
-func TestCalculateAge(t *testing.T) {
-	tests := []struct {
-		name      string
-		birthDate time.Time
-		expected  int
-	}{
-		{
-			name:      "30 years old",
-			birthDate: time.Date(1995, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected:  30,
-		},
-		{
-			name:      "25 years old",
-			birthDate: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected:  25,
-		},
-		{
-			name:      "Born today",
-			birthDate: time.Now(),
-			expected:  0,
-		},
-		{
-			name:      "Zero time",
-			birthDate: time.Time{},
-			expected:  0,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := calculateAge(tt.birthDate)
-			// For age calculation, we need to account for the fact that the test runs at a different time
-			// than when the test was written. Let's check if the age is reasonable.
-			if tt.birthDate.IsZero() {
-				assert.Equal(t, tt.expected, result)
-			} else if tt.birthDate.Equal(time.Now()) {
-				assert.Equal(t, tt.expected, result)
-			} else {
-				// For past dates, the age should be close to expected
-				expectedMin := tt.expected - 1
-				expectedMax := tt.expected + 1
-				assert.True(t, result >= expectedMin && result <= expectedMax,
-					"Age %d should be between %d and %d for birth date %v",
-					result, expectedMin, expectedMax, tt.birthDate)
-			}
-		})
-	}
-}
-
 // Helper function to create string pointers
 func stringPtr(s string) *string {
 	return &s
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/internal/domain/repository"
+	"arritech-user-management/pkg/tenant"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultSignupWindow   = 30 * 24 * time.Hour
+	defaultTopDomains     = 10
+	maxAgeBuckets         = 20
+	maxDailySignupRange   = 366 * 24 * time.Hour
+	maxSignupRangeYears   = 5
+	defaultAgeBucketList  = "18,25,35,45,55,65"
+	defaultStatsCacheSize = 1000
+)
+
+// ErrInvalidStatsParams is returned when statistics parameters cannot be used
+var ErrInvalidStatsParams = errors.New("invalid statistics parameters")
+
+// UserStatsConfig holds statistics settings
+type UserStatsConfig struct {
+	// CacheTTL keeps computed statistics for this long; zero disables caching
+	CacheTTL time.Duration
+	// CacheMaxEntries bounds the number of cached results across all organizations, evicting the
+	// oldest first; zero uses the default of 1000
+	CacheMaxEntries int
+}
+
+type UserStatsService interface {
+	GetStats(ctx context.Context, params entity.UserStatsParams) (*entity.UserStats, error)
+}
+
+type cachedStats struct {
+	stats     *entity.UserStats
+	expiresAt time.Time
+}
+
+type userStatsService struct {
+	statsRepo repository.UserStatsRepository
+	config    UserStatsConfig
+	logger    *logrus.Logger
+	now       func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedStats
+}
+
+func NewUserStatsService(statsRepo repository.UserStatsRepository, config UserStatsConfig, logger *logrus.Logger) UserStatsService {
+	if config.CacheMaxEntries <= 0 {
+		config.CacheMaxEntries = defaultStatsCacheSize
+	}
+	return &userStatsService{
+		statsRepo: statsRepo,
+		config:    config,
+		logger:    logger,
+		now:       time.Now,
+		cache:     make(map[string]cachedStats),
+	}
+}
+
+func (s *userStatsService) GetStats(ctx context.Context, params entity.UserStatsParams) (*entity.UserStats, error) {
+	query, err := s.normalize(params)
+	if err != nil {
+		return nil, err
+	}
+
+	orgID, _ := tenant.FromContext(ctx)
+	key := fmt.Sprintf("%d|%v|%s|%s|%s|%d", orgID, query.AgeBuckets, query.Interval,
+		query.From.Format("2006-01-02"), query.To.Format("2006-01-02"), query.TopDomains)
+	if stats, ok := s.cached(key); ok {
+		return stats, nil
+	}
+
+	stats, err := s.statsRepo.Stats(ctx, query)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to compute user statistics")
+		return nil, err
+	}
+
+	s.store(key, stats)
+	return stats, nil
+}
+
+// normalize parses the request parameters and applies defaults
+func (s *userStatsService) normalize(params entity.UserStatsParams) (entity.UserStatsQuery, error) {
+	query := entity.UserStatsQuery{
+		Interval:   params.Interval,
+		TopDomains: params.TopDomains,
+	}
+	if query.Interval == "" {
+		query.Interval = entity.StatsIntervalDay
+	}
+	if query.TopDomains == 0 {
+		query.TopDomains = defaultTopDomains
+	}
+
+	buckets := params.AgeBuckets
+	if buckets == "" {
+		buckets = defaultAgeBucketList
+	}
+	for _, part := range strings.Split(buckets, ",") {
+		age, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || age < 1 {
+			return query, fmt.Errorf("%w: age buckets must be positive whole numbers", ErrInvalidStatsParams)
+		}
+		if n := len(query.AgeBuckets); n > 0 && age <= query.AgeBuckets[n-1] {
+			return query, fmt.Errorf("%w: age buckets must be increasing", ErrInvalidStatsParams)
+		}
+		query.AgeBuckets = append(query.AgeBuckets, age)
+	}
+	if len(query.AgeBuckets) > maxAgeBuckets {
+		return query, fmt.Errorf("%w: at most %d age buckets are allowed", ErrInvalidStatsParams, maxAgeBuckets)
+	}
+
+	today := dateOf(s.now())
+	query.To = today
+	if params.To != "" {
+		to, err := time.Parse("2006-01-02", params.To)
+		if err != nil {
+			return query, fmt.Errorf("%w: to must be a date in YYYY-MM-DD format", ErrInvalidStatsParams)
+		}
+		query.To = to
+	}
+	query.From = query.To.Add(-defaultSignupWindow)
+	if params.From != "" {
+		from, err := time.Parse("2006-01-02", params.From)
+		if err != nil {
+			return query, fmt.Errorf("%w: from must be a date in YYYY-MM-DD format", ErrInvalidStatsParams)
+		}
+		query.From = from
+	}
+
+	// Business rule: keep the signup series to a size a dashboard can show
+	if query.To.Before(query.From) {
+		return query, fmt.Errorf("%w: to must not be before from", ErrInvalidStatsParams)
+	}
+	if query.Interval == entity.StatsIntervalDay && query.To.Sub(query.From) > maxDailySignupRange {
+		return query, fmt.Errorf("%w: daily signups are limited to a year, use interval=week or month", ErrInvalidStatsParams)
+	}
+	if query.To.After(query.From.AddDate(maxSignupRangeYears, 0, 0)) {
+		return query, fmt.Errorf("%w: the signup range is limited to %d years", ErrInvalidStatsParams, maxSignupRangeYears)
+	}
+	return query, nil
+}
+
+func (s *userStatsService) cached(key string) (*entity.UserStats, bool) {
+	if s.config.CacheTTL <= 0 {
+		return nil, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.cache[key]
+	if !ok || s.now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.stats, true
+}
+
+func (s *userStatsService) store(key string, stats *entity.UserStats) {
+	if s.config.CacheTTL <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Drop expired entries so the cache only holds parameter combinations in recent use
+	now := s.now()
+	oldest := ""
+	for k, entry := range s.cache {
+		if now.After(entry.expiresAt) {
+			delete(s.cache, k)
+		} else if oldest == "" || entry.expiresAt.Before(s.cache[oldest].expiresAt) {
+			oldest = k
+		}
+	}
+	// Every entry lives for the same TTL, so the one expiring first is the oldest
+	if _, ok := s.cache[key]; !ok && len(s.cache) >= s.config.CacheMaxEntries {
+		delete(s.cache, oldest)
+	}
+	s.cache[key] = cachedStats{stats: stats, expiresAt: now.Add(s.config.CacheTTL)}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/tenant"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockUserStatsRepository is a mock implementation of the UserStatsRepository interface
+type MockUserStatsRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserStatsRepository) Stats(ctx context.Context, query entity.UserStatsQuery) (*entity.UserStats, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.UserStats), args.Error(1)
+}
+
+func setupUserStatsService(cacheTTL time.Duration) (*userStatsService, *MockUserStatsRepository, *time.Time) {
+	mockRepo := &MockUserStatsRepository{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	now := time.Date(2025, 3, 31, 15, 0, 0, 0, time.UTC)
+	service := NewUserStatsService(mockRepo, UserStatsConfig{CacheTTL: cacheTTL}, logger).(*userStatsService)
+	service.now = func() time.Time { return now }
+	return service, mockRepo, &now
+}
+
+func TestUserStatsService_GetStats_Defaults(t *testing.T) {
+	service, mockRepo, _ := setupUserStatsService(0)
+	expected := entity.UserStatsQuery{
+		AgeBuckets: []int{18, 25, 35, 45, 55, 65},
+		Interval:   entity.StatsIntervalDay,
+		From:       date(2025, 3, 1),
+		To:         date(2025, 3, 31),
+		TopDomains: 10,
+	}
+	mockRepo.On("Stats", mock.Anything, expected).Return(&entity.UserStats{Total: 3}, nil)
+
+	stats, err := service.GetStats(context.Background(), entity.UserStatsParams{})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats.Total)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserStatsService_GetStats_CustomParams(t *testing.T) {
+	service, mockRepo, _ := setupUserStatsService(0)
+	expected := entity.UserStatsQuery{
+		AgeBuckets: []int{21, 40},
+		Interval:   entity.StatsIntervalMonth,
+		From:       date(2023, 1, 1),
+		To:         date(2024, 12, 31),
+		TopDomains: 5,
+	}
+	mockRepo.On("Stats", mock.Anything, expected).Return(&entity.UserStats{}, nil)
+
+	_, err := service.GetStats(context.Background(), entity.UserStatsParams{
+		AgeBuckets: "21, 40",
+		Interval:   "month",
+		From:       "2023-01-01",
+		To:         "2024-12-31",
+		TopDomains: 5,
+	})
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserStatsService_GetStats_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params entity.UserStatsParams
+	}{
+		{"Non-numeric bucket", entity.UserStatsParams{AgeBuckets: "18,abc"}},
+		{"Decreasing buckets", entity.UserStatsParams{AgeBuckets: "30,20"}},
+		{"Zero bucket", entity.UserStatsParams{AgeBuckets: "0,20"}},
+		{"Reversed range", entity.UserStatsParams{From: "2025-02-01", To: "2025-01-01"}},
+		{"Daily range over a year", entity.UserStatsParams{From: "2023-01-01", To: "2025-01-01"}},
+		{"Range over five years", entity.UserStatsParams{Interval: "month", From: "2015-01-01", To: "2025-01-01"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockRepo, _ := setupUserStatsService(0)
+
+			_, err := service.GetStats(context.Background(), tt.params)
+
+			assert.ErrorIs(t, err, ErrInvalidStatsParams)
+			mockRepo.AssertNotCalled(t, "Stats", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUserStatsService_GetStats_Cache(t *testing.T) {
+	service, mockRepo, now := setupUserStatsService(time.Minute)
+	mockRepo.On("Stats", mock.Anything, mock.Anything).Return(&entity.UserStats{Total: 1}, nil).Twice()
+	org1 := tenant.NewContext(context.Background(), 1)
+	org2 := tenant.NewContext(context.Background(), 2)
+
+	_, err := service.GetStats(org1, entity.UserStatsParams{})
+	require.NoError(t, err)
+	_, err = service.GetStats(org1, entity.UserStatsParams{})
+	require.NoError(t, err)
+	mockRepo.AssertNumberOfCalls(t, "Stats", 1)
+
+	// Organizations are cached separately
+	_, err = service.GetStats(org2, entity.UserStatsParams{})
+	require.NoError(t, err)
+	mockRepo.AssertNumberOfCalls(t, "Stats", 2)
+
+	*now = now.Add(2 * time.Minute)
+	mockRepo.On("Stats", mock.Anything, mock.Anything).Return(nil, errors.New("database error")).Once()
+	_, err = service.GetStats(org1, entity.UserStatsParams{})
+	assert.Error(t, err)
+}
+
+func TestUserStatsService_GetStats_CacheSize(t *testing.T) {
+	service, mockRepo, now := setupUserStatsService(time.Minute)
+	service.config.CacheMaxEntries = 2
+	mockRepo.On("Stats", mock.Anything, mock.Anything).Return(&entity.UserStats{Total: 1}, nil)
+
+	for org := uint(1); org <= 3; org++ {
+		_, err := service.GetStats(tenant.NewContext(context.Background(), org), entity.UserStatsParams{})
+		require.NoError(t, err)
+		*now = now.Add(time.Second)
+	}
+	assert.Len(t, service.cache, 2)
+	mockRepo.AssertNumberOfCalls(t, "Stats", 3)
+
+	// The oldest organization was evicted, the newest ones are still cached
+	_, err := service.GetStats(tenant.NewContext(context.Background(), 3), entity.UserStatsParams{})
+	require.NoError(t, err)
+	mockRepo.AssertNumberOfCalls(t, "Stats", 3)
+	_, err = service.GetStats(tenant.NewContext(context.Background(), 1), entity.UserStatsParams{})
+	require.NoError(t, err)
+	mockRepo.AssertNumberOfCalls(t, "Stats", 4)
+	assert.Len(t, service.cache, 2)
+}
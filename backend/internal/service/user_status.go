@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+	"arritech-user-management/pkg/auth"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrInvalidStatusTransition is returned when a user cannot move to the requested status
+var ErrInvalidStatusTransition = errors.New("invalid status transition")
+
+// statusTransitions defines which statuses a user may move to from its current status.
+// Archived is terminal.
+var statusTransitions = map[entity.UserStatus][]entity.UserStatus{
+	entity.UserStatusPending:   {entity.UserStatusActive, entity.UserStatusSuspended, entity.UserStatusArchived},
+	entity.UserStatusActive:    {entity.UserStatusSuspended, entity.UserStatusArchived},
+	entity.UserStatusSuspended: {entity.UserStatusActive, entity.UserStatusArchived},
+	entity.UserStatusArchived:  {},
+}
+
+// canTransition reports whether a user in status from may move to status to
+func canTransition(from, to entity.UserStatus) bool {
+	for _, allowed := range statusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *userService) ChangeUserStatus(ctx context.Context, id uint, status entity.UserStatus, reason string) (*entity.User, error) {
+	if err := s.authorize(ctx, auth.PermissionUsersWrite); err != nil {
+		return nil, err
+	}
+
+	s.log(ctx).WithFields(logrus.Fields{
+		"user_id": id,
+		"status":  status,
+	}).Info("Changing user status")
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("status change reason is required")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		s.log(ctx).WithError(err).WithField("user_id", id).Error("Failed to get user for status change")
+		return nil, err
+	}
+
+	// Business rule: only transitions defined in the state machine are allowed
+	if !canTransition(user.Status, status) {
+		return nil, fmt.Errorf("%w: cannot change status from %s to %s", ErrInvalidStatusTransition, user.Status, status)
+	}
+
+	now := time.Now()
+	previous := user.Status
+	user.Status = status
+	user.StatusReason = reason
+	user.StatusChangedAt = &now
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		s.log(ctx).WithError(err).WithField("user_id", id).Error("Failed to change user status")
+		return nil, err
+	}
+
+	user.Age = user.CalculateAge()
+	user.AvatarURL = avatarURL(user)
+
+	s.recordEvent(ctx, id, entity.UserEventStatusChanged, fmt.Sprintf("Status changed from %s to %s: %s", previous, status, reason))
+
+	s.log(ctx).WithFields(logrus.Fields{
+		"user_id": id,
+		"status":  status,
+	}).Info("User status changed successfully")
+	s.redactor.Redact(ctx, user)
+	return user, nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     entity.UserStatus
+		to       entity.UserStatus
+		expected bool
+	}{
+		{"pending to active", entity.UserStatusPending, entity.UserStatusActive, true},
+		{"pending to archived", entity.UserStatusPending, entity.UserStatusArchived, true},
+		{"active to suspended", entity.UserStatusActive, entity.UserStatusSuspended, true},
+		{"active to archived", entity.UserStatusActive, entity.UserStatusArchived, true},
+		{"suspended to active", entity.UserStatusSuspended, entity.UserStatusActive, true},
+		{"suspended to archived", entity.UserStatusSuspended, entity.UserStatusArchived, true},
+		{"active to active", entity.UserStatusActive, entity.UserStatusActive, false},
+		{"active to pending", entity.UserStatusActive, entity.UserStatusPending, false},
+		{"archived to active", entity.UserStatusArchived, entity.UserStatusActive, false},
+		{"archived to suspended", entity.UserStatusArchived, entity.UserStatusSuspended, false},
+		{"unknown status", entity.UserStatus("unknown"), entity.UserStatusActive, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, canTransition(tt.from, tt.to))
+		})
+	}
+}
+
+func TestUserService_ChangeUserStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        uint
+		status        entity.UserStatus
+		reason        string
+		expectedError error
+		setupMock     func(*MockUserRepository)
+	}{
+		{
+			name:   "Successful suspension",
+			userID: 1,
+			status: entity.UserStatusSuspended,
+			reason: "Policy violation",
+			setupMock: func(mockRepo *MockUserRepository) {
+				mockRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{
+					ID:          1,
+					Name:        "Test User",
+					DateOfBirth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+					Status:      entity.UserStatusActive,
+				}, nil)
+				mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil)
+			},
+		},
+		{
+			name:          "Missing reason",
+			userID:        1,
+			status:        entity.UserStatusArchived,
+			reason:        "   ",
+			expectedError: errors.New("status change reason is required"),
+			setupMock:     func(mockRepo *MockUserRepository) {},
+		},
+		{
+			name:          "User not found",
+			userID:        999,
+			status:        entity.UserStatusArchived,
+			reason:        "Left the company",
+			expectedError: errors.New("user not found"),
+			setupMock: func(mockRepo *MockUserRepository) {
+				mockRepo.On("GetByID", mock.Anything, uint(999)).Return(nil, errors.New("user not found"))
+			},
+		},
+		{
+			name:          "Archived user cannot be reactivated",
+			userID:        1,
+			status:        entity.UserStatusActive,
+			reason:        "Came back",
+			expectedError: ErrInvalidStatusTransition,
+			setupMock: func(mockRepo *MockUserRepository) {
+				mockRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{
+					ID:     1,
+					Status: entity.UserStatusArchived,
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockRepo := setupTestService()
+			tt.setupMock(mockRepo)
+
+			user, err := service.ChangeUserStatus(context.Background(), tt.userID, tt.status, tt.reason)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Nil(t, user)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, user)
+				assert.Equal(t, tt.status, user.Status)
+				assert.Equal(t, tt.reason, user.StatusReason)
+				assert.NotNil(t, user.StatusChangedAt)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
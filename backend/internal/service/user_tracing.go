@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+
+	"arritech-user-management/internal/domain/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// userTracerName names the tracer of the user service spans
+const userTracerName = "arritech-user-management/service"
+
+// tracedUserService records a span for every call to the wrapped service
+type tracedUserService struct {
+	next   UserService
+	tracer trace.Tracer
+}
+
+// NewTracedUserService wraps a user service so that every operation is traced as a child of the
+// span in its context. Repository queries made by the operation become children of its span.
+func NewTracedUserService(next UserService, provider trace.TracerProvider) UserService {
+	return &tracedUserService{next: next, tracer: provider.Tracer(userTracerName)}
+}
+
+func (s *tracedUserService) CreateUser(ctx context.Context, req entity.CreateUserRequest) (*entity.User, error) {
+	ctx, span := s.tracer.Start(ctx, "UserService.CreateUser")
+	defer span.End()
+
+	user, err := s.next.CreateUser(ctx, req)
+	if err == nil {
+		span.SetAttributes(attribute.Int64("user.id", int64(user.ID)))
+	}
+	recordOutcome(span, err)
+	return user, err
+}
+
+func (s *tracedUserService) GetUser(ctx context.Context, id uint) (*entity.User, error) {
+	ctx, span := s.tracer.Start(ctx, "UserService.GetUser", trace.WithAttributes(attribute.Int64("user.id", int64(id))))
+	defer span.End()
+
+	user, err := s.next.GetUser(ctx, id)
+	recordOutcome(span, err)
+	return user, err
+}
+
+func (s *tracedUserService) GetUserProjected(ctx context.Context, id uint, projection entity.UserProjection) (*entity.User, error) {
+	ctx, span := s.tracer.Start(ctx, "UserService.GetUser", trace.WithAttributes(
+		attribute.Int64("user.id", int64(id)),
+		attribute.StringSlice("users.fields", projection.Fields),
+		attribute.StringSlice("users.expand", projection.Expand),
+	))
+	defer span.End()
+
+	user, err := s.next.GetUserProjected(ctx, id, projection)
+	recordOutcome(span, err)
+	return user, err
+}
+
+func (s *tracedUserService) UpdateUser(ctx context.Context, id uint, req entity.UpdateUserRequest) (*entity.User, error) {
+	ctx, span := s.tracer.Start(ctx, "UserService.UpdateUser", trace.WithAttributes(attribute.Int64("user.id", int64(id))))
+	defer span.End()
+
+	user, err := s.next.UpdateUser(ctx, id, req)
+	recordOutcome(span, err)
+	return user, err
+}
+
+func (s *tracedUserService) PatchUser(ctx context.Context, id uint, patchType PatchType, patch []byte, validate func(entity.UpdateUserRequest) error) (*entity.User, error) {
+	ctx, span := s.tracer.Start(ctx, "UserService.PatchUser", trace.WithAttributes(
+		attribute.Int64("user.id", int64(id)),
+		attribute.String("patch.type", string(patchType)),
+	))
+	defer span.End()
+
+	user, err := s.next.PatchUser(ctx, id, patchType, patch, validate)
+	recordOutcome(span, err)
+	return user, err
+}
+
+func (s *tracedUserService) DeleteUser(ctx context.Context, id uint) error {
+	ctx, span := s.tracer.Start(ctx, "UserService.DeleteUser", trace.WithAttributes(attribute.Int64("user.id", int64(id))))
+	defer span.End()
+
+	err := s.next.DeleteUser(ctx, id)
+	recordOutcome(span, err)
+	return err
+}
+
+func (s *tracedUserService) ListUsers(ctx context.Context, params entity.UserSearchParams) (*entity.UserListResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "UserService.ListUsers", trace.WithAttributes(
+		attribute.String("users.search", params.Search),
+		attribute.String("users.status", params.Status),
+		attribute.String("users.sort_by", params.SortBy),
+		attribute.String("users.sort_dir", params.SortDir),
+		attribute.Int("users.page", params.Page),
+		attribute.Int("users.per_page", params.PerPage),
+		attribute.StringSlice("users.fields", params.Projection.Fields),
+		attribute.StringSlice("users.expand", params.Projection.Expand),
+	))
+	defer span.End()
+
+	result, err := s.next.ListUsers(ctx, params)
+	if err == nil {
+		span.SetAttributes(
+			attribute.Int64("users.total", result.Total),
+			attribute.Int("users.returned", len(result.Users)),
+		)
+	}
+	recordOutcome(span, err)
+	return result, err
+}
+
+func (s *tracedUserService) ChangeUserStatus(ctx context.Context, id uint, status entity.UserStatus, reason string) (*entity.User, error) {
+	ctx, span := s.tracer.Start(ctx, "UserService.ChangeUserStatus", trace.WithAttributes(
+		attribute.Int64("user.id", int64(id)),
+		attribute.String("user.status", string(status)),
+	))
+	defer span.End()
+
+	user, err := s.next.ChangeUserStatus(ctx, id, status, reason)
+	recordOutcome(span, err)
+	return user, err
+}
+
+// recordOutcome records the outcome of an operation on its span. Rejections such as an underage
+// user are expected answers, so only unexpected errors mark the span as failed.
+func recordOutcome(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	outcome := FailureOutcome(err)
+	span.SetAttributes(attribute.String("users.outcome", outcome))
+	if outcome == OutcomeError {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"arritech-user-management/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTracedUserService(t *testing.T) {
+	t.Run("Lists users with search params and row counts", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		service, mockRepo := setupTestService()
+		params := entity.UserSearchParams{Search: "john", SortBy: "name", SortDir: "asc", Page: 1, PerPage: 10}
+		mockRepo.On("List", mock.Anything, mock.Anything).Return(&entity.UserListResponse{
+			Users: []entity.User{{ID: 1, DateOfBirth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)}},
+			Total: 11,
+		}, nil)
+
+		_, err := NewTracedUserService(service, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))).ListUsers(context.Background(), params)
+
+		require.NoError(t, err)
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "UserService.ListUsers", spans[0].Name())
+		attrs := spanAttributes(spans[0])
+		assert.Equal(t, "john", attrs["users.search"].AsString())
+		assert.Equal(t, "name", attrs["users.sort_by"].AsString())
+		assert.Equal(t, "asc", attrs["users.sort_dir"].AsString())
+		assert.Equal(t, int64(11), attrs["users.total"].AsInt64())
+		assert.Equal(t, int64(1), attrs["users.returned"].AsInt64())
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	})
+
+	t.Run("Rejections don't fail the span", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		service, mockRepo := setupTestService()
+		mockRepo.On("GetByID", mock.Anything, uint(9)).Return(nil, errors.New("user not found"))
+
+		_, err := NewTracedUserService(service, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))).GetUser(context.Background(), 9)
+
+		require.Error(t, err)
+		span := recorder.Ended()[0]
+		assert.Equal(t, int64(9), spanAttributes(span)["user.id"].AsInt64())
+		assert.Equal(t, OutcomeNotFound, spanAttributes(span)["users.outcome"].AsString())
+		assert.Equal(t, codes.Unset, span.Status().Code)
+	})
+
+	t.Run("Unexpected errors fail the span", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		service, mockRepo := setupTestService()
+		mockRepo.On("Delete", mock.Anything, uint(1)).Return(errors.New("connection refused"))
+
+		err := NewTracedUserService(service, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))).DeleteUser(context.Background(), 1)
+
+		require.Error(t, err)
+		span := recorder.Ended()[0]
+		assert.Equal(t, "UserService.DeleteUser", span.Name())
+		assert.Equal(t, codes.Error, span.Status().Code)
+		assert.Len(t, span.Events(), 1)
+	})
+}
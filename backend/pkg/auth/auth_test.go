@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudience_UnmarshalJSON(t *testing.T) {
+	t.Run("single string", func(t *testing.T) {
+		var aud Audience
+		require.NoError(t, json.Unmarshal([]byte(`"users-api"`), &aud))
+		assert.Equal(t, Audience{"users-api"}, aud)
+		assert.True(t, aud.Contains("users-api"))
+	})
+
+	t.Run("array", func(t *testing.T) {
+		var aud Audience
+		require.NoError(t, json.Unmarshal([]byte(`["billing","users-api"]`), &aud))
+		assert.True(t, aud.Contains("users-api"))
+		assert.False(t, aud.Contains("admin"))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var aud Audience
+		assert.Error(t, json.Unmarshal([]byte(`42`), &aud))
+	})
+}
+
+func TestContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	claims := &Claims{Subject: "user-1"}
+	got, ok := FromContext(NewContext(context.Background(), claims))
+	assert.True(t, ok)
+	assert.Same(t, claims, got)
+
+	_, ok = FromContext(NewContext(context.Background(), nil))
+	assert.False(t, ok)
+}
+
+func TestClaims_Scopes(t *testing.T) {
+	token := &Claims{Subject: "user-1"}
+	assert.False(t, token.IsAPIKey())
+	assert.False(t, token.HasScope("users:read"))
+
+	key := &Claims{Subject: "api-key:uk_1", APIKeyID: 3, Scopes: []string{"users:read"}}
+	assert.True(t, key.IsAPIKey())
+	assert.True(t, key.HasScope("users:read"))
+	assert.False(t, key.HasScope("users:write"))
+}
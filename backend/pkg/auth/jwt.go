@@ -0,0 +1,362 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+// Supported signing algorithms
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	ES256 = "ES256"
+)
+
+var (
+	// ErrInvalidToken is returned when a token is malformed, has a bad signature or fails a claim check
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrExpiredToken is returned when a token is well formed but past its expiry
+	ErrExpiredToken = errors.New("token expired")
+)
+
+// Key is a verification key; Key is a []byte secret for HS256, *rsa.PublicKey for RS256
+// or *ecdsa.PublicKey for ES256
+type Key struct {
+	ID        string
+	Algorithm string
+	Key       interface{}
+}
+
+// Config holds JWT verification configuration
+type Config struct {
+	Issuer        string
+	Audience      string
+	ClockSkew     time.Duration
+	HMACSecret    string
+	PublicKeyFile string
+	JWKSFile      string
+}
+
+// GetConfigFromEnv creates JWT config from environment variables
+func GetConfigFromEnv() Config {
+	skew, err := time.ParseDuration(getEnv("JWT_CLOCK_SKEW", "1m"))
+	if err != nil {
+		skew = time.Minute
+	}
+	return Config{
+		Issuer:        os.Getenv("JWT_ISSUER"),
+		Audience:      os.Getenv("JWT_AUDIENCE"),
+		ClockSkew:     skew,
+		HMACSecret:    os.Getenv("JWT_HMAC_SECRET"),
+		PublicKeyFile: os.Getenv("JWT_PUBLIC_KEY_FILE"),
+		JWKSFile:      os.Getenv("JWT_JWKS_FILE"),
+	}
+}
+
+// Verifier validates JWTs signed with HS256, RS256 or ES256
+type Verifier struct {
+	keys      []Key
+	issuer    string
+	audience  string
+	clockSkew time.Duration
+	now       func() time.Time
+}
+
+// NewVerifier creates a verifier with the keys named by config; at least one key is required
+func NewVerifier(config Config) (*Verifier, error) {
+	var keys []Key
+	if config.HMACSecret != "" {
+		keys = append(keys, Key{Algorithm: HS256, Key: []byte(config.HMACSecret)})
+	}
+	if config.PublicKeyFile != "" {
+		key, err := LoadPublicKey(config.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if config.JWKSFile != "" {
+		jwks, err := LoadJWKS(config.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, jwks...)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no JWT verification keys configured")
+	}
+	return NewVerifierWithKeys(keys, config), nil
+}
+
+// NewVerifierWithKeys creates a verifier with the given keys, ignoring the key settings of config
+func NewVerifierWithKeys(keys []Key, config Config) *Verifier {
+	return &Verifier{
+		keys:      keys,
+		issuer:    config.Issuer,
+		audience:  config.Audience,
+		clockSkew: config.ClockSkew,
+		now:       time.Now,
+	}
+}
+
+// Verify checks the token signature, issuer, audience and validity period and returns its claims
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if !v.verifySignature(header.Algorithm, header.KeyID, parts[0]+"."+parts[1], signature) {
+		return nil, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if err := decodeSegment(parts[1], &claims.Extra); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	return &claims, v.validate(&claims)
+}
+
+// verifySignature checks the signature against every key of the token's algorithm, or only
+// the key named by kid. The algorithm must match the key type, so an RSA public key can never
+// be used as an HMAC secret.
+func (v *Verifier) verifySignature(algorithm, keyID, signingInput string, signature []byte) bool {
+	digest := sha256.Sum256([]byte(signingInput))
+
+	for _, key := range v.keys {
+		if key.Algorithm != algorithm || (keyID != "" && key.ID != "" && key.ID != keyID) {
+			continue
+		}
+
+		switch k := key.Key.(type) {
+		case []byte:
+			mac := hmac.New(sha256.New, k)
+			mac.Write([]byte(signingInput))
+			if hmac.Equal(signature, mac.Sum(nil)) {
+				return true
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature) == nil {
+				return true
+			}
+		case *ecdsa.PublicKey:
+			// JWS encodes ECDSA signatures as the fixed-width concatenation of r and s
+			if len(signature) != 64 {
+				continue
+			}
+			r := new(big.Int).SetBytes(signature[:32])
+			s := new(big.Int).SetBytes(signature[32:])
+			if ecdsa.Verify(k, digest[:], r, s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (v *Verifier) validate(claims *Claims) error {
+	now := v.now()
+
+	if claims.ExpiresAt == 0 {
+		return ErrInvalidToken
+	}
+	if now.Add(-v.clockSkew).Unix() >= claims.ExpiresAt {
+		return ErrExpiredToken
+	}
+	if claims.NotBefore != 0 && now.Add(v.clockSkew).Unix() < claims.NotBefore {
+		return ErrInvalidToken
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return ErrInvalidToken
+	}
+	if v.audience != "" && !claims.Audience.Contains(v.audience) {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// LoadPublicKey reads a PEM encoded RSA or P-256 public key or certificate
+func LoadPublicKey(path string) (Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Key{}, fmt.Errorf("failed to read public key: %w", err)
+	}
+	return ParsePublicKey(data)
+}
+
+// ParsePublicKey parses a PEM encoded RSA or P-256 public key or certificate
+func ParsePublicKey(data []byte) (Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, fmt.Errorf("public key is not PEM encoded")
+	}
+
+	var public interface{}
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return Key{}, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		public = cert.PublicKey
+	case "RSA PUBLIC KEY":
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return Key{}, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		public = key
+	default:
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return Key{}, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		public = key
+	}
+
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		return Key{Algorithm: RS256, Key: key}, nil
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return Key{}, fmt.Errorf("unsupported elliptic curve %s", key.Curve.Params().Name)
+		}
+		return Key{Algorithm: ES256, Key: key}, nil
+	default:
+		return Key{}, fmt.Errorf("unsupported public key type %T", public)
+	}
+}
+
+// jwk is a JSON Web Key (RFC 7517) with the members used by RSA, EC and oct keys
+type jwk struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+	K         string `json:"k"`
+}
+
+// LoadJWKS reads a JSON Web Key Set file
+func LoadJWKS(path string) ([]Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	return ParseJWKS(data)
+}
+
+// ParseJWKS parses a JSON Web Key Set; keys that aren't meant for signatures are skipped
+func ParseJWKS(data []byte) ([]Key, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	keys := make([]Key, 0, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.parse()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWK %q: %w", k.KeyID, err)
+		}
+		if k.Algorithm != "" && k.Algorithm != key.Algorithm {
+			return nil, fmt.Errorf("invalid JWK %q: unsupported algorithm %s", k.KeyID, k.Algorithm)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (k jwk) parse() (Key, error) {
+	switch k.KeyType {
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil || len(secret) == 0 {
+			return Key{}, fmt.Errorf("invalid k")
+		}
+		return Key{ID: k.KeyID, Algorithm: HS256, Key: secret}, nil
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil || len(n) == 0 {
+			return Key{}, fmt.Errorf("invalid n")
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return Key{}, fmt.Errorf("invalid e")
+		}
+		key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		return Key{ID: k.KeyID, Algorithm: RS256, Key: key}, nil
+	case "EC":
+		if k.Curve != "P-256" {
+			return Key{}, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		if errX != nil || errY != nil || len(x) != 32 || len(y) != 32 {
+			return Key{}, fmt.Errorf("invalid coordinates")
+		}
+		// Reject points that are not on the curve before using the key
+		if _, err := ecdh.P256().NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+			return Key{}, fmt.Errorf("invalid point: %w", err)
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		return Key{ID: k.KeyID, Algorithm: ES256, Key: key}, nil
+	default:
+		return Key{}, fmt.Errorf("unsupported key type %q", k.KeyType)
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
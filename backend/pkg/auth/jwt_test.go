@@ -0,0 +1,275 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// signToken builds a JWT with the given header and claims, signed with key
+func signToken(t *testing.T, header, claims map[string]interface{}, key interface{}) string {
+	t.Helper()
+	headerJSON, err := json.Marshal(header)
+	require.NoError(t, err)
+	claimsJSON, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	input := b64(headerJSON) + "." + b64(claimsJSON)
+	digest := sha256.Sum256([]byte(input))
+
+	var signature []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(input))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		require.NoError(t, err)
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	}
+	return input + "." + b64(signature)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":    "user-1",
+		"iss":    "https://issuer.example.com",
+		"aud":    "users-api",
+		"exp":    testNow.Add(time.Hour).Unix(),
+		"iat":    testNow.Unix(),
+		"org_id": 7,
+		"email":  "jane@example.com",
+	}
+}
+
+func testConfig() Config {
+	return Config{Issuer: "https://issuer.example.com", Audience: "users-api", ClockSkew: time.Minute}
+}
+
+func newTestVerifier(keys ...Key) *Verifier {
+	v := NewVerifierWithKeys(keys, testConfig())
+	v.now = func() time.Time { return testNow }
+	return v
+}
+
+func TestVerifier_Algorithms(t *testing.T) {
+	secret := []byte("top-secret")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	v := newTestVerifier(
+		Key{Algorithm: HS256, Key: secret},
+		Key{ID: "rsa-1", Algorithm: RS256, Key: &rsaKey.PublicKey},
+		Key{ID: "ec-1", Algorithm: ES256, Key: &ecKey.PublicKey},
+	)
+
+	tests := []struct {
+		name   string
+		header map[string]interface{}
+		key    interface{}
+	}{
+		{"HS256", map[string]interface{}{"alg": "HS256", "typ": "JWT"}, secret},
+		{"RS256", map[string]interface{}{"alg": "RS256", "kid": "rsa-1"}, rsaKey},
+		{"ES256", map[string]interface{}{"alg": "ES256", "kid": "ec-1"}, ecKey},
+		{"ES256 without kid", map[string]interface{}{"alg": "ES256"}, ecKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := v.Verify(signToken(t, tt.header, validClaims(), tt.key))
+
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", claims.Subject)
+			assert.Equal(t, uint(7), claims.OrganizationID)
+			assert.Equal(t, "jane@example.com", claims.Extra["email"])
+		})
+	}
+}
+
+func TestVerifier_Rejects(t *testing.T) {
+	secret := []byte("top-secret")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rsaDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+
+	v := newTestVerifier(
+		Key{Algorithm: HS256, Key: secret},
+		Key{ID: "rsa-1", Algorithm: RS256, Key: &rsaKey.PublicKey},
+	)
+	hs := map[string]interface{}{"alg": "HS256"}
+
+	with := func(key string, value interface{}) map[string]interface{} {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		expected error
+	}{
+		{"malformed", "not-a-token", ErrInvalidToken},
+		{"wrong secret", signToken(t, hs, validClaims(), []byte("other")), ErrInvalidToken},
+		{"alg none", signToken(t, map[string]interface{}{"alg": "none"}, validClaims(), []byte{}), ErrInvalidToken},
+		{"RSA public key used as HMAC secret", signToken(t, hs, validClaims(), rsaDER), ErrInvalidToken},
+		{"unknown kid", signToken(t, map[string]interface{}{"alg": "RS256", "kid": "rsa-2"}, validClaims(), rsaKey), ErrInvalidToken},
+		{"expired", signToken(t, hs, with("exp", testNow.Add(-2*time.Minute).Unix()), secret), ErrExpiredToken},
+		{"missing exp", signToken(t, hs, with("exp", nil), secret), ErrInvalidToken},
+		{"not yet valid", signToken(t, hs, with("nbf", testNow.Add(5*time.Minute).Unix()), secret), ErrInvalidToken},
+		{"wrong issuer", signToken(t, hs, with("iss", "https://evil.example.com"), secret), ErrInvalidToken},
+		{"wrong audience", signToken(t, hs, with("aud", []string{"billing"}), secret), ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := v.Verify(tt.token)
+
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestVerifier_ClockSkew(t *testing.T) {
+	secret := []byte("top-secret")
+	v := newTestVerifier(Key{Algorithm: HS256, Key: secret})
+	hs := map[string]interface{}{"alg": "HS256"}
+
+	claims := validClaims()
+	claims["exp"] = testNow.Add(-30 * time.Second).Unix()
+	claims["nbf"] = testNow.Add(30 * time.Second).Unix()
+
+	_, err := v.Verify(signToken(t, hs, claims, secret))
+
+	assert.NoError(t, err)
+}
+
+func TestParsePublicKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	ecDER, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	require.NoError(t, err)
+
+	key, err := ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: rsaDER}))
+	require.NoError(t, err)
+	assert.Equal(t, RS256, key.Algorithm)
+
+	key, err = ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)}))
+	require.NoError(t, err)
+	assert.Equal(t, RS256, key.Algorithm)
+
+	key, err = ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: ecDER}))
+	require.NoError(t, err)
+	assert.Equal(t, ES256, key.Algorithm)
+
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	p384DER, err := x509.MarshalPKIXPublicKey(&p384.PublicKey)
+	require.NoError(t, err)
+	_, err = ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: p384DER}))
+	assert.Error(t, err)
+
+	_, err = ParsePublicKey([]byte("not pem"))
+	assert.Error(t, err)
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	coordinate := func(n *big.Int) string {
+		buf := make([]byte, 32)
+		n.FillBytes(buf)
+		return b64(buf)
+	}
+	jwks := fmt.Sprintf(`{"keys":[
+		{"kty":"RSA","kid":"rsa-1","alg":"RS256","use":"sig","n":%q,"e":%q},
+		{"kty":"EC","kid":"ec-1","crv":"P-256","x":%q,"y":%q},
+		{"kty":"oct","kid":"hs-1","k":%q},
+		{"kty":"RSA","kid":"enc-1","use":"enc","n":"AQAB","e":"AQAB"}
+	]}`,
+		b64(rsaKey.N.Bytes()), b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+		coordinate(ecKey.X), coordinate(ecKey.Y),
+		b64([]byte("shared-secret")),
+	)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, []byte(jwks), 0o600))
+
+	keys, err := LoadJWKS(path)
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+
+	v := newTestVerifier(keys...)
+	for kid, key := range map[string]interface{}{"rsa-1": rsaKey, "ec-1": ecKey, "hs-1": []byte("shared-secret")} {
+		alg := map[string]string{"rsa-1": RS256, "ec-1": ES256, "hs-1": HS256}[kid]
+		_, err := v.Verify(signToken(t, map[string]interface{}{"alg": alg, "kid": kid}, validClaims(), key))
+		assert.NoError(t, err, kid)
+	}
+
+	t.Run("point not on curve", func(t *testing.T) {
+		_, err := ParseJWKS([]byte(fmt.Sprintf(`{"keys":[{"kty":"EC","crv":"P-256","x":%q,"y":%q}]}`,
+			coordinate(ecKey.X), coordinate(big.NewInt(1)))))
+		assert.Error(t, err)
+	})
+
+	t.Run("algorithm mismatch", func(t *testing.T) {
+		_, err := ParseJWKS([]byte(`{"keys":[{"kty":"oct","alg":"RS256","k":"c2VjcmV0"}]}`))
+		assert.Error(t, err)
+	})
+}
+
+func TestNewVerifier(t *testing.T) {
+	_, err := NewVerifier(Config{})
+	assert.Error(t, err)
+
+	v, err := NewVerifier(Config{HMACSecret: "secret"})
+	require.NoError(t, err)
+	assert.Len(t, v.keys, 1)
+
+	_, err = NewVerifier(Config{JWKSFile: filepath.Join(t.TempDir(), "missing.json")})
+	assert.Error(t, err)
+}
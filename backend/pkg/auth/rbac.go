@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Permissions name the operations a caller may perform. API key scopes use the same names,
+// so a key is allowed exactly the permissions listed in its scopes.
+const (
+	PermissionUsersRead     = "users:read"
+	PermissionUsersWrite    = "users:write"
+	PermissionUsersDelete   = "users:delete"
+	PermissionUsersExport   = "users:export"
+	PermissionUsersPII      = "users:pii"
+	PermissionAPIKeysManage = "api-keys:manage"
+	// PermissionOrganizationsManage allows creating organizations and changing their settings
+	PermissionOrganizationsManage = "organizations:manage"
+	// PermissionAttributesManage allows defining, changing and deleting custom attributes
+	PermissionAttributesManage = "attributes:manage"
+	// PermissionPlatformAdmin allows acting in every organization rather than only the caller's
+	// own, and creating organizations
+	PermissionPlatformAdmin = "platform:admin"
+)
+
+// Roles
+const (
+	RoleViewer  = "viewer"
+	RoleEditor  = "editor"
+	RoleAdmin   = "admin"
+	RoleAuditor = "auditor"
+	// RolePlatformAdmin administers every organization of the deployment
+	RolePlatformAdmin = "platform-admin"
+)
+
+// PermissionError is returned when the caller lacks a permission
+type PermissionError struct {
+	Permission string
+}
+
+func (e *PermissionError) Error() string {
+	return "missing permission " + e.Permission
+}
+
+// Policy maps roles to permissions and assigns roles to token subjects
+type Policy struct {
+	// Roles lists the permissions of each role
+	Roles map[string][]string `json:"roles"`
+	// Assignments lists the roles of token subjects, in addition to the roles claim of the token
+	Assignments map[string][]string `json:"assignments"`
+	// DefaultRoles are granted to every token subject
+	DefaultRoles []string `json:"default_roles"`
+}
+
+// DefaultPolicy returns the built-in roles with no assignments
+func DefaultPolicy() *Policy {
+	return &Policy{
+		Roles: map[string][]string{
+			RoleViewer:  {PermissionUsersRead},
+			RoleAuditor: {PermissionUsersRead, PermissionUsersExport, PermissionUsersPII},
+			RoleEditor:  {PermissionUsersRead, PermissionUsersWrite, PermissionUsersPII},
+			RoleAdmin: {
+				PermissionUsersRead, PermissionUsersWrite, PermissionUsersDelete,
+				PermissionUsersExport, PermissionUsersPII, PermissionAPIKeysManage,
+				PermissionOrganizationsManage, PermissionAttributesManage,
+			},
+			RolePlatformAdmin: {
+				PermissionUsersRead, PermissionUsersWrite, PermissionUsersDelete,
+				PermissionUsersExport, PermissionUsersPII, PermissionAPIKeysManage,
+				PermissionOrganizationsManage, PermissionAttributesManage, PermissionPlatformAdmin,
+			},
+		},
+		Assignments: map[string][]string{},
+	}
+}
+
+// LoadPolicy reads role assignments from a JSON file. Roles defined in the file replace the
+// built-in role of the same name. An empty path returns the default policy.
+func LoadPolicy(path string) (*Policy, error) {
+	policy := DefaultPolicy()
+	if path == "" {
+		return policy, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read RBAC policy: %w", err)
+	}
+
+	var file Policy
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse RBAC policy: %w", err)
+	}
+	for role, permissions := range file.Roles {
+		policy.Roles[role] = permissions
+	}
+	for subject, roles := range file.Assignments {
+		policy.Assignments[subject] = roles
+	}
+	policy.DefaultRoles = file.DefaultRoles
+
+	if err := policy.validate(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// validate rejects assignments of roles that don't exist, which would silently grant nothing
+func (p *Policy) validate() error {
+	check := func(roles []string, where string) error {
+		for _, role := range roles {
+			if _, ok := p.Roles[role]; !ok {
+				return fmt.Errorf("unknown role %q in %s", role, where)
+			}
+		}
+		return nil
+	}
+	for subject, roles := range p.Assignments {
+		if err := check(roles, "assignment of "+subject); err != nil {
+			return err
+		}
+	}
+	return check(p.DefaultRoles, "default_roles")
+}
+
+// RolesOf returns the roles of a token subject: the roles claim, assigned roles and default roles
+func (p *Policy) RolesOf(claims *Claims) []string {
+	roles := append([]string{}, claims.Roles...)
+	roles = append(roles, p.Assignments[claims.Subject]...)
+	return append(roles, p.DefaultRoles...)
+}
+
+// Allows reports whether the caller has permission. API keys have the permissions named by
+// their scopes; token subjects have the permissions of their roles.
+func (p *Policy) Allows(claims *Claims, permission string) bool {
+	if claims.IsAPIKey() {
+		return claims.HasScope(permission)
+	}
+	for _, role := range p.RolesOf(claims) {
+		for _, granted := range p.Roles[role] {
+			if granted == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Authorize returns a *PermissionError unless the caller in ctx has permission.
+// Contexts without a caller are denied.
+func (p *Policy) Authorize(ctx context.Context, permission string) error {
+	claims, ok := FromContext(ctx)
+	if !ok || !p.Allows(claims, permission) {
+		return &PermissionError{Permission: permission}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_DefaultRoles(t *testing.T) {
+	policy := DefaultPolicy()
+
+	tests := []struct {
+		role    string
+		allowed []string
+		denied  []string
+	}{
+		{RoleViewer, []string{PermissionUsersRead}, []string{PermissionUsersWrite, PermissionUsersDelete, PermissionUsersExport, PermissionUsersPII}},
+		{RoleAuditor, []string{PermissionUsersRead, PermissionUsersExport, PermissionUsersPII}, []string{PermissionUsersWrite, PermissionUsersDelete}},
+		{RoleEditor, []string{PermissionUsersRead, PermissionUsersWrite, PermissionUsersPII}, []string{PermissionUsersDelete, PermissionAPIKeysManage, PermissionOrganizationsManage, PermissionAttributesManage}},
+		{RoleAdmin, []string{PermissionUsersDelete, PermissionUsersPII, PermissionAPIKeysManage, PermissionOrganizationsManage, PermissionAttributesManage}, []string{PermissionPlatformAdmin}},
+		{RolePlatformAdmin, []string{PermissionUsersDelete, PermissionOrganizationsManage, PermissionAttributesManage, PermissionPlatformAdmin}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			claims := &Claims{Subject: "someone", Roles: []string{tt.role}}
+			for _, permission := range tt.allowed {
+				assert.True(t, policy.Allows(claims, permission), permission)
+			}
+			for _, permission := range tt.denied {
+				assert.False(t, policy.Allows(claims, permission), permission)
+			}
+		})
+	}
+
+	assert.False(t, policy.Allows(&Claims{Subject: "nobody"}, PermissionUsersRead))
+}
+
+func TestPolicy_APIKeysUseScopes(t *testing.T) {
+	policy := DefaultPolicy()
+	key := &Claims{Subject: "api-key:uk_1", APIKeyID: 1, Roles: []string{RoleAdmin}, Scopes: []string{PermissionUsersRead}}
+
+	assert.True(t, policy.Allows(key, PermissionUsersRead))
+	assert.False(t, policy.Allows(key, PermissionUsersDelete))
+}
+
+func TestPolicy_Authorize(t *testing.T) {
+	policy := DefaultPolicy()
+
+	err := policy.Authorize(context.Background(), PermissionUsersRead)
+	var permErr *PermissionError
+	require.True(t, errors.As(err, &permErr))
+	assert.Equal(t, PermissionUsersRead, permErr.Permission)
+	assert.EqualError(t, err, "missing permission users:read")
+
+	ctx := NewContext(context.Background(), &Claims{Subject: "intern", Roles: []string{RoleViewer}})
+	assert.NoError(t, policy.Authorize(ctx, PermissionUsersRead))
+	assert.EqualError(t, policy.Authorize(ctx, PermissionUsersDelete), "missing permission users:delete")
+}
+
+func TestLoadPolicy(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "rbac.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	t.Run("empty path returns defaults", func(t *testing.T) {
+		policy, err := LoadPolicy("")
+		require.NoError(t, err)
+		assert.Equal(t, DefaultPolicy(), policy)
+	})
+
+	t.Run("assignments and role overrides", func(t *testing.T) {
+		policy, err := LoadPolicy(write(t, `{
+			"roles": {"support": ["users:read", "users:write"], "viewer": []},
+			"assignments": {"intern@example.com": ["auditor"], "agent@example.com": ["support"]},
+			"default_roles": ["viewer"]
+		}`))
+		require.NoError(t, err)
+
+		intern := &Claims{Subject: "intern@example.com"}
+		assert.True(t, policy.Allows(intern, PermissionUsersExport))
+		assert.False(t, policy.Allows(intern, PermissionUsersDelete))
+
+		assert.True(t, policy.Allows(&Claims{Subject: "agent@example.com"}, PermissionUsersWrite))
+		assert.False(t, policy.Allows(&Claims{Subject: "stranger"}, PermissionUsersRead))
+		assert.True(t, policy.Allows(&Claims{Subject: "boss", Roles: []string{"admin"}}, PermissionUsersDelete))
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		_, err := LoadPolicy(write(t, `{"assignments": {"intern@example.com": ["superuser"]}}`))
+		assert.EqualError(t, err, `unknown role "superuser" in assignment of intern@example.com`)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadPolicy(filepath.Join(t.TempDir(), "missing.json"))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,138 @@
+// Package blob stores binary objects such as avatars behind a pluggable BlobStore.
+package blob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore defines the interface for storing objects by key.
+// Keys are slash-separated paths such as "avatars/1/42/256.jpg".
+type BlobStore interface {
+	// Put stores data under key, replacing any existing object
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+
+	// Get opens the object stored under key; it returns ErrNotFound if there is none
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the object stored under key; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// Config holds blob store configuration
+type Config struct {
+	Driver    string
+	LocalPath string
+	S3        S3Config
+}
+
+// GetConfigFromEnv creates blob store config from environment variables
+func GetConfigFromEnv() Config {
+	return Config{
+		Driver:    getEnv("BLOB_DRIVER", "local"),
+		LocalPath: getEnv("BLOB_LOCAL_PATH", "data/blobs"),
+		S3: S3Config{
+			Endpoint:        getEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+			Region:          getEnv("S3_REGION", "us-east-1"),
+			Bucket:          getEnv("S3_BUCKET", ""),
+			AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+		},
+	}
+}
+
+// New creates the blob store selected by config.Driver (local, s3 or memory)
+func New(config Config) (BlobStore, error) {
+	switch strings.ToLower(config.Driver) {
+	case "local", "":
+		return NewFileStore(config.LocalPath)
+	case "s3":
+		if config.S3.Bucket == "" {
+			return nil, fmt.Errorf("S3_BUCKET is required for the s3 blob driver")
+		}
+		return NewS3Store(config.S3), nil
+	case "memory":
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown blob driver: %s", config.Driver)
+	}
+}
+
+// MemoryStore keeps objects in memory so tests can inspect them
+type MemoryStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+// NewMemoryStore creates a new in-memory blob store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{objects: make(map[string][]byte)}
+}
+
+func (s *MemoryStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.objects[key] = append([]byte(nil), data...)
+	return nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.objects, key)
+	return nil
+}
+
+// Keys returns the keys of all stored objects
+func (s *MemoryStore) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// validKey rejects keys that could escape the store's namespace
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid blob key %q", key)
+		}
+	}
+	return nil
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,55 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	store, err := New(Config{Driver: "memory"})
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryStore{}, store)
+
+	store, err = New(Config{Driver: "local", LocalPath: t.TempDir()})
+	require.NoError(t, err)
+	assert.IsType(t, &FileStore{}, store)
+
+	store, err = New(Config{Driver: "s3", S3: S3Config{Bucket: "avatars"}})
+	require.NoError(t, err)
+	assert.IsType(t, &S3Store{}, store)
+
+	_, err = New(Config{Driver: "s3"})
+	assert.Error(t, err)
+
+	_, err = New(Config{Driver: "ftp"})
+	assert.Error(t, err)
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "avatars/1/64.jpg", []byte("data"), "image/jpeg"))
+
+	r, err := store.Get(ctx, "avatars/1/64.jpg")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(r)
+	assert.Equal(t, "data", string(data))
+	assert.Equal(t, []string{"avatars/1/64.jpg"}, store.Keys())
+
+	require.NoError(t, store.Delete(ctx, "avatars/1/64.jpg"))
+	_, err = store.Get(ctx, "avatars/1/64.jpg")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestValidKey(t *testing.T) {
+	assert.NoError(t, validKey("avatars/1/64.jpg"))
+
+	for _, key := range []string{"", "/etc/passwd", "avatars/../secret", "avatars//64.jpg", "./avatars"} {
+		assert.Error(t, validKey(key), key)
+	}
+}
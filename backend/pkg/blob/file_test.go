@@ -0,0 +1,47 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "avatars/1/64.jpg", []byte("first"), "image/jpeg"))
+	require.NoError(t, store.Put(ctx, "avatars/1/64.jpg", []byte("second"), "image/jpeg"))
+
+	r, err := store.Get(ctx, "avatars/1/64.jpg")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "second", string(data))
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(filepath.Join(dir, "avatars", "1"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	require.NoError(t, store.Delete(ctx, "avatars/1/64.jpg"))
+	require.NoError(t, store.Delete(ctx, "avatars/1/64.jpg"))
+
+	_, err = store.Get(ctx, "avatars/1/64.jpg")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestFileStore_RejectsTraversal(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	err = store.Put(context.Background(), "../outside.txt", []byte("x"), "text/plain")
+	assert.Error(t, err)
+}
@@ -1,14 +1,17 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"arritech-user-management/internal/domain/entity"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
 )
 
 // Config holds database configuration
@@ -64,11 +67,51 @@ func GetConfigFromEnv() Config {
 	}
 }
 
+// models are the entities whose tables RunMigrations creates, in dependency order
+var models = []interface{}{
+	&entity.Organization{},
+	&entity.Tag{},
+	&entity.Group{},
+	&entity.User{},
+	&entity.AttributeDefinition{},
+	&entity.UserNote{},
+	&entity.UserEvent{},
+	&entity.APIKey{},
+	&entity.IdempotencyKey{},
+}
+
 // RunMigrations runs database migrations
 func RunMigrations(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&entity.User{},
-	)
+	if err := db.AutoMigrate(models...); err != nil {
+		return err
+	}
+
+	// Existing users belong to the default organization
+	defaultOrg := entity.Organization{ID: entity.DefaultOrganizationID, Slug: "default", Name: "Default"}
+	if err := db.FirstOrCreate(&defaultOrg, entity.Organization{ID: entity.DefaultOrganizationID}).Error; err != nil {
+		return fmt.Errorf("failed to create default organization: %w", err)
+	}
+
+	// Email, tag, group and attribute key uniqueness is per organization since multi-tenancy;
+	// drop the old global indexes
+	globalIndexes := []struct {
+		model interface{}
+		name  string
+	}{
+		{&entity.User{}, "idx_users_email"},
+		{&entity.Tag{}, "idx_tags_name"},
+		{&entity.Group{}, "idx_groups_name"},
+		{&entity.AttributeDefinition{}, "idx_attribute_definitions_key"},
+	}
+	for _, index := range globalIndexes {
+		if db.Migrator().HasIndex(index.model, index.name) {
+			if err := db.Migrator().DropIndex(index.model, index.name); err != nil {
+				return fmt.Errorf("failed to drop global index %s: %w", index.name, err)
+			}
+		}
+	}
+
+	return nil
 }
 
 func getEnv(key, defaultValue string) string {
@@ -77,3 +120,56 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// CheckMigrations reports an error when a table or column the entities need is missing, which
+// means the migrations of this version haven't run against the database
+func CheckMigrations(ctx context.Context, db *gorm.DB) error {
+	var columns []struct {
+		TableName  string
+		ColumnName string
+	}
+	if err := db.WithContext(ctx).Raw(
+		"SELECT table_name AS table_name, column_name AS column_name FROM information_schema.columns WHERE table_schema = DATABASE()",
+	).Scan(&columns).Error; err != nil {
+		return fmt.Errorf("failed to read schema: %w", err)
+	}
+	existing := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		existing[col.TableName+"."+col.ColumnName] = true
+		existing[col.TableName] = true
+	}
+
+	var missing []string
+	seen := make(map[string]bool)
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return fmt.Errorf("failed to parse model: %w", err)
+		}
+		tables := []*schema.Schema{stmt.Schema}
+		for _, rel := range stmt.Schema.Relationships.Relations {
+			if rel.JoinTable != nil {
+				tables = append(tables, rel.JoinTable)
+			}
+		}
+		for _, table := range tables {
+			if seen[table.Table] {
+				continue
+			}
+			seen[table.Table] = true
+			if !existing[table.Table] {
+				missing = append(missing, table.Table)
+				continue
+			}
+			for _, field := range table.Fields {
+				if field.DBName != "" && !field.IgnoreMigration && !existing[table.Table+"."+field.DBName] {
+					missing = append(missing, table.Table+"."+field.DBName)
+				}
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("pending migrations: missing %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
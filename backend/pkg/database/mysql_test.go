@@ -1,10 +1,16 @@
 package database
 
 import (
+	"context"
 	"os"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
 func TestGetConfigFromEnv(t *testing.T) {
@@ -206,3 +212,65 @@ func TestConfig_DSN(t *testing.T) {
 	assert.Equal(t, "test_db", config.DBName)
 	assert.Equal(t, "utf8mb4", config.Charset)
 }
+
+func TestCheckMigrations(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{})
+	require.NoError(t, err)
+
+	// The columns of a fully migrated schema
+	var columns [][2]string
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		require.NoError(t, stmt.Parse(model))
+		tables := []*schema.Schema{stmt.Schema}
+		for _, rel := range stmt.Schema.Relationships.Relations {
+			if rel.JoinTable != nil {
+				tables = append(tables, rel.JoinTable)
+			}
+		}
+		for _, table := range tables {
+			for _, field := range table.Fields {
+				if field.DBName != "" && !field.IgnoreMigration {
+					columns = append(columns, [2]string{table.Table, field.DBName})
+				}
+			}
+		}
+	}
+	schemaRows := func(skip string) *sqlmock.Rows {
+		rows := sqlmock.NewRows([]string{"table_name", "column_name"})
+		for _, col := range columns {
+			if col[0]+"."+col[1] != skip && col[0] != skip {
+				rows.AddRow(col[0], col[1])
+			}
+		}
+		return rows
+	}
+	query := "SELECT table_name AS table_name, column_name AS column_name FROM information_schema.columns"
+
+	t.Run("Current schema", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnRows(schemaRows(""))
+		assert.NoError(t, CheckMigrations(context.Background(), db))
+	})
+
+	t.Run("Missing column", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnRows(schemaRows("users.status"))
+		err := CheckMigrations(context.Background(), db)
+		assert.EqualError(t, err, "pending migrations: missing users.status")
+	})
+
+	t.Run("Missing table", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnRows(schemaRows("user_groups"))
+		err := CheckMigrations(context.Background(), db)
+		assert.EqualError(t, err, "pending migrations: missing user_groups")
+	})
+
+	t.Run("Schema can't be read", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(assert.AnError)
+		assert.Error(t, CheckMigrations(context.Background(), db))
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
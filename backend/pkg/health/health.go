@@ -0,0 +1,159 @@
+// Package health reports whether the service is alive and whether it is ready to serve traffic,
+// based on checks of the components it depends on.
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status is the outcome of a check or of the readiness probe as a whole
+type Status string
+
+const (
+	StatusPass         Status = "pass"
+	StatusFail         Status = "fail"
+	StatusReady        Status = "ready"
+	StatusNotReady     Status = "not_ready"
+	StatusShuttingDown Status = "shutting_down"
+)
+
+// Checker checks a component the service depends on
+type Checker interface {
+	// Check returns an error when the component can't be used. It must return once ctx is done.
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to the Checker interface
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// CheckResult is the outcome of one check
+type CheckResult struct {
+	Status Status `json:"status"`
+	// LatencyMs is how long the check took in milliseconds
+	LatencyMs float64 `json:"latency_ms"`
+	// Optional checks are reported but don't make the service not ready
+	Optional bool   `json:"optional,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Report is the readiness of the service with the result of every check
+type Report struct {
+	Status Status                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// Ready reports whether the service can take traffic
+func (r Report) Ready() bool {
+	return r.Status == StatusReady
+}
+
+type check struct {
+	name     string
+	checker  Checker
+	optional bool
+}
+
+// Health runs the registered checks. It is safe for concurrent use.
+type Health struct {
+	timeout  time.Duration
+	mu       sync.RWMutex
+	checks   []check
+	draining atomic.Bool
+}
+
+// New creates a Health whose checks are each given timeout to complete
+func New(timeout time.Duration) *Health {
+	return &Health{timeout: timeout}
+}
+
+// Register adds a check that must pass for the service to be ready
+func (h *Health) Register(name string, checker Checker) {
+	h.register(check{name: name, checker: checker})
+}
+
+// RegisterOptional adds a check that is reported but doesn't affect readiness, for components
+// whose outage degrades some features rather than the whole service
+func (h *Health) RegisterOptional(name string, checker Checker) {
+	h.register(check{name: name, checker: checker, optional: true})
+}
+
+func (h *Health) register(c check) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks = append(h.checks, c)
+	sort.Slice(h.checks, func(i, j int) bool { return h.checks[i].name < h.checks[j].name })
+}
+
+// Drain marks the service as shutting down, so that readiness fails and load balancers stop
+// sending traffic while in-flight requests complete
+func (h *Health) Drain() {
+	h.draining.Store(true)
+}
+
+// Ready runs all checks concurrently and reports the readiness of the service
+func (h *Health) Ready(ctx context.Context) Report {
+	if h.draining.Load() {
+		return Report{Status: StatusShuttingDown}
+	}
+
+	h.mu.RLock()
+	checks := append([]check(nil), h.checks...)
+	h.mu.RUnlock()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c check) {
+			defer wg.Done()
+			results[i] = h.run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusReady, Checks: make(map[string]CheckResult, len(checks))}
+	for i, c := range checks {
+		report.Checks[c.name] = results[i]
+		if results[i].Status == StatusFail && !c.optional {
+			report.Status = StatusNotReady
+		}
+	}
+	return report
+}
+
+func (h *Health) run(ctx context.Context, c check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	// A checker that ignores its deadline must not hold up the probe
+	done := make(chan error, 1)
+	go func() {
+		done <- c.checker.Check(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := CheckResult{
+		Status:    StatusPass,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		Optional:  c.optional,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
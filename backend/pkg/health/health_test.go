@@ -0,0 +1,80 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealth_Ready(t *testing.T) {
+	pass := CheckerFunc(func(ctx context.Context) error { return nil })
+	fail := CheckerFunc(func(ctx context.Context) error { return errors.New("connection refused") })
+	hang := CheckerFunc(func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	t.Run("Ready when all checks pass", func(t *testing.T) {
+		h := New(time.Second)
+		h.Register("database", pass)
+		h.Register("migrations", pass)
+
+		report := h.Ready(context.Background())
+
+		assert.True(t, report.Ready())
+		assert.Len(t, report.Checks, 2)
+		assert.Equal(t, StatusPass, report.Checks["database"].Status)
+	})
+
+	t.Run("Not ready when a check fails", func(t *testing.T) {
+		h := New(time.Second)
+		h.Register("database", fail)
+		h.Register("migrations", pass)
+
+		report := h.Ready(context.Background())
+
+		assert.Equal(t, StatusNotReady, report.Status)
+		assert.Equal(t, StatusFail, report.Checks["database"].Status)
+		assert.Equal(t, "connection refused", report.Checks["database"].Error)
+		assert.Equal(t, StatusPass, report.Checks["migrations"].Status)
+	})
+
+	t.Run("Optional checks don't affect readiness", func(t *testing.T) {
+		h := New(time.Second)
+		h.Register("database", pass)
+		h.RegisterOptional("mailer", fail)
+
+		report := h.Ready(context.Background())
+
+		assert.True(t, report.Ready())
+		assert.Equal(t, StatusFail, report.Checks["mailer"].Status)
+		assert.True(t, report.Checks["mailer"].Optional)
+	})
+
+	t.Run("Checks time out", func(t *testing.T) {
+		h := New(20 * time.Millisecond)
+		h.Register("database", hang)
+
+		start := time.Now()
+		report := h.Ready(context.Background())
+
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.Equal(t, StatusNotReady, report.Status)
+		assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["database"].Error)
+	})
+
+	t.Run("Not ready while draining", func(t *testing.T) {
+		h := New(time.Second)
+		h.Register("database", pass)
+		h.Drain()
+
+		report := h.Ready(context.Background())
+
+		assert.Equal(t, StatusShuttingDown, report.Status)
+		assert.False(t, report.Ready())
+		assert.Empty(t, report.Checks)
+	})
+}
@@ -0,0 +1,118 @@
+// Package ical writes iCalendar (RFC 5545) calendars.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the longest content line allowed before folding (RFC 5545 section 3.1)
+const maxLineOctets = 75
+
+// Calendar is a VCALENDAR object
+type Calendar struct {
+	ProdID string
+	// Name is shown by clients as the calendar's title
+	Name   string
+	Events []Event
+}
+
+// Event is an all-day VEVENT
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	// Date is the day of the first occurrence; only its year, month and day are used
+	Date time.Time
+	// RRule is an optional recurrence rule, e.g. FREQ=YEARLY
+	RRule string
+	// Stamp is when the event information was created
+	Stamp time.Time
+}
+
+// Encode writes the calendar to w
+func (c *Calendar) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	e := &encoder{w: bw}
+
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:" + c.ProdID)
+	e.line("CALSCALE:GREGORIAN")
+	e.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		e.line("X-WR-CALNAME:" + escapeText(c.Name))
+	}
+	for _, event := range c.Events {
+		e.line("BEGIN:VEVENT")
+		e.line("UID:" + event.UID)
+		e.line("DTSTAMP:" + event.Stamp.UTC().Format("20060102T150405Z"))
+		e.line("DTSTART;VALUE=DATE:" + event.Date.Format("20060102"))
+		if event.RRule != "" {
+			e.line("RRULE:" + event.RRule)
+		}
+		e.line("SUMMARY:" + escapeText(event.Summary))
+		if event.Description != "" {
+			e.line("DESCRIPTION:" + escapeText(event.Description))
+		}
+		e.line("TRANSP:TRANSPARENT")
+		e.line("END:VEVENT")
+	}
+	e.line("END:VCALENDAR")
+
+	if e.err != nil {
+		return e.err
+	}
+	return bw.Flush()
+}
+
+// encoder writes folded content lines and keeps the first write error
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *encoder) line(s string) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.WriteString(fold(s) + "\r\n")
+}
+
+// fold splits a content line into lines of at most 75 octets, continuing each with a space,
+// without breaking multi-byte characters
+func fold(s string) string {
+	if len(s) <= maxLineOctets {
+		return s
+	}
+
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// escapeText escapes a TEXT property value
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
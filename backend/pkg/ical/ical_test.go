@@ -0,0 +1,70 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendar_Encode(t *testing.T) {
+	cal := &Calendar{
+		ProdID: "-//Arritech//Test//EN",
+		Name:   "Birthdays",
+		Events: []Event{{
+			UID:     "user-1@example.com",
+			Summary: "Doe, Jane; birthday",
+			Date:    time.Date(1990, 3, 14, 0, 0, 0, 0, time.UTC),
+			RRule:   "FREQ=YEARLY",
+			Stamp:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("X", 3600)),
+		}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, cal.Encode(&buf))
+
+	expected := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Arritech//Test//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Birthdays",
+		"BEGIN:VEVENT",
+		"UID:user-1@example.com",
+		"DTSTAMP:20240102T020405Z",
+		"DTSTART;VALUE=DATE:19900314",
+		"RRULE:FREQ=YEARLY",
+		`SUMMARY:Doe\, Jane\; birthday`,
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestFold(t *testing.T) {
+	short := strings.Repeat("a", 75)
+	assert.Equal(t, short, fold(short))
+
+	folded := fold("SUMMARY:" + strings.Repeat("é", 60))
+	lines := strings.Split(folded, "\r\n")
+	require.Greater(t, len(lines), 1)
+	for i, line := range lines {
+		assert.LessOrEqual(t, len(line), 75)
+		if i > 0 {
+			assert.True(t, strings.HasPrefix(line, " "))
+		}
+	}
+
+	// Unfolding restores the original line without splitting characters
+	assert.Equal(t, "SUMMARY:"+strings.Repeat("é", 60), strings.ReplaceAll(folded, "\r\n ", ""))
+}
+
+func TestEscapeText(t *testing.T) {
+	assert.Equal(t, `a\\b\;c\,d\ne`, escapeText("a\\b;c,d\ne"))
+}
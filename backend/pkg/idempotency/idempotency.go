@@ -0,0 +1,39 @@
+// Package idempotency defines how responses to requests made with an Idempotency-Key are stored,
+// so that retried requests get the original response instead of running again.
+package idempotency
+
+import "context"
+
+// Header is the request header that carries the client's idempotency key
+const Header = "Idempotency-Key"
+
+// ReplayedHeader marks responses that were replayed from the store
+const ReplayedHeader = "Idempotent-Replayed"
+
+// Response is a stored response
+type Response struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+// Record is the state of a key that is already in use
+type Record struct {
+	// Fingerprint identifies the request the key was first used for
+	Fingerprint string
+	// Response is nil while the first request is still in progress
+	Response *Response
+}
+
+// Store keeps idempotency keys and the responses to their requests
+type Store interface {
+	// Reserve claims key for a request with fingerprint. It returns nil if the key was free and is
+	// now held by the caller, or the record of the request that already holds it.
+	Reserve(ctx context.Context, key, fingerprint string) (*Record, error)
+
+	// Complete stores the response to the request holding key
+	Complete(ctx context.Context, key string, response Response) error
+
+	// Release frees key without storing a response, so the request can be retried
+	Release(ctx context.Context, key string) error
+}
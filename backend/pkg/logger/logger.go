@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"os"
 	"strings"
 
@@ -49,6 +50,22 @@ func NewLogger() *logrus.Logger {
 	return logger
 }
 
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries a request-scoped log entry
+func NewContext(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, contextKey{}, entry)
+}
+
+// FromContext returns the log entry carried by ctx, so that lines logged while serving a request
+// share its request ID and trace fields. Without one it returns an entry of fallback.
+func FromContext(ctx context.Context, fallback *logrus.Logger) *logrus.Entry {
+	if entry, ok := ctx.Value(contextKey{}).(*logrus.Entry); ok {
+		return entry.WithContext(ctx)
+	}
+	return logrus.NewEntry(fallback).WithContext(ctx)
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"testing"
 
 	"github.com/sirupsen/logrus"
@@ -78,3 +79,23 @@ func TestLoggerErrorHandling(t *testing.T) {
 	// Verify the logger is working with errors
 	assert.True(t, true) // If we get here, the logger didn't panic
 }
+
+func TestFromContext(t *testing.T) {
+	base := logrus.New()
+
+	t.Run("Returns the entry carried by the context", func(t *testing.T) {
+		ctx := NewContext(context.Background(), base.WithField("request_id", "req-1"))
+
+		entry := FromContext(ctx, logrus.New())
+
+		assert.Same(t, base, entry.Logger)
+		assert.Equal(t, "req-1", entry.Data["request_id"])
+	})
+
+	t.Run("Falls back to the given logger", func(t *testing.T) {
+		entry := FromContext(context.Background(), base)
+
+		assert.Same(t, base, entry.Logger)
+		assert.Empty(t, entry.Data)
+	})
+}
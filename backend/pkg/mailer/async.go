@@ -0,0 +1,104 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrQueueFull is returned when a message can't be queued because too many are waiting
+	ErrQueueFull = errors.New("mail queue is full")
+	// ErrMailerClosed is returned for messages sent after Close
+	ErrMailerClosed = errors.New("mailer is closed")
+)
+
+// AsyncConfig holds the settings of an AsyncMailer
+type AsyncConfig struct {
+	// QueueSize is how many messages may wait for delivery
+	QueueSize int
+	// Timeout bounds the delivery of each message
+	Timeout time.Duration
+}
+
+// AsyncMailer queues messages and delivers them in the background, so that requests don't wait
+// on a slow mail server. Send only fails when the message can't be queued; delivery failures are
+// logged.
+type AsyncMailer struct {
+	next    Mailer
+	timeout time.Duration
+	logger  *logrus.Logger
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan Message
+	done   chan struct{}
+}
+
+// NewAsyncMailer starts delivering queued messages through next
+func NewAsyncMailer(next Mailer, config AsyncConfig, logger *logrus.Logger) *AsyncMailer {
+	m := &AsyncMailer{
+		next:    next,
+		timeout: config.Timeout,
+		logger:  logger,
+		queue:   make(chan Message, config.QueueSize),
+		done:    make(chan struct{}),
+	}
+	go m.run()
+	return m
+}
+
+// Send queues msg for delivery. The message outlives ctx, which only the caller's request is bound to.
+func (m *AsyncMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return ErrMailerClosed
+	}
+
+	select {
+	case m.queue <- msg:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting messages and waits until the queued ones are delivered or ctx is done
+func (m *AsyncMailer) Close(ctx context.Context) error {
+	m.mu.Lock()
+	if !m.closed {
+		m.closed = true
+		close(m.queue)
+	}
+	m.mu.Unlock()
+
+	select {
+	case <-m.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *AsyncMailer) run() {
+	defer close(m.done)
+	for msg := range m.queue {
+		m.deliver(msg)
+	}
+}
+
+func (m *AsyncMailer) deliver(msg Message) {
+	ctx := context.Background()
+	if m.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+	}
+	if err := m.next.Send(ctx, msg); err != nil {
+		m.logger.WithError(err).WithField("subject", msg.Subject).Error("Failed to deliver email")
+	}
+}
@@ -0,0 +1,141 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message represents an outgoing email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer defines the interface for sending emails
+type Mailer interface {
+	// Send delivers a message
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config holds mailer configuration
+type Config struct {
+	Driver   string
+	From     string
+	Host     string
+	Port     string
+	Username string
+	Password string
+	FilePath string
+	// Timeout bounds an SMTP delivery, from dialing to the server's last reply
+	Timeout time.Duration
+}
+
+// GetConfigFromEnv creates mailer config from environment variables
+func GetConfigFromEnv() Config {
+	return Config{
+		Driver:   getEnv("MAILER_DRIVER", "stdout"),
+		From:     getEnv("MAILER_FROM", "no-reply@arritech.com"),
+		Host:     getEnv("SMTP_HOST", "localhost"),
+		Port:     getEnv("SMTP_PORT", "587"),
+		Username: getEnv("SMTP_USERNAME", ""),
+		Password: getEnv("SMTP_PASSWORD", ""),
+		FilePath: getEnv("MAILER_FILE_PATH", "mail.log"),
+		Timeout:  getDurationEnv("SMTP_TIMEOUT", 10*time.Second),
+	}
+}
+
+// New creates the mailer selected by config.Driver (smtp, file, stdout or memory)
+func New(config Config) (Mailer, error) {
+	switch strings.ToLower(config.Driver) {
+	case "smtp":
+		return NewSMTPMailer(config), nil
+	case "file":
+		f, err := os.OpenFile(config.FilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open mail file: %w", err)
+		}
+		return NewWriterMailer(f, config.From), nil
+	case "stdout", "":
+		return NewWriterMailer(os.Stdout, config.From), nil
+	case "memory":
+		return NewMemoryMailer(), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer driver: %s", config.Driver)
+	}
+}
+
+// WriterMailer writes messages to an io.Writer instead of delivering them.
+// It is meant for local development, where a file or stdout is enough.
+type WriterMailer struct {
+	mu   sync.Mutex
+	w    io.Writer
+	from string
+}
+
+// NewWriterMailer creates a mailer that writes messages to w
+func NewWriterMailer(w io.Writer, from string) *WriterMailer {
+	return &WriterMailer{w: w, from: from}
+}
+
+func (m *WriterMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err := fmt.Fprintf(m.w, "Date: %s\r\nFrom: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n\r\n",
+		time.Now().Format(time.RFC1123Z), m.from, msg.To, msg.Subject, msg.Body)
+	if err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	return nil
+}
+
+// MemoryMailer keeps sent messages in memory so tests can inspect them
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewMemoryMailer creates a new in-memory mailer
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages returns a copy of all messages sent so far
+func (m *MemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	messages := make([]Message, len(m.messages))
+	copy(messages, m.messages)
+	return messages
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,248 @@
+package mailer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       Config
+		expectedType interface{}
+		expectError  bool
+	}{
+		{"SMTP driver", Config{Driver: "smtp", Host: "localhost", Port: "25"}, &SMTPMailer{}, false},
+		{"Stdout driver", Config{Driver: "stdout"}, &WriterMailer{}, false},
+		{"File driver", Config{Driver: "file", FilePath: filepath.Join(t.TempDir(), "mail.log")}, &WriterMailer{}, false},
+		{"Memory driver", Config{Driver: "memory"}, &MemoryMailer{}, false},
+		{"Unknown driver", Config{Driver: "carrier-pigeon"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := New(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.IsType(t, tt.expectedType, m)
+		})
+	}
+}
+
+func TestWriterMailer_Send(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewWriterMailer(&buf, "no-reply@example.com")
+
+	err := m.Send(context.Background(), Message{
+		To:      "test@example.com",
+		Subject: "Hello",
+		Body:    "Welcome aboard",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "From: no-reply@example.com")
+	assert.Contains(t, buf.String(), "To: test@example.com")
+	assert.Contains(t, buf.String(), "Subject: Hello")
+	assert.Contains(t, buf.String(), "Welcome aboard")
+}
+
+func TestMemoryMailer_Send(t *testing.T) {
+	m := NewMemoryMailer()
+
+	require.NoError(t, m.Send(context.Background(), Message{To: "a@example.com", Subject: "One"}))
+	require.NoError(t, m.Send(context.Background(), Message{To: "b@example.com", Subject: "Two"}))
+
+	messages := m.Messages()
+	assert.Len(t, messages, 2)
+	assert.Equal(t, "a@example.com", messages[0].To)
+	assert.Equal(t, "Two", messages[1].Subject)
+}
+
+// serveSMTP runs a minimal SMTP server that accepts one message and returns its address and the
+// received DATA
+func serveSMTP(t *testing.T) (host, port string, received <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	data := make(chan string, 1)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = conn.Write([]byte("220 localhost ESMTP\r\n"))
+				reader := bufio.NewReader(conn)
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					switch {
+					case strings.HasPrefix(line, "EHLO"), strings.HasPrefix(line, "MAIL"), strings.HasPrefix(line, "RCPT"):
+						_, _ = conn.Write([]byte("250 OK\r\n"))
+					case strings.HasPrefix(line, "DATA"):
+						_, _ = conn.Write([]byte("354 Go ahead\r\n"))
+						var body strings.Builder
+						for {
+							line, err := reader.ReadString('\n')
+							if err != nil || line == ".\r\n" {
+								break
+							}
+							body.WriteString(line)
+						}
+						data <- body.String()
+						_, _ = conn.Write([]byte("250 Queued\r\n"))
+					case strings.HasPrefix(line, "QUIT"):
+						_, _ = conn.Write([]byte("221 Bye\r\n"))
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	host, port, err = net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	return host, port, data
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	t.Run("Delivers the message", func(t *testing.T) {
+		host, port, received := serveSMTP(t)
+		m := NewSMTPMailer(Config{From: "no-reply@example.com", Host: host, Port: port, Timeout: time.Second})
+
+		err := m.Send(context.Background(), Message{To: "test@example.com", Subject: "Verify", Body: "line 1\nline 2"})
+
+		require.NoError(t, err)
+		msg := <-received
+		assert.Contains(t, msg, "From: no-reply@example.com\r\n")
+		assert.Contains(t, msg, "To: test@example.com\r\n")
+		assert.Contains(t, msg, "Subject: Verify\r\n")
+		assert.Contains(t, msg, "line 1\r\nline 2")
+	})
+
+	t.Run("Gives up on a server that doesn't answer", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+		// Accepts connections but never greets
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+			}
+		}()
+		host, port, err := net.SplitHostPort(listener.Addr().String())
+		require.NoError(t, err)
+		m := NewSMTPMailer(Config{Host: host, Port: port, Timeout: 100 * time.Millisecond})
+
+		start := time.Now()
+		err = m.Send(context.Background(), Message{To: "test@example.com"})
+
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("Stops when the context is cancelled", func(t *testing.T) {
+		host, port, _ := serveSMTP(t)
+		m := NewSMTPMailer(Config{Host: host, Port: port})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := m.Send(ctx, Message{To: "test@example.com"})
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Fails when the server is down", func(t *testing.T) {
+		m := NewSMTPMailer(Config{Host: "127.0.0.1", Port: "1", Timeout: time.Second})
+
+		err := m.Send(context.Background(), Message{To: "test@example.com"})
+
+		assert.ErrorContains(t, err, "failed to connect to SMTP server")
+	})
+}
+
+func TestSMTPMailer_Ping(t *testing.T) {
+	host, port, _ := serveSMTP(t)
+	m := NewSMTPMailer(Config{Host: host, Port: port})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, m.Ping(ctx))
+
+	m = NewSMTPMailer(Config{Host: host, Port: "1"})
+	assert.Error(t, m.Ping(ctx))
+}
+
+// failingMailer fails every delivery
+type failingMailer struct{}
+
+func (failingMailer) Send(ctx context.Context, msg Message) error {
+	return errors.New("connection refused")
+}
+
+func TestAsyncMailer(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	t.Run("Delivers queued messages before closing", func(t *testing.T) {
+		memory := NewMemoryMailer()
+		m := NewAsyncMailer(memory, AsyncConfig{QueueSize: 10, Timeout: time.Second}, logger)
+
+		require.NoError(t, m.Send(context.Background(), Message{To: "a@example.com"}))
+		require.NoError(t, m.Send(context.Background(), Message{To: "b@example.com"}))
+		require.NoError(t, m.Close(context.Background()))
+
+		assert.Len(t, memory.Messages(), 2)
+		assert.ErrorIs(t, m.Send(context.Background(), Message{To: "c@example.com"}), ErrMailerClosed)
+	})
+
+	t.Run("Outlives the caller's context", func(t *testing.T) {
+		memory := NewMemoryMailer()
+		m := NewAsyncMailer(memory, AsyncConfig{QueueSize: 1}, logger)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		require.NoError(t, m.Send(ctx, Message{To: "a@example.com"}))
+		cancel()
+		require.NoError(t, m.Close(context.Background()))
+
+		assert.Len(t, memory.Messages(), 1)
+	})
+
+	t.Run("Rejects messages when the queue is full", func(t *testing.T) {
+		m := &AsyncMailer{next: NewMemoryMailer(), logger: logger, queue: make(chan Message, 1), done: make(chan struct{})}
+
+		require.NoError(t, m.Send(context.Background(), Message{To: "a@example.com"}))
+		assert.ErrorIs(t, m.Send(context.Background(), Message{To: "b@example.com"}), ErrQueueFull)
+	})
+
+	t.Run("Logs failed deliveries", func(t *testing.T) {
+		m := NewAsyncMailer(failingMailer{}, AsyncConfig{QueueSize: 1}, logger)
+
+		require.NoError(t, m.Send(context.Background(), Message{To: "a@example.com"}))
+		assert.NoError(t, m.Close(context.Background()))
+	})
+}
@@ -0,0 +1,143 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPMailer delivers messages through an SMTP server
+type SMTPMailer struct {
+	addr    string
+	host    string
+	from    string
+	auth    smtp.Auth
+	timeout time.Duration
+}
+
+// NewSMTPMailer creates a new SMTP mailer. PLAIN auth is used when a username is configured.
+func NewSMTPMailer(config Config) *SMTPMailer {
+	var auth smtp.Auth
+	if config.Username != "" {
+		auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	}
+
+	return &SMTPMailer{
+		addr:    net.JoinHostPort(config.Host, config.Port),
+		host:    config.Host,
+		from:    config.From,
+		auth:    auth,
+		timeout: config.Timeout,
+	}
+}
+
+// Send delivers msg, upgrading to TLS when the server offers STARTTLS. The whole exchange must
+// finish within the configured timeout and before ctx is done.
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if m.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+	}
+
+	client, stop, err := m.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer stop()
+	defer client.Close()
+
+	if err := m.deliver(client, msg); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = errors.Join(ctxErr, err)
+		}
+		return fmt.Errorf("failed to send email via SMTP: %w", err)
+	}
+	return nil
+}
+
+func (m *SMTPMailer) deliver(client *smtp.Client, msg Message) error {
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("server doesn't support AUTH")
+		}
+		if err := client.Auth(m.auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(m.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(m.buildMessage(msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// Ping connects to the SMTP server, waits for its greeting and quits without sending anything
+func (m *SMTPMailer) Ping(ctx context.Context) error {
+	client, stop, err := m.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer stop()
+	defer client.Close()
+	return client.Quit()
+}
+
+// connect dials the server and waits for its greeting. The connection is held to the deadline of
+// ctx and closed when ctx is cancelled, until stop is called.
+func (m *SMTPMailer) connect(ctx context.Context) (*smtp.Client, func() bool, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		stop()
+		conn.Close()
+		return nil, nil, fmt.Errorf("SMTP server did not greet: %w", err)
+	}
+	return client, stop, nil
+}
+
+// buildMessage renders msg as an RFC 5322 plain text message
+func (m *SMTPMailer) buildMessage(msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + m.from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + msg.Subject + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+func TestGORMPlugin(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{})
+	require.NoError(t, err)
+
+	m := New()
+	require.NoError(t, db.Use(m.GORMPlugin()))
+
+	mock.ExpectQuery("SELECT \\* FROM `widgets`").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+	mock.ExpectQuery("SELECT \\* FROM `widgets`").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	mock.ExpectQuery("SELECT \\* FROM `widgets`").WillReturnError(assert.AnError)
+
+	var w widget
+	require.NoError(t, db.First(&w).Error)
+	// Not found still counts as a successful query
+	assert.ErrorIs(t, db.First(&w, 2).Error, gorm.ErrRecordNotFound)
+	assert.Error(t, db.First(&w, 3).Error)
+
+	assert.Equal(t, 2, testutil.CollectAndCount(m.dbQueries))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, uint64(2), sampleCount(t, m, "query", "widgets", "ok"))
+	assert.Equal(t, uint64(1), sampleCount(t, m, "query", "widgets", "error"))
+}
+
+func sampleCount(t *testing.T, m *Metrics, labels ...string) uint64 {
+	var metric dto.Metric
+	require.NoError(t, m.dbQueries.WithLabelValues(labels...).(prometheus.Metric).Write(&metric))
+	return metric.GetHistogram().GetSampleCount()
+}
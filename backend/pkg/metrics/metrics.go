@@ -0,0 +1,95 @@
+// Package metrics collects Prometheus metrics for HTTP requests, user operations and the database,
+// and serves them for scraping.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace prefixes the names of all application metrics
+const Namespace = "arritech"
+
+// dbBuckets are finer than the HTTP buckets, since most queries take a few milliseconds
+var dbBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5}
+
+// Metrics owns a registry with the application metrics and the Go runtime and process collectors
+type Metrics struct {
+	registry       *prometheus.Registry
+	httpRequests   *prometheus.CounterVec
+	httpDuration   *prometheus.HistogramVec
+	userOperations *prometheus.CounterVec
+	dbQueries      *prometheus.HistogramVec
+}
+
+// New creates the metrics and registers them in a new registry
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests by route template, method and status code.",
+		}, []string{"route", "method", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by route template, method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		userOperations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "users",
+			Name:      "operations_total",
+			Help:      "User service operations by operation and outcome.",
+		}, []string{"operation", "outcome"}),
+		dbQueries: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "db",
+			Name:      "query_duration_seconds",
+			Help:      "Database query latency by operation, table and status.",
+			Buckets:   dbBuckets,
+		}, []string{"operation", "table", "status"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.userOperations,
+		m.dbQueries,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveHTTPRequest records a served request. route is the route template, e.g. /api/v1/users/:id,
+// so that the label doesn't grow with every user ID.
+func (m *Metrics) ObserveHTTPRequest(route, method string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(route, method, code).Inc()
+	m.httpDuration.WithLabelValues(route, method, code).Observe(duration.Seconds())
+}
+
+// ObserveUserOperation counts a user service operation with its outcome
+func (m *Metrics) ObserveUserOperation(operation, outcome string) {
+	m.userOperations.WithLabelValues(operation, outcome).Inc()
+}
+
+// RegisterDB exports the connection pool stats of db, labeled with the database name
+func (m *Metrics) RegisterDB(db *sql.DB, name string) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, name))
+}
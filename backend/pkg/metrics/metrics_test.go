@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	m := New()
+
+	m.ObserveHTTPRequest("/api/v1/users/:id", http.MethodGet, http.StatusOK, 20*time.Millisecond)
+	m.ObserveHTTPRequest("/api/v1/users/:id", http.MethodGet, http.StatusOK, 30*time.Millisecond)
+	m.ObserveUserOperation("create", "rejected_underage")
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("/api/v1/users/:id", "GET", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.userOperations.WithLabelValues("create", "rejected_underage")))
+
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, m.RegisterDB(db, "arritech_users"))
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(w.Body)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	for _, name := range []string{
+		`arritech_http_request_duration_seconds_count{method="GET",route="/api/v1/users/:id",status="200"} 2`,
+		`arritech_users_operations_total{operation="create",outcome="rejected_underage"} 1`,
+		`go_sql_open_connections{db_name="arritech_users"}`,
+		"go_goroutines",
+	} {
+		assert.True(t, strings.Contains(string(body), name), name)
+	}
+}
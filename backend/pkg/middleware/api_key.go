@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/problem"
+	"arritech-user-management/pkg/tenant"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader is the request header that carries an API key
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator verifies an API key and returns the caller's claims. It returns
+// auth.ErrInvalidToken or auth.ErrExpiredToken for keys that must be rejected.
+type APIKeyAuthenticator func(ctx context.Context, key string) (*auth.Claims, error)
+
+// APIKeyMiddleware authenticates requests that carry an X-API-Key header and places the
+// key's claims and organization in the request context. Requests without the header pass
+// through to the bearer token check.
+func APIKeyMiddleware(authenticate APIKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(APIKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		claims, err := authenticate(c.Request.Context(), key)
+		if err != nil {
+			switch {
+			case errors.Is(err, auth.ErrExpiredToken):
+				problem.Abort(c, problem.CodeAPIKeyExpired, "")
+			case errors.Is(err, auth.ErrInvalidToken):
+				problem.Abort(c, problem.CodeAPIKeyInvalid, "")
+			default:
+				problem.Abort(c, problem.CodeInternalError, "Failed to verify API key")
+			}
+			return
+		}
+
+		ctx := auth.NewContext(c.Request.Context(), claims)
+		if claims.OrganizationID != 0 {
+			ctx = tenant.NewContext(ctx, claims.OrganizationID)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// DenyAPIKeys rejects API keys on routes reserved for people, such as key management
+func DenyAPIKeys() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims, ok := auth.FromContext(c.Request.Context()); ok && claims.IsAPIKey() {
+			problem.Abort(c, problem.CodeAPIKeyNotAllowed, "")
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/tenant"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAPIKeyAuthenticator(ctx context.Context, key string) (*auth.Claims, error) {
+	switch key {
+	case "reader":
+		return &auth.Claims{Subject: "api-key:reader", APIKeyID: 1, OrganizationID: 4, Scopes: []string{"users:read"}}, nil
+	case "expired":
+		return nil, auth.ErrExpiredToken
+	case "broken":
+		return nil, errors.New("database unavailable")
+	default:
+		return nil, auth.ErrInvalidToken
+	}
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		apiKey         string
+		bearer         string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "Valid key with scope", apiKey: "reader", path: "/read", expectedStatus: http.StatusOK, expectedBody: "api-key:reader:4"},
+		{name: "Valid key without scope", apiKey: "reader", path: "/write", expectedStatus: http.StatusForbidden},
+		{name: "Key denied on admin route", apiKey: "reader", path: "/admin", expectedStatus: http.StatusForbidden},
+		{name: "Invalid key", apiKey: "forged", path: "/read", expectedStatus: http.StatusUnauthorized},
+		{name: "Expired key", apiKey: "expired", path: "/read", expectedStatus: http.StatusUnauthorized},
+		{name: "Authenticator failure", apiKey: "broken", path: "/read", expectedStatus: http.StatusInternalServerError},
+		{name: "Token uses roles instead of scopes", bearer: "valid", path: "/write", expectedStatus: http.StatusOK, expectedBody: "user-1:0"},
+		{name: "Token may use admin routes", bearer: "valid", path: "/admin", expectedStatus: http.StatusOK, expectedBody: "user-1:0"},
+		{name: "Neither key nor token", path: "/read", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			router := gin.New()
+			router.Use(APIKeyMiddleware(testAPIKeyAuthenticator))
+			router.Use(AuthMiddleware(testVerifier{"valid": {Subject: "user-1", Roles: []string{auth.RoleAdmin}}}, nil))
+			handler := func(c *gin.Context) {
+				claims, _ := auth.FromContext(c.Request.Context())
+				org, _ := tenant.FromContext(c.Request.Context())
+				c.String(http.StatusOK, claims.Subject+":"+strconv.FormatUint(uint64(org), 10))
+			}
+			router.GET("/read", RequirePermission(auth.DefaultPolicy(), auth.PermissionUsersRead), handler)
+			router.GET("/write", RequirePermission(auth.DefaultPolicy(), auth.PermissionUsersWrite), handler)
+			router.GET("/admin", DenyAPIKeys(), handler)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.apiKey != "" {
+				req.Header.Set(APIKeyHeader, tt.apiKey)
+			}
+			if tt.bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.bearer)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/problem"
+	"arritech-user-management/pkg/tenant"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultPublicPaths are reachable without a token: the health checks, the API docs and
+// GraphiQL, and the endpoints that carry their own signed token for callers that can't send
+// headers
+var DefaultPublicPaths = []string{
+	"/health",
+	"/livez",
+	"/readyz",
+	"/swagger/*",
+	"/graphiql",
+	"/api/v1/users/verify-email",
+	"/api/v1/users/birthdays.ics",
+}
+
+// TokenVerifier validates a bearer token and returns its claims
+type TokenVerifier interface {
+	Verify(token string) (*auth.Claims, error)
+}
+
+// AuthMiddleware requires a valid bearer token on every path except publicPaths, where a
+// trailing "*" matches any suffix. The token claims are placed in the request context, and an
+// org_id claim selects the organization ahead of the X-Organization header.
+func AuthMiddleware(verifier TokenVerifier, publicPaths []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isPublicPath(c.Request.URL.Path, publicPaths) {
+			c.Next()
+			return
+		}
+		// Already authenticated, e.g. with an API key
+		if _, ok := auth.FromContext(c.Request.Context()); ok {
+			c.Next()
+			return
+		}
+
+		token, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok {
+			c.Header("WWW-Authenticate", `Bearer`)
+			problem.Abort(c, problem.CodeAuthenticationRequired, "")
+			return
+		}
+
+		claims, err := verifier.Verify(token)
+		if err != nil {
+			code := problem.CodeTokenInvalid
+			if errors.Is(err, auth.ErrExpiredToken) {
+				code = problem.CodeTokenExpired
+			}
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			problem.Abort(c, code, "")
+			return
+		}
+
+		ctx := auth.NewContext(c.Request.Context(), claims)
+		if claims.OrganizationID != 0 {
+			ctx = tenant.NewContext(ctx, claims.OrganizationID)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>" header
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func isPublicPath(path string, publicPaths []string) bool {
+	for _, public := range publicPaths {
+		if prefix, ok := strings.CutSuffix(public, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+			continue
+		}
+		if path == public {
+			return true
+		}
+	}
+	return false
+}
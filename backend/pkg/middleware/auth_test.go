@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/problem"
+	"arritech-user-management/pkg/tenant"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type testVerifier map[string]*auth.Claims
+
+func (v testVerifier) Verify(token string) (*auth.Claims, error) {
+	if token == "expired" {
+		return nil, auth.ErrExpiredToken
+	}
+	claims, ok := v[token]
+	if !ok {
+		return nil, auth.ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	verifier := testVerifier{
+		"valid":      {Subject: "user-1"},
+		"tenant-org": {Subject: "user-2", OrganizationID: 9},
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		header         string
+		expectedStatus int
+		expectedBody   string
+		expectedChall  string
+		expectedCode   problem.Code
+	}{
+		{name: "Valid token", path: "/api/v1/users", header: "Bearer valid", expectedStatus: http.StatusOK, expectedBody: "user-1:0"},
+		{name: "Scheme is case-insensitive", path: "/api/v1/users", header: "bearer valid", expectedStatus: http.StatusOK, expectedBody: "user-1:0"},
+		{name: "Organization claim sets tenant", path: "/api/v1/users", header: "Bearer tenant-org", expectedStatus: http.StatusOK, expectedBody: "user-2:9"},
+		{name: "Missing token", path: "/api/v1/users", expectedStatus: http.StatusUnauthorized, expectedChall: "Bearer", expectedCode: problem.CodeAuthenticationRequired},
+		{name: "Wrong scheme", path: "/api/v1/users", header: "Basic dXNlcjpwYXNz", expectedStatus: http.StatusUnauthorized, expectedChall: "Bearer"},
+		{name: "Invalid token", path: "/api/v1/users", header: "Bearer forged", expectedStatus: http.StatusUnauthorized, expectedChall: `Bearer error="invalid_token"`, expectedCode: problem.CodeTokenInvalid},
+		{name: "Expired token", path: "/api/v1/users", header: "Bearer expired", expectedStatus: http.StatusUnauthorized, expectedChall: `Bearer error="invalid_token"`, expectedCode: problem.CodeTokenExpired},
+		{name: "Exact public path", path: "/health", expectedStatus: http.StatusOK, expectedBody: "anonymous"},
+		{name: "Wildcard public path", path: "/swagger/index.html", expectedStatus: http.StatusOK, expectedBody: "anonymous"},
+		{name: "Public path prefix is not a wildcard", path: "/healthz", expectedStatus: http.StatusUnauthorized, expectedChall: "Bearer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			router := gin.New()
+			router.Use(AuthMiddleware(verifier, []string{"/health", "/swagger/*"}))
+			router.NoRoute(func(c *gin.Context) {
+				claims, ok := auth.FromContext(c.Request.Context())
+				if !ok {
+					c.String(http.StatusOK, "anonymous")
+					return
+				}
+				org, _ := tenant.FromContext(c.Request.Context())
+				c.String(http.StatusOK, claims.Subject+":"+strconv.FormatUint(uint64(org), 10))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+			assert.Equal(t, tt.expectedChall, w.Header().Get("WWW-Authenticate"))
+			if tt.expectedCode != "" {
+				assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+				assert.Contains(t, w.Body.String(), `"code":"`+string(tt.expectedCode)+`"`)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"arritech-user-management/pkg/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimitMiddleware rejects requests whose body is larger than maxBytes with 413. A declared
+// Content-Length over the limit is rejected before the body is read; otherwise the body is
+// wrapped so that reading past the limit fails with an *http.MaxBytesError, which handlers
+// report as REQUEST_TOO_LARGE.
+func BodyLimitMiddleware(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > maxBytes {
+			problem.Abort(c, problem.CodeRequestTooLarge, fmt.Sprintf("Request body may have at most %d bytes", maxBytes))
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		c.Next()
+	}
+}
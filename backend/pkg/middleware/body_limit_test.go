@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		chunked        bool
+		expectedStatus int
+	}{
+		{name: "Body within the limit", body: strings.Repeat("a", 16), expectedStatus: http.StatusOK},
+		{name: "Declared length over the limit", body: strings.Repeat("a", 17), expectedStatus: http.StatusRequestEntityTooLarge},
+		{name: "Chunked body over the limit", body: strings.Repeat("a", 17), chunked: true, expectedStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handled := false
+			router := gin.New()
+			router.POST("/test", BodyLimitMiddleware(16), func(c *gin.Context) {
+				handled = true
+				if _, err := io.ReadAll(c.Request.Body); err != nil {
+					var tooLarge *http.MaxBytesError
+					assert.True(t, errors.As(err, &tooLarge))
+					c.Status(http.StatusRequestEntityTooLarge)
+					return
+				}
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			// Only bodies of unknown length reach the handler once they are over the limit
+			assert.Equal(t, tt.expectedStatus == http.StatusOK || tt.chunked, handled)
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig is the cross-origin policy of the API. It can be read from a JSON file with LoadCORSConfig.
+type CORSConfig struct {
+	// AllowedOrigins lists origins such as https://app.example.com. A leading "*." label matches
+	// any subdomain (https://*.example.com), and "*" alone matches every origin.
+	AllowedOrigins []string `json:"allowed_origins"`
+	AllowedMethods []string `json:"allowed_methods"`
+	// AllowedHeaders lists request headers clients may send; "*" allows any header
+	AllowedHeaders []string `json:"allowed_headers"`
+	// ExposedHeaders lists response headers scripts may read
+	ExposedHeaders []string `json:"exposed_headers"`
+	// AllowCredentials lets browsers send cookies and HTTP authentication cross-origin
+	AllowCredentials bool `json:"allow_credentials"`
+	// MaxAge is how many seconds browsers may cache a preflight response; zero omits the header
+	MaxAge int `json:"max_age"`
+}
+
+// DefaultCORSConfig allows every origin without credentials, which is enough for bearer tokens
+// and API keys but not for cookies
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
+		AllowedHeaders: []string{
+			"Accept", "Authorization", "Cache-Control", "Content-Type",
+			"X-Requested-With", "X-Organization", "X-API-Key", "Idempotency-Key", "X-Request-ID", "traceparent", "tracestate",
+		},
+		ExposedHeaders: []string{
+			"Content-Disposition", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After",
+			"Idempotent-Replayed", "X-Request-ID", "traceparent",
+		},
+		MaxAge: 600,
+	}
+}
+
+// LoadCORSConfig reads a CORS policy from a JSON file. Fields missing from the file keep their
+// defaults. An empty path returns the default policy.
+func LoadCORSConfig(path string) (CORSConfig, error) {
+	config := DefaultCORSConfig()
+	if path == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("failed to read CORS config: %w", err)
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse CORS config: %w", err)
+	}
+	return config, nil
+}
+
+// Validate rejects origins that can never match and policies that browsers refuse
+func (c CORSConfig) Validate() error {
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			// Browsers reject "Access-Control-Allow-Origin: *" on credentialed requests, and
+			// echoing every origin instead would let any site act as the signed in user
+			if c.AllowCredentials {
+				return fmt.Errorf("CORS origin * can't be combined with credentials; list the allowed origins")
+			}
+			continue
+		}
+		if _, ok := parseOriginPattern(origin); !ok {
+			return fmt.Errorf("invalid CORS origin %q: must be scheme://host[:port], optionally with a leading *. label", origin)
+		}
+	}
+	if c.MaxAge < 0 {
+		return fmt.Errorf("CORS max age must not be negative")
+	}
+	return nil
+}
+
+// originPattern matches an origin exactly or, with wildcard set, any subdomain of host
+type originPattern struct {
+	scheme   string
+	host     string
+	port     string
+	wildcard bool
+}
+
+func parseOriginPattern(origin string) (originPattern, bool) {
+	u, err := url.Parse(strings.ToLower(origin))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+		u.User != nil || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return originPattern{}, false
+	}
+
+	p := originPattern{scheme: u.Scheme, host: u.Hostname(), port: u.Port()}
+	if strings.HasPrefix(p.host, "*.") {
+		p.wildcard = true
+		p.host = p.host[2:]
+	}
+	if p.host == "" || strings.Contains(p.host, "*") {
+		return originPattern{}, false
+	}
+	return p, true
+}
+
+func (p originPattern) matches(origin originPattern) bool {
+	if origin.scheme != p.scheme || origin.port != p.port {
+		return false
+	}
+	if !p.wildcard {
+		return origin.host == p.host
+	}
+	sub := strings.TrimSuffix(origin.host, "."+p.host)
+	return sub != origin.host && sub != ""
+}
+
+// CORSMiddleware applies config to cross-origin requests. An allowed origin is echoed back with
+// Vary: Origin, so caches keep the responses of different origins apart. Preflight requests are
+// answered directly: 204 when the origin, method and headers are allowed, otherwise 403.
+// Requests from origins that aren't allowed are served without CORS headers, so browsers hide
+// the response from the calling script.
+func CORSMiddleware(config CORSConfig) gin.HandlerFunc {
+	anyOrigin := false
+	var patterns []originPattern
+	for _, origin := range config.AllowedOrigins {
+		if origin == "*" {
+			anyOrigin = true
+		} else if p, ok := parseOriginPattern(origin); ok {
+			patterns = append(patterns, p)
+		}
+	}
+	methods := toSet(config.AllowedMethods, strings.ToUpper)
+	headers := toSet(config.AllowedHeaders, strings.ToLower)
+	anyHeader := headers["*"]
+	allowMethods := strings.Join(config.AllowedMethods, ", ")
+	exposeHeaders := strings.Join(config.ExposedHeaders, ", ")
+	// Without credentials "*" answers every origin the same way, so responses don't vary by origin
+	literalWildcard := anyOrigin && !config.AllowCredentials
+
+	allowed := func(origin string) bool {
+		if anyOrigin {
+			return true
+		}
+		o, ok := parseOriginPattern(origin)
+		if !ok || o.wildcard {
+			return false
+		}
+		for _, p := range patterns {
+			if p.matches(o) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		origin := c.GetHeader("Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if !literalWildcard {
+			header.Add("Vary", "Origin")
+		}
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if origin == "" {
+			c.Next()
+			return
+		}
+		if !allowed(origin) {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if literalWildcard {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if config.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if exposeHeaders != "" {
+				header.Set("Access-Control-Expose-Headers", exposeHeaders)
+			}
+			c.Next()
+			return
+		}
+
+		if !methods[strings.ToUpper(c.GetHeader("Access-Control-Request-Method"))] {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		requested := splitHeaderList(c.GetHeader("Access-Control-Request-Headers"))
+		if !anyHeader {
+			for _, name := range requested {
+				if !headers[strings.ToLower(name)] {
+					c.AbortWithStatus(http.StatusForbidden)
+					return
+				}
+			}
+		}
+
+		header.Set("Access-Control-Allow-Methods", allowMethods)
+		if len(requested) > 0 {
+			// Echoing the requested headers also works for "*", which browsers ignore with credentials
+			header.Set("Access-Control-Allow-Headers", strings.Join(requested, ", "))
+		}
+		if config.MaxAge > 0 {
+			header.Set("Access-Control-Max-Age", strconv.Itoa(config.MaxAge))
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+func toSet(values []string, normalize func(string) string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[normalize(strings.TrimSpace(v))] = true
+	}
+	return set
+}
+
+// splitHeaderList splits a comma-separated header value, dropping blanks
+func splitHeaderList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func corsRouter(config CORSConfig) *gin.Engine {
+	router := gin.New()
+	router.Use(CORSMiddleware(config))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "test"})
+	})
+	return router
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	config := DefaultCORSConfig()
+	config.AllowedOrigins = []string{"https://*.arritech.com", "http://localhost:5173"}
+	config.AllowCredentials = true
+	router := corsRouter(config)
+
+	tests := []struct {
+		name           string
+		method         string
+		origin         string
+		requestMethod  string
+		requestHeaders string
+		expectedStatus int
+		expectedOrigin string
+	}{
+		{name: "Same-origin request", method: http.MethodGet, expectedStatus: http.StatusOK},
+		{name: "Exact origin", method: http.MethodGet, origin: "http://localhost:5173", expectedStatus: http.StatusOK, expectedOrigin: "http://localhost:5173"},
+		{name: "Wildcard subdomain", method: http.MethodGet, origin: "https://app.arritech.com", expectedStatus: http.StatusOK, expectedOrigin: "https://app.arritech.com"},
+		{name: "Nested subdomain", method: http.MethodGet, origin: "https://eu.admin.arritech.com", expectedStatus: http.StatusOK, expectedOrigin: "https://eu.admin.arritech.com"},
+		{name: "Wildcard doesn't match the bare domain", method: http.MethodGet, origin: "https://arritech.com", expectedStatus: http.StatusOK},
+		{name: "Wildcard doesn't match a lookalike domain", method: http.MethodGet, origin: "https://evilarritech.com", expectedStatus: http.StatusOK},
+		{name: "Scheme must match", method: http.MethodGet, origin: "http://app.arritech.com", expectedStatus: http.StatusOK},
+		{name: "Port must match", method: http.MethodGet, origin: "http://localhost:8081", expectedStatus: http.StatusOK},
+		{name: "Unknown origin", method: http.MethodGet, origin: "https://evil.com", expectedStatus: http.StatusOK},
+		{
+			name: "Preflight", method: http.MethodOptions, origin: "https://app.arritech.com",
+			requestMethod: http.MethodPatch, requestHeaders: "content-type, authorization, x-organization",
+			expectedStatus: http.StatusNoContent, expectedOrigin: "https://app.arritech.com",
+		},
+		{name: "Preflight from unknown origin", method: http.MethodOptions, origin: "https://evil.com", requestMethod: http.MethodGet, expectedStatus: http.StatusForbidden},
+		{name: "Preflight with disallowed method", method: http.MethodOptions, origin: "https://app.arritech.com", requestMethod: "TRACE", expectedStatus: http.StatusForbidden, expectedOrigin: "https://app.arritech.com"},
+		{name: "Preflight with disallowed header", method: http.MethodOptions, origin: "https://app.arritech.com", requestMethod: http.MethodGet, requestHeaders: "X-Secret", expectedStatus: http.StatusForbidden, expectedOrigin: "https://app.arritech.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/test", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.requestMethod != "" {
+				req.Header.Set("Access-Control-Request-Method", tt.requestMethod)
+			}
+			if tt.requestHeaders != "" {
+				req.Header.Set("Access-Control-Request-Headers", tt.requestHeaders)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedOrigin, w.Header().Get("Access-Control-Allow-Origin"))
+			assert.Contains(t, w.Header().Values("Vary"), "Origin")
+			if tt.expectedOrigin == "" {
+				assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+				return
+			}
+			assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+
+			if tt.expectedStatus == http.StatusNoContent {
+				assert.Equal(t, "GET, POST, PUT, PATCH, DELETE", w.Header().Get("Access-Control-Allow-Methods"))
+				assert.Equal(t, "content-type, authorization, x-organization", w.Header().Get("Access-Control-Allow-Headers"))
+				assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+				assert.Empty(t, w.Body.String())
+			} else if tt.method == http.MethodGet {
+				assert.Contains(t, w.Header().Get("Access-Control-Expose-Headers"), "RateLimit-Remaining")
+				assert.Contains(t, w.Header().Get("Access-Control-Expose-Headers"), "X-Request-ID")
+			}
+		})
+	}
+}
+
+func TestCORSMiddleware_AnyOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := corsRouter(DefaultCORSConfig())
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Origin", "https://example.com")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Empty(t, w.Header().Values("Vary"))
+}
+
+func TestCORSConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		origins     []string
+		credentials bool
+		expectError bool
+	}{
+		{name: "Any origin", origins: []string{"*"}},
+		{name: "Origins and wildcard subdomains", origins: []string{"https://app.example.com", "https://*.example.com", "http://localhost:5173"}, credentials: true},
+		{name: "Any origin with credentials", origins: []string{"*"}, credentials: true, expectError: true},
+		{name: "Missing scheme", origins: []string{"app.example.com"}, expectError: true},
+		{name: "Path", origins: []string{"https://example.com/app"}, expectError: true},
+		{name: "Wildcard inside the host", origins: []string{"https://app.*.example.com"}, expectError: true},
+		{name: "Bare wildcard label", origins: []string{"https://*"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultCORSConfig()
+			config.AllowedOrigins = tt.origins
+			config.AllowCredentials = tt.credentials
+
+			err := config.Validate()
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLoadCORSConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cors.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"allowed_origins": ["https://*.arritech.com"], "allow_credentials": true, "max_age": 3600}`), 0o600))
+
+	config, err := LoadCORSConfig(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://*.arritech.com"}, config.AllowedOrigins)
+	assert.True(t, config.AllowCredentials)
+	assert.Equal(t, 3600, config.MaxAge)
+	// Fields missing from the file keep their defaults
+	assert.Equal(t, DefaultCORSConfig().AllowedMethods, config.AllowedMethods)
+
+	_, err = LoadCORSConfig(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"arritech-user-management/pkg/idempotency"
+	"arritech-user-management/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// maxIdempotencyKeyLength bounds the Idempotency-Key header
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyMiddleware makes POST, PUT, PATCH and DELETE requests that carry an Idempotency-Key
+// header safe to retry. The first request with a key runs and its response is stored; retries with
+// the same key and request get the stored response with an Idempotent-Replayed header. A key reused
+// for a different request gets 422, and a retry while the first request is still running gets 409.
+// Keys belong to the caller, so different callers can't see each other's responses.
+//
+// Server errors, rate limiting and authorization failures don't reflect the outcome of the
+// operation, so those responses free the key for another attempt instead of being stored.
+// The body is read to fingerprint the request, so bodies over maxBodyBytes are rejected with 413.
+func IdempotencyMiddleware(store idempotency.Store, maxBodyBytes int64, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientKey := c.GetHeader(idempotency.Header)
+		if clientKey == "" || !isMutation(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(clientKey) > maxIdempotencyKeyLength {
+			problem.Abort(c, problem.CodeIdempotencyKeyInvalid, "Idempotency-Key must be at most 255 characters")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBodyBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				problem.Abort(c, problem.CodeRequestTooLarge, fmt.Sprintf("Requests with an Idempotency-Key may have at most %d bytes of body", maxBodyBytes))
+				return
+			}
+			problem.Abort(c, problem.CodeMalformedRequest, "Failed to read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		key := hashParts(callerIdentity(c), clientKey)
+		fingerprint := hashParts(c.Request.Method, c.Request.URL.RequestURI(), c.GetHeader("X-Organization"), c.ContentType(), string(body))
+
+		record, err := store.Reserve(c.Request.Context(), key, fingerprint)
+		if err != nil {
+			logger.WithError(err).Error("Failed to reserve idempotency key")
+			problem.Abort(c, problem.CodeInternalError, "Failed to process Idempotency-Key")
+			return
+		}
+		if record != nil {
+			switch {
+			case record.Fingerprint != fingerprint:
+				problem.Abort(c, problem.CodeIdempotencyKeyReused, "Idempotency-Key was already used for a different request")
+			case record.Response == nil:
+				c.Header("Retry-After", "1")
+				problem.Abort(c, problem.CodeIdempotencyKeyInProgress, "A request with this Idempotency-Key is still in progress")
+			default:
+				c.Header(idempotency.ReplayedHeader, "true")
+				c.Data(record.Response.StatusCode, record.Response.ContentType, record.Response.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		// The outcome is stored even if the client went away, since that's when it retries
+		ctx := context.WithoutCancel(c.Request.Context())
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		stored := false
+		defer func() {
+			if !stored {
+				if err := store.Release(ctx, key); err != nil {
+					logger.WithError(err).Error("Failed to release idempotency key")
+				}
+			}
+		}()
+
+		c.Next()
+
+		status := writer.Status()
+		if !storableStatus(status) {
+			return
+		}
+		response := idempotency.Response{
+			StatusCode:  status,
+			ContentType: writer.Header().Get("Content-Type"),
+			Body:        writer.body.Bytes(),
+		}
+		if err := store.Complete(ctx, key, response); err != nil {
+			logger.WithError(err).Error("Failed to store idempotent response")
+			return
+		}
+		stored = true
+	}
+}
+
+func isMutation(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func storableStatus(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return false
+	}
+	return status < http.StatusInternalServerError
+}
+
+// hashParts hashes values so that their boundaries are unambiguous
+func hashParts(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingWriter keeps a copy of the response body
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
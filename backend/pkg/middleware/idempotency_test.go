@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/idempotency"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryIdempotencyStore keeps idempotency records in a map
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*idempotency.Record
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: make(map[string]*idempotency.Record)}
+}
+
+func (s *memoryIdempotencyStore) Reserve(_ context.Context, key, fingerprint string) (*idempotency.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record, ok := s.records[key]; ok {
+		return record, nil
+	}
+	s.records[key] = &idempotency.Record{Fingerprint: fingerprint}
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(_ context.Context, key string, response idempotency.Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key].Response = &response
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+func newIdempotentRouter(store idempotency.Store, claims *auth.Claims, calls *int, status int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	router := gin.New()
+	if claims != nil {
+		router.Use(func(c *gin.Context) {
+			c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), claims))
+		})
+	}
+	router.Use(IdempotencyMiddleware(store, 64, logger))
+	handler := func(c *gin.Context) {
+		*calls++
+		body, _ := io.ReadAll(c.Request.Body)
+		c.JSON(status, gin.H{"call": *calls, "body": string(body)})
+	}
+	router.POST("/users", handler)
+	router.GET("/users", handler)
+	return router
+}
+
+func idempotentRequest(router *gin.Engine, method, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/users", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(idempotency.Header, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	t.Run("Replays the stored response to a retry", func(t *testing.T) {
+		calls := 0
+		router := newIdempotentRouter(newMemoryIdempotencyStore(), nil, &calls, http.StatusCreated)
+
+		first := idempotentRequest(router, http.MethodPost, "key-1", `{"name":"Jane"}`)
+		retry := idempotentRequest(router, http.MethodPost, "key-1", `{"name":"Jane"}`)
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.JSONEq(t, `{"call":1,"body":"{\"name\":\"Jane\"}"}`, retry.Body.String())
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+		assert.Equal(t, "application/json; charset=utf-8", retry.Header().Get("Content-Type"))
+		assert.Equal(t, "true", retry.Header().Get(idempotency.ReplayedHeader))
+		assert.Empty(t, first.Header().Get(idempotency.ReplayedHeader))
+	})
+
+	t.Run("Rejects a key reused for a different request", func(t *testing.T) {
+		calls := 0
+		router := newIdempotentRouter(newMemoryIdempotencyStore(), nil, &calls, http.StatusCreated)
+
+		idempotentRequest(router, http.MethodPost, "key-1", `{"name":"Jane"}`)
+		w := idempotentRequest(router, http.MethodPost, "key-1", `{"name":"John"}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"IDEMPOTENCY_KEY_REUSED"`)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Rejects a retry while the first request is in progress", func(t *testing.T) {
+		calls := 0
+		store := newMemoryIdempotencyStore()
+		router := newIdempotentRouter(store, nil, &calls, http.StatusCreated)
+
+		// Reserve the key the way the first request would
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = req
+		fingerprint := hashParts(http.MethodPost, "/users", "", "application/json", `{}`)
+		_, _ = store.Reserve(context.Background(), hashParts(callerIdentity(c), "key-1"), fingerprint)
+
+		w := idempotentRequest(router, http.MethodPost, "key-1", `{}`)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), `"code":"IDEMPOTENCY_KEY_IN_PROGRESS"`)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("Frees the key after a server error", func(t *testing.T) {
+		calls := 0
+		store := newMemoryIdempotencyStore()
+		router := newIdempotentRouter(store, nil, &calls, http.StatusInternalServerError)
+
+		idempotentRequest(router, http.MethodPost, "key-1", `{}`)
+		idempotentRequest(router, http.MethodPost, "key-1", `{}`)
+
+		assert.Equal(t, 2, calls)
+		assert.Empty(t, store.records)
+	})
+
+	t.Run("Keys belong to the caller", func(t *testing.T) {
+		calls := 0
+		store := newMemoryIdempotencyStore()
+		jane := newIdempotentRouter(store, &auth.Claims{Subject: "jane"}, &calls, http.StatusCreated)
+		john := newIdempotentRouter(store, &auth.Claims{Subject: "john"}, &calls, http.StatusCreated)
+
+		idempotentRequest(jane, http.MethodPost, "key-1", `{}`)
+		w := idempotentRequest(john, http.MethodPost, "key-1", `{}`)
+
+		assert.Equal(t, 2, calls)
+		assert.Empty(t, w.Header().Get(idempotency.ReplayedHeader))
+	})
+
+	t.Run("Ignores reads and requests without a key", func(t *testing.T) {
+		calls := 0
+		store := newMemoryIdempotencyStore()
+		router := newIdempotentRouter(store, nil, &calls, http.StatusOK)
+
+		idempotentRequest(router, http.MethodGet, "key-1", "")
+		idempotentRequest(router, http.MethodGet, "key-1", "")
+		idempotentRequest(router, http.MethodPost, "", `{}`)
+		idempotentRequest(router, http.MethodPost, "", `{}`)
+
+		assert.Equal(t, 4, calls)
+		assert.Empty(t, store.records)
+	})
+
+	t.Run("Rejects bodies over the limit", func(t *testing.T) {
+		calls := 0
+		store := newMemoryIdempotencyStore()
+		router := newIdempotentRouter(store, nil, &calls, http.StatusCreated)
+
+		w := idempotentRequest(router, http.MethodPost, "key-1", `{"name":"`+strings.Repeat("a", 64)+`"}`)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"REQUEST_TOO_LARGE"`)
+		assert.Equal(t, 0, calls)
+		assert.Empty(t, store.records)
+	})
+
+	t.Run("Rejects overlong keys", func(t *testing.T) {
+		calls := 0
+		router := newIdempotentRouter(newMemoryIdempotencyStore(), nil, &calls, http.StatusCreated)
+
+		w := idempotentRequest(router, http.MethodPost, strings.Repeat("k", 256), `{}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, 0, calls)
+	})
+}
@@ -3,14 +3,15 @@ package middleware
 import (
 	"time"
 
+	pkglogger "arritech-user-management/pkg/logger"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
-// RequestLoggingMiddleware logs HTTP requests
+// RequestLoggingMiddleware logs HTTP requests, with the request ID when RequestIDMiddleware runs first
 func RequestLoggingMiddleware(logger *logrus.Logger) gin.HandlerFunc {
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		logger.WithFields(logrus.Fields{
+		pkglogger.FromContext(param.Request.Context(), logger).WithFields(logrus.Fields{
 			"timestamp":  param.TimeStamp.Format(time.RFC3339),
 			"status":     param.StatusCode,
 			"latency":    param.Latency,
@@ -25,20 +26,3 @@ func RequestLoggingMiddleware(logger *logrus.Logger) gin.HandlerFunc {
 		return ""
 	})
 }
-
-// CORSMiddleware handles CORS
-func CORSMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-
-		c.Next()
-	}
-}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels requests that matched no route, so unknown paths can't grow the metrics
+const unmatchedRoute = "unmatched"
+
+// HTTPMetrics records served requests
+type HTTPMetrics interface {
+	// ObserveHTTPRequest records a request by route template, method and response status
+	ObserveHTTPRequest(route, method string, status int, duration time.Duration)
+}
+
+// MetricsMiddleware records the count and latency of every request, labeled with the route
+// template rather than the path so that IDs don't become labels
+func MetricsMiddleware(metrics HTTPMetrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		metrics.ObserveHTTPRequest(route, c.Request.Method, c.Writer.Status(), time.Since(start))
+	}
+}
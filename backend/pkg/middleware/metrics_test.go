@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockHTTPMetrics struct {
+	mock.Mock
+}
+
+func (m *mockHTTPMetrics) ObserveHTTPRequest(route, method string, status int, duration time.Duration) {
+	m.Called(route, method, status, duration)
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		path          string
+		expectedRoute string
+		expectedCode  int
+	}{
+		{name: "Labels with the route template", path: "/users/42", expectedRoute: "/users/:id", expectedCode: http.StatusOK},
+		{name: "Labels unknown paths as unmatched", path: "/nope/42", expectedRoute: "unmatched", expectedCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := &mockHTTPMetrics{}
+			metrics.On("ObserveHTTPRequest", tt.expectedRoute, http.MethodGet, tt.expectedCode, mock.AnythingOfType("time.Duration")).Once()
+
+			router := gin.New()
+			router.Use(MetricsMiddleware(metrics))
+			router.GET("/users/:id", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			metrics.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// Limit is a token bucket: it holds up to Burst requests and refills at Requests per Period.
+// The zero Limit disables rate limiting.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	// Burst is the bucket capacity; zero means Requests
+	Burst int
+}
+
+// Enabled reports whether the limit restricts anything
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+func (l Limit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.Requests)
+}
+
+// rate returns the refill rate in requests per second
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// ParseLimit parses a limit written as requests per period, e.g. "300/1m". An empty string,
+// "0" or "off" yields the zero Limit.
+func ParseLimit(value string) (Limit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" || value == "off" {
+		return Limit{}, nil
+	}
+
+	requests, period, ok := strings.Cut(value, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: expected requests/period, e.g. 300/1m", value)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: requests must be a positive number", value)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(period))
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: period must be a positive duration", value)
+	}
+	return Limit{Requests: n, Period: d}, nil
+}
+
+// RateLimitResult is the state of a bucket after taking a request from it
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed, for rejected requests
+	RetryAfter time.Duration
+}
+
+// RateLimitStore keeps token buckets. A store shared between instances, such as one backed by
+// Redis, enforces limits across all of them; MemoryRateLimitStore limits each instance on its own.
+type RateLimitStore interface {
+	// Take removes one request from the bucket named key, creating it full if it doesn't exist
+	Take(ctx context.Context, key string, limit Limit) (RateLimitResult, error)
+}
+
+// rateLimitSweepInterval is how often the memory store drops buckets that have refilled
+const rateLimitSweepInterval = time.Minute
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket will be back at capacity; it can be dropped from then on
+	full time.Time
+}
+
+// MemoryRateLimitStore keeps token buckets in process memory
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryRateLimitStore) Take(_ context.Context, key string, limit Limit) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	capacity, rate := limit.capacity(), limit.rate()
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: capacity, updated: now}
+		s.buckets[key] = bucket
+	}
+	bucket.tokens = math.Min(capacity, bucket.tokens+now.Sub(bucket.updated).Seconds()*rate)
+	bucket.updated = now
+
+	result := RateLimitResult{Limit: int(capacity)}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - bucket.tokens) / rate)
+	}
+	result.Remaining = int(bucket.tokens)
+	result.Reset = secondsToDuration((capacity - bucket.tokens) / rate)
+	bucket.full = now.Add(result.Reset)
+	return result, nil
+}
+
+// sweep drops buckets that are full again, which behave like new ones
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, bucket := range s.buckets {
+		if !now.Before(bucket.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// RateLimitConfig sets the limits of a route group
+type RateLimitConfig struct {
+	// Group names the buckets, so every route group is limited separately
+	Group string
+	// Read limits GET, HEAD and OPTIONS requests; Write limits all others
+	Read  Limit
+	Write Limit
+}
+
+// RateLimitMiddleware limits requests with a token bucket per caller: API keys, token subjects
+// and, for anonymous requests, client IPs each get their own bucket. Responses carry
+// RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers; rejected requests get
+// 429 with Retry-After. If the store fails, requests are let through.
+func RateLimitMiddleware(store RateLimitStore, config RateLimitConfig, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, kind := config.Write, "write"
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			limit, kind = config.Read, "read"
+		}
+		if !limit.Enabled() {
+			c.Next()
+			return
+		}
+
+		if !takeRequest(c, store, config.Group+":"+kind+":"+callerIdentity(c), limit, logger) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// ClientIPRateLimitMiddleware limits all requests with a token bucket per client IP, whoever they
+// claim to be. Registered ahead of authentication, it also limits requests with bad credentials,
+// which never reach the limits of the route groups. The zero Limit disables it.
+func ClientIPRateLimitMiddleware(store RateLimitStore, limit Limit, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !limit.Enabled() {
+			c.Next()
+			return
+		}
+		if !takeRequest(c, store, "client:ip:"+c.ClientIP(), limit, logger) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// takeRequest takes the request from the bucket named key and sets the rate limit headers,
+// aborting with 429 and returning false when the bucket is empty
+func takeRequest(c *gin.Context, store RateLimitStore, key string, limit Limit, logger *logrus.Logger) bool {
+	result, err := store.Take(c.Request.Context(), key, limit)
+	if err != nil {
+		logger.WithError(err).WithField("bucket", key).Error("Rate limit store failed, allowing request")
+		return true
+	}
+
+	header := c.Writer.Header()
+	header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+	if !result.Allowed {
+		header.Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+		logger.WithFields(logrus.Fields{"bucket": key, "path": c.Request.URL.Path}).Warn("Rate limit exceeded")
+		problem.Abort(c, problem.CodeRateLimited, "")
+		return false
+	}
+	return true
+}
+
+// callerIdentity names the caller: an API key, a token subject or, for anonymous requests, the
+// client IP. Client IPs come from X-Forwarded-For only when the request passed through a trusted
+// proxy, see gin.Engine.SetTrustedProxies.
+func callerIdentity(c *gin.Context) string {
+	if claims, ok := auth.FromContext(c.Request.Context()); ok {
+		if claims.IsAPIKey() {
+			return "key:" + strconv.FormatUint(uint64(claims.APIKeyID), 10)
+		}
+		if claims.Subject != "" {
+			return "user:" + claims.Subject
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
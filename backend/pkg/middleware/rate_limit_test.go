@@ -0,0 +1,233 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected Limit
+		wantErr  bool
+	}{
+		{value: "300/1m", expected: Limit{Requests: 300, Period: time.Minute}},
+		{value: " 10 / 1s ", expected: Limit{Requests: 10, Period: time.Second}},
+		{value: "", expected: Limit{}},
+		{value: "off", expected: Limit{}},
+		{value: "0", expected: Limit{}},
+		{value: "300", wantErr: true},
+		{value: "-1/1m", wantErr: true},
+		{value: "10/minute", wantErr: true},
+		{value: "10/0s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			limit, err := ParseLimit(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, limit)
+		})
+	}
+}
+
+func TestMemoryRateLimitStore_Take(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryRateLimitStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Requests: 60, Period: time.Minute, Burst: 3}
+	ctx := context.Background()
+
+	for i := 2; i >= 0; i-- {
+		result, err := store.Take(ctx, "a", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 3, result.Limit)
+		assert.Equal(t, i, result.Remaining)
+	}
+
+	result, _ := store.Take(ctx, "a", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.Reset)
+
+	// Other keys have their own bucket
+	result, _ = store.Take(ctx, "b", limit)
+	assert.True(t, result.Allowed)
+
+	// One request per second refills
+	now = now.Add(time.Second)
+	result, _ = store.Take(ctx, "a", limit)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// Refilled buckets are dropped and start full again
+	now = now.Add(2 * time.Minute)
+	result, _ = store.Take(ctx, "c", limit)
+	assert.True(t, result.Allowed)
+	assert.NotContains(t, store.buckets, "a")
+	assert.NotContains(t, store.buckets, "b")
+}
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Take(context.Context, string, Limit) (RateLimitResult, error) {
+	return RateLimitResult{}, errors.New("connection refused")
+}
+
+func newRateLimitedRouter(store RateLimitStore, claims *auth.Claims) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	router := gin.New()
+	if err := router.SetTrustedProxies([]string{"10.0.0.1"}); err != nil {
+		panic(err)
+	}
+	if claims != nil {
+		router.Use(func(c *gin.Context) {
+			c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), claims))
+		})
+	}
+	router.Use(RateLimitMiddleware(store, RateLimitConfig{
+		Group: "users",
+		Read:  Limit{Requests: 2, Period: time.Minute},
+		Write: Limit{Requests: 1, Period: time.Minute},
+	}, logger))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/users", ok)
+	router.POST("/users", ok)
+	return router
+}
+
+func rateLimitedRequest(router *gin.Engine, method, remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/users", nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	t.Run("Rejects requests over the limit with 429", func(t *testing.T) {
+		router := newRateLimitedRouter(NewMemoryRateLimitStore(), nil)
+
+		w := rateLimitedRequest(router, http.MethodGet, "192.0.2.1:1234", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", w.Header().Get("RateLimit-Reset"))
+
+		rateLimitedRequest(router, http.MethodGet, "192.0.2.1:1234", "")
+		w = rateLimitedRequest(router, http.MethodGet, "192.0.2.1:1234", "")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "30", w.Header().Get("Retry-After"))
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `"code":"RATE_LIMITED"`)
+	})
+
+	t.Run("Limits reads and writes separately", func(t *testing.T) {
+		router := newRateLimitedRouter(NewMemoryRateLimitStore(), nil)
+
+		assert.Equal(t, http.StatusOK, rateLimitedRequest(router, http.MethodPost, "192.0.2.1:1234", "").Code)
+		assert.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(router, http.MethodPost, "192.0.2.1:1234", "").Code)
+		assert.Equal(t, http.StatusOK, rateLimitedRequest(router, http.MethodGet, "192.0.2.1:1234", "").Code)
+	})
+
+	t.Run("Client IPs have separate buckets", func(t *testing.T) {
+		router := newRateLimitedRouter(NewMemoryRateLimitStore(), nil)
+
+		rateLimitedRequest(router, http.MethodPost, "192.0.2.1:1234", "")
+		assert.Equal(t, http.StatusOK, rateLimitedRequest(router, http.MethodPost, "192.0.2.2:1234", "").Code)
+	})
+
+	t.Run("Trusts X-Forwarded-For only from trusted proxies", func(t *testing.T) {
+		router := newRateLimitedRouter(NewMemoryRateLimitStore(), nil)
+
+		// Behind the load balancer, clients are told apart by the forwarded address
+		assert.Equal(t, http.StatusOK, rateLimitedRequest(router, http.MethodPost, "10.0.0.1:80", "198.51.100.1").Code)
+		assert.Equal(t, http.StatusOK, rateLimitedRequest(router, http.MethodPost, "10.0.0.1:80", "198.51.100.2").Code)
+		assert.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(router, http.MethodPost, "10.0.0.1:80", "198.51.100.1").Code)
+
+		// A client can't escape its bucket by forging the header
+		assert.Equal(t, http.StatusOK, rateLimitedRequest(router, http.MethodPost, "192.0.2.1:1234", "198.51.100.3").Code)
+		assert.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(router, http.MethodPost, "192.0.2.1:1234", "198.51.100.4").Code)
+	})
+
+	t.Run("API keys and users have their own buckets", func(t *testing.T) {
+		store := NewMemoryRateLimitStore()
+		key := newRateLimitedRouter(store, &auth.Claims{APIKeyID: 7})
+		user := newRateLimitedRouter(store, &auth.Claims{Subject: "jane"})
+
+		assert.Equal(t, http.StatusOK, rateLimitedRequest(key, http.MethodPost, "192.0.2.1:1234", "").Code)
+		assert.Equal(t, http.StatusOK, rateLimitedRequest(user, http.MethodPost, "192.0.2.1:1234", "").Code)
+		assert.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(key, http.MethodPost, "192.0.2.9:1234", "").Code)
+		assert.Contains(t, store.buckets, "users:write:key:7")
+		assert.Contains(t, store.buckets, "users:write:user:jane")
+	})
+
+	t.Run("Lets requests through when the store fails", func(t *testing.T) {
+		router := newRateLimitedRouter(failingRateLimitStore{}, nil)
+
+		w := rateLimitedRequest(router, http.MethodGet, "192.0.2.1:1234", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	})
+}
+
+func TestClientIPRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	newRouter := func(store RateLimitStore, limit Limit) *gin.Engine {
+		router := gin.New()
+		router.Use(ClientIPRateLimitMiddleware(store, limit, logger))
+		// Stands in for authentication rejecting bad credentials
+		router.Use(func(c *gin.Context) {
+			problem.Abort(c, problem.CodeTokenInvalid, "")
+		})
+		router.POST("/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+		return router
+	}
+
+	t.Run("Limits requests that fail authentication", func(t *testing.T) {
+		router := newRouter(NewMemoryRateLimitStore(), Limit{Requests: 2, Period: time.Minute})
+
+		assert.Equal(t, http.StatusUnauthorized, rateLimitedRequest(router, http.MethodPost, "192.0.2.1:1234", "").Code)
+		assert.Equal(t, http.StatusUnauthorized, rateLimitedRequest(router, http.MethodPost, "192.0.2.1:1234", "").Code)
+		w := rateLimitedRequest(router, http.MethodPost, "192.0.2.1:1234", "")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "30", w.Header().Get("Retry-After"))
+
+		assert.Equal(t, http.StatusUnauthorized, rateLimitedRequest(router, http.MethodPost, "192.0.2.2:1234", "").Code)
+	})
+
+	t.Run("Zero limit disables it", func(t *testing.T) {
+		router := newRouter(NewMemoryRateLimitStore(), Limit{})
+
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, http.StatusUnauthorized, rateLimitedRequest(router, http.MethodPost, "192.0.2.1:1234", "").Code)
+		}
+	})
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission rejects callers without permission under policy with 403, naming the
+// missing permission. API keys need the permission as a scope; token subjects need a role
+// that grants it. Requests without a caller pass, which only happens when authentication
+// is disabled.
+func RequirePermission(policy *auth.Policy, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := auth.FromContext(c.Request.Context())
+		if ok && !policy.Allows(claims, permission) {
+			problem.Abort(c, problem.CodePermissionDenied, "Missing permission "+permission)
+			return
+		}
+		c.Next()
+	}
+}
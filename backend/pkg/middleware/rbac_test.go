@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"arritech-user-management/pkg/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name           string
+		claims         *auth.Claims
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "Role grants permission", claims: &auth.Claims{Subject: "admin", Roles: []string{auth.RoleAdmin}}, expectedStatus: http.StatusOK},
+		{name: "Role lacks permission", claims: &auth.Claims{Subject: "intern", Roles: []string{auth.RoleViewer}}, expectedStatus: http.StatusForbidden, expectedBody: `{"type":"https://arritech.com/problems/permission-denied","title":"Permission denied","status":403,"detail":"Missing permission users:delete","code":"PERMISSION_DENIED"}`},
+		{name: "API key scope grants permission", claims: &auth.Claims{APIKeyID: 1, Scopes: []string{auth.PermissionUsersDelete}}, expectedStatus: http.StatusOK},
+		{name: "API key lacks scope", claims: &auth.Claims{APIKeyID: 1, Scopes: []string{auth.PermissionUsersRead}}, expectedStatus: http.StatusForbidden},
+		{name: "Authentication disabled", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			router := gin.New()
+			if tt.claims != nil {
+				router.Use(func(c *gin.Context) {
+					c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), tt.claims))
+				})
+			}
+			router.DELETE("/users/1", RequirePermission(auth.DefaultPolicy(), auth.PermissionUsersDelete), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/1", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
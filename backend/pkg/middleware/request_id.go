@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+
+	"arritech-user-management/pkg/logger"
+	"arritech-user-management/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceparentHeader is the W3C Trace Context header that carries the trace of a request
+const TraceparentHeader = "traceparent"
+
+// requestIDPattern limits client supplied request IDs to short tokens that are safe to log and echo
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// traceparentPattern matches version 00 of the traceparent header
+var traceparentPattern = regexp.MustCompile(`^00-([0-9a-f]{32})-([0-9a-f]{16})-([0-9a-f]{2})$`)
+
+// RequestIDMiddleware identifies every request. It keeps a well-formed X-Request-ID header or
+// generates one, and echoes it in the response together with a traceparent header. The trace is
+// taken from the span started by TracingMiddleware; without one it continues the trace of a valid
+// traceparent header or starts a new one. The request context gets a log entry carrying the
+// request ID, trace ID and span ID, so everything logged while serving the request can be tied
+// back to it.
+func RequestIDMiddleware(log *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := RequestID(c.GetHeader(problem.RequestIDHeader))
+
+		var traceID, spanID, flags string
+		if sc := trace.SpanContextFromContext(c.Request.Context()); sc.IsValid() {
+			// TracingMiddleware already joined or started the trace; log with its IDs
+			traceID, spanID, flags = sc.TraceID().String(), sc.SpanID().String(), sc.TraceFlags().String()
+		} else {
+			flags = "01"
+			if m := traceparentPattern.FindStringSubmatch(c.GetHeader(TraceparentHeader)); m != nil && !isZeroHex(m[1]) && !isZeroHex(m[2]) {
+				traceID, flags = m[1], m[3]
+			} else {
+				traceID = randomHex(16)
+			}
+			spanID = randomHex(8)
+		}
+
+		c.Header(problem.RequestIDHeader, requestID)
+		c.Header(TraceparentHeader, fmt.Sprintf("00-%s-%s-%s", traceID, spanID, flags))
+
+		entry := log.WithFields(logrus.Fields{
+			"request_id": requestID,
+			"trace_id":   traceID,
+			"span_id":    spanID,
+		})
+		c.Request = c.Request.WithContext(logger.NewContext(c.Request.Context(), entry))
+		c.Next()
+	}
+}
+
+// RequestID returns the client supplied ID when it is well-formed and a new one otherwise
+func RequestID(supplied string) string {
+	if requestIDPattern.MatchString(supplied) {
+		return supplied
+	}
+	return newRequestID()
+}
+
+// newRequestID returns a random version 4 UUID
+func newRequestID() string {
+	b := randomBytes(16)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func randomHex(n int) string {
+	return hex.EncodeToString(randomBytes(n))
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to read random bytes: %v", err))
+	}
+	return b
+}
+
+func isZeroHex(s string) bool {
+	for _, r := range s {
+		if r != '0' {
+			return false
+		}
+	}
+	return true
+}
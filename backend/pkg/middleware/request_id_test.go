@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"arritech-user-management/pkg/logger"
+	"arritech-user-management/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	tests := []struct {
+		name          string
+		requestID     string
+		traceparent   string
+		keepRequestID bool
+		keepTraceID   bool
+	}{
+		{name: "Generates missing IDs"},
+		{name: "Keeps a valid request ID", requestID: "req-123_abc.1", keepRequestID: true},
+		{name: "Replaces an invalid request ID", requestID: "bad id\r\nX-Injected: 1"},
+		{name: "Replaces an overlong request ID", requestID: strings.Repeat("a", 129)},
+		{name: "Continues a valid trace", traceparent: traceparent, keepTraceID: true},
+		{name: "Ignores a malformed traceparent", traceparent: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7"},
+		{name: "Ignores an all-zero trace ID", traceparent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, hook := test.NewNullLogger()
+
+			router := gin.New()
+			router.Use(RequestIDMiddleware(log))
+			router.GET("/test", func(c *gin.Context) {
+				logger.FromContext(c.Request.Context(), logrus.New()).Info("handled")
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.requestID != "" {
+				req.Header.Set(problem.RequestIDHeader, tt.requestID)
+			}
+			if tt.traceparent != "" {
+				req.Header.Set(TraceparentHeader, tt.traceparent)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			requestID := w.Header().Get(problem.RequestIDHeader)
+			if tt.keepRequestID {
+				assert.Equal(t, tt.requestID, requestID)
+			} else {
+				assert.Regexp(t, uuidPattern, requestID)
+			}
+
+			m := traceparentPattern.FindStringSubmatch(w.Header().Get(TraceparentHeader))
+			require.NotNil(t, m)
+			if tt.keepTraceID {
+				assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", m[1])
+				assert.NotEqual(t, "00f067aa0ba902b7", m[2])
+			} else {
+				assert.NotEqual(t, "4bf92f3577b34da6a3ce929d0e0e4736", m[1])
+				assert.False(t, isZeroHex(m[1]))
+			}
+
+			// Lines logged while handling the request carry its IDs
+			require.Len(t, hook.Entries, 1)
+			assert.Equal(t, requestID, hook.LastEntry().Data["request_id"])
+			assert.Equal(t, m[1], hook.LastEntry().Data["trace_id"])
+			assert.Equal(t, m[2], hook.LastEntry().Data["span_id"])
+		})
+	}
+}
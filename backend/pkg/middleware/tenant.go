@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/problem"
+	"arritech-user-management/pkg/tenant"
+	"github.com/gin-gonic/gin"
+)
+
+// OrganizationHeader is the request header that selects an organization by ID or slug
+const OrganizationHeader = "X-Organization"
+
+var (
+	// ErrUnknownOrganization is returned by resolvers when the referenced organization does not exist
+	ErrUnknownOrganization = errors.New("unknown organization")
+	// ErrOrganizationRequired is returned when no organization is selected and there is no default
+	ErrOrganizationRequired = errors.New("organization is required")
+	// ErrOrganizationForbidden is returned when the caller selects an organization it may not act in
+	ErrOrganizationForbidden = errors.New("organization not allowed")
+)
+
+// OrganizationResolver maps an organization ID or slug to the organization ID
+type OrganizationResolver func(ctx context.Context, ref string) (uint, error)
+
+// SelectOrganization returns the organization a request acts in, given the organization ref it
+// names. Callers bound to an organization by their credentials stay in it, and naming another
+// one returns ErrOrganizationForbidden. Other callers get defaultRef when ref is empty and may
+// only name a different organization with the platform:admin permission. Requests without a
+// caller, which only reach public routes or run with authentication disabled, may name any.
+func SelectOrganization(ctx context.Context, resolve OrganizationResolver, policy *auth.Policy, ref, defaultRef string) (uint, error) {
+	bound, isBound := tenant.FromContext(ctx)
+	if ref == "" {
+		if isBound {
+			return bound, nil
+		}
+		if defaultRef == "" {
+			return 0, ErrOrganizationRequired
+		}
+		return resolve(ctx, defaultRef)
+	}
+
+	id, err := resolve(ctx, ref)
+	if err != nil {
+		return 0, err
+	}
+	if isBound {
+		if id != bound {
+			return 0, ErrOrganizationForbidden
+		}
+		return id, nil
+	}
+
+	claims, ok := auth.FromContext(ctx)
+	if !ok || policy.Allows(claims, auth.PermissionPlatformAdmin) {
+		return id, nil
+	}
+	if defaultRef != "" {
+		defaultID, err := resolve(ctx, defaultRef)
+		if err != nil {
+			return 0, err
+		}
+		if id == defaultID {
+			return id, nil
+		}
+	}
+	return 0, ErrOrganizationForbidden
+}
+
+// TenantMiddleware places the organization of the request in the request context: the
+// organization bound to the credentials, the one named by the header when the caller may choose,
+// or defaultRef. An empty defaultRef makes the header mandatory for callers not bound to one.
+func TenantMiddleware(resolve OrganizationResolver, policy *auth.Policy, defaultRef string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := SelectOrganization(c.Request.Context(), resolve, policy, c.GetHeader(OrganizationHeader), defaultRef)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrOrganizationRequired):
+				problem.Abort(c, problem.CodeOrganizationRequired, "Set the "+OrganizationHeader+" header")
+			case errors.Is(err, ErrUnknownOrganization):
+				problem.Abort(c, problem.CodeOrganizationUnknown, "")
+			case errors.Is(err, ErrOrganizationForbidden):
+				problem.Abort(c, problem.CodeOrganizationForbidden, "")
+			default:
+				problem.Abort(c, problem.CodeInternalError, "Failed to resolve organization")
+			}
+			return
+		}
+
+		c.Request = c.Request.WithContext(tenant.NewContext(c.Request.Context(), id))
+		c.Next()
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"arritech-user-management/pkg/auth"
+	"arritech-user-management/pkg/tenant"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func testResolver(ctx context.Context, ref string) (uint, error) {
+	switch ref {
+	case "default":
+		return 1, nil
+	case "acme":
+		return 2, nil
+	case "broken":
+		return 0, errors.New("database unavailable")
+	default:
+		return 0, ErrUnknownOrganization
+	}
+}
+
+func TestTenantMiddleware(t *testing.T) {
+	member := &auth.Claims{Subject: "jane", Roles: []string{auth.RoleAdmin}}
+	platformAdmin := &auth.Claims{Subject: "ops", Roles: []string{auth.RolePlatformAdmin}}
+
+	tests := []struct {
+		name           string
+		header         string
+		defaultRef     string
+		caller         *auth.Claims
+		preset         uint
+		expectedStatus int
+		expectedOrg    string
+	}{
+		{name: "Header selects organization", header: "acme", defaultRef: "default", expectedStatus: http.StatusOK, expectedOrg: "2"},
+		{name: "Falls back to default", defaultRef: "default", expectedStatus: http.StatusOK, expectedOrg: "1"},
+		{name: "Header required without default", expectedStatus: http.StatusBadRequest},
+		{name: "Unknown organization", header: "nope", defaultRef: "default", expectedStatus: http.StatusBadRequest},
+		{name: "Resolver failure", header: "broken", expectedStatus: http.StatusInternalServerError},
+		{name: "Authenticated organization without header", caller: member, preset: 5, expectedStatus: http.StatusOK, expectedOrg: "5"},
+		{name: "Header matching the authenticated organization", header: "acme", caller: member, preset: 2, expectedStatus: http.StatusOK, expectedOrg: "2"},
+		{name: "Header naming another organization than the credentials", header: "acme", caller: member, preset: 5, expectedStatus: http.StatusForbidden},
+		{name: "Unbound caller naming the default", header: "default", defaultRef: "default", caller: member, expectedStatus: http.StatusOK, expectedOrg: "1"},
+		{name: "Unbound caller naming another organization", header: "acme", defaultRef: "default", caller: member, expectedStatus: http.StatusForbidden},
+		{name: "Platform admin naming any organization", header: "acme", defaultRef: "default", caller: platformAdmin, expectedStatus: http.StatusOK, expectedOrg: "2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				ctx := c.Request.Context()
+				if tt.caller != nil {
+					ctx = auth.NewContext(ctx, tt.caller)
+				}
+				if tt.preset != 0 {
+					ctx = tenant.NewContext(ctx, tt.preset)
+				}
+				c.Request = c.Request.WithContext(ctx)
+			})
+			router.Use(TenantMiddleware(testResolver, auth.DefaultPolicy(), tt.defaultRef))
+			router.GET("/test", func(c *gin.Context) {
+				id, _ := tenant.FromContext(c.Request.Context())
+				c.String(http.StatusOK, strconv.FormatUint(uint64(id), 10))
+			})
+
+			req, _ := http.NewRequest("GET", "/test", nil)
+			if tt.header != "" {
+				req.Header.Set(OrganizationHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedOrg != "" {
+				assert.Equal(t, tt.expectedOrg, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"arritech-user-management/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	setup := func() (*gin.Engine, *tracetest.SpanRecorder, *test.Hook) {
+		recorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		log, hook := test.NewNullLogger()
+
+		router := gin.New()
+		router.Use(TracingMiddleware(provider, propagation.TraceContext{}))
+		router.Use(RequestIDMiddleware(log))
+		router.GET("/users/:id", func(c *gin.Context) {
+			logger.FromContext(c.Request.Context(), logrus.New()).Info("handled")
+			if c.Param("id") == "0" {
+				c.Status(http.StatusInternalServerError)
+				return
+			}
+			c.Status(http.StatusOK)
+		})
+		return router, recorder, hook
+	}
+
+	t.Run("Continues the caller's trace", func(t *testing.T) {
+		router, recorder, hook := setup()
+
+		req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+		req.Header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		span := spans[0]
+		assert.Equal(t, "GET /users/:id", span.Name())
+		assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+		assert.Contains(t, span.Attributes(), attribute.String("http.route", "/users/:id"))
+		assert.Contains(t, span.Attributes(), attribute.Int("http.response.status_code", http.StatusOK))
+
+		// Logs and the echoed traceparent carry the IDs of the server span
+		assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+span.SpanContext().SpanID().String()+"-01", w.Header().Get(TraceparentHeader))
+		assert.Equal(t, span.SpanContext().SpanID().String(), hook.LastEntry().Data["span_id"])
+	})
+
+	t.Run("Server errors fail the span", func(t *testing.T) {
+		router, recorder, _ := setup()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/0", nil))
+
+		span := recorder.Ended()[0]
+		assert.False(t, span.Parent().IsValid())
+		assert.Equal(t, codes.Error, span.Status().Code)
+	})
+}
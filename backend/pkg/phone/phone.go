@@ -0,0 +1,57 @@
+// Package phone extracts country calling codes from international phone numbers.
+package phone
+
+import "strings"
+
+// twoDigitCodes are the E.164 country calling codes of two digits. Calling codes are
+// prefix-free: numbers starting with 1 or 7 have a one-digit code, numbers starting with
+// one of these pairs have a two-digit code and all other numbers have a three-digit code.
+var twoDigitCodes = map[string]bool{
+	"20": true, "27": true,
+	"30": true, "31": true, "32": true, "33": true, "34": true, "36": true, "39": true,
+	"40": true, "41": true, "43": true, "44": true, "45": true, "46": true, "47": true, "48": true, "49": true,
+	"51": true, "52": true, "53": true, "54": true, "55": true, "56": true, "57": true, "58": true,
+	"60": true, "61": true, "62": true, "63": true, "64": true, "65": true, "66": true,
+	"81": true, "82": true, "84": true, "86": true,
+	"90": true, "91": true, "92": true, "93": true, "94": true, "95": true, "98": true,
+}
+
+// CountryCode returns the calling code of an international number, such as "+44" for
+// "+44 20 7946 0958". Numbers may use the "00" international prefix. Numbers without an
+// international prefix have no recognizable country code.
+func CountryCode(number string) (string, bool) {
+	digits := normalize(number)
+	switch {
+	case strings.HasPrefix(digits, "+"):
+		digits = digits[1:]
+	case strings.HasPrefix(digits, "00"):
+		digits = digits[2:]
+	default:
+		return "", false
+	}
+
+	length := 3
+	switch {
+	case digits == "" || digits[0] == '0':
+		return "", false
+	case digits[0] == '1' || digits[0] == '7':
+		length = 1
+	case len(digits) >= 2 && twoDigitCodes[digits[:2]]:
+		length = 2
+	}
+	if len(digits) < length {
+		return "", false
+	}
+	return "+" + digits[:length], true
+}
+
+// normalize drops the separators commonly used in phone numbers
+func normalize(number string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(number))
+}
@@ -0,0 +1,35 @@
+package phone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountryCode(t *testing.T) {
+	tests := []struct {
+		number   string
+		expected string
+		ok       bool
+	}{
+		{"+1 415 555 2671", "+1", true},
+		{"+7 495 123-45-67", "+7", true},
+		{"+44 20 7946 0958", "+44", true},
+		{"+55 (11) 91234-5678", "+55", true},
+		{"0055 11 91234 5678", "+55", true},
+		{"+351 912 345 678", "+351", true},
+		{"+880 1712 345678", "+880", true},
+		{"+2", "", false},
+		{"+0 123", "", false},
+		{"(11) 91234-5678", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			code, ok := CountryCode(tt.number)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, code)
+		})
+	}
+}
@@ -0,0 +1,235 @@
+// Package problem writes error responses as RFC 7807 problem details
+// (application/problem+json) with a stable, machine-readable code.
+package problem
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType is the media type of problem responses
+const ContentType = "application/problem+json"
+
+// RequestIDHeader carries the request ID that becomes the instance of a problem
+const RequestIDHeader = "X-Request-ID"
+
+// TypeBaseURI prefixes the type of every problem. Type URIs identify the code; they need not resolve.
+var TypeBaseURI = "https://arritech.com/problems/"
+
+// Code identifies a kind of error. Codes are stable, so clients should match on them
+// rather than on titles or details, which are written for people and may change.
+// The comment of each code, which starts with its HTTP status, is published in the API docs.
+type Code string
+
+const (
+	CodeMalformedRequest     Code = "MALFORMED_REQUEST"      // 400: The request body is not valid JSON or doesn't match the expected shape
+	CodeValidationFailed     Code = "VALIDATION_FAILED"      // 400: One or more fields failed validation; errors lists them
+	CodeInvalidParameter     Code = "INVALID_PARAMETER"      // 400: A path or query parameter is invalid; errors names it
+	CodeUnsupportedMediaType Code = "UNSUPPORTED_MEDIA_TYPE" // 415: The request body has a content type the endpoint doesn't accept
+	CodeRequestTooLarge      Code = "REQUEST_TOO_LARGE"      // 413: The request body exceeds the size limit
+	CodeInternalError        Code = "INTERNAL_ERROR"         // 500: The server failed to process the request
+
+	CodeAuthenticationRequired Code = "AUTHENTICATION_REQUIRED" // 401: The request carries neither a bearer token nor an API key
+	CodeTokenInvalid           Code = "TOKEN_INVALID"           // 401: The bearer token is malformed, has a bad signature or is not yet valid
+	CodeTokenExpired           Code = "TOKEN_EXPIRED"           // 401: The bearer token has expired
+	CodeAPIKeyInvalid          Code = "API_KEY_INVALID"         // 401: The API key is unknown or revoked
+	CodeAPIKeyExpired          Code = "API_KEY_EXPIRED"         // 401: The API key has expired
+	CodeAPIKeyNotAllowed       Code = "API_KEY_NOT_ALLOWED"     // 403: The route is reserved for people and can't be called with an API key
+	CodePermissionDenied       Code = "PERMISSION_DENIED"       // 403: The caller lacks the permission named in the detail
+
+	CodeOrganizationRequired     Code = "ORGANIZATION_REQUIRED"       // 400: No organization was selected and the server has no default
+	CodeOrganizationUnknown      Code = "ORGANIZATION_UNKNOWN"        // 400: The X-Organization header names an organization that doesn't exist
+	CodeOrganizationForbidden    Code = "ORGANIZATION_FORBIDDEN"      // 403: The X-Organization header names an organization the caller can't act in
+	CodeRateLimited              Code = "RATE_LIMITED"                // 429: The caller sent too many requests; Retry-After says when to try again
+	CodeIdempotencyKeyInvalid    Code = "IDEMPOTENCY_KEY_INVALID"     // 400: The Idempotency-Key header is too long
+	CodeIdempotencyKeyReused     Code = "IDEMPOTENCY_KEY_REUSED"      // 422: The Idempotency-Key was already used for a different request
+	CodeIdempotencyKeyInProgress Code = "IDEMPOTENCY_KEY_IN_PROGRESS" // 409: A request with the same Idempotency-Key is still running; Retry-After says when to try again
+
+	CodeUserNotFound                Code = "USER_NOT_FOUND"                 // 404: The user doesn't exist in the organization
+	CodeUserEmailTaken              Code = "USER_EMAIL_TAKEN"               // 400: Another user in the organization has the email address
+	CodeUserUnderage                Code = "USER_UNDERAGE"                  // 400: The user is younger than the organization's minimum age
+	CodeUserEmailDomainNotAllowed   Code = "USER_EMAIL_DOMAIN_NOT_ALLOWED"  // 400: The organization doesn't accept email addresses from the domain
+	CodeUserStatusTransitionInvalid Code = "USER_STATUS_TRANSITION_INVALID" // 409: The user can't move from its current status to the requested one
+	CodePatchInvalid                Code = "PATCH_INVALID"                  // 400: The patch document is malformed or uses an unsupported operation
+	CodePatchTestFailed             Code = "PATCH_TEST_FAILED"              // 409: A test operation of the patch didn't match the current user
+	CodePatchResultInvalid          Code = "PATCH_RESULT_INVALID"           // 422: The patch applied, but the resulting user is invalid
+	CodeUserModified                Code = "USER_MODIFIED"                  // 409: The user changed while the patch was applied; apply it again
+
+	CodeVerificationTokenInvalid Code = "VERIFICATION_TOKEN_INVALID" // 400: The email verification token is invalid or has expired
+	CodeEmailAlreadyVerified     Code = "EMAIL_ALREADY_VERIFIED"     // 409: The user's email address is already verified
+	CodeVerificationRateLimited  Code = "VERIFICATION_RATE_LIMITED"  // 429: A verification email was sent recently; Retry-After says when another can be sent
+
+	CodeAvatarNotFound Code = "AVATAR_NOT_FOUND" // 404: The user has no avatar
+	CodeAvatarInvalid  Code = "AVATAR_INVALID"   // 400: The avatar file is missing or isn't a supported image
+	CodeAvatarTooLarge Code = "AVATAR_TOO_LARGE" // 413: The avatar file exceeds the upload limit
+	CodeAvatarReplaced Code = "AVATAR_REPLACED"  // 409: Another upload replaced the avatar while this one was processed; upload it again
+
+	CodeBirthdayRangeInvalid Code = "BIRTHDAY_RANGE_INVALID" // 400: The birthday date range is empty or too long
+	CodeFeedTokenInvalid     Code = "FEED_TOKEN_INVALID"     // 401: The calendar feed token is missing, invalid or expired
+	CodeStatsParamsInvalid   Code = "STATS_PARAMS_INVALID"   // 400: The statistics parameters are inconsistent, e.g. from is after to
+
+	CodeNoteNotFound               Code = "NOTE_NOT_FOUND"               // 404: The note doesn't exist on the user
+	CodeTagNotFound                Code = "TAG_NOT_FOUND"                // 404: The tag doesn't exist in the organization
+	CodeTagNameTaken               Code = "TAG_NAME_TAKEN"               // 409: Another tag in the organization has the name
+	CodeGroupNotFound              Code = "GROUP_NOT_FOUND"              // 404: The group doesn't exist in the organization
+	CodeGroupNameTaken             Code = "GROUP_NAME_TAKEN"             // 409: Another group in the organization has the name
+	CodeOrganizationNotFound       Code = "ORGANIZATION_NOT_FOUND"       // 404: The organization doesn't exist
+	CodeOrganizationSlugTaken      Code = "ORGANIZATION_SLUG_TAKEN"      // 409: Another organization has the slug
+	CodeOrganizationInvalid        Code = "ORGANIZATION_INVALID"         // 400: The organization settings are inconsistent
+	CodeAPIKeyNotFound             Code = "API_KEY_NOT_FOUND"            // 404: The API key doesn't exist in the organization
+	CodeAttributeNotFound          Code = "ATTRIBUTE_NOT_FOUND"          // 404: The attribute definition doesn't exist in the organization
+	CodeAttributeKeyTaken          Code = "ATTRIBUTE_KEY_TAKEN"          // 409: Another attribute definition in the organization has the key
+	CodeAttributeDefinitionInvalid Code = "ATTRIBUTE_DEFINITION_INVALID" // 400: The attribute definition is inconsistent, e.g. a select without options
+)
+
+type definition struct {
+	status int
+	title  string
+}
+
+// catalogue gives the status and title of every code
+var catalogue = map[Code]definition{
+	CodeMalformedRequest:     {http.StatusBadRequest, "Malformed request"},
+	CodeValidationFailed:     {http.StatusBadRequest, "Validation failed"},
+	CodeInvalidParameter:     {http.StatusBadRequest, "Invalid parameter"},
+	CodeUnsupportedMediaType: {http.StatusUnsupportedMediaType, "Unsupported media type"},
+	CodeRequestTooLarge:      {http.StatusRequestEntityTooLarge, "Request too large"},
+	CodeInternalError:        {http.StatusInternalServerError, "Internal server error"},
+
+	CodeAuthenticationRequired: {http.StatusUnauthorized, "Authentication required"},
+	CodeTokenInvalid:           {http.StatusUnauthorized, "Invalid token"},
+	CodeTokenExpired:           {http.StatusUnauthorized, "Token expired"},
+	CodeAPIKeyInvalid:          {http.StatusUnauthorized, "Invalid API key"},
+	CodeAPIKeyExpired:          {http.StatusUnauthorized, "API key expired"},
+	CodeAPIKeyNotAllowed:       {http.StatusForbidden, "API keys cannot access this resource"},
+	CodePermissionDenied:       {http.StatusForbidden, "Permission denied"},
+
+	CodeOrganizationRequired:     {http.StatusBadRequest, "Organization is required"},
+	CodeOrganizationUnknown:      {http.StatusBadRequest, "Unknown organization"},
+	CodeOrganizationForbidden:    {http.StatusForbidden, "Organization not allowed"},
+	CodeRateLimited:              {http.StatusTooManyRequests, "Too many requests"},
+	CodeIdempotencyKeyInvalid:    {http.StatusBadRequest, "Invalid Idempotency-Key"},
+	CodeIdempotencyKeyReused:     {http.StatusUnprocessableEntity, "Idempotency-Key reused"},
+	CodeIdempotencyKeyInProgress: {http.StatusConflict, "Request in progress"},
+
+	CodeUserNotFound:                {http.StatusNotFound, "User not found"},
+	CodeUserEmailTaken:              {http.StatusBadRequest, "Email already exists"},
+	CodeUserUnderage:                {http.StatusBadRequest, "User is under the minimum age"},
+	CodeUserEmailDomainNotAllowed:   {http.StatusBadRequest, "Email domain not allowed"},
+	CodeUserStatusTransitionInvalid: {http.StatusConflict, "Invalid status transition"},
+	CodePatchInvalid:                {http.StatusBadRequest, "Invalid patch"},
+	CodePatchTestFailed:             {http.StatusConflict, "Patch test failed"},
+	CodePatchResultInvalid:          {http.StatusUnprocessableEntity, "Patched user is invalid"},
+	CodeUserModified:                {http.StatusConflict, "User was modified"},
+
+	CodeVerificationTokenInvalid: {http.StatusBadRequest, "Invalid verification token"},
+	CodeEmailAlreadyVerified:     {http.StatusConflict, "Email already verified"},
+	CodeVerificationRateLimited:  {http.StatusTooManyRequests, "Verification email sent recently"},
+
+	CodeAvatarNotFound: {http.StatusNotFound, "Avatar not found"},
+	CodeAvatarInvalid:  {http.StatusBadRequest, "Invalid avatar"},
+	CodeAvatarTooLarge: {http.StatusRequestEntityTooLarge, "Avatar is too large"},
+	CodeAvatarReplaced: {http.StatusConflict, "Avatar was replaced"},
+
+	CodeBirthdayRangeInvalid: {http.StatusBadRequest, "Invalid birthday range"},
+	CodeFeedTokenInvalid:     {http.StatusUnauthorized, "Invalid calendar feed token"},
+	CodeStatsParamsInvalid:   {http.StatusBadRequest, "Invalid statistics parameters"},
+
+	CodeNoteNotFound:               {http.StatusNotFound, "Note not found"},
+	CodeTagNotFound:                {http.StatusNotFound, "Tag not found"},
+	CodeTagNameTaken:               {http.StatusConflict, "Tag name already exists"},
+	CodeGroupNotFound:              {http.StatusNotFound, "Group not found"},
+	CodeGroupNameTaken:             {http.StatusConflict, "Group name already exists"},
+	CodeOrganizationNotFound:       {http.StatusNotFound, "Organization not found"},
+	CodeOrganizationSlugTaken:      {http.StatusConflict, "Organization slug already exists"},
+	CodeOrganizationInvalid:        {http.StatusBadRequest, "Invalid organization"},
+	CodeAPIKeyNotFound:             {http.StatusNotFound, "API key not found"},
+	CodeAttributeNotFound:          {http.StatusNotFound, "Attribute definition not found"},
+	CodeAttributeKeyTaken:          {http.StatusConflict, "Attribute key already exists"},
+	CodeAttributeDefinitionInvalid: {http.StatusBadRequest, "Invalid attribute definition"},
+}
+
+// Status returns the HTTP status of the code; codes missing from the catalogue are server errors
+func (c Code) Status() int {
+	if def, ok := catalogue[c]; ok {
+		return def.status
+	}
+	return http.StatusInternalServerError
+}
+
+// Title returns the short, human-readable summary of the code
+func (c Code) Title() string {
+	if def, ok := catalogue[c]; ok {
+		return def.title
+	}
+	return http.StatusText(http.StatusInternalServerError)
+}
+
+// Type returns the URI that identifies the code, e.g. https://arritech.com/problems/user-email-taken
+func (c Code) Type() string {
+	return TypeBaseURI + strings.ReplaceAll(strings.ToLower(string(c)), "_", "-")
+}
+
+// FieldError points at one invalid part of a request: a body field by JSON pointer, or a
+// path or query parameter by name
+type FieldError struct {
+	Pointer   string `json:"pointer,omitempty" example:"/date_of_birth"`
+	Parameter string `json:"parameter,omitempty" example:"sort_by"`
+	Detail    string `json:"detail" example:"Must be a date in YYYY-MM-DD format"`
+}
+
+// Problem is an RFC 7807 problem details object
+type Problem struct {
+	Type     string `json:"type" example:"https://arritech.com/problems/validation-failed"`
+	Title    string `json:"title" example:"Validation failed"`
+	Status   int    `json:"status" example:"400"`
+	Detail   string `json:"detail,omitempty" example:"The request has 1 invalid field"`
+	Instance string `json:"instance,omitempty" example:"3f2b8c1e-9a4d-4e57-b1a2-6c0d9e8f7a65"`
+	// Code is the stable, machine-readable error code
+	Code   Code         `json:"code" example:"VALIDATION_FAILED"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// New creates the problem for code. An empty detail falls back to the title.
+func New(code Code, detail string) *Problem {
+	if detail == "" {
+		detail = code.Title()
+	}
+	return &Problem{
+		Type:   code.Type(),
+		Title:  code.Title(),
+		Status: code.Status(),
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// WithErrors adds the invalid parts of the request to the problem
+func (p *Problem) WithErrors(errs ...FieldError) *Problem {
+	p.Errors = append(p.Errors, errs...)
+	return p
+}
+
+// Respond writes p as the response and aborts the handler chain. The instance is the
+// request ID, when the request has one.
+func Respond(c *gin.Context, p *Problem) {
+	if p.Instance == "" {
+		p.Instance = requestID(c)
+	}
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
+
+// Abort responds with the problem for code
+func Abort(c *gin.Context, code Code, detail string) {
+	Respond(c, New(code, detail))
+}
+
+func requestID(c *gin.Context) string {
+	if id := c.Writer.Header().Get(RequestIDHeader); id != "" {
+		return id
+	}
+	return c.GetHeader(RequestIDHeader)
+}
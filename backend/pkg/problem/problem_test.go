@@ -0,0 +1,104 @@
+package problem
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogue(t *testing.T) {
+	// Every Code constant must be in the catalogue, or it would silently become a 500
+	file, err := parser.ParseFile(token.NewFileSet(), "problem.go", nil, parser.ParseComments)
+	require.NoError(t, err)
+
+	declared := 0
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		if ident, ok := spec.Type.(*ast.Ident); !ok || ident.Name != "Code" {
+			return true
+		}
+		for _, value := range spec.Values {
+			name, err := strconv.Unquote(value.(*ast.BasicLit).Value)
+			require.NoError(t, err)
+			declared++
+			code := Code(name)
+			if assert.Contains(t, catalogue, code) && assert.NotNil(t, spec.Comment, code) {
+				// The published comment starts with the status
+				assert.True(t, strings.HasPrefix(spec.Comment.Text(), strconv.Itoa(code.Status())+": "), code)
+			}
+		}
+		return true
+	})
+	assert.Equal(t, len(catalogue), declared)
+
+	for code, def := range catalogue {
+		assert.GreaterOrEqual(t, def.status, 400, code)
+		assert.NotEmpty(t, def.title, code)
+	}
+}
+
+func TestCode(t *testing.T) {
+	assert.Equal(t, http.StatusBadRequest, CodeUserEmailTaken.Status())
+	assert.Equal(t, "Email already exists", CodeUserEmailTaken.Title())
+	assert.Equal(t, "https://arritech.com/problems/user-email-taken", CodeUserEmailTaken.Type())
+
+	unknown := Code("NOT_A_CODE")
+	assert.Equal(t, http.StatusInternalServerError, unknown.Status())
+	assert.Equal(t, "Internal Server Error", unknown.Title())
+}
+
+func TestRespond(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Writes problem+json with the request ID as instance", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/users", nil)
+		c.Request.Header.Set(RequestIDHeader, "req-1")
+
+		Respond(c, New(CodeValidationFailed, "The request has 1 invalid field").
+			WithErrors(FieldError{Pointer: "/date_of_birth", Detail: "Must be a date in YYYY-MM-DD format"}))
+
+		assert.True(t, c.IsAborted())
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{
+			"type": "https://arritech.com/problems/validation-failed",
+			"title": "Validation failed",
+			"status": 400,
+			"detail": "The request has 1 invalid field",
+			"instance": "req-1",
+			"code": "VALIDATION_FAILED",
+			"errors": [{"pointer": "/date_of_birth", "detail": "Must be a date in YYYY-MM-DD format"}]
+		}`, w.Body.String())
+	})
+
+	t.Run("Detail defaults to the title", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/users/1", nil)
+
+		Abort(c, CodeUserNotFound, "")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{
+			"type": "https://arritech.com/problems/user-not-found",
+			"title": "User not found",
+			"status": 404,
+			"detail": "User not found",
+			"code": "USER_NOT_FOUND"
+		}`, w.Body.String())
+	})
+}
@@ -0,0 +1,17 @@
+// Package tenant carries the organization a request acts on through a context.
+package tenant
+
+import "context"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries the given organization ID
+func NewContext(ctx context.Context, organizationID uint) context.Context {
+	return context.WithValue(ctx, contextKey{}, organizationID)
+}
+
+// FromContext returns the organization ID carried by ctx, if any
+func FromContext(ctx context.Context) (uint, bool) {
+	id, ok := ctx.Value(contextKey{}).(uint)
+	return id, ok && id != 0
+}
@@ -0,0 +1,25 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	ctx := NewContext(context.Background(), 7)
+	id, ok := FromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, uint(7), id)
+
+	// An inner organization shadows the outer one
+	id, _ = FromContext(NewContext(ctx, 9))
+	assert.Equal(t, uint(9), id)
+
+	_, ok = FromContext(NewContext(context.Background(), 0))
+	assert.False(t, ok)
+}
@@ -0,0 +1,66 @@
+package token
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_SignAndVerify(t *testing.T) {
+	signer := NewSigner([]byte("test-secret"))
+
+	token, err := signer.Sign("email_verification", "1:test@example.com", time.Hour)
+	require.NoError(t, err)
+
+	subject, err := signer.Verify("email_verification", token)
+	assert.NoError(t, err)
+	assert.Equal(t, "1:test@example.com", subject)
+}
+
+func TestSigner_Verify(t *testing.T) {
+	signer := NewSigner([]byte("test-secret"))
+	validToken, err := signer.Sign("email_verification", "1:test@example.com", time.Hour)
+	require.NoError(t, err)
+
+	otherSigner := NewSigner([]byte("other-secret"))
+	foreignToken, err := otherSigner.Sign("email_verification", "1:test@example.com", time.Hour)
+	require.NoError(t, err)
+
+	encoded, _, _ := strings.Cut(validToken, ".")
+
+	tests := []struct {
+		name          string
+		purpose       string
+		token         string
+		expectedError error
+	}{
+		{"Missing separator", "email_verification", "not-a-token", ErrInvalidToken},
+		{"Tampered signature", "email_verification", encoded + ".tampered", ErrInvalidToken},
+		{"Signed with another secret", "email_verification", foreignToken, ErrInvalidToken},
+		{"Wrong purpose", "password_reset", validToken, ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject, err := signer.Verify(tt.purpose, tt.token)
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Empty(t, subject)
+		})
+	}
+}
+
+func TestSigner_VerifyExpired(t *testing.T) {
+	signer := NewSigner([]byte("test-secret"))
+	signer.now = func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	token, err := signer.Sign("email_verification", "1:test@example.com", time.Hour)
+	require.NoError(t, err)
+
+	signer.now = func() time.Time { return time.Date(2025, 1, 1, 2, 0, 0, 0, time.UTC) }
+
+	_, err = signer.Verify("email_verification", token)
+	assert.ErrorIs(t, err, ErrExpiredToken)
+}
@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+func TestGORMPlugin(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{})
+	require.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	require.NoError(t, db.Use(GORMPlugin(provider)))
+
+	mock.ExpectQuery("SELECT \\* FROM `widgets`").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "b"))
+	mock.ExpectQuery("SELECT \\* FROM `widgets`").WillReturnError(assert.AnError)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	var widgets []widget
+	require.NoError(t, db.WithContext(ctx).Where("name <> ?", "secret").Find(&widgets).Error)
+	assert.Error(t, db.WithContext(ctx).Raw("SELECT * FROM `widgets` WHERE name = 'secret'").Scan(&widgets).Error)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	query := spans[0]
+	assert.Equal(t, "gorm.query", query.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent().SpanID())
+	assert.Contains(t, query.Attributes(), attribute.String("db.system", "mysql"))
+	assert.Contains(t, query.Attributes(), attribute.String("db.sql.table", "widgets"))
+	assert.Contains(t, query.Attributes(), attribute.String("db.statement", "SELECT * FROM `widgets` WHERE name <> ?"))
+	assert.Contains(t, query.Attributes(), attribute.Int64("db.rows_affected", 2))
+
+	// Raw(...).Scan runs through the row callbacks
+	raw := spans[1]
+	assert.Equal(t, "gorm.row", raw.Name())
+	assert.Contains(t, raw.Attributes(), attribute.String("db.statement", "SELECT * FROM `widgets` WHERE name = ?"))
+	assert.Equal(t, codes.Error, raw.Status().Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
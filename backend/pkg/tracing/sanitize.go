@@ -0,0 +1,12 @@
+package tracing
+
+import "regexp"
+
+// sqlLiteral matches quoted strings and numbers that aren't part of an identifier
+var sqlLiteral = regexp.MustCompile(`'(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.|"")*"|\b\d+(?:\.\d+)?\b`)
+
+// SanitizeSQL replaces the literals in a SQL statement with ?, so that traces carry the shape of a
+// query but none of the data in it. Values bound as parameters are never part of the statement.
+func SanitizeSQL(sql string) string {
+	return sqlLiteral.ReplaceAllString(sql, "?")
+}
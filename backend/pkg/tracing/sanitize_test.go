@@ -0,0 +1,27 @@
+package tracing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeSQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{name: "Placeholders are kept", sql: "SELECT * FROM `users` WHERE email = ? LIMIT ?", expected: "SELECT * FROM `users` WHERE email = ? LIMIT ?"},
+		{name: "Strings", sql: "SELECT * FROM users WHERE email = 'john@example.com' AND name = \"John\"", expected: "SELECT * FROM users WHERE email = ? AND name = ?"},
+		{name: "Escaped quotes", sql: `SELECT * FROM users WHERE name = 'O\'Brien' OR name = 'O''Brien'`, expected: "SELECT * FROM users WHERE name = ? OR name = ?"},
+		{name: "Numbers", sql: "SELECT * FROM users WHERE id = 42 AND score > 1.5 LIMIT 10", expected: "SELECT * FROM users WHERE id = ? AND score > ? LIMIT ?"},
+		{name: "Identifiers with digits", sql: "SELECT t2.id FROM user_tags AS t2", expected: "SELECT t2.id FROM user_tags AS t2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SanitizeSQL(tt.sql))
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Package tracing sets up OpenTelemetry tracing: the tracer provider with its exporter and sampler,
+// W3C trace context propagation, and spans for the queries GORM runs.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// InstrumentationName names the tracers of this application
+const InstrumentationName = "arritech-user-management"
+
+// Config holds tracing configuration
+type Config struct {
+	// Exporter is otlp, stdout or none. With none, spans are still created so that trace IDs
+	// reach the logs and outgoing context, but nothing is exported.
+	Exporter    string
+	ServiceName string
+	// SampleRatio is the fraction of new traces that are sampled. Requests continuing a trace
+	// follow the sampling decision of their caller.
+	SampleRatio float64
+}
+
+// GetConfigFromEnv creates tracing config from environment variables. The OTLP exporter reads
+// its endpoint, headers and protocol options from the standard OTEL_EXPORTER_OTLP_* variables.
+func GetConfigFromEnv() Config {
+	ratio, err := strconv.ParseFloat(getEnv("TRACING_SAMPLE_RATIO", "1"), 64)
+	if err != nil {
+		ratio = 1
+	}
+	return Config{
+		Exporter:    getEnv("TRACING_EXPORTER", "none"),
+		ServiceName: getEnv("OTEL_SERVICE_NAME", "user-management-api"),
+		SampleRatio: ratio,
+	}
+}
+
+// Setup creates a tracer provider for config and installs it, together with the W3C trace context
+// and baggage propagators, as the global provider. The returned provider must be shut down on exit
+// to flush buffered spans.
+func Setup(ctx context.Context, config Config) (*sdktrace.TracerProvider, error) {
+	if config.SampleRatio < 0 || config.SampleRatio > 1 {
+		return nil, fmt.Errorf("trace sample ratio must be between 0 and 1")
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(config.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	}
+	switch strings.ToLower(config.Exporter) {
+	case "otlp":
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	case "stdout":
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithSyncer(exporter))
+	case "none", "":
+	default:
+		return nil, fmt.Errorf("unsupported trace exporter: %s", config.Exporter)
+	}
+
+	provider := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider, nil
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      Config
+		expectError bool
+	}{
+		{name: "Without exporter", config: Config{Exporter: "none", ServiceName: "test", SampleRatio: 1}},
+		{name: "Stdout exporter", config: Config{Exporter: "stdout", ServiceName: "test", SampleRatio: 0.5}},
+		{name: "OTLP exporter", config: Config{Exporter: "otlp", ServiceName: "test", SampleRatio: 1}},
+		{name: "Unknown exporter", config: Config{Exporter: "zipkin", SampleRatio: 1}, expectError: true},
+		{name: "Invalid sample ratio", config: Config{Exporter: "none", SampleRatio: 2}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := Setup(context.Background(), tt.config)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Same(t, provider, otel.GetTracerProvider())
+			assert.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, otel.GetTextMapPropagator().Fields())
+			assert.NoError(t, provider.Shutdown(context.Background()))
+		})
+	}
+}
+
+func TestGetConfigFromEnv(t *testing.T) {
+	t.Setenv("TRACING_EXPORTER", "otlp")
+	t.Setenv("TRACING_SAMPLE_RATIO", "0.25")
+	t.Setenv("OTEL_SERVICE_NAME", "users")
+
+	config := GetConfigFromEnv()
+
+	assert.Equal(t, Config{Exporter: "otlp", ServiceName: "users", SampleRatio: 0.25}, config)
+}
@@ -27,7 +27,8 @@ api.interceptors.response.use(
     return response.data
   },
   (error) => {
-    const message = error.response?.data?.error || error.message || 'An error occurred'
+    // Errors are problem+json; code identifies the error, detail describes it
+    const message = error.response?.data?.detail || error.message || 'An error occurred'
     
     // Show error message
     ElMessage.error(message)